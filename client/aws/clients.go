@@ -1,16 +1,21 @@
 package aws
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/aws/aws-sdk-go/service/kms"
@@ -18,6 +23,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/aws/aws-sdk-go/service/support"
@@ -37,17 +44,32 @@ type Config struct {
 	Region          string
 	RoleARN         string
 	SessionToken    string
+
+	// RoleExternalID is the external ID the trust policy of the role given by
+	// RoleARN requires. It is optional.
+	RoleExternalID string
+	// RoleSessionDuration is the duration of the role sessions. The SDK default
+	// of 15 minutes is used in case it is zero.
+	RoleSessionDuration time.Duration
+	// RoleSessionName is the name of the role sessions, which shows up in
+	// CloudTrail. A timestamp is used in case it is empty.
+	RoleSessionName string
+	// RoleSessionPolicy is an IAM policy in JSON format further restricting the
+	// permissions of the role sessions. It is optional.
+	RoleSessionPolicy string
 }
 
 type Clients struct {
-	AutoScaling    *autoscaling.AutoScaling
+	AutoScaling    autoscalingiface.AutoScalingAPI
 	CloudFormation *cloudformation.CloudFormation
 	EC2            ec2iface.EC2API
 	ELB            elbiface.ELBAPI
+	ELBV2          elbv2iface.ELBV2API
 	IAM            iamiface.IAMAPI
 	KMS            kmsiface.KMSAPI
 	Route53        *route53.Route53
 	S3             s3iface.S3API
+	SSM            ssmiface.SSMAPI
 	STS            stsiface.STSAPI
 	Support        supportiface.SupportAPI
 }
@@ -80,7 +102,20 @@ func NewClients(config Config) (Clients, error) {
 
 	var c Clients
 	if config.RoleARN != "" {
-		creds := stscreds.NewCredentials(s, config.RoleARN)
+		creds := stscreds.NewCredentials(s, config.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+			if config.RoleExternalID != "" {
+				p.ExternalID = aws.String(config.RoleExternalID)
+			}
+			if config.RoleSessionDuration != 0 {
+				p.Duration = config.RoleSessionDuration
+			}
+			if config.RoleSessionName != "" {
+				p.RoleSessionName = config.RoleSessionName
+			}
+			if config.RoleSessionPolicy != "" {
+				p.Policy = aws.String(config.RoleSessionPolicy)
+			}
+		})
 		c = newClients(s, &aws.Config{Credentials: creds})
 	} else {
 		c = newClients(s)
@@ -97,10 +132,12 @@ func newClients(session *session.Session, configs ...*aws.Config) Clients {
 		CloudFormation: cloudformation.New(session, configs...),
 		EC2:            ec2.New(session, configs...),
 		ELB:            elb.New(session, configs...),
+		ELBV2:          elbv2.New(session, configs...),
 		IAM:            iam.New(session, configs...),
 		KMS:            kms.New(session, configs...),
 		Route53:        route53.New(session, configs...),
 		S3:             s3.New(session, configs...),
+		SSM:            ssm.New(session, configs...),
 		STS:            sts.New(session, configs...),
 		Support:        support.New(session, supportConfigs...),
 	}
@@ -2,8 +2,10 @@ package aws
 
 import (
 	"github.com/giantswarm/aws-operator/flag/service/aws/accesskey"
+	"github.com/giantswarm/aws-operator/flag/service/aws/ebssnapshot"
 	"github.com/giantswarm/aws-operator/flag/service/aws/loggingbucket"
 	"github.com/giantswarm/aws-operator/flag/service/aws/route53"
+	"github.com/giantswarm/aws-operator/flag/service/aws/transitgateway"
 	"github.com/giantswarm/aws-operator/flag/service/aws/trustedadvisor"
 )
 
@@ -11,6 +13,8 @@ type AWS struct {
 	AccessKey              accesskey.AccessKey
 	AdvancedMonitoringEC2  string
 	AvailabilityZones      string
+	EBSEncryptionKey       string
+	EBSSnapshot            ebssnapshot.EBSSnapshot
 	Encrypter              string
 	HostAccessKey          accesskey.AccessKey
 	IncludeTags            string
@@ -21,6 +25,8 @@ type AWS struct {
 	Route53                route53.Route53
 	RouteTables            string
 	S3AccessLogsExpiration string
+	TransitGateway         transitgateway.TransitGateway
 	TrustedAdvisor         trustedadvisor.TrustedAdvisor
 	VaultAddress           string
+	VPCEndpoints           string
 }
@@ -0,0 +1,6 @@
+package ebssnapshot
+
+type EBSSnapshot struct {
+	Enabled       string
+	RetentionDays string
+}
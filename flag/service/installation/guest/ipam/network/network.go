@@ -5,6 +5,11 @@ type Network struct {
 	// clusters.
 	CIDR string
 
+	// AdditionalCIDRs are further network segments from which IPAM allocates
+	// subnets for guest clusters once CIDR is exhausted. They are used in the
+	// given order.
+	AdditionalCIDRs string
+
 	// SubnetMaskBits is number of bits in guest cluster subnet mask. This
 	// defines size of the guest cluster subnet that is allocated from CIDR.
 	SubnetMaskBits string
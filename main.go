@@ -112,7 +112,7 @@ func mainError() error {
 	daemonCommand.PersistentFlags().String(f.Service.AWS.AccessKey.Secret, "", "Secret of the AWS access key for the  account to create guest clusters in.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.AccessKey.Session, "", "Session token of the AWS access key for the  account to create guest clusters in. (Can be empty)")
 	daemonCommand.PersistentFlags().StringSlice(f.Service.AWS.AvailabilityZones, []string{}, "Availability zones as a slice.")
-	daemonCommand.PersistentFlags().String(f.Service.AWS.Encrypter, "kms", "Encryption backend to use.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.Encrypter, "kms", "Encryption backend to use, one of kms, ssm or vault.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.HostAccessKey.ID, "", "ID of the AWS access key for the host cluster account. If empty, guest cluster account is used.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.HostAccessKey.Secret, "", "Secret of the AWS access key for the host cluster account. If empty, guest cluster account is used.")
 	daemonCommand.PersistentFlags().String(f.Service.AWS.HostAccessKey.Session, "", "Session token of the AWS access key for the host cluster account. If empty, guest cluster token is used.")
@@ -133,8 +133,17 @@ func mainError() error {
 
 	daemonCommand.PersistentFlags().Bool(f.Service.AWS.LoggingBucket.Delete, false, "Should be logging bucket deleted.")
 
+	daemonCommand.PersistentFlags().String(f.Service.AWS.EBSEncryptionKey, "", "ARN of the customer managed KMS key the EBS volumes of tenant clusters are encrypted with. Tenant clusters can override it via annotation. The default EBS key of the tenant cluster account is used when empty.")
+	daemonCommand.PersistentFlags().Bool(f.Service.AWS.EBSSnapshot.Enabled, false, "Whether etcd and persistent volumes of tenant clusters are snapshotted before they are deleted. Tenant clusters can override it via annotation.")
+	daemonCommand.PersistentFlags().Int(f.Service.AWS.EBSSnapshot.RetentionDays, 30, "Number of days snapshots of deleted tenant cluster volumes are kept. Expired snapshots are deleted while tenant clusters of the same AWS account are reconciled.")
+
 	daemonCommand.PersistentFlags().Bool(f.Service.AWS.Route53.Enabled, true, "Should Route53 be enabled.")
 
+	daemonCommand.PersistentFlags().String(f.Service.AWS.TransitGateway.ID, "", "ID of the transit gateway new tenant clusters are attached to instead of being peered with the control plane VPC. The transit gateway is shared with tenant cluster accounts via RAM, which requires them to be part of the same AWS Organization as the control plane account with resource sharing enabled. The transit gateway must accept shared attachments automatically.")
+	daemonCommand.PersistentFlags().String(f.Service.AWS.TransitGateway.RouteTableID, "", "ID of the transit gateway route table tenant cluster attachments are associated with and propagate their routes to. Requires the default route table association and propagation of the transit gateway to be disabled. The default route table of the transit gateway is used when empty.")
+
+	daemonCommand.PersistentFlags().StringSlice(f.Service.AWS.VPCEndpoints, []string{}, "Names of the AWS services tenant cluster VPCs get interface endpoints with private DNS for, e.g. ec2, elasticloadbalancing, autoscaling, kms, sts, ecr.api and ecr.dkr.")
+
 	daemonCommand.PersistentFlags().String(f.Service.AWS.PodInfraContainerImage, "", "Image to be used for the pause container. If empty, default image from gcr.io/google_containers/pause-amd64 is used.")
 
 	daemonCommand.PersistentFlags().Bool(f.Service.AWS.IncludeTags, true, "Should resource tags be included (especially for restricted regions, like S3 buckets in China regions).")
@@ -145,6 +154,7 @@ func mainError() error {
 
 	daemonCommand.PersistentFlags().String(f.Service.Installation.Name, "", "Installation name for tagging AWS resources.")
 	daemonCommand.PersistentFlags().String(f.Service.Installation.Guest.IPAM.Network.CIDR, "", "Guest cluster network segment from which IPAM allocates subnets.")
+	daemonCommand.PersistentFlags().StringSlice(f.Service.Installation.Guest.IPAM.Network.AdditionalCIDRs, []string{}, "Further guest cluster network segments from which IPAM allocates subnets once the previous ones are exhausted, in order of their priority.")
 	daemonCommand.PersistentFlags().Int(f.Service.Installation.Guest.IPAM.Network.SubnetMaskBits, 24, "Number of bits in guest cluster subnet network mask.")
 	daemonCommand.PersistentFlags().Int(f.Service.Installation.Guest.IPAM.Network.PrivateSubnetMaskBits, 25, "Number of bits in guest cluster private subnet network mask. This must be smaller than SubnetMaskBits.")
 	daemonCommand.PersistentFlags().Int(f.Service.Installation.Guest.IPAM.Network.PublicSubnetMaskBits, 25, "Number of bits in guest cluster public subnet network mask. This must be smaller than SubnetMaskBits.")
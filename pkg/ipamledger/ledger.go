@@ -0,0 +1,225 @@
+// Package ipamledger implements the IPAM ledger of the installation, which
+// records the subnets allocated for tenant clusters, manual reservations and
+// additional network ranges in a config map.
+package ipamledger
+
+import (
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// Name is the name of the config map holding the IPAM ledger of the
+	// installation.
+	Name = "aws-operator-ipam-ledger"
+	// Namespace is the namespace of the config map holding the IPAM ledger of
+	// the installation.
+	Namespace = metav1.NamespaceSystem
+)
+
+const (
+	// EntryKindCluster marks subnets allocated for tenant clusters. These
+	// are released when the tenant cluster is deleted.
+	EntryKindCluster = "cluster"
+	// EntryKindReservation marks subnets reserved manually, e.g. for
+	// networks peered with the installation. These are never allocated nor
+	// released by the operator.
+	EntryKindReservation = "reservation"
+	// EntryKindRange marks network ranges subnets are allocated from in
+	// addition to the configured ones. These are never allocated nor released by
+	// the operator.
+	EntryKindRange = "range"
+)
+
+// Entry is a single allocation of the IPAM ledger. The entries are
+// stored as JSON in the data of the ledger config map, keyed by the holder of
+// the subnet, which is the tenant cluster ID for cluster allocations and an
+// arbitrary name for reservations. Reservations are added by editing the
+// config map, e.g.
+//
+//	peered-network: '{"subnet":"10.1.0.0/16","kind":"reservation","createdBy":"ops"}'
+type Entry struct {
+	Subnet    string    `json:"subnet"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy string    `json:"createdBy"`
+	// Priority is the priority of network ranges. Lower values are used first.
+	Priority int `json:"priority,omitempty"`
+}
+
+type Config struct {
+	K8sClient kubernetes.Interface
+}
+
+// Ledger persists the subnets allocated by the operator in a config map. All
+// modifications are done with optimistic concurrency, so that concurrent
+// allocations based on the same state of the ledger conflict and get retried
+// instead of handing out the same subnet twice.
+type Ledger struct {
+	k8sClient kubernetes.Interface
+
+	name      string
+	namespace string
+}
+
+func New(config Config) (*Ledger, error) {
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
+
+	l := &Ledger{
+		k8sClient: config.K8sClient,
+
+		name:      Name,
+		namespace: Namespace,
+	}
+
+	return l, nil
+}
+
+// Entries returns all entries of the ledger by their holder.
+func (l *Ledger) Entries() (map[string]Entry, error) {
+	cm, err := l.k8sClient.CoreV1().ConfigMaps(l.namespace).Get(l.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return map[string]Entry{}, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	entries, err := decodeEntries(cm.Data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return entries, nil
+}
+
+// Update applies fn to the current entries of the ledger and writes them back
+// in case fn reports a modification. fn is called again with the latest
+// entries in case the ledger was modified concurrently, so it must not have
+// side effects besides modifying the given entries.
+func (l *Ledger) Update(fn func(entries map[string]Entry) (bool, error)) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := l.k8sClient.CoreV1().ConfigMaps(l.namespace).Get(l.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = nil
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		var data map[string]string
+		if cm != nil {
+			data = cm.Data
+		}
+		entries, err := decodeEntries(data)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		modified, err := fn(entries)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if !modified {
+			return nil
+		}
+
+		data, err = encodeEntries(entries)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if cm == nil {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      l.name,
+					Namespace: l.namespace,
+				},
+				Data: data,
+			}
+
+			_, err = l.k8sClient.CoreV1().ConfigMaps(l.namespace).Create(cm)
+			if apierrors.IsAlreadyExists(err) {
+				// The ledger was created concurrently. Its creation is treated like
+				// any other conflicting update and retried.
+				return apierrors.NewConflict(corev1.Resource("configmaps"), l.name, err)
+			} else if err != nil {
+				return err
+			}
+		} else {
+			cm.Data = data
+
+			_, err = l.k8sClient.CoreV1().ConfigMaps(l.namespace).Update(cm)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// Subnets returns the allocated and reserved subnets of all given entries.
+// Network ranges are not part of the returned subnets.
+func Subnets(entries map[string]Entry) ([]net.IPNet, error) {
+	var subnets []net.IPNet
+
+	for holder, e := range entries {
+		if e.Kind == EntryKindRange {
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(e.Subnet)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "ledger entry %#q: %s", holder, err)
+		}
+
+		subnets = append(subnets, *n)
+	}
+
+	return subnets, nil
+}
+
+func decodeEntries(data map[string]string) (map[string]Entry, error) {
+	entries := map[string]Entry{}
+
+	for holder, v := range data {
+		var e Entry
+		err := json.Unmarshal([]byte(v), &e)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "ledger entry %#q: %s", holder, err)
+		}
+
+		entries[holder] = e
+	}
+
+	return entries, nil
+}
+
+func encodeEntries(entries map[string]Entry) (map[string]string, error) {
+	data := map[string]string{}
+
+	for holder, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		data[holder] = string(b)
+	}
+
+	return data, nil
+}
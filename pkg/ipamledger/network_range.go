@@ -0,0 +1,103 @@
+package ipamledger
+
+import (
+	"encoding/binary"
+	"net"
+	"sort"
+
+	"github.com/giantswarm/microerror"
+)
+
+// NetworkRange is a network segment tenant cluster subnets are allocated from.
+// Ranges with lower priority values are exhausted first.
+type NetworkRange struct {
+	CIDR     net.IPNet
+	Priority int
+}
+
+// NetworkRanges returns the network ranges subnets are allocated from, ordered
+// by their priority. The configured ranges get their index as priority. Ranges
+// added at runtime are recorded in the ledger, e.g.
+//
+//	second-range: '{"subnet":"10.2.0.0/16","kind":"range","priority":1}'
+//
+// Configured ranges come first in case ranges have the same priority.
+func NetworkRanges(configured []net.IPNet, entries map[string]Entry) ([]NetworkRange, error) {
+	var ranges []NetworkRange
+
+	for i, n := range configured {
+		r := NetworkRange{
+			CIDR:     n,
+			Priority: i,
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	var holders []string
+	for holder, e := range entries {
+		if e.Kind == EntryKindRange {
+			holders = append(holders, holder)
+		}
+	}
+	sort.Strings(holders)
+
+	for _, holder := range holders {
+		_, n, err := net.ParseCIDR(entries[holder].Subnet)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "ledger entry %#q: %s", holder, err)
+		}
+
+		r := NetworkRange{
+			CIDR:     *n,
+			Priority: entries[holder].Priority,
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Priority < ranges[j].Priority
+	})
+
+	return ranges, nil
+}
+
+// CountSubnets returns the number of allocated and free subnets of the given
+// mask within the given network range. A subnet counts as allocated as soon as
+// any of the given allocated subnets overlaps with it.
+func CountSubnets(networkRange net.IPNet, mask net.IPMask, allocatedSubnets []net.IPNet) (int, int) {
+	rangeOnes, _ := networkRange.Mask.Size()
+	maskOnes, _ := mask.Size()
+	if networkRange.IP.To4() == nil || maskOnes < rangeOnes || maskOnes > 32 {
+		return 0, 0
+	}
+
+	total := 1 << uint(maskOnes-rangeOnes)
+	start := binary.BigEndian.Uint32(networkRange.IP.To4())
+	shift := uint(32 - maskOnes)
+
+	allocated := map[uint32]struct{}{}
+	for _, s := range allocatedSubnets {
+		if s.IP.To4() == nil || !(networkRange.Contains(s.IP) || s.Contains(networkRange.IP)) {
+			continue
+		}
+
+		ones, _ := s.Mask.Size()
+		if ones <= rangeOnes {
+			return total, 0
+		}
+
+		first := (binary.BigEndian.Uint32(s.IP.To4()) - start) >> shift
+		n := uint32(1)
+		if ones < maskOnes {
+			n = 1 << uint(maskOnes-ones)
+		}
+
+		for i := first; i < first+n; i++ {
+			allocated[i] = struct{}{}
+		}
+	}
+
+	return len(allocated), total - len(allocated)
+}
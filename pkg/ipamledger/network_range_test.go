@@ -0,0 +1,146 @@
+package ipamledger
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func Test_NetworkRanges(t *testing.T) {
+	testCases := []struct {
+		name           string
+		configured     []net.IPNet
+		entries        map[string]Entry
+		expectedRanges []NetworkRange
+		errorMatcher   func(error) bool
+	}{
+		{
+			name: "case 0: configured ranges are used in the given order",
+			configured: []net.IPNet{
+				mustParseCIDR("10.1.0.0/16"),
+				mustParseCIDR("10.2.0.0/16"),
+			},
+			expectedRanges: []NetworkRange{
+				{CIDR: mustParseCIDR("10.1.0.0/16"), Priority: 0},
+				{CIDR: mustParseCIDR("10.2.0.0/16"), Priority: 1},
+			},
+		},
+		{
+			name: "case 1: ranges of the ledger are sorted by their priority",
+			configured: []net.IPNet{
+				mustParseCIDR("10.1.0.0/16"),
+				mustParseCIDR("10.2.0.0/16"),
+			},
+			entries: map[string]Entry{
+				"al9qy":  {Subnet: "10.1.0.0/24", Kind: EntryKindCluster},
+				"range1": {Subnet: "10.3.0.0/16", Kind: EntryKindRange, Priority: 5},
+				"range2": {Subnet: "10.4.0.0/16", Kind: EntryKindRange, Priority: 1},
+			},
+			expectedRanges: []NetworkRange{
+				{CIDR: mustParseCIDR("10.1.0.0/16"), Priority: 0},
+				{CIDR: mustParseCIDR("10.2.0.0/16"), Priority: 1},
+				{CIDR: mustParseCIDR("10.4.0.0/16"), Priority: 1},
+				{CIDR: mustParseCIDR("10.3.0.0/16"), Priority: 5},
+			},
+		},
+		{
+			name: "case 2: error on malformed ranges",
+			entries: map[string]Entry{
+				"range1": {Subnet: "10.3.0.0", Kind: EntryKindRange},
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ranges, err := NetworkRanges(tc.configured, tc.entries)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !reflect.DeepEqual(ranges, tc.expectedRanges) {
+				t.Fatalf("expected %#v, got %#v", tc.expectedRanges, ranges)
+			}
+		})
+	}
+}
+
+func Test_CountSubnets(t *testing.T) {
+	testCases := []struct {
+		name              string
+		networkRange      net.IPNet
+		allocated         []net.IPNet
+		expectedAllocated int
+		expectedFree      int
+	}{
+		{
+			name:              "case 0: empty range",
+			networkRange:      mustParseCIDR("10.1.0.0/16"),
+			expectedAllocated: 0,
+			expectedFree:      256,
+		},
+		{
+			name:         "case 1: subnets of other ranges are ignored",
+			networkRange: mustParseCIDR("10.1.0.0/16"),
+			allocated: []net.IPNet{
+				mustParseCIDR("10.1.0.0/24"),
+				mustParseCIDR("10.1.1.0/24"),
+				mustParseCIDR("10.2.0.0/24"),
+			},
+			expectedAllocated: 2,
+			expectedFree:      254,
+		},
+		{
+			name:         "case 2: bigger and smaller subnets cover whole blocks",
+			networkRange: mustParseCIDR("10.1.0.0/16"),
+			allocated: []net.IPNet{
+				mustParseCIDR("10.1.0.0/22"),
+				mustParseCIDR("10.1.1.0/24"),
+				mustParseCIDR("10.1.8.0/28"),
+				mustParseCIDR("10.1.8.128/28"),
+			},
+			expectedAllocated: 5,
+			expectedFree:      251,
+		},
+		{
+			name:         "case 3: subnets containing the range allocate it completely",
+			networkRange: mustParseCIDR("10.1.0.0/16"),
+			allocated: []net.IPNet{
+				mustParseCIDR("10.0.0.0/8"),
+			},
+			expectedAllocated: 256,
+			expectedFree:      0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			allocated, free := CountSubnets(tc.networkRange, net.CIDRMask(24, 32), tc.allocated)
+
+			if allocated != tc.expectedAllocated {
+				t.Fatalf("expected %d allocated subnets, got %d", tc.expectedAllocated, allocated)
+			}
+			if free != tc.expectedFree {
+				t.Fatalf("expected %d free subnets, got %d", tc.expectedFree, free)
+			}
+		})
+	}
+}
+
+func mustParseCIDR(val string) net.IPNet {
+	_, n, err := net.ParseCIDR(val)
+	if err != nil {
+		panic(err)
+	}
+
+	return *n
+}
@@ -0,0 +1,361 @@
+package collector
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/errgroup"
+
+	clientaws "github.com/giantswarm/aws-operator/client/aws"
+)
+
+const (
+	labelLogicalID    = "logical_id"
+	labelReason       = "reason"
+	labelResourceType = "resource_type"
+	labelStackType    = "stack_type"
+	labelStatus       = "status"
+)
+
+const (
+	subsystemCloudFormation = "cloudformation"
+)
+
+const (
+	// maxReasonLength limits the length of the failure reasons exposed as
+	// label values.
+	maxReasonLength = 256
+)
+
+var (
+	cloudFormationStackStatusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystemCloudFormation, "stack_status"),
+		"Gauge about the current status of the cloud formation stacks of a tenant cluster.",
+		[]string{
+			labelAccount,
+			labelCluster,
+			labelInstallation,
+			labelOrganization,
+			labelStack,
+			labelStackType,
+			labelStatus,
+		},
+		nil,
+	)
+
+	cloudFormationStackStatusSecondsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystemCloudFormation, "stack_status_seconds"),
+		"Gauge about the number of seconds the cloud formation stacks of a tenant cluster are in their current status.",
+		[]string{
+			labelAccount,
+			labelCluster,
+			labelInstallation,
+			labelOrganization,
+			labelStack,
+			labelStackType,
+			labelStatus,
+		},
+		nil,
+	)
+
+	cloudFormationStackFailedResourceDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystemCloudFormation, "stack_failed_resource"),
+		"Gauge about the latest resource which failed in the cloud formation stacks of a tenant cluster.",
+		[]string{
+			labelAccount,
+			labelCluster,
+			labelInstallation,
+			labelLogicalID,
+			labelOrganization,
+			labelReason,
+			labelResourceType,
+			labelStack,
+			labelStackType,
+		},
+		nil,
+	)
+)
+
+// cloudFormationStackNameRegexp matches the names of the TCCP, CPI and CPF
+// cloud formation stacks of tenant clusters. The second submatch is the stack
+// name suffix identifying the stack type.
+var cloudFormationStackNameRegexp = regexp.MustCompile("^cluster-([a-z0-9]+)-(guest-main|host-setup|host-main)$")
+
+// cloudFormationStackTypes maps stack name suffixes to stack types.
+var cloudFormationStackTypes = map[string]string{
+	"guest-main": "tccp",
+	"host-main":  "cpf",
+	"host-setup": "cpi",
+}
+
+// stackEventsClient is the part of the cloud formation client used to find
+// the failed resources of stacks.
+type stackEventsClient interface {
+	DescribeStackEvents(*cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error)
+}
+
+type CloudFormationConfig struct {
+	Helper *helper
+	Logger micrologger.Logger
+
+	InstallationName string
+}
+
+// CloudFormation is the collector for the cloud formation stacks of tenant
+// clusters.
+type CloudFormation struct {
+	helper *helper
+	logger micrologger.Logger
+
+	installationName string
+}
+
+func NewCloudFormation(config CloudFormationConfig) (*CloudFormation, error) {
+	if config.Helper == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Helper must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	if config.InstallationName == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.InstallationName must not be empty", config)
+	}
+
+	c := &CloudFormation{
+		helper: config.Helper,
+		logger: config.Logger,
+
+		installationName: config.InstallationName,
+	}
+
+	return c, nil
+}
+
+func (c *CloudFormation) Collect(ch chan<- prometheus.Metric) error {
+	awsClientsList, err := c.helper.GetAWSClients()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var g errgroup.Group
+
+	for _, item := range awsClientsList {
+		awsClients := item
+
+		g.Go(func() error {
+			err := c.collectForAccount(ch, awsClients)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			return nil
+		})
+	}
+
+	err = g.Wait()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (c *CloudFormation) Describe(ch chan<- *prometheus.Desc) error {
+	ch <- cloudFormationStackStatusDesc
+	ch <- cloudFormationStackStatusSecondsDesc
+	ch <- cloudFormationStackFailedResourceDesc
+	return nil
+}
+
+func (c *CloudFormation) collectForAccount(ch chan<- prometheus.Metric, awsClients clientaws.Clients) error {
+	account, err := c.helper.AWSAccountID(awsClients)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var nextToken *string
+	for {
+		var stacks []*cloudformation.Stack
+		{
+			i := &cloudformation.DescribeStacksInput{
+				NextToken: nextToken,
+			}
+			o, err := awsClients.CloudFormation.DescribeStacks(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			stacks = o.Stacks
+			nextToken = o.NextToken
+		}
+
+		for _, stack := range stacks {
+			stackType, ok := cloudFormationStackType(*stack.StackName)
+			if !ok {
+				continue
+			}
+
+			var cluster, installation, organization string
+
+			for _, tag := range stack.Tags {
+				switch *tag.Key {
+				case tagCluster:
+					cluster = *tag.Value
+				case tagInstallation:
+					installation = *tag.Value
+				case tagOrganization:
+					organization = *tag.Value
+				}
+			}
+
+			if installation != c.installationName {
+				continue
+			}
+
+			ch <- prometheus.MustNewConstMetric(
+				cloudFormationStackStatusDesc,
+				prometheus.GaugeValue,
+				GaugeValue,
+				account,
+				cluster,
+				installation,
+				organization,
+				*stack.StackName,
+				stackType,
+				*stack.StackStatus,
+			)
+
+			since := stackStatusSince(stack)
+			if since != nil {
+				ch <- prometheus.MustNewConstMetric(
+					cloudFormationStackStatusSecondsDesc,
+					prometheus.GaugeValue,
+					time.Since(*since).Seconds(),
+					account,
+					cluster,
+					installation,
+					organization,
+					*stack.StackName,
+					stackType,
+					*stack.StackStatus,
+				)
+			}
+
+			// Only stacks in a failure status are checked for failed resources,
+			// since fetching the stack events for every stack is expensive.
+			if !isFailureStackStatus(*stack.StackStatus) {
+				continue
+			}
+
+			event, err := latestFailedStackEvent(awsClients.CloudFormation, *stack.StackName)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			if event == nil {
+				continue
+			}
+
+			ch <- prometheus.MustNewConstMetric(
+				cloudFormationStackFailedResourceDesc,
+				prometheus.GaugeValue,
+				GaugeValue,
+				account,
+				cluster,
+				installation,
+				aws.StringValue(event.LogicalResourceId),
+				organization,
+				truncateReason(aws.StringValue(event.ResourceStatusReason)),
+				aws.StringValue(event.ResourceType),
+				*stack.StackName,
+				stackType,
+			)
+		}
+
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// cloudFormationStackType returns the type of the tenant cluster stack with
+// the given name. False is returned in case the stack does not belong to a
+// tenant cluster.
+func cloudFormationStackType(stackName string) (string, bool) {
+	matches := cloudFormationStackNameRegexp.FindStringSubmatch(stackName)
+	if matches == nil {
+		return "", false
+	}
+
+	return cloudFormationStackTypes[matches[2]], true
+}
+
+func isFailureStackStatus(status string) bool {
+	return strings.HasSuffix(status, "_FAILED") || strings.Contains(status, "ROLLBACK")
+}
+
+// latestFailedStackEvent returns the most recent stack event of a resource
+// which failed. The first page of stack events is sufficient, since events are
+// returned in reverse chronological order. Nil is returned in case no failed
+// resource was found.
+func latestFailedStackEvent(client stackEventsClient, stackName string) (*cloudformation.StackEvent, error) {
+	i := &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(stackName),
+	}
+
+	o, err := client.DescribeStackEvents(i)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	for _, e := range o.StackEvents {
+		// Events of the stack itself only summarize the failed resources.
+		if aws.StringValue(e.LogicalResourceId) == stackName {
+			continue
+		}
+		if strings.HasSuffix(aws.StringValue(e.ResourceStatus), "_FAILED") {
+			return e, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// stackStatusSince returns the time the given stack is in its current status
+// since. Deleted stacks and stacks being deleted are in their status since
+// their deletion. The last updated time is not set for stacks which were never
+// updated. Then the stack is in its current status since its creation.
+func stackStatusSince(stack *cloudformation.Stack) *time.Time {
+	if strings.HasPrefix(aws.StringValue(stack.StackStatus), "DELETE_") && stack.DeletionTime != nil {
+		return stack.DeletionTime
+	}
+	if stack.LastUpdatedTime != nil {
+		return stack.LastUpdatedTime
+	}
+
+	return stack.CreationTime
+}
+
+// truncateReason truncates the given reason to at most maxReasonLength bytes
+// without splitting multi-byte characters, so that it stays a valid label
+// value.
+func truncateReason(reason string) string {
+	if len(reason) <= maxReasonLength {
+		return reason
+	}
+
+	n := maxReasonLength
+	for n > 0 && !utf8.RuneStart(reason[n]) {
+		n--
+	}
+
+	return reason[:n]
+}
@@ -0,0 +1,299 @@
+package collector
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+type stackEventsClientMock struct {
+	events []*cloudformation.StackEvent
+}
+
+func (s *stackEventsClientMock) DescribeStackEvents(*cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error) {
+	o := &cloudformation.DescribeStackEventsOutput{
+		StackEvents: s.events,
+	}
+
+	return o, nil
+}
+
+func Test_cloudFormationStackType(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name              string
+		stackName         string
+		expectedStackType string
+		expectedOK        bool
+	}{
+		{
+			name:              "case 0: tenant cluster control plane stack",
+			stackName:         "cluster-al9qy-guest-main",
+			expectedStackType: "tccp",
+			expectedOK:        true,
+		},
+		{
+			name:              "case 1: control plane initializer stack",
+			stackName:         "cluster-al9qy-host-setup",
+			expectedStackType: "cpi",
+			expectedOK:        true,
+		},
+		{
+			name:              "case 2: control plane finalizer stack",
+			stackName:         "cluster-al9qy-host-main",
+			expectedStackType: "cpf",
+			expectedOK:        true,
+		},
+		{
+			name:       "case 3: unknown stack suffix",
+			stackName:  "cluster-al9qy-guest-other",
+			expectedOK: false,
+		},
+		{
+			name:       "case 4: stack not belonging to a tenant cluster",
+			stackName:  "vault-main",
+			expectedOK: false,
+		},
+		{
+			name:       "case 5: cluster ID with upper case letters",
+			stackName:  "cluster-AL9QY-guest-main",
+			expectedOK: false,
+		},
+		{
+			name:       "case 6: suffix after the stack type",
+			stackName:  "cluster-al9qy-guest-main-copy",
+			expectedOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stackType, ok := cloudFormationStackType(tc.stackName)
+			if ok != tc.expectedOK {
+				t.Fatalf("expected %t, got %t", tc.expectedOK, ok)
+			}
+			if stackType != tc.expectedStackType {
+				t.Fatalf("expected stack type %#q, got %#q", tc.expectedStackType, stackType)
+			}
+		})
+	}
+}
+
+func Test_isFailureStackStatus(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		status   string
+		expected bool
+	}{
+		{status: cloudformation.StackStatusCreateComplete, expected: false},
+		{status: cloudformation.StackStatusCreateInProgress, expected: false},
+		{status: cloudformation.StackStatusCreateFailed, expected: true},
+		{status: cloudformation.StackStatusDeleteFailed, expected: true},
+		{status: cloudformation.StackStatusRollbackComplete, expected: true},
+		{status: cloudformation.StackStatusRollbackInProgress, expected: true},
+		{status: cloudformation.StackStatusUpdateComplete, expected: false},
+		{status: cloudformation.StackStatusUpdateRollbackComplete, expected: true},
+		{status: cloudformation.StackStatusUpdateRollbackFailed, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.status, func(t *testing.T) {
+			result := isFailureStackStatus(tc.status)
+			if result != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test_latestFailedStackEvent(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name                   string
+		events                 []*cloudformation.StackEvent
+		expectedLogicalID      string
+		expectedNoFailedEvents bool
+	}{
+		{
+			name:                   "case 0: no events",
+			events:                 nil,
+			expectedNoFailedEvents: true,
+		},
+		{
+			name: "case 1: no failed resources",
+			events: []*cloudformation.StackEvent{
+				newTestStackEvent("cluster-al9qy-guest-main", cloudformation.ResourceStatusUpdateComplete),
+				newTestStackEvent("MasterInstance", cloudformation.ResourceStatusUpdateComplete),
+			},
+			expectedNoFailedEvents: true,
+		},
+		{
+			name: "case 2: failed events of the stack itself are ignored",
+			events: []*cloudformation.StackEvent{
+				newTestStackEvent("cluster-al9qy-guest-main", cloudformation.ResourceStatusUpdateFailed),
+				newTestStackEvent("MasterInstance", cloudformation.ResourceStatusUpdateInProgress),
+			},
+			expectedNoFailedEvents: true,
+		},
+		{
+			name: "case 3: most recent failed resource is returned",
+			events: []*cloudformation.StackEvent{
+				newTestStackEvent("cluster-al9qy-guest-main", cloudformation.ResourceStatusUpdateFailed),
+				newTestStackEvent("WorkerAutoScalingGroup", cloudformation.ResourceStatusUpdateFailed),
+				newTestStackEvent("MasterInstance", cloudformation.ResourceStatusCreateFailed),
+			},
+			expectedLogicalID: "WorkerAutoScalingGroup",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := latestFailedStackEvent(&stackEventsClientMock{events: tc.events}, "cluster-al9qy-guest-main")
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if tc.expectedNoFailedEvents {
+				if event != nil {
+					t.Fatalf("expected no failed event, got %#v", event)
+				}
+				return
+			}
+
+			if event == nil {
+				t.Fatalf("expected failed event of %#q, got nil", tc.expectedLogicalID)
+			}
+			if aws.StringValue(event.LogicalResourceId) != tc.expectedLogicalID {
+				t.Fatalf("expected failed event of %#q, got %#q", tc.expectedLogicalID, aws.StringValue(event.LogicalResourceId))
+			}
+		})
+	}
+}
+
+func Test_stackStatusSince(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)
+	deleted := time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		stack    *cloudformation.Stack
+		expected *time.Time
+	}{
+		{
+			name: "case 0: never updated stack",
+			stack: &cloudformation.Stack{
+				CreationTime: &created,
+				StackStatus:  aws.String(cloudformation.StackStatusCreateComplete),
+			},
+			expected: &created,
+		},
+		{
+			name: "case 1: updated stack",
+			stack: &cloudformation.Stack{
+				CreationTime:    &created,
+				LastUpdatedTime: &updated,
+				StackStatus:     aws.String(cloudformation.StackStatusUpdateComplete),
+			},
+			expected: &updated,
+		},
+		{
+			name: "case 2: stack being deleted",
+			stack: &cloudformation.Stack{
+				CreationTime:    &created,
+				DeletionTime:    &deleted,
+				LastUpdatedTime: &updated,
+				StackStatus:     aws.String(cloudformation.StackStatusDeleteInProgress),
+			},
+			expected: &deleted,
+		},
+		{
+			name: "case 3: stack failed to be deleted",
+			stack: &cloudformation.Stack{
+				CreationTime: &created,
+				DeletionTime: &deleted,
+				StackStatus:  aws.String(cloudformation.StackStatusDeleteFailed),
+			},
+			expected: &deleted,
+		},
+		{
+			name: "case 4: stack being deleted without deletion time",
+			stack: &cloudformation.Stack{
+				CreationTime:    &created,
+				LastUpdatedTime: &updated,
+				StackStatus:     aws.String(cloudformation.StackStatusDeleteInProgress),
+			},
+			expected: &updated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := stackStatusSince(tc.stack)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test_truncateReason(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name           string
+		reason         string
+		expectedLength int
+	}{
+		{
+			name:           "case 0: short reason is kept",
+			reason:         "Resource creation cancelled",
+			expectedLength: 27,
+		},
+		{
+			name:           "case 1: long ASCII reason is truncated",
+			reason:         strings.Repeat("a", 300),
+			expectedLength: maxReasonLength,
+		},
+		{
+			name:           "case 2: multi-byte character crossing the limit is dropped",
+			reason:         strings.Repeat("a", maxReasonLength-1) + "ü" + "b",
+			expectedLength: maxReasonLength - 1,
+		},
+		{
+			name:           "case 3: long reason of multi-byte characters is truncated on rune boundaries",
+			reason:         strings.Repeat("€", 100),
+			expectedLength: 255,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := truncateReason(tc.reason)
+
+			if len(result) != tc.expectedLength {
+				t.Fatalf("expected length %d, got %d", tc.expectedLength, len(result))
+			}
+			if !utf8.ValidString(result) {
+				t.Fatalf("expected valid UTF-8, got %q", result)
+			}
+			if !strings.HasPrefix(tc.reason, result) {
+				t.Fatalf("expected prefix of %q, got %q", tc.reason, result)
+			}
+		})
+	}
+}
+
+func newTestStackEvent(logicalID, status string) *cloudformation.StackEvent {
+	e := &cloudformation.StackEvent{
+		LogicalResourceId: aws.String(logicalID),
+		ResourceStatus:    aws.String(status),
+	}
+
+	return e
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"sync"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sync/errgroup"
 
@@ -31,7 +33,7 @@ const (
 var (
 	elbsDesc *prometheus.Desc = prometheus.NewDesc(
 		prometheus.BuildFQName(namespace, subsystemELB, "instance_out_of_service_count"),
-		"Gauge about ELB instances being out of service.",
+		"Gauge about ELB instances being out of service. For NLBs and ALBs, unhealthy targets are counted.",
 		[]string{
 			labelELB,
 			labelAccount,
@@ -91,13 +93,18 @@ func (e *ELB) Collect(ch chan<- prometheus.Metric) error {
 		return microerror.Mask(err)
 	}
 
+	clusterIDs, err := e.existingClusterIDs()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
 	var g errgroup.Group
 
 	for _, item := range awsClientsList {
 		awsClients := item
 
 		g.Go(func() error {
-			err := e.collectForAccount(ch, awsClients)
+			err := e.collectForAccount(ch, awsClients, clusterIDs)
 			if err != nil {
 				return microerror.Mask(err)
 			}
@@ -116,15 +123,36 @@ func (e *ELB) Collect(ch chan<- prometheus.Metric) error {
 
 func (e *ELB) Describe(ch chan<- *prometheus.Desc) error {
 	ch <- elbsDesc
+	ch <- elbsOrphanedDesc
 	return nil
 }
 
-func (e *ELB) collectForAccount(ch chan<- prometheus.Metric, awsClients clientaws.Clients) error {
+func (e *ELB) collectForAccount(ch chan<- prometheus.Metric, awsClients clientaws.Clients, clusterIDs map[string]bool) error {
 	account, err := e.helper.AWSAccountID(awsClients)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
+	err = e.collectClassicForAccount(ch, awsClients, account)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = e.collectELBV2ForAccount(ch, awsClients, account)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = e.collectOrphansForAccount(ch, awsClients, account, clusterIDs)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (e *ELB) collectClassicForAccount(ch chan<- prometheus.Metric, awsClients clientaws.Clients, account string) error {
+
 	var loadBalancerNames []*string
 	{
 		i := &elb.DescribeLoadBalancersInput{}
@@ -247,3 +275,120 @@ func (e *ELB) collectForAccount(ch chan<- prometheus.Metric, awsClients clientaw
 
 	return nil
 }
+
+// collectELBV2ForAccount emits the number of unhealthy targets of the network
+// and application load balancers of the installation, e.g. the NLBs of tenant
+// clusters using network load balancers.
+func (e *ELB) collectELBV2ForAccount(ch chan<- prometheus.Metric, awsClients clientaws.Clients, account string) error {
+	arns := map[string]string{}
+	var loadBalancerARNs []*string
+	{
+		fn := func(o *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, lb := range o.LoadBalancers {
+				arns[*lb.LoadBalancerArn] = *lb.LoadBalancerName
+				loadBalancerARNs = append(loadBalancerARNs, lb.LoadBalancerArn)
+			}
+			return true
+		}
+
+		err := awsClients.ELBV2.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{}, fn)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	var lbs []loadBalancer
+	var lbARNs []string
+	{
+		for len(loadBalancerARNs) > 0 {
+			batchSize := maxELBsInOneDescribeTagsBatch
+			if len(loadBalancerARNs) < batchSize {
+				batchSize = len(loadBalancerARNs)
+			}
+
+			i := &elbv2.DescribeTagsInput{
+				ResourceArns: loadBalancerARNs[0:batchSize],
+			}
+			loadBalancerARNs = loadBalancerARNs[batchSize:]
+
+			o, err := awsClients.ELBV2.DescribeTags(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			for _, d := range o.TagDescriptions {
+				lb := loadBalancer{
+					Name: arns[*d.ResourceArn],
+					Tags: make(map[string]string),
+				}
+
+				for _, t := range d.Tags {
+					lb.Tags[*t.Key] = *t.Value
+				}
+
+				if lb.Tags[tagInstallation] != e.installationName {
+					continue
+				}
+
+				lbs = append(lbs, lb)
+				lbARNs = append(lbARNs, *d.ResourceArn)
+			}
+		}
+	}
+
+	{
+		// The target health can only be described per target group, so it
+		// must be done with N API calls per load balancer.
+		for n, arn := range lbARNs {
+			var targetGroupARNs []*string
+			{
+				i := &elbv2.DescribeTargetGroupsInput{
+					LoadBalancerArn: aws.String(arn),
+				}
+				fn := func(o *elbv2.DescribeTargetGroupsOutput, lastPage bool) bool {
+					for _, tg := range o.TargetGroups {
+						targetGroupARNs = append(targetGroupARNs, tg.TargetGroupArn)
+					}
+					return true
+				}
+
+				err := awsClients.ELBV2.DescribeTargetGroupsPages(i, fn)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+			}
+
+			for _, tgARN := range targetGroupARNs {
+				i := &elbv2.DescribeTargetHealthInput{
+					TargetGroupArn: tgARN,
+				}
+
+				o, err := awsClients.ELBV2.DescribeTargetHealth(i)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+
+				for _, d := range o.TargetHealthDescriptions {
+					if d.TargetHealth != nil && *d.TargetHealth.State == elbv2.TargetHealthStateEnumUnhealthy {
+						lbs[n].InstancesOutOfService++
+					}
+				}
+			}
+		}
+	}
+
+	for _, lb := range lbs {
+		ch <- prometheus.MustNewConstMetric(
+			elbsDesc,
+			prometheus.GaugeValue,
+			lb.InstancesOutOfService,
+			lb.Name,
+			account,
+			lb.Tags[tagCluster],
+			lb.Tags[tagInstallation],
+			lb.Tags[tagOrganization],
+		)
+	}
+
+	return nil
+}
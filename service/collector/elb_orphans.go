@@ -0,0 +1,249 @@
+package collector
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/giantswarm/microerror"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clientaws "github.com/giantswarm/aws-operator/client/aws"
+)
+
+const (
+	cloudProviderClusterTagPrefix = "kubernetes.io/cluster/"
+	cloudProviderClusterTagValue  = "owned"
+	cloudProviderIngressTagKey    = "kubernetes.io/ingress-name"
+	cloudProviderServiceTagKey    = "kubernetes.io/service-name"
+)
+
+var (
+	elbsOrphanedDesc *prometheus.Desc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystemELB, "orphaned"),
+		"Gauge about load balancers of Kubernetes Services and Ingresses of tenant clusters which no longer exist.",
+		[]string{
+			labelELB,
+			labelAccount,
+			labelCluster,
+			labelInstallation,
+		},
+		nil,
+	)
+)
+
+type orphanedLoadBalancer struct {
+	ClusterID string
+	Name      string
+}
+
+// existingClusterIDs returns the IDs of all tenant clusters of the
+// installation. It is looked up once per collection and shared by all
+// accounts.
+func (e *ELB) existingClusterIDs() (map[string]bool, error) {
+	list, err := e.helper.g8sClient.ProviderV1alpha1().AWSConfigs(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterIDs := map[string]bool{}
+	for _, cr := range list.Items {
+		clusterIDs[cr.Spec.Cluster.ID] = true
+	}
+
+	return clusterIDs, nil
+}
+
+// collectOrphansForAccount emits the load balancers of Kubernetes Services and
+// Ingresses in the VPCs of the installation which belong to tenant clusters
+// which no longer exist. Such load balancers are left over when the deletion
+// of a tenant cluster did not clean them up. They are only reported and not
+// deleted, because the tenant cluster they are tagged with cannot be verified
+// to be gone for good.
+func (e *ELB) collectOrphansForAccount(ch chan<- prometheus.Metric, awsClients clientaws.Clients, account string, clusterIDs map[string]bool) error {
+	vpcIDs, err := e.installationVPCIDs(awsClients)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var orphans []orphanedLoadBalancer
+	{
+		var names []*string
+		{
+			fn := func(o *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+				for _, lb := range o.LoadBalancerDescriptions {
+					if vpcIDs[aws.StringValue(lb.VPCId)] {
+						names = append(names, lb.LoadBalancerName)
+					}
+				}
+				return true
+			}
+
+			err := awsClients.ELB.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{}, fn)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		tags := map[string]map[string]string{}
+		for len(names) > 0 {
+			batchSize := maxELBsInOneDescribeTagsBatch
+			if len(names) < batchSize {
+				batchSize = len(names)
+			}
+
+			i := &elb.DescribeTagsInput{
+				LoadBalancerNames: names[0:batchSize],
+			}
+			names = names[batchSize:]
+
+			o, err := awsClients.ELB.DescribeTags(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			for _, d := range o.TagDescriptions {
+				t := map[string]string{}
+				for _, tag := range d.Tags {
+					t[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+				}
+				tags[aws.StringValue(d.LoadBalancerName)] = t
+			}
+		}
+
+		orphans = append(orphans, findOrphanedLoadBalancers(tags, clusterIDs)...)
+	}
+
+	{
+		var arns []*string
+		{
+			fn := func(o *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+				for _, lb := range o.LoadBalancers {
+					if vpcIDs[aws.StringValue(lb.VpcId)] {
+						arns = append(arns, lb.LoadBalancerArn)
+					}
+				}
+				return true
+			}
+
+			err := awsClients.ELBV2.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{}, fn)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		tags := map[string]map[string]string{}
+		for len(arns) > 0 {
+			batchSize := maxELBsInOneDescribeTagsBatch
+			if len(arns) < batchSize {
+				batchSize = len(arns)
+			}
+
+			i := &elbv2.DescribeTagsInput{
+				ResourceArns: arns[0:batchSize],
+			}
+			arns = arns[batchSize:]
+
+			o, err := awsClients.ELBV2.DescribeTags(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			for _, d := range o.TagDescriptions {
+				t := map[string]string{}
+				for _, tag := range d.Tags {
+					t[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+				}
+				tags[aws.StringValue(d.ResourceArn)] = t
+			}
+		}
+
+		orphans = append(orphans, findOrphanedLoadBalancers(tags, clusterIDs)...)
+	}
+
+	for _, o := range orphans {
+		ch <- prometheus.MustNewConstMetric(
+			elbsOrphanedDesc,
+			prometheus.GaugeValue,
+			GaugeValue,
+			o.Name,
+			account,
+			o.ClusterID,
+			e.installationName,
+		)
+	}
+
+	return nil
+}
+
+// installationVPCIDs returns the IDs of the VPCs of all tenant clusters of the
+// installation in the given account. Load balancers outside of these VPCs are
+// not considered, because they may belong to tenant clusters of other
+// installations sharing the account.
+func (e *ELB) installationVPCIDs(awsClients clientaws.Clients) (map[string]bool, error) {
+	i := &ec2.DescribeVpcsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:" + tagInstallation),
+				Values: []*string{aws.String(e.installationName)},
+			},
+		},
+	}
+
+	o, err := awsClients.EC2.DescribeVpcs(i)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	vpcIDs := map[string]bool{}
+	for _, v := range o.Vpcs {
+		vpcIDs[aws.StringValue(v.VpcId)] = true
+	}
+
+	return vpcIDs, nil
+}
+
+// cloudProviderClusterID returns the ID of the tenant cluster owning a load
+// balancer of a Kubernetes Service or Ingress according to its tags. False is
+// returned in case the load balancer does not belong to a Service or Ingress.
+func cloudProviderClusterID(tags map[string]string) (string, bool) {
+	_, service := tags[cloudProviderServiceTagKey]
+	_, ingress := tags[cloudProviderIngressTagKey]
+	if !service && !ingress {
+		return "", false
+	}
+
+	for k, v := range tags {
+		if strings.HasPrefix(k, cloudProviderClusterTagPrefix) && v == cloudProviderClusterTagValue {
+			return strings.TrimPrefix(k, cloudProviderClusterTagPrefix), true
+		}
+	}
+
+	return "", false
+}
+
+// findOrphanedLoadBalancers returns the load balancers of Kubernetes Services
+// and Ingresses, given by their names or ARNs and tags, whose tenant cluster
+// is not one of the given existing clusters. The result is sorted by name.
+func findOrphanedLoadBalancers(tags map[string]map[string]string, clusterIDs map[string]bool) []orphanedLoadBalancer {
+	var orphans []orphanedLoadBalancer
+
+	for name, t := range tags {
+		id, ok := cloudProviderClusterID(t)
+		if !ok || clusterIDs[id] {
+			continue
+		}
+
+		orphans = append(orphans, orphanedLoadBalancer{ClusterID: id, Name: name})
+	}
+
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].Name < orphans[j].Name
+	})
+
+	return orphans
+}
@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_findOrphanedLoadBalancers(t *testing.T) {
+	t.Parallel()
+	clusterIDs := map[string]bool{
+		"existing": true,
+	}
+
+	testCases := []struct {
+		name            string
+		tags            map[string]map[string]string
+		expectedOrphans []orphanedLoadBalancer
+	}{
+		{
+			name:            "case 0: no load balancers",
+			tags:            map[string]map[string]string{},
+			expectedOrphans: nil,
+		},
+		{
+			name: "case 1: service load balancer of existing cluster",
+			tags: map[string]map[string]string{
+				"lb-1": {
+					"kubernetes.io/cluster/existing": "owned",
+					"kubernetes.io/service-name":     "default/hello-world",
+				},
+			},
+			expectedOrphans: nil,
+		},
+		{
+			name: "case 2: service and ingress load balancers of deleted cluster",
+			tags: map[string]map[string]string{
+				"lb-2": {
+					"kubernetes.io/cluster/deleted": "owned",
+					"kubernetes.io/ingress-name":    "hello-world",
+				},
+				"lb-1": {
+					"kubernetes.io/cluster/deleted": "owned",
+					"kubernetes.io/service-name":    "default/hello-world",
+				},
+				"lb-3": {
+					"kubernetes.io/cluster/existing": "owned",
+					"kubernetes.io/service-name":     "default/hello-world",
+				},
+			},
+			expectedOrphans: []orphanedLoadBalancer{
+				{ClusterID: "deleted", Name: "lb-1"},
+				{ClusterID: "deleted", Name: "lb-2"},
+			},
+		},
+		{
+			name: "case 3: load balancers not belonging to services are ignored",
+			tags: map[string]map[string]string{
+				"lb-1": {
+					"kubernetes.io/cluster/deleted": "owned",
+				},
+				"lb-2": {
+					"kubernetes.io/cluster/deleted": "shared",
+					"kubernetes.io/service-name":    "default/hello-world",
+				},
+			},
+			expectedOrphans: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := findOrphanedLoadBalancers(tc.tags, clusterIDs)
+
+			if !reflect.DeepEqual(result, tc.expectedOrphans) {
+				t.Fatalf("orphans == %#v, want %#v", result, tc.expectedOrphans)
+			}
+		})
+	}
+}
@@ -11,7 +11,7 @@ import (
 
 	clientaws "github.com/giantswarm/aws-operator/client/aws"
 	"github.com/giantswarm/aws-operator/service/accountid"
-	"github.com/giantswarm/aws-operator/service/controller/v24/credential"
+	"github.com/giantswarm/aws-operator/service/controller/v25/credential"
 )
 
 type helperConfig struct {
@@ -57,9 +57,9 @@ func newHelper(config helperConfig) (*helper, error) {
 	return h, nil
 }
 
-// GetARNs list all unique aws IAM ARN from credential secret.
-func (h *helper) GetARNs() ([]string, error) {
-	var arns []string
+// GetRoles list all unique aws IAM roles from credential secret.
+func (h *helper) GetRoles() ([]credential.Role, error) {
+	var roles []credential.Role
 
 	// List AWSConfigs.
 	awsConfigClient := h.g8sClient.ProviderV1alpha1().AWSConfigs("")
@@ -68,11 +68,11 @@ func (h *helper) GetARNs() ([]string, error) {
 		return nil, microerror.Mask(err)
 	}
 
-	// Get unique ARNs.
-	arnsMap := make(map[string]bool)
+	// Get unique roles.
+	rolesMap := make(map[credential.Role]bool)
 	for _, awsConfig := range awsConfigs.Items {
-		arn, err := credential.GetARN(h.k8sClient, &awsConfig)
-		// Collect as many ARNs as possible in order to provide most metrics.
+		role, err := credential.GetRole(h.k8sClient, &awsConfig)
+		// Collect as many roles as possible in order to provide most metrics.
 		// Ignore old cluster which do not have credential.
 		if credential.IsCredentialNameEmptyError(err) {
 			continue
@@ -82,21 +82,21 @@ func (h *helper) GetARNs() ([]string, error) {
 			return nil, microerror.Mask(err)
 		}
 
-		arnsMap[arn] = true
+		rolesMap[role] = true
 	}
 
 	// Ensure we check the default guest account for old cluster not having credential.
-	arn, err := credential.GetDefaultARN(h.k8sClient)
+	role, err := credential.GetDefaultRole(h.k8sClient)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
-	arnsMap[arn] = true
+	rolesMap[role] = true
 
-	for arn := range arnsMap {
-		arns = append(arns, arn)
+	for role := range rolesMap {
+		roles = append(roles, role)
 	}
 
-	return arns, nil
+	return roles, nil
 }
 
 // GetAWSClients return a list of aws clients for every guest cluster account plus
@@ -107,7 +107,7 @@ func (h *helper) GetAWSClients() ([]clientaws.Clients, error) {
 		clientsMap = make(map[string]clientaws.Clients)
 	)
 
-	arns, err := h.GetARNs()
+	roles, err := h.GetRoles()
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
@@ -138,9 +138,8 @@ func (h *helper) GetAWSClients() ([]clientaws.Clients, error) {
 	}
 
 	// Tenant cluster accounts.
-	for _, arn := range arns {
-		awsConfig := h.awsConfig
-		awsConfig.RoleARN = arn
+	for _, role := range roles {
+		awsConfig := role.AWSConfig(h.awsConfig)
 
 		awsClients, err := clientaws.NewClients(awsConfig)
 		if err != nil {
@@ -0,0 +1,145 @@
+package collector
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/giantswarm/aws-operator/pkg/ipamledger"
+)
+
+const (
+	labelPriority = "priority"
+)
+
+const (
+	subsystemIPAM = "ipam"
+)
+
+var (
+	ipamAllocatedSubnetsDesc *prometheus.Desc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystemIPAM, "allocated_subnets"),
+		"Number of allocated tenant cluster subnets within an IPAM network range.",
+		[]string{
+			labelCIDR,
+			labelPriority,
+		},
+		nil,
+	)
+	ipamFreeSubnetsDesc *prometheus.Desc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystemIPAM, "free_subnets"),
+		"Number of free tenant cluster subnets within an IPAM network range.",
+		[]string{
+			labelCIDR,
+			labelPriority,
+		},
+		nil,
+	)
+)
+
+type IPAMConfig struct {
+	Helper *helper
+	Logger micrologger.Logger
+
+	NetworkRanges  []net.IPNet
+	SubnetMaskBits int
+}
+
+// IPAM exposes the utilisation of the network ranges tenant cluster subnets
+// are allocated from, based on the IPAM ledger.
+type IPAM struct {
+	helper *helper
+	ledger *ipamledger.Ledger
+	logger micrologger.Logger
+
+	networkRanges []net.IPNet
+	subnetMask    net.IPMask
+}
+
+func NewIPAM(config IPAMConfig) (*IPAM, error) {
+	if config.Helper == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Helper must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	if len(config.NetworkRanges) == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.NetworkRanges must not be empty", config)
+	}
+	if config.SubnetMaskBits <= 0 || config.SubnetMaskBits > 32 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.SubnetMaskBits must be between 1 and 32", config)
+	}
+
+	var err error
+
+	var ledger *ipamledger.Ledger
+	{
+		c := ipamledger.Config{
+			K8sClient: config.Helper.k8sClient,
+		}
+
+		ledger, err = ipamledger.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	i := &IPAM{
+		helper: config.Helper,
+		ledger: ledger,
+		logger: config.Logger,
+
+		networkRanges: config.NetworkRanges,
+		subnetMask:    net.CIDRMask(config.SubnetMaskBits, 32),
+	}
+
+	return i, nil
+}
+
+func (i *IPAM) Collect(ch chan<- prometheus.Metric) error {
+	entries, err := i.ledger.Entries()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	networkRanges, err := ipamledger.NetworkRanges(i.networkRanges, entries)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	allocatedSubnets, err := ipamledger.Subnets(entries)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, r := range networkRanges {
+		allocated, free := ipamledger.CountSubnets(r.CIDR, i.subnetMask, allocatedSubnets)
+
+		ch <- prometheus.MustNewConstMetric(
+			ipamAllocatedSubnetsDesc,
+			prometheus.GaugeValue,
+			float64(allocated),
+			r.CIDR.String(),
+			strconv.Itoa(r.Priority),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			ipamFreeSubnetsDesc,
+			prometheus.GaugeValue,
+			float64(free),
+			r.CIDR.String(),
+			strconv.Itoa(r.Priority),
+		)
+	}
+
+	return nil
+}
+
+func (i *IPAM) Describe(ch chan<- *prometheus.Desc) error {
+	ch <- ipamAllocatedSubnetsDesc
+	ch <- ipamFreeSubnetsDesc
+	return nil
+}
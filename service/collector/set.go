@@ -1,6 +1,8 @@
 package collector
 
 import (
+	"net"
+
 	"github.com/giantswarm/apiextensions/pkg/clientset/versioned"
 	"github.com/giantswarm/exporterkit/collector"
 	"github.com/giantswarm/microerror"
@@ -17,6 +19,8 @@ type SetConfig struct {
 
 	AWSConfig             clientaws.Config
 	InstallationName      string
+	IPAMNetworkRanges     []net.IPNet
+	IPAMSubnetMaskBits    int
 	TrustedAdvisorEnabled bool
 }
 
@@ -62,6 +66,21 @@ func NewSet(config SetConfig) (*Set, error) {
 		}
 	}
 
+	var cloudFormationCollector *CloudFormation
+	{
+		c := CloudFormationConfig{
+			Helper: h,
+			Logger: config.Logger,
+
+			InstallationName: config.InstallationName,
+		}
+
+		cloudFormationCollector, err = NewCloudFormation(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var ec2InstancesCollector *EC2Instances
 	{
 		c := EC2InstancesConfig{
@@ -92,6 +111,22 @@ func NewSet(config SetConfig) (*Set, error) {
 		}
 	}
 
+	var ipamCollector *IPAM
+	{
+		c := IPAMConfig{
+			Helper: h,
+			Logger: config.Logger,
+
+			NetworkRanges:  config.IPAMNetworkRanges,
+			SubnetMaskBits: config.IPAMSubnetMaskBits,
+		}
+
+		ipamCollector, err = NewIPAM(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var trustedAdvisorCollector *TrustedAdvisor
 	{
 		c := TrustedAdvisorConfig{
@@ -125,8 +160,10 @@ func NewSet(config SetConfig) (*Set, error) {
 		c := collector.SetConfig{
 			Collectors: []collector.Interface{
 				asgCollector,
+				cloudFormationCollector,
 				ec2InstancesCollector,
 				elbCollector,
+				ipamCollector,
 				vpcCollector,
 			},
 			Logger: config.Logger,
@@ -43,6 +43,8 @@ type ClusterConfig struct {
 	AdvancedMonitoringEC2      bool
 	APIWhitelist               FrameworkConfigAPIWhitelistConfig
 	DeleteLoggingBucket        bool
+	EBSEncryptionKey           string
+	EBSSnapshot                ClusterConfigEBSSnapshot
 	EncrypterBackend           string
 	GuestAWSConfig             ClusterConfigAWSConfig
 	GuestPrivateSubnetMaskBits int
@@ -53,6 +55,7 @@ type ClusterConfig struct {
 	IgnitionPath               string
 	IncludeTags                bool
 	InstallationName           string
+	IPAMAdditionalRanges       []net.IPNet
 	IPAMNetworkRange           net.IPNet
 	OIDC                       ClusterConfigOIDC
 	PodInfraContainerImage     string
@@ -62,7 +65,9 @@ type ClusterConfig struct {
 	Route53Enabled             bool
 	RouteTables                string
 	SSOPublicKey               string
+	TransitGateway             ClusterConfigTransitGateway
 	VaultAddress               string
+	VPCEndpoints               []string
 }
 
 type ClusterConfigAWSConfig struct {
@@ -73,6 +78,21 @@ type ClusterConfigAWSConfig struct {
 	SessionToken      string
 }
 
+// ClusterConfigEBSSnapshot represents the configuration of the snapshots taken
+// from the volumes of tenant clusters before they are deleted.
+type ClusterConfigEBSSnapshot struct {
+	Enabled       bool
+	RetentionDays int
+}
+
+// ClusterConfigTransitGateway represents the configuration of the transit
+// gateway new tenant clusters are attached to instead of being peered with the
+// control plane VPC.
+type ClusterConfigTransitGateway struct {
+	ID           string
+	RouteTableID string
+}
+
 // ClusterConfigOIDC represents the configuration of the OIDC authorization
 // provider.
 type ClusterConfigOIDC struct {
@@ -437,6 +457,9 @@ func newClusterResourceSets(config ClusterConfig) ([]*controller.ResourceSet, er
 			AccessLogsExpiration:       config.AccessLogsExpiration,
 			AdvancedMonitoringEC2:      config.AdvancedMonitoringEC2,
 			DeleteLoggingBucket:        config.DeleteLoggingBucket,
+			EBSEncryptionKey:           config.EBSEncryptionKey,
+			EBSSnapshotEnabled:         config.EBSSnapshot.Enabled,
+			EBSSnapshotRetentionDays:   config.EBSSnapshot.RetentionDays,
 			EncrypterBackend:           config.EncrypterBackend,
 			GuestAvailabilityZones:     config.GuestAWSConfig.AvailabilityZones,
 			GuestPrivateSubnetMaskBits: config.GuestPrivateSubnetMaskBits,
@@ -447,7 +470,10 @@ func newClusterResourceSets(config ClusterConfig) ([]*controller.ResourceSet, er
 			IgnitionPath:               config.IgnitionPath,
 			IncludeTags:                config.IncludeTags,
 			InstallationName:           config.InstallationName,
-			IPAMNetworkRange:           config.IPAMNetworkRange,
+			IPAMNetworkRanges:          append([]net.IPNet{config.IPAMNetworkRange}, config.IPAMAdditionalRanges...),
+			TransitGatewayID:           config.TransitGateway.ID,
+			TransitGatewayRouteTableID: config.TransitGateway.RouteTableID,
+			VPCEndpoints:               config.VPCEndpoints,
 			OIDC: v25cloudconfig.OIDCConfig{
 				ClientID:      config.OIDC.ClientID,
 				IssuerURL:     config.OIDC.IssuerURL,
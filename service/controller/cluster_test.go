@@ -23,6 +23,9 @@ func newTestClusterConfig() ClusterConfig {
 		Logger:       microloggertest.New(),
 
 		AccessLogsExpiration: 365,
+		EBSSnapshot: ClusterConfigEBSSnapshot{
+			RetentionDays: 30,
+		},
 		GuestAWSConfig: ClusterConfigAWSConfig{
 			AccessKeyID:       "guest-key",
 			AccessKeySecret:   "guest-secret",
@@ -20,7 +20,7 @@
 // `service/template/cloudformation/main.yaml` to include the new template.
 // * Add the adapter logic file in `service/resource/cloudformation/adapter` with the type
 // definition and the Hydrater function to fill the fields (like asg.go or
-// launch_configuration.go).
+// launch_template.go).
 // * Add the new type to the Adapter type in `service/resource/cloudformation/adapter/adapter.go`
 // and include the Hydrater function in the `hydraters` slice.
 package adapter
@@ -46,6 +46,9 @@ type Config struct {
 	StackState                      StackState
 	TenantClusterAccountID          string
 	TenantClusterKMSKeyARN          string
+	TenantClusterPreviousKMSKeyARN  string
+	TransitGatewayID                string
+	VPCEndpoints                    []string
 }
 
 type Adapter struct {
@@ -60,7 +63,7 @@ func NewGuest(cfg Config) (Adapter, error) {
 		a.Guest.IAMPolicies.Adapt,
 		a.Guest.InternetGateway.Adapt,
 		a.Guest.Instance.Adapt,
-		a.Guest.LaunchConfiguration.Adapt,
+		a.Guest.LaunchTemplate.Adapt,
 		a.Guest.LifecycleHooks.Adapt,
 		a.Guest.LoadBalancers.Adapt,
 		a.Guest.NATGateway.Adapt,
@@ -82,18 +85,18 @@ func NewGuest(cfg Config) (Adapter, error) {
 }
 
 type GuestAdapter struct {
-	AutoScalingGroup    GuestAutoScalingGroupAdapter
-	IAMPolicies         GuestIAMPoliciesAdapter
-	InternetGateway     GuestInternetGatewayAdapter
-	Instance            GuestInstanceAdapter
-	LaunchConfiguration GuestLaunchConfigAdapter
-	LifecycleHooks      GuestLifecycleHooksAdapter
-	LoadBalancers       GuestLoadBalancersAdapter
-	NATGateway          GuestNATGatewayAdapter
-	Outputs             GuestOutputsAdapter
-	RecordSets          GuestRecordSetsAdapter
-	RouteTables         GuestRouteTablesAdapter
-	SecurityGroups      GuestSecurityGroupsAdapter
-	Subnets             GuestSubnetsAdapter
-	VPC                 GuestVPCAdapter
+	AutoScalingGroup GuestAutoScalingGroupAdapter
+	IAMPolicies      GuestIAMPoliciesAdapter
+	InternetGateway  GuestInternetGatewayAdapter
+	Instance         GuestInstanceAdapter
+	LaunchTemplate   GuestLaunchTemplateAdapter
+	LifecycleHooks   GuestLifecycleHooksAdapter
+	LoadBalancers    GuestLoadBalancersAdapter
+	NATGateway       GuestNATGatewayAdapter
+	Outputs          GuestOutputsAdapter
+	RecordSets       GuestRecordSetsAdapter
+	RouteTables      GuestRouteTablesAdapter
+	SecurityGroups   GuestSecurityGroupsAdapter
+	Subnets          GuestSubnetsAdapter
+	VPC              GuestVPCAdapter
 }
@@ -144,7 +144,11 @@ func TestAdapterGuestMain(t *testing.T) {
 				CustomObject:          tc.customObject,
 				InstallationName:      "myinstallation",
 				StackState: StackState{
-					MasterImageID: "master-image-id",
+					Masters: []StackStateMaster{
+						{
+							ImageID: "master-image-id",
+						},
+					},
 					WorkerImageID: "worker-image-id",
 				},
 			}
@@ -160,19 +164,19 @@ func TestAdapterGuestMain(t *testing.T) {
 				t.Fatalf("error == %#v, want matching", err)
 			}
 
-			if tc.expectedASGType != a.Guest.AutoScalingGroup.ASGType {
-				t.Fatalf("unexpected ASG type, expected %q, got %q", tc.expectedASGType, a.Guest.AutoScalingGroup.ASGType)
+			if tc.expectedASGType != a.Guest.AutoScalingGroup.WorkerPools[0].ASGType {
+				t.Fatalf("unexpected ASG type, expected %q, got %q", tc.expectedASGType, a.Guest.AutoScalingGroup.WorkerPools[0].ASGType)
 			}
-			if tc.expectedASGType != a.Guest.LaunchConfiguration.ASGType {
-				t.Fatalf("unexpected ASG type, expected %q, got %q", tc.expectedASGType, a.Guest.LaunchConfiguration.ASGType)
+			if tc.expectedASGType != a.Guest.LaunchTemplate.WorkerPools[0].ASGType {
+				t.Fatalf("unexpected ASG type, expected %q, got %q", tc.expectedASGType, a.Guest.LaunchTemplate.WorkerPools[0].ASGType)
 			}
 
 			if tc.expectedEC2ServiceDomain != a.Guest.IAMPolicies.EC2ServiceDomain {
 				t.Fatalf("unexpected EC2 service domain, expected %q, got %q", tc.expectedEC2ServiceDomain, a.Guest.IAMPolicies.EC2ServiceDomain)
 			}
 
-			if tc.expectedWorkerImageID != a.Guest.LaunchConfiguration.WorkerImageID {
-				t.Fatalf("unexpected WorkerImageID, expected %q, got %q", tc.expectedWorkerImageID, a.Guest.LaunchConfiguration.WorkerImageID)
+			if tc.expectedWorkerImageID != a.Guest.LaunchTemplate.WorkerImageID {
+				t.Fatalf("unexpected WorkerImageID, expected %q, got %q", tc.expectedWorkerImageID, a.Guest.LaunchTemplate.WorkerImageID)
 			}
 		})
 	}
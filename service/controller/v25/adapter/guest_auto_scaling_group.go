@@ -9,17 +9,35 @@ import (
 )
 
 type GuestAutoScalingGroupAdapter struct {
-	ASGDesiredCapacity     int
-	ASGMaxSize             int
-	ASGMinSize             int
-	ASGType                string
 	ClusterID              string
 	HealthCheckGracePeriod int
-	MaxBatchSize           string
-	MinInstancesInService  string
-	PrivateSubnets         []string
-	RollingUpdatePauseTime string
-	WorkerAZs              []string
+	// IngressTargetGroupResourceNames holds the logical IDs of the NLB target
+	// groups of the ingress load balancer. It is empty in case the tenant
+	// cluster uses classic ELBs.
+	IngressTargetGroupResourceNames []string
+	PrivateSubnets                  []string
+	RollingUpdatePauseTime          string
+	WorkerAZs                       []string
+	// WorkerPools holds the ASGs of all worker pools. The first worker pool is
+	// always the default worker pool defined by the CR spec.
+	WorkerPools []GuestAutoScalingGroupAdapterWorkerPool
+}
+
+type GuestAutoScalingGroupAdapterWorkerPool struct {
+	ASGDesiredCapacity    int
+	ASGMaxSize            int
+	ASGMinSize            int
+	ASGType               string
+	InstanceDistribution  GuestAutoScalingGroupAdapterInstanceDistribution
+	InstanceTypeOverrides []string
+	MaxBatchSize          string
+	MinInstancesInService string
+	Name                  string
+}
+
+type GuestAutoScalingGroupAdapterInstanceDistribution struct {
+	OnDemandBaseCapacity                int
+	OnDemandPercentageAboveBaseCapacity int
 }
 
 func (a *GuestAutoScalingGroupAdapter) Adapt(cfg Config) error {
@@ -41,19 +59,44 @@ func (a *GuestAutoScalingGroupAdapter) Adapt(cfg Config) error {
 		}
 	}
 
-	// Find out the minimum desired number of workers.
-	currentDesiredMinWorkers := minDesiredWorkers(minWorkers, maxWorkers, cfg.StackState.WorkerDesired)
-
-	a.ASGDesiredCapacity = currentDesiredMinWorkers
-	a.ASGMaxSize = maxWorkers
-	a.ASGMinSize = minWorkers
-	a.ASGType = key.KindWorker
 	a.ClusterID = key.ClusterID(cfg.CustomObject)
-	a.MaxBatchSize = workerCountRatio(currentDesiredMinWorkers, asgMaxBatchSizeRatio)
-	a.MinInstancesInService = workerCountRatio(currentDesiredMinWorkers, asgMinInstancesRatio)
 	a.HealthCheckGracePeriod = gracePeriodSeconds
 	a.RollingUpdatePauseTime = rollingUpdatePauseTime
 
+	{
+		lbType, err := key.LoadBalancerType(cfg.CustomObject)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if lbType == key.LoadBalancerTypeNetwork {
+			a.IngressTargetGroupResourceNames = ingressTargetGroupResourceNames()
+		}
+	}
+
+	{
+		d, err := key.WorkerInstanceDistribution(cfg.CustomObject)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		p := newWorkerPoolASG("", minWorkers, maxWorkers, cfg.StackState.WorkerDesired)
+		p.InstanceDistribution = newInstanceDistribution(d)
+		p.InstanceTypeOverrides = key.InstanceTypeOverrides(key.WorkerInstanceType(cfg.CustomObject), d)
+		a.WorkerPools = append(a.WorkerPools, p)
+	}
+
+	pools, err := key.WorkerPools(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	for _, pool := range pools {
+		p := newWorkerPoolASG(pool.Name, pool.Min, pool.Max, cfg.StackState.WorkerPoolsDesired[pool.Name])
+		p.InstanceDistribution = newInstanceDistribution(pool.InstanceDistribution)
+		p.InstanceTypeOverrides = key.InstanceTypeOverrides(pool.InstanceType, pool.InstanceDistribution)
+		a.WorkerPools = append(a.WorkerPools, p)
+	}
+
 	for i, az := range key.StatusAvailabilityZones(cfg.CustomObject) {
 		a.PrivateSubnets = append(a.PrivateSubnets, key.PrivateSubnetName(i))
 		a.WorkerAZs = append(a.WorkerAZs, az.Name)
@@ -62,6 +105,33 @@ func (a *GuestAutoScalingGroupAdapter) Adapt(cfg Config) error {
 	return nil
 }
 
+// newInstanceDistribution translates the given instance distribution into the
+// instances distribution of the ASG's mixed instances policy, which defines
+// the percentage of on-demand instead of spot instances.
+func newInstanceDistribution(d key.InstanceDistribution) GuestAutoScalingGroupAdapterInstanceDistribution {
+	return GuestAutoScalingGroupAdapterInstanceDistribution{
+		OnDemandBaseCapacity:                d.OnDemandBaseCapacity,
+		OnDemandPercentageAboveBaseCapacity: 100 - d.SpotPercentage,
+	}
+}
+
+func newWorkerPoolASG(name string, minWorkers, maxWorkers, desiredWorkers int) GuestAutoScalingGroupAdapterWorkerPool {
+	// Find out the minimum desired number of workers.
+	currentDesiredMinWorkers := minDesiredWorkers(minWorkers, maxWorkers, desiredWorkers)
+
+	p := GuestAutoScalingGroupAdapterWorkerPool{
+		ASGDesiredCapacity:    currentDesiredMinWorkers,
+		ASGMaxSize:            maxWorkers,
+		ASGMinSize:            minWorkers,
+		ASGType:               key.WorkerPoolASGType(name),
+		MaxBatchSize:          workerCountRatio(currentDesiredMinWorkers, asgMaxBatchSizeRatio),
+		MinInstancesInService: workerCountRatio(currentDesiredMinWorkers, asgMinInstancesRatio),
+		Name:                  key.WorkerPoolRole(name),
+	}
+
+	return p
+}
+
 func workerCountRatio(workers int, ratio float32) string {
 	value := float32(workers) * ratio
 	rounded := int(value + 0.5)
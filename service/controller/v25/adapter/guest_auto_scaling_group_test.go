@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 func TestAdapterAutoScalingGroupRegularFields(t *testing.T) {
@@ -121,24 +123,24 @@ func TestAdapterAutoScalingGroupRegularFields(t *testing.T) {
 			}
 
 			if !tc.expectedError {
-				if a.Guest.AutoScalingGroup.ASGMaxSize != tc.expectedASGMaxSize {
-					t.Errorf("unexpected output, got %d, want %d", a.Guest.AutoScalingGroup.ASGMaxSize, tc.expectedASGMaxSize)
+				if a.Guest.AutoScalingGroup.WorkerPools[0].ASGMaxSize != tc.expectedASGMaxSize {
+					t.Errorf("unexpected output, got %d, want %d", a.Guest.AutoScalingGroup.WorkerPools[0].ASGMaxSize, tc.expectedASGMaxSize)
 				}
 
-				if a.Guest.AutoScalingGroup.ASGMinSize != tc.expectedASGMinSize {
-					t.Errorf("unexpected output, got %d, want %d", a.Guest.AutoScalingGroup.ASGMinSize, tc.expectedASGMinSize)
+				if a.Guest.AutoScalingGroup.WorkerPools[0].ASGMinSize != tc.expectedASGMinSize {
+					t.Errorf("unexpected output, got %d, want %d", a.Guest.AutoScalingGroup.WorkerPools[0].ASGMinSize, tc.expectedASGMinSize)
 				}
 
 				if a.Guest.AutoScalingGroup.HealthCheckGracePeriod != tc.expectedHealthCheckGracePeriod {
 					t.Errorf("unexpected output, got %d, want %d", a.Guest.AutoScalingGroup.HealthCheckGracePeriod, tc.expectedHealthCheckGracePeriod)
 				}
 
-				if a.Guest.AutoScalingGroup.MaxBatchSize != tc.expectedMaxBatchSize {
-					t.Errorf("unexpected output, got %q, want %q", a.Guest.AutoScalingGroup.MaxBatchSize, tc.expectedMaxBatchSize)
+				if a.Guest.AutoScalingGroup.WorkerPools[0].MaxBatchSize != tc.expectedMaxBatchSize {
+					t.Errorf("unexpected output, got %q, want %q", a.Guest.AutoScalingGroup.WorkerPools[0].MaxBatchSize, tc.expectedMaxBatchSize)
 				}
 
-				if a.Guest.AutoScalingGroup.MinInstancesInService != tc.expectedMinInstancesInService {
-					t.Errorf("unexpected output, got %q, want %q", a.Guest.AutoScalingGroup.MinInstancesInService, tc.expectedMinInstancesInService)
+				if a.Guest.AutoScalingGroup.WorkerPools[0].MinInstancesInService != tc.expectedMinInstancesInService {
+					t.Errorf("unexpected output, got %q, want %q", a.Guest.AutoScalingGroup.WorkerPools[0].MinInstancesInService, tc.expectedMinInstancesInService)
 				}
 
 				if a.Guest.AutoScalingGroup.RollingUpdatePauseTime != tc.expectedRollingUpdatePauseTime {
@@ -187,3 +189,80 @@ func TestWorkerCountRatioMaxBatchSize(t *testing.T) {
 		})
 	}
 }
+
+func TestAdapterAutoScalingGroupWorkerPools(t *testing.T) {
+	t.Parallel()
+	customObject := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: defaultClusterWithScaling(3, 4),
+			AWS: v1alpha1.AWSConfigSpecAWS{
+				Workers: []v1alpha1.AWSConfigSpecAWSNode{
+					{},
+				},
+			},
+		},
+		Status: v1alpha1.AWSConfigStatus{
+			AWS: v1alpha1.AWSConfigStatusAWS{
+				AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+					{
+						Name: "myaz",
+					},
+				},
+			},
+		},
+	}
+	customObject.SetAnnotations(map[string]string{
+		key.WorkerPoolsAnnotation: `[{"name":"gpu","instanceType":"p2.xlarge","instanceTypes":["p3.2xlarge"],"onDemandBaseCapacity":1,"spotPercentage":60,"min":1,"max":5}]`,
+	})
+
+	cfg := Config{
+		CustomObject: customObject,
+		StackState: StackState{
+			WorkerPoolsDesired: map[string]int{
+				"gpu": 2,
+			},
+		},
+	}
+
+	a := Adapter{}
+	err := a.Guest.AutoScalingGroup.Adapt(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []GuestAutoScalingGroupAdapterWorkerPool{
+		{
+			ASGDesiredCapacity: 3,
+			ASGMaxSize:         4,
+			ASGMinSize:         3,
+			ASGType:            "worker",
+			InstanceDistribution: GuestAutoScalingGroupAdapterInstanceDistribution{
+				OnDemandBaseCapacity:                0,
+				OnDemandPercentageAboveBaseCapacity: 100,
+			},
+			MaxBatchSize:          "1",
+			MinInstancesInService: "2",
+			Name:                  "worker",
+		},
+		{
+			ASGDesiredCapacity: 2,
+			ASGMaxSize:         5,
+			ASGMinSize:         1,
+			ASGType:            "workerPoolGpu",
+			InstanceDistribution: GuestAutoScalingGroupAdapterInstanceDistribution{
+				OnDemandBaseCapacity:                1,
+				OnDemandPercentageAboveBaseCapacity: 40,
+			},
+			InstanceTypeOverrides: []string{
+				"p2.xlarge",
+				"p3.2xlarge",
+			},
+			MaxBatchSize:          "1",
+			MinInstancesInService: "1",
+			Name:                  "worker-gpu",
+		},
+	}
+	if !reflect.DeepEqual(a.Guest.AutoScalingGroup.WorkerPools, expected) {
+		t.Fatalf("expected worker pools %#v, got %#v", expected, a.Guest.AutoScalingGroup.WorkerPools)
+	}
+}
@@ -1,21 +1,27 @@
 package adapter
 
 import (
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter"
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 type GuestIAMPoliciesAdapter struct {
 	ClusterID         string
 	EC2ServiceDomain  string
+	EtcdBackupPrefix  string
 	KMSKeyARN         string
 	MasterRoleName    string
 	MasterPolicyName  string
 	MasterProfileName string
+	PreviousKMSKeyARN string
 	RegionARN         string
 	S3Bucket          string
-	WorkerRoleName    string
-	WorkerPolicyName  string
-	WorkerProfileName string
+	// SSMParameterPathARN is the ARN of the parameters the assets of the tenant
+	// cluster are stored in. It is empty unless the SSM encrypter is used.
+	SSMParameterPathARN string
+	WorkerRoleName      string
+	WorkerPolicyName    string
+	WorkerProfileName   string
 }
 
 func (i *GuestIAMPoliciesAdapter) Adapt(cfg Config) error {
@@ -23,6 +29,7 @@ func (i *GuestIAMPoliciesAdapter) Adapt(cfg Config) error {
 
 	i.ClusterID = clusterID
 	i.EC2ServiceDomain = key.EC2ServiceDomain(cfg.CustomObject)
+	i.EtcdBackupPrefix = key.EtcdBackupPrefix
 	i.MasterPolicyName = key.PolicyName(cfg.CustomObject, key.KindMaster)
 	i.MasterProfileName = key.InstanceProfileName(cfg.CustomObject, key.KindMaster)
 	i.MasterRoleName = key.RoleName(cfg.CustomObject, key.KindMaster)
@@ -31,7 +38,12 @@ func (i *GuestIAMPoliciesAdapter) Adapt(cfg Config) error {
 	i.WorkerRoleName = key.RoleName(cfg.CustomObject, key.KindWorker)
 	i.RegionARN = key.RegionARN(cfg.CustomObject)
 	i.KMSKeyARN = cfg.TenantClusterKMSKeyARN
+	i.PreviousKMSKeyARN = cfg.TenantClusterPreviousKMSKeyARN
 	i.S3Bucket = key.BucketName(cfg.CustomObject, cfg.TenantClusterAccountID)
 
+	if cfg.EncrypterBackend == encrypter.SSMBackend {
+		i.SSMParameterPathARN = key.SSMParameterPathARN(cfg.CustomObject, cfg.TenantClusterAccountID)
+	}
+
 	return nil
 }
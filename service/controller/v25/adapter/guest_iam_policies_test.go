@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter"
 )
 
 func TestAdapterIamPoliciesRegularFields(t *testing.T) {
@@ -116,3 +118,48 @@ func TestAdapterIamPoliciesS3Bucket(t *testing.T) {
 		})
 	}
 }
+
+func TestAdapterIamPoliciesSSMParameterPathARN(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description                 string
+		encrypterBackend            string
+		expectedSSMParameterPathARN string
+	}{
+		{
+			description:                 "kms encrypter",
+			encrypterBackend:            encrypter.KMSBackend,
+			expectedSSMParameterPathARN: "",
+		},
+		{
+			description:                 "ssm encrypter",
+			encrypterBackend:            encrypter.SSMBackend,
+			expectedSSMParameterPathARN: "arn:aws:ssm:eu-central-1:111111111111:parameter/giantswarm/test-cluster/*",
+		},
+	}
+	for _, tc := range testCases {
+		a := Adapter{}
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := Config{
+				CustomObject: v1alpha1.AWSConfig{
+					Spec: v1alpha1.AWSConfigSpec{
+						AWS: v1alpha1.AWSConfigSpecAWS{
+							Region: "eu-central-1",
+						},
+						Cluster: defaultCluster,
+					},
+				},
+				EncrypterBackend:       tc.encrypterBackend,
+				TenantClusterAccountID: "111111111111",
+			}
+			err := a.Guest.IAMPolicies.Adapt(cfg)
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+
+			if a.Guest.IAMPolicies.SSMParameterPathARN != tc.expectedSSMParameterPathARN {
+				t.Errorf("unexpected SSMParameterPathARN, got %q, want %q", a.Guest.IAMPolicies.SSMParameterPathARN, tc.expectedSSMParameterPathARN)
+			}
+		})
+	}
+}
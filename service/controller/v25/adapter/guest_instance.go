@@ -2,50 +2,71 @@ package adapter
 
 import (
 	"encoding/base64"
-	"sort"
 
 	"github.com/giantswarm/microerror"
 
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter"
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 	"github.com/giantswarm/aws-operator/service/controller/v25/templates"
 )
 
 type GuestInstanceAdapter struct {
 	Cluster GuestInstanceAdapterCluster
-	Image   GuestInstanceAdapterImage
-	Master  GuestInstanceAdapterMaster
+	Masters []GuestInstanceAdapterMaster
 }
 
 type GuestInstanceAdapterCluster struct {
 	ID string
 }
 
-type GuestInstanceAdapterImage struct {
-	ID string
-}
-
 type GuestInstanceAdapterMaster struct {
-	AZ               string
-	CloudConfig      string
-	EncrypterBackend string
-	DockerVolume     GuestInstanceAdapterMasterDockerVolume
-	EtcdVolume       GuestInstanceAdapterMasterEtcdVolume
-	LogVolume        GuestInstanceAdapterMasterLogVolume
-	Instance         GuestInstanceAdapterMasterInstance
+	AZ           string
+	CloudConfig  string
+	DockerVolume GuestInstanceAdapterMasterDockerVolume
+	EtcdVolume   GuestInstanceAdapterMasterEtcdVolume
+	ImageID      string
+	LogVolume    GuestInstanceAdapterMasterLogVolume
+	Index        int
+	Instance     GuestInstanceAdapterMasterInstance
+	// NetworkInterface is only set for tenant clusters with multiple masters.
+	// The network interface holds the fixed private IP etcd peers use to
+	// reach the master.
+	NetworkInterface *GuestInstanceAdapterMasterNetworkInterface
 	PrivateSubnet    string
+	RootVolume       GuestInstanceAdapterMasterRootVolume
 }
 
 type GuestInstanceAdapterMasterDockerVolume struct {
+	Encryption   GuestInstanceAdapterVolumeEncryption
 	Name         string
 	ResourceName string
 }
 
 type GuestInstanceAdapterMasterEtcdVolume struct {
-	Name string
+	Encryption   GuestInstanceAdapterVolumeEncryption
+	Name         string
+	ResourceName string
 }
 
 type GuestInstanceAdapterMasterLogVolume struct {
-	Name string
+	Encryption   GuestInstanceAdapterVolumeEncryption
+	Name         string
+	ResourceName string
+}
+
+// GuestInstanceAdapterMasterRootVolume is only rendered as block device
+// mapping of the master instance in case the root volume is encrypted.
+type GuestInstanceAdapterMasterRootVolume struct {
+	DeviceName string
+	Encryption GuestInstanceAdapterVolumeEncryption
+}
+
+// GuestInstanceAdapterVolumeEncryption defines the encryption of a single
+// EBS volume. The default EBS key of the tenant cluster account is used in
+// case KMSKeyID is empty.
+type GuestInstanceAdapterVolumeEncryption struct {
+	Encrypted bool
+	KMSKeyID  string
 }
 
 type GuestInstanceAdapterMasterInstance struct {
@@ -54,54 +75,126 @@ type GuestInstanceAdapterMasterInstance struct {
 	Monitoring   bool
 }
 
+type GuestInstanceAdapterMasterNetworkInterface struct {
+	PrivateIP    string
+	ResourceName string
+}
+
 func (i *GuestInstanceAdapter) Adapt(config Config) error {
 	{
 		i.Cluster.ID = key.ClusterID(config.CustomObject)
 	}
 
-	{
-		i.Image.ID = config.StackState.MasterImageID
+	masters := config.StackState.Masters
+	if len(masters) == 0 {
+		return microerror.Maskf(invalidConfigError, "StackState.Masters must not be empty")
+	}
+	// Tenant clusters have an odd number of etcd members to keep quorum. Only
+	// rolling master updates of tenant clusters with at least three masters
+	// temporarily add a surge master, which is no etcd member.
+	memberCount := key.StackEtcdMemberCount(config.StackState.MasterEtcdMemberCount, len(masters))
+	if memberCount%2 == 0 {
+		return microerror.Maskf(invalidConfigError, "etcd member count must be odd for etcd to keep quorum, got %d", memberCount)
+	}
+	if len(masters) != memberCount && (len(masters) != memberCount+1 || memberCount < 3) {
+		return microerror.Maskf(invalidConfigError, "master count must equal the etcd member count %d or add a single surge master to at least three etcd members, got %d", memberCount, len(masters))
 	}
 
-	{
-		zones := key.StatusAvailabilityZones(config.CustomObject)
-		sort.Slice(zones, func(i, j int) bool {
-			return zones[i].Name < zones[j].Name
-		})
-
-		if len(zones) < 1 {
+	for idx, sm := range masters {
+		zone, zoneIdx, err := key.MasterAvailabilityZone(config.CustomObject, idx)
+		if key.IsNotFound(err) {
 			return microerror.Maskf(notFoundError, "CustomObject has no availability zones")
-		}
-
-		i.Master.AZ = zones[0].Name
-		i.Master.PrivateSubnet = key.PrivateSubnetName(0)
-
-		c := SmallCloudconfigConfig{
-			InstanceRole: key.KindMaster,
-			S3URL:        key.SmallCloudConfigS3URL(config.CustomObject, config.TenantClusterAccountID, key.KindMaster),
-		}
-		rendered, err := templates.Render(key.CloudConfigSmallTemplates(), c)
-		if err != nil {
+		} else if err != nil {
 			return microerror.Mask(err)
 		}
-		i.Master.CloudConfig = base64.StdEncoding.EncodeToString([]byte(rendered))
-
-		i.Master.EncrypterBackend = config.EncrypterBackend
 
-		i.Master.DockerVolume.Name = key.DockerVolumeName(config.CustomObject)
-
-		i.Master.DockerVolume.ResourceName = config.StackState.DockerVolumeResourceName
-
-		i.Master.EtcdVolume.Name = key.EtcdVolumeName(config.CustomObject)
+		// Masters which are not yet replaced during a rolling update keep
+		// fetching the cloud config of the version they were created with, so
+		// that their user data does not change.
+		var cloudConfig string
+		{
+			cr := config.CustomObject.DeepCopy()
+			if sm.VersionBundleVersion != "" {
+				cr.Spec.VersionBundle.Version = sm.VersionBundleVersion
+			}
+
+			c := SmallCloudconfigConfig{
+				InstanceRole: key.KindMaster,
+				S3URL:        key.SmallCloudConfigS3URL(*cr, config.TenantClusterAccountID, key.KindMaster),
+			}
+			rendered, err := templates.Render(key.CloudConfigSmallTemplates(), c)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			cloudConfig = base64.StdEncoding.EncodeToString([]byte(rendered))
+		}
 
-		i.Master.LogVolume.Name = key.LogVolumeName(config.CustomObject)
+		// Masters created before all volumes got encrypted keep their volumes as
+		// they are. Their docker, etcd and log volumes are only encrypted in case
+		// of the KMS encrypter backend and their root volumes are not encrypted.
+		legacyEncrypted := config.EncrypterBackend == encrypter.KMSBackend
+
+		m := GuestInstanceAdapterMaster{
+			AZ:          zone.Name,
+			CloudConfig: cloudConfig,
+			DockerVolume: GuestInstanceAdapterMasterDockerVolume{
+				Encryption:   newVolumeEncryption(sm.VolumeEncryption, legacyEncrypted),
+				Name:         key.DockerVolumeName(config.CustomObject),
+				ResourceName: sm.DockerVolumeResourceName,
+			},
+			EtcdVolume: GuestInstanceAdapterMasterEtcdVolume{
+				Encryption:   newVolumeEncryption(config.StackState.MasterEtcdVolumeEncryption, legacyEncrypted),
+				Name:         key.EtcdVolumeName(config.CustomObject),
+				ResourceName: key.EtcdVolumeResourceName(idx),
+			},
+			ImageID: sm.ImageID,
+			LogVolume: GuestInstanceAdapterMasterLogVolume{
+				Encryption:   newVolumeEncryption(sm.VolumeEncryption, legacyEncrypted),
+				Name:         key.LogVolumeName(config.CustomObject),
+				ResourceName: key.LogVolumeResourceName(idx),
+			},
+			Index: idx,
+			Instance: GuestInstanceAdapterMasterInstance{
+				ResourceName: sm.InstanceResourceName,
+				Type:         sm.InstanceType,
+				Monitoring:   config.StackState.MasterInstanceMonitoring,
+			},
+			PrivateSubnet: key.PrivateSubnetName(zoneIdx),
+			RootVolume: GuestInstanceAdapterMasterRootVolume{
+				DeviceName: rootEBSVolumeMountPoint,
+				Encryption: newVolumeEncryption(sm.VolumeEncryption, false),
+			},
+		}
 
-		i.Master.Instance.ResourceName = config.StackState.MasterInstanceResourceName
+		if len(masters) > 1 {
+			ip, err := key.MasterPrivateIP(config.CustomObject, idx)
+			if err != nil {
+				return microerror.Mask(err)
+			}
 
-		i.Master.Instance.Type = config.StackState.MasterInstanceType
+			m.NetworkInterface = &GuestInstanceAdapterMasterNetworkInterface{
+				PrivateIP:    ip,
+				ResourceName: key.MasterNetworkInterfaceResourceName(idx),
+			}
+		}
 
-		i.Master.Instance.Monitoring = config.StackState.MasterInstanceMonitoring
+		i.Masters = append(i.Masters, m)
 	}
 
 	return nil
 }
+
+// newVolumeEncryption returns the encryption of an EBS volume encrypted with
+// the given KMS key ARN or key.VolumeEncryptionDefault. Volumes created before
+// all volumes got encrypted have no volume encryption and are only encrypted
+// in case legacyEncrypted is true.
+func newVolumeEncryption(volumeEncryption string, legacyEncrypted bool) GuestInstanceAdapterVolumeEncryption {
+	switch volumeEncryption {
+	case "":
+		return GuestInstanceAdapterVolumeEncryption{Encrypted: legacyEncrypted}
+	case key.VolumeEncryptionDefault:
+		return GuestInstanceAdapterVolumeEncryption{Encrypted: true}
+	default:
+		return GuestInstanceAdapterVolumeEncryption{Encrypted: true, KMSKeyID: volumeEncryption}
+	}
+}
@@ -14,46 +14,119 @@ import (
 func Test_Adapter_Instance_RegularFields(t *testing.T) {
 	t.Parallel()
 
+	customObject := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "test-cluster",
+			},
+			AWS: v1alpha1.AWSConfigSpecAWS{
+				AZ:     "eu-west-1a",
+				Region: "eu-west-1",
+			},
+		},
+		Status: v1alpha1.AWSConfigStatus{
+			AWS: v1alpha1.AWSConfigStatusAWS{
+				AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+					v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+						Name: "eu-west-1a",
+					},
+				},
+			},
+		},
+	}
+
 	testCases := []struct {
-		Description              string
-		Config                   Config
-		ExpectedAZ               string
-		ExpectedEtcdVolumeName   string
-		ExpectedInstanceType     string
-		ExpectedEncrypterBackend string
+		Description                    string
+		Config                         Config
+		ExpectedAZ                     string
+		ExpectedEtcdVolumeName         string
+		ExpectedInstanceType           string
+		ExpectedDockerVolumeEncryption GuestInstanceAdapterVolumeEncryption
+		ExpectedEtcdVolumeEncryption   GuestInstanceAdapterVolumeEncryption
+		ExpectedRootVolumeEncryption   GuestInstanceAdapterVolumeEncryption
 	}{
 		{
 			Description: "case 0 basic matching, all fields present",
 			Config: Config{
-				CustomObject: v1alpha1.AWSConfig{
-					Spec: v1alpha1.AWSConfigSpec{
-						Cluster: v1alpha1.Cluster{
-							ID: "test-cluster",
+				CustomObject: customObject,
+				StackState: StackState{
+					Masters: []StackStateMaster{
+						{
+							InstanceType: "m3.large",
 						},
-						AWS: v1alpha1.AWSConfigSpecAWS{
-							AZ:     "eu-west-1a",
-							Region: "eu-west-1",
+					},
+				},
+				EncrypterBackend: "my-encrypter-backend",
+			},
+			ExpectedAZ:                     "eu-west-1a",
+			ExpectedEtcdVolumeName:         "test-cluster-etcd",
+			ExpectedInstanceType:           "m3.large",
+			ExpectedDockerVolumeEncryption: GuestInstanceAdapterVolumeEncryption{},
+			ExpectedEtcdVolumeEncryption:   GuestInstanceAdapterVolumeEncryption{},
+			ExpectedRootVolumeEncryption:   GuestInstanceAdapterVolumeEncryption{},
+		},
+		{
+			Description: "case 1 master created before all volumes got encrypted with kms encrypter backend",
+			Config: Config{
+				CustomObject: customObject,
+				StackState: StackState{
+					Masters: []StackStateMaster{
+						{
+							InstanceType: "m3.large",
 						},
 					},
-					Status: v1alpha1.AWSConfigStatus{
-						AWS: v1alpha1.AWSConfigStatusAWS{
-							AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
-								v1alpha1.AWSConfigStatusAWSAvailabilityZone{
-									Name: "eu-west-1a",
-								},
-							},
+				},
+				EncrypterBackend: "kms",
+			},
+			ExpectedAZ:                     "eu-west-1a",
+			ExpectedEtcdVolumeName:         "test-cluster-etcd",
+			ExpectedInstanceType:           "m3.large",
+			ExpectedDockerVolumeEncryption: GuestInstanceAdapterVolumeEncryption{Encrypted: true},
+			ExpectedEtcdVolumeEncryption:   GuestInstanceAdapterVolumeEncryption{Encrypted: true},
+			ExpectedRootVolumeEncryption:   GuestInstanceAdapterVolumeEncryption{},
+		},
+		{
+			Description: "case 2 master with volumes encrypted with the default EBS key and legacy etcd volume",
+			Config: Config{
+				CustomObject: customObject,
+				StackState: StackState{
+					Masters: []StackStateMaster{
+						{
+							InstanceType:     "m3.large",
+							VolumeEncryption: key.VolumeEncryptionDefault,
 						},
 					},
 				},
+				EncrypterBackend: "vault",
+			},
+			ExpectedAZ:                     "eu-west-1a",
+			ExpectedEtcdVolumeName:         "test-cluster-etcd",
+			ExpectedInstanceType:           "m3.large",
+			ExpectedDockerVolumeEncryption: GuestInstanceAdapterVolumeEncryption{Encrypted: true},
+			ExpectedEtcdVolumeEncryption:   GuestInstanceAdapterVolumeEncryption{},
+			ExpectedRootVolumeEncryption:   GuestInstanceAdapterVolumeEncryption{Encrypted: true},
+		},
+		{
+			Description: "case 3 master with volumes encrypted with a customer managed key",
+			Config: Config{
+				CustomObject: customObject,
 				StackState: StackState{
-					MasterInstanceType: "m3.large",
+					MasterEtcdVolumeEncryption: "arn:aws:kms:eu-west-1:000000000000:key/etcd",
+					Masters: []StackStateMaster{
+						{
+							InstanceType:     "m3.large",
+							VolumeEncryption: "arn:aws:kms:eu-west-1:000000000000:key/master",
+						},
+					},
 				},
-				EncrypterBackend: "my-encrypter-backend",
+				EncrypterBackend: "vault",
 			},
-			ExpectedAZ:               "eu-west-1a",
-			ExpectedEtcdVolumeName:   "test-cluster-etcd",
-			ExpectedInstanceType:     "m3.large",
-			ExpectedEncrypterBackend: "my-encrypter-backend",
+			ExpectedAZ:                     "eu-west-1a",
+			ExpectedEtcdVolumeName:         "test-cluster-etcd",
+			ExpectedInstanceType:           "m3.large",
+			ExpectedDockerVolumeEncryption: GuestInstanceAdapterVolumeEncryption{Encrypted: true, KMSKeyID: "arn:aws:kms:eu-west-1:000000000000:key/master"},
+			ExpectedEtcdVolumeEncryption:   GuestInstanceAdapterVolumeEncryption{Encrypted: true, KMSKeyID: "arn:aws:kms:eu-west-1:000000000000:key/etcd"},
+			ExpectedRootVolumeEncryption:   GuestInstanceAdapterVolumeEncryption{Encrypted: true, KMSKeyID: "arn:aws:kms:eu-west-1:000000000000:key/master"},
 		},
 	}
 
@@ -65,20 +138,36 @@ func Test_Adapter_Instance_RegularFields(t *testing.T) {
 				t.Fatal("expected", nil, "got", err)
 			}
 
-			if a.Master.AZ != tc.ExpectedAZ {
-				t.Fatalf("unexpected a.Master.AZ, got %q, want %q", a.Master.AZ, tc.ExpectedAZ)
+			if len(a.Masters) != 1 {
+				t.Fatalf("unexpected number of masters, got %d, want %d", len(a.Masters), 1)
 			}
 
-			if a.Master.EtcdVolume.Name != tc.ExpectedEtcdVolumeName {
-				t.Fatalf("unexpected a.Master.EtcdVolume.Name, got %q, want %q", a.Master.EtcdVolume.Name, tc.ExpectedEtcdVolumeName)
+			if a.Masters[0].AZ != tc.ExpectedAZ {
+				t.Fatalf("unexpected a.Masters[0].AZ, got %q, want %q", a.Masters[0].AZ, tc.ExpectedAZ)
 			}
 
-			if a.Master.Instance.Type != tc.ExpectedInstanceType {
-				t.Fatalf("unexpected a.Master.Instance.Type, got %q, want %q", a.Master.Instance.Type, tc.ExpectedInstanceType)
+			if a.Masters[0].EtcdVolume.Name != tc.ExpectedEtcdVolumeName {
+				t.Fatalf("unexpected a.Masters[0].EtcdVolume.Name, got %q, want %q", a.Masters[0].EtcdVolume.Name, tc.ExpectedEtcdVolumeName)
 			}
 
-			if a.Master.EncrypterBackend != tc.ExpectedEncrypterBackend {
-				t.Fatalf("unexpected a.Master.Instance.Type, got %q, want %q", a.Master.EncrypterBackend, tc.ExpectedEncrypterBackend)
+			if a.Masters[0].Instance.Type != tc.ExpectedInstanceType {
+				t.Fatalf("unexpected a.Masters[0].Instance.Type, got %q, want %q", a.Masters[0].Instance.Type, tc.ExpectedInstanceType)
+			}
+
+			if a.Masters[0].DockerVolume.Encryption != tc.ExpectedDockerVolumeEncryption {
+				t.Fatalf("unexpected a.Masters[0].DockerVolume.Encryption, got %#v, want %#v", a.Masters[0].DockerVolume.Encryption, tc.ExpectedDockerVolumeEncryption)
+			}
+
+			if a.Masters[0].LogVolume.Encryption != tc.ExpectedDockerVolumeEncryption {
+				t.Fatalf("unexpected a.Masters[0].LogVolume.Encryption, got %#v, want %#v", a.Masters[0].LogVolume.Encryption, tc.ExpectedDockerVolumeEncryption)
+			}
+
+			if a.Masters[0].EtcdVolume.Encryption != tc.ExpectedEtcdVolumeEncryption {
+				t.Fatalf("unexpected a.Masters[0].EtcdVolume.Encryption, got %#v, want %#v", a.Masters[0].EtcdVolume.Encryption, tc.ExpectedEtcdVolumeEncryption)
+			}
+
+			if a.Masters[0].RootVolume.Encryption != tc.ExpectedRootVolumeEncryption {
+				t.Fatalf("unexpected a.Masters[0].RootVolume.Encryption, got %#v, want %#v", a.Masters[0].RootVolume.Encryption, tc.ExpectedRootVolumeEncryption)
 			}
 		})
 	}
@@ -133,6 +222,11 @@ func Test_Adapter_Instance_SmallCloudConfig(t *testing.T) {
 				CustomObject: customObject,
 				StackState: StackState{
 					MasterCloudConfigVersion: "foo",
+					Masters: []StackStateMaster{
+						{
+							InstanceType: "m3.large",
+						},
+					},
 				},
 				TenantClusterAccountID: "000000000000",
 			}
@@ -143,9 +237,9 @@ func Test_Adapter_Instance_SmallCloudConfig(t *testing.T) {
 				t.Fatalf("unexpected error %v", err)
 			}
 
-			data, err := base64.StdEncoding.DecodeString(a.Master.CloudConfig)
+			data, err := base64.StdEncoding.DecodeString(a.Masters[0].CloudConfig)
 			if err != nil {
-				t.Fatalf("unexpected error decoding a.Master.CloudConfig %v", err)
+				t.Fatalf("unexpected error decoding a.Masters[0].CloudConfig %v", err)
 			}
 
 			if !strings.Contains(string(data), tc.ExpectedLine) {
@@ -154,3 +248,315 @@ func Test_Adapter_Instance_SmallCloudConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_Adapter_Instance_MultipleMasters(t *testing.T) {
+	t.Parallel()
+
+	customObject := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "test-cluster",
+			},
+			AWS: v1alpha1.AWSConfigSpecAWS{
+				Masters: []v1alpha1.AWSConfigSpecAWSNode{
+					{
+						InstanceType: "m4.large",
+					},
+					{
+						InstanceType: "m4.xlarge",
+					},
+					{},
+				},
+				Region: "eu-west-1",
+			},
+		},
+		Status: v1alpha1.AWSConfigStatus{
+			AWS: v1alpha1.AWSConfigStatusAWS{
+				AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+					{
+						Name: "eu-west-1b",
+						Subnet: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnet{
+							Private: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPrivate{
+								CIDR: "10.1.2.0/25",
+							},
+						},
+					},
+					{
+						Name: "eu-west-1a",
+						Subnet: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnet{
+							Private: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPrivate{
+								CIDR: "10.1.1.0/25",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		Description     string
+		Masters         []StackStateMaster
+		EtcdMemberCount int
+		ExpectedMaster  []GuestInstanceAdapterMaster
+		ErrorMatcher    func(error) bool
+	}{
+		{
+			Description: "case 0 three masters spread across two availability zones",
+			Masters: []StackStateMaster{
+				{
+					InstanceResourceName: "MasterInstance",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance01",
+					InstanceType:         "m4.xlarge",
+				},
+				{
+					InstanceResourceName: "MasterInstance02",
+					InstanceType:         "m4.large",
+				},
+			},
+			ExpectedMaster: []GuestInstanceAdapterMaster{
+				{
+					AZ:            "eu-west-1a",
+					EtcdVolume:    GuestInstanceAdapterMasterEtcdVolume{ResourceName: "EtcdVolume"},
+					Instance:      GuestInstanceAdapterMasterInstance{ResourceName: "MasterInstance", Type: "m3.large"},
+					PrivateSubnet: "PrivateSubnet",
+					NetworkInterface: &GuestInstanceAdapterMasterNetworkInterface{
+						PrivateIP:    "10.1.1.10",
+						ResourceName: "MasterNetworkInterface",
+					},
+				},
+				{
+					AZ:            "eu-west-1b",
+					EtcdVolume:    GuestInstanceAdapterMasterEtcdVolume{ResourceName: "EtcdVolume01"},
+					Instance:      GuestInstanceAdapterMasterInstance{ResourceName: "MasterInstance01", Type: "m4.xlarge"},
+					PrivateSubnet: "PrivateSubnet01",
+					NetworkInterface: &GuestInstanceAdapterMasterNetworkInterface{
+						PrivateIP:    "10.1.2.10",
+						ResourceName: "MasterNetworkInterface01",
+					},
+				},
+				{
+					AZ:            "eu-west-1a",
+					EtcdVolume:    GuestInstanceAdapterMasterEtcdVolume{ResourceName: "EtcdVolume02"},
+					Instance:      GuestInstanceAdapterMasterInstance{ResourceName: "MasterInstance02", Type: "m4.large"},
+					PrivateSubnet: "PrivateSubnet",
+					NetworkInterface: &GuestInstanceAdapterMasterNetworkInterface{
+						PrivateIP:    "10.1.1.11",
+						ResourceName: "MasterNetworkInterface02",
+					},
+				},
+			},
+			ErrorMatcher: nil,
+		},
+		{
+			Description: "case 1 even number of masters is rejected",
+			Masters: []StackStateMaster{
+				{
+					InstanceResourceName: "MasterInstance",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance01",
+					InstanceType:         "m3.large",
+				},
+			},
+			ExpectedMaster: nil,
+			ErrorMatcher:   IsInvalidConfig,
+		},
+		{
+			Description: "case 2 surge master of a rolling master update is accepted",
+			Masters: []StackStateMaster{
+				{
+					InstanceResourceName: "MasterInstance",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance01",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance02",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance03",
+					InstanceType:         "m4.large",
+				},
+			},
+			EtcdMemberCount: 3,
+			ExpectedMaster: []GuestInstanceAdapterMaster{
+				{
+					AZ:            "eu-west-1a",
+					EtcdVolume:    GuestInstanceAdapterMasterEtcdVolume{ResourceName: "EtcdVolume"},
+					Instance:      GuestInstanceAdapterMasterInstance{ResourceName: "MasterInstance", Type: "m3.large"},
+					PrivateSubnet: "PrivateSubnet",
+					NetworkInterface: &GuestInstanceAdapterMasterNetworkInterface{
+						PrivateIP:    "10.1.1.10",
+						ResourceName: "MasterNetworkInterface",
+					},
+				},
+				{
+					AZ:            "eu-west-1b",
+					EtcdVolume:    GuestInstanceAdapterMasterEtcdVolume{ResourceName: "EtcdVolume01"},
+					Instance:      GuestInstanceAdapterMasterInstance{ResourceName: "MasterInstance01", Type: "m3.large"},
+					PrivateSubnet: "PrivateSubnet01",
+					NetworkInterface: &GuestInstanceAdapterMasterNetworkInterface{
+						PrivateIP:    "10.1.2.10",
+						ResourceName: "MasterNetworkInterface01",
+					},
+				},
+				{
+					AZ:            "eu-west-1a",
+					EtcdVolume:    GuestInstanceAdapterMasterEtcdVolume{ResourceName: "EtcdVolume02"},
+					Instance:      GuestInstanceAdapterMasterInstance{ResourceName: "MasterInstance02", Type: "m3.large"},
+					PrivateSubnet: "PrivateSubnet",
+					NetworkInterface: &GuestInstanceAdapterMasterNetworkInterface{
+						PrivateIP:    "10.1.1.11",
+						ResourceName: "MasterNetworkInterface02",
+					},
+				},
+				{
+					AZ:            "eu-west-1b",
+					EtcdVolume:    GuestInstanceAdapterMasterEtcdVolume{ResourceName: "EtcdVolume03"},
+					Instance:      GuestInstanceAdapterMasterInstance{ResourceName: "MasterInstance03", Type: "m4.large"},
+					PrivateSubnet: "PrivateSubnet01",
+					NetworkInterface: &GuestInstanceAdapterMasterNetworkInterface{
+						PrivateIP:    "10.1.2.11",
+						ResourceName: "MasterNetworkInterface03",
+					},
+				},
+			},
+			ErrorMatcher: nil,
+		},
+		{
+			Description: "case 3 four masters without surge master are rejected",
+			Masters: []StackStateMaster{
+				{
+					InstanceResourceName: "MasterInstance",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance01",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance02",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance03",
+					InstanceType:         "m4.large",
+				},
+			},
+			EtcdMemberCount: 0,
+			ExpectedMaster:  nil,
+			ErrorMatcher:    IsInvalidConfig,
+		},
+		{
+			Description: "case 4 even etcd member count is rejected",
+			Masters: []StackStateMaster{
+				{
+					InstanceResourceName: "MasterInstance",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance01",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance02",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance03",
+					InstanceType:         "m4.large",
+				},
+			},
+			EtcdMemberCount: 4,
+			ExpectedMaster:  nil,
+			ErrorMatcher:    IsInvalidConfig,
+		},
+		{
+			Description: "case 5 surge master of a single master is rejected",
+			Masters: []StackStateMaster{
+				{
+					InstanceResourceName: "MasterInstance",
+					InstanceType:         "m3.large",
+				},
+				{
+					InstanceResourceName: "MasterInstance01",
+					InstanceType:         "m3.large",
+				},
+			},
+			EtcdMemberCount: 1,
+			ExpectedMaster:  nil,
+			ErrorMatcher:    IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			cfg := Config{
+				CustomObject: customObject,
+				StackState: StackState{
+					Masters:               tc.Masters,
+					MasterEtcdMemberCount: tc.EtcdMemberCount,
+				},
+			}
+
+			a := &GuestInstanceAdapter{}
+			err := a.Adapt(cfg)
+
+			switch {
+			case err == nil && tc.ErrorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.ErrorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.ErrorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.ErrorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.ErrorMatcher != nil {
+				return
+			}
+
+			if len(a.Masters) != len(tc.ExpectedMaster) {
+				t.Fatalf("unexpected number of masters, got %d, want %d", len(a.Masters), len(tc.ExpectedMaster))
+			}
+
+			for i, e := range tc.ExpectedMaster {
+				m := a.Masters[i]
+
+				if m.Index != i {
+					t.Fatalf("unexpected a.Masters[%d].Index, got %d, want %d", i, m.Index, i)
+				}
+				if m.AZ != e.AZ {
+					t.Fatalf("unexpected a.Masters[%d].AZ, got %q, want %q", i, m.AZ, e.AZ)
+				}
+				if m.EtcdVolume.ResourceName != e.EtcdVolume.ResourceName {
+					t.Fatalf("unexpected a.Masters[%d].EtcdVolume.ResourceName, got %q, want %q", i, m.EtcdVolume.ResourceName, e.EtcdVolume.ResourceName)
+				}
+				if m.Instance.ResourceName != e.Instance.ResourceName {
+					t.Fatalf("unexpected a.Masters[%d].Instance.ResourceName, got %q, want %q", i, m.Instance.ResourceName, e.Instance.ResourceName)
+				}
+				if m.Instance.Type != e.Instance.Type {
+					t.Fatalf("unexpected a.Masters[%d].Instance.Type, got %q, want %q", i, m.Instance.Type, e.Instance.Type)
+				}
+				if m.PrivateSubnet != e.PrivateSubnet {
+					t.Fatalf("unexpected a.Masters[%d].PrivateSubnet, got %q, want %q", i, m.PrivateSubnet, e.PrivateSubnet)
+				}
+				if m.NetworkInterface == nil {
+					t.Fatalf("unexpected a.Masters[%d].NetworkInterface, got nil", i)
+				}
+				if *m.NetworkInterface != *e.NetworkInterface {
+					t.Fatalf("unexpected a.Masters[%d].NetworkInterface, got %#v, want %#v", i, *m.NetworkInterface, *e.NetworkInterface)
+				}
+			}
+		})
+	}
+}
@@ -1,16 +1,27 @@
 package adapter
 
 import (
+	"github.com/giantswarm/microerror"
+
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 type GuestInternetGatewayAdapter struct {
 	ClusterID          string
+	ExistingVPC        bool
+	IPv6               bool
 	PrivateRouteTables []string
 }
 
 func (a *GuestInternetGatewayAdapter) Adapt(cfg Config) error {
+	ipv6, err := key.IPv6(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
 	a.ClusterID = key.ClusterID(cfg.CustomObject)
+	a.ExistingVPC = key.IsExistingVPC(cfg.CustomObject)
+	a.IPv6 = ipv6
 
 	for i := 0; i < len(key.StatusAvailabilityZones(cfg.CustomObject)); i++ {
 		a.PrivateRouteTables = append(a.PrivateRouteTables, key.PrivateRouteTableName(i))
@@ -0,0 +1,196 @@
+package adapter
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+	"github.com/giantswarm/aws-operator/service/controller/v25/templates"
+)
+
+type GuestLaunchTemplateAdapter struct {
+	WorkerAssociatePublicIPAddress bool
+	WorkerInstanceMonitoring       bool
+	WorkerImageID                  string
+	WorkerSecurityGroupID          string
+	// WorkerPools holds the launch templates of all worker pools. The
+	// first worker pool is always the default worker pool defined by the CR
+	// spec.
+	WorkerPools []GuestLaunchTemplateAdapterWorkerPool
+}
+
+type GuestLaunchTemplateAdapterWorkerPool struct {
+	ASGType             string
+	BlockDeviceMappings []BlockDeviceMapping
+	InstanceType        string
+	SmallCloudConfig    string
+}
+
+// BlockDeviceMapping defines an EBS volume of the workers. The volume size of
+// the root volume is empty, so that it keeps the size of the image.
+type BlockDeviceMapping struct {
+	DeleteOnTermination bool
+	DeviceName          string
+	Encryption          GuestInstanceAdapterVolumeEncryption
+	VolumeSize          string
+	VolumeType          string
+}
+
+func (l *GuestLaunchTemplateAdapter) Adapt(config Config) error {
+	l.WorkerImageID = config.StackState.WorkerImageID
+	l.WorkerAssociatePublicIPAddress = false
+
+	{
+		cur := config.StackState.WorkerDockerVolumeSizeGB
+		def := defaultEBSVolumeSize
+
+		if cur == "" {
+			cur = "0"
+		}
+
+		curi, err := strconv.Atoi(cur)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if curi <= 0 {
+			config.StackState.WorkerDockerVolumeSizeGB = def
+		}
+	}
+
+	{
+		cur := config.StackState.WorkerLogVolumeSizeGB
+		def := defaultEBSVolumeSize
+
+		if cur == "" {
+			cur = "0"
+		}
+
+		curi, err := strconv.Atoi(cur)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if curi <= 0 {
+			config.StackState.WorkerLogVolumeSizeGB = def
+		}
+	}
+
+	{
+		cur := config.StackState.WorkerKubeletVolumeSizeGB
+		def := defaultEBSVolumeSize
+
+		if cur == "" {
+			cur = "0"
+		}
+
+		curi, err := strconv.Atoi(cur)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if curi <= 0 {
+			config.StackState.WorkerKubeletVolumeSizeGB = def
+		}
+	}
+
+	l.WorkerInstanceMonitoring = config.StackState.WorkerInstanceMonitoring
+
+	// Worker volumes are always encrypted with the current volume encryption,
+	// since the workers are replaced whenever their launch template changes.
+	encryption := newVolumeEncryption(config.StackState.WorkerVolumeEncryption, true)
+
+	{
+		blockDeviceMappings := newWorkerBlockDeviceMappings(
+			encryption,
+			config.StackState.WorkerDockerVolumeSizeGB,
+			config.StackState.WorkerLogVolumeSizeGB,
+			config.StackState.WorkerKubeletVolumeSizeGB,
+		)
+
+		p, err := newWorkerPoolLaunchTemplate(config, "", key.WorkerInstanceType(config.CustomObject), blockDeviceMappings)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		l.WorkerPools = append(l.WorkerPools, p)
+	}
+
+	pools, err := key.WorkerPools(config.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	for _, pool := range pools {
+		blockDeviceMappings := newWorkerBlockDeviceMappings(
+			encryption,
+			key.WorkerPoolDockerVolumeSizeGB(pool),
+			defaultEBSVolumeSize,
+			key.WorkerPoolDockerVolumeSizeGB(pool),
+		)
+
+		p, err := newWorkerPoolLaunchTemplate(config, pool.Name, pool.InstanceType, blockDeviceMappings)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		l.WorkerPools = append(l.WorkerPools, p)
+	}
+
+	return nil
+}
+
+func newWorkerBlockDeviceMappings(encryption GuestInstanceAdapterVolumeEncryption, dockerVolumeSizeGB, logVolumeSizeGB, kubeletVolumeSizeGB string) []BlockDeviceMapping {
+	return []BlockDeviceMapping{
+		{
+			DeleteOnTermination: true,
+			DeviceName:          rootEBSVolumeMountPoint,
+			Encryption:          encryption,
+			VolumeType:          defaultEBSVolumeType,
+		},
+		{
+			DeleteOnTermination: true,
+			DeviceName:          defaultEBSVolumeMountPoint,
+			Encryption:          encryption,
+			VolumeSize:          dockerVolumeSizeGB,
+			VolumeType:          defaultEBSVolumeType,
+		},
+		{
+			DeleteOnTermination: true,
+			DeviceName:          logEBSVolumeMountPoint,
+			Encryption:          encryption,
+			VolumeSize:          logVolumeSizeGB,
+			VolumeType:          defaultEBSVolumeType,
+		},
+		{
+			// TL;DR; kubelet volume same size as docker volume
+			// this is a temporary solution that should stay around until Node Pools story is ready.
+			// See here for furhter info https://github.com/giantswarm/giantswarm/issues/5582#issuecomment-476170597
+			DeleteOnTermination: true,
+			DeviceName:          kubeletEBSVolumeMountPoint,
+			Encryption:          encryption,
+			VolumeSize:          kubeletVolumeSizeGB,
+			VolumeType:          defaultEBSVolumeType,
+		},
+	}
+}
+
+func newWorkerPoolLaunchTemplate(config Config, name string, instanceType string, blockDeviceMappings []BlockDeviceMapping) (GuestLaunchTemplateAdapterWorkerPool, error) {
+	// small cloud config field.
+	c := SmallCloudconfigConfig{
+		InstanceRole: key.KindWorker,
+		S3URL:        key.SmallCloudConfigS3URL(config.CustomObject, config.TenantClusterAccountID, key.WorkerPoolRole(name)),
+	}
+	rendered, err := templates.Render(key.CloudConfigSmallTemplates(), c)
+	if err != nil {
+		return GuestLaunchTemplateAdapterWorkerPool{}, microerror.Mask(err)
+	}
+
+	p := GuestLaunchTemplateAdapterWorkerPool{
+		ASGType:             key.WorkerPoolASGType(name),
+		BlockDeviceMappings: blockDeviceMappings,
+		InstanceType:        instanceType,
+		SmallCloudConfig:    base64.StdEncoding.EncodeToString([]byte(rendered)),
+	}
+
+	return p, nil
+}
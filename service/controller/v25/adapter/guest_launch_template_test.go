@@ -12,7 +12,7 @@ import (
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
-func Test_AdapterLaunchConfiguration_RegularFields(t *testing.T) {
+func Test_AdapterLaunchTemplate_RegularFields(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
 		description                      string
@@ -42,21 +42,30 @@ func Test_AdapterLaunchConfiguration_RegularFields(t *testing.T) {
 			expectedInstanceType:             "myinstancetype",
 			expectedAssociatePublicIPAddress: false,
 			expectedBlockDeviceMappings: []BlockDeviceMapping{
+				{
+					DeleteOnTermination: true,
+					DeviceName:          rootEBSVolumeMountPoint,
+					Encryption:          GuestInstanceAdapterVolumeEncryption{Encrypted: true},
+					VolumeType:          defaultEBSVolumeType,
+				},
 				{
 					DeleteOnTermination: true,
 					DeviceName:          defaultEBSVolumeMountPoint,
+					Encryption:          GuestInstanceAdapterVolumeEncryption{Encrypted: true},
 					VolumeSize:          "250",
 					VolumeType:          defaultEBSVolumeType,
 				},
 				{
 					DeleteOnTermination: true,
 					DeviceName:          logEBSVolumeMountPoint,
+					Encryption:          GuestInstanceAdapterVolumeEncryption{Encrypted: true},
 					VolumeSize:          defaultEBSVolumeSize,
 					VolumeType:          defaultEBSVolumeType,
 				},
 				{
 					DeleteOnTermination: true,
 					DeviceName:          kubeletEBSVolumeMountPoint,
+					Encryption:          GuestInstanceAdapterVolumeEncryption{Encrypted: true},
 					VolumeSize:          "250",
 					VolumeType:          defaultEBSVolumeType,
 				},
@@ -81,21 +90,30 @@ func Test_AdapterLaunchConfiguration_RegularFields(t *testing.T) {
 			expectedInstanceType:             "myinstancetype",
 			expectedAssociatePublicIPAddress: false,
 			expectedBlockDeviceMappings: []BlockDeviceMapping{
+				{
+					DeleteOnTermination: true,
+					DeviceName:          rootEBSVolumeMountPoint,
+					Encryption:          GuestInstanceAdapterVolumeEncryption{Encrypted: true},
+					VolumeType:          defaultEBSVolumeType,
+				},
 				{
 					DeleteOnTermination: true,
 					DeviceName:          defaultEBSVolumeMountPoint,
+					Encryption:          GuestInstanceAdapterVolumeEncryption{Encrypted: true},
 					VolumeSize:          defaultEBSVolumeSize,
 					VolumeType:          defaultEBSVolumeType,
 				},
 				{
 					DeleteOnTermination: true,
 					DeviceName:          logEBSVolumeMountPoint,
+					Encryption:          GuestInstanceAdapterVolumeEncryption{Encrypted: true},
 					VolumeSize:          defaultEBSVolumeSize,
 					VolumeType:          defaultEBSVolumeType,
 				},
 				{
 					DeleteOnTermination: true,
 					DeviceName:          kubeletEBSVolumeMountPoint,
+					Encryption:          GuestInstanceAdapterVolumeEncryption{Encrypted: true},
 					VolumeSize:          defaultEBSVolumeSize,
 					VolumeType:          defaultEBSVolumeType,
 				},
@@ -113,7 +131,7 @@ func Test_AdapterLaunchConfiguration_RegularFields(t *testing.T) {
 					WorkerKubeletVolumeSizeGB: key.WorkerDockerVolumeSizeGB(tc.customObject),
 				},
 			}
-			err := a.Guest.LaunchConfiguration.Adapt(cfg)
+			err := a.Guest.LaunchTemplate.Adapt(cfg)
 			if tc.expectedError && err == nil {
 				t.Error("expected error didn't happen")
 			}
@@ -122,23 +140,23 @@ func Test_AdapterLaunchConfiguration_RegularFields(t *testing.T) {
 				t.Errorf("unexpected error %v", err)
 			}
 
-			if a.Guest.LaunchConfiguration.ASGType != key.KindWorker {
-				t.Errorf("unexpected ASGType, got %q, want %q", a.Guest.LaunchConfiguration.ASGType, key.KindWorker)
+			if a.Guest.LaunchTemplate.WorkerPools[0].ASGType != key.KindWorker {
+				t.Errorf("unexpected ASGType, got %q, want %q", a.Guest.LaunchTemplate.WorkerPools[0].ASGType, key.KindWorker)
 			}
-			if a.Guest.LaunchConfiguration.WorkerInstanceType != tc.expectedInstanceType {
-				t.Errorf("unexpected InstanceType, got %q, want %q", a.Guest.LaunchConfiguration.WorkerInstanceType, tc.expectedInstanceType)
+			if a.Guest.LaunchTemplate.WorkerPools[0].InstanceType != tc.expectedInstanceType {
+				t.Errorf("unexpected InstanceType, got %q, want %q", a.Guest.LaunchTemplate.WorkerPools[0].InstanceType, tc.expectedInstanceType)
 			}
-			if a.Guest.LaunchConfiguration.WorkerAssociatePublicIPAddress != tc.expectedAssociatePublicIPAddress {
-				t.Errorf("unexpected WorkerAssociatePublicIPAddress, got %t, want %t", a.Guest.LaunchConfiguration.WorkerAssociatePublicIPAddress, tc.expectedAssociatePublicIPAddress)
+			if a.Guest.LaunchTemplate.WorkerAssociatePublicIPAddress != tc.expectedAssociatePublicIPAddress {
+				t.Errorf("unexpected WorkerAssociatePublicIPAddress, got %t, want %t", a.Guest.LaunchTemplate.WorkerAssociatePublicIPAddress, tc.expectedAssociatePublicIPAddress)
 			}
-			if !reflect.DeepEqual(a.Guest.LaunchConfiguration.WorkerBlockDeviceMappings, tc.expectedBlockDeviceMappings) {
-				t.Errorf("unexpected BlockDeviceMappings, got %v, want %v", a.Guest.LaunchConfiguration.WorkerBlockDeviceMappings, tc.expectedBlockDeviceMappings)
+			if !reflect.DeepEqual(a.Guest.LaunchTemplate.WorkerPools[0].BlockDeviceMappings, tc.expectedBlockDeviceMappings) {
+				t.Errorf("unexpected BlockDeviceMappings, got %v, want %v", a.Guest.LaunchTemplate.WorkerPools[0].BlockDeviceMappings, tc.expectedBlockDeviceMappings)
 			}
 		})
 	}
 }
 
-func Test_AdapterLaunchConfiguration_SmallCloudConfig(t *testing.T) {
+func Test_AdapterLaunchTemplate_SmallCloudConfig(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
 		description  string
@@ -174,13 +192,13 @@ func Test_AdapterLaunchConfiguration_SmallCloudConfig(t *testing.T) {
 		CustomObject:           customObject,
 		TenantClusterAccountID: "000000000000",
 	}
-	err := a.Guest.LaunchConfiguration.Adapt(cfg)
+	err := a.Guest.LaunchTemplate.Adapt(cfg)
 
 	if err != nil {
 		t.Errorf("unexpected error %v", err)
 	}
 
-	data, err := base64.StdEncoding.DecodeString(a.Guest.LaunchConfiguration.WorkerSmallCloudConfig)
+	data, err := base64.StdEncoding.DecodeString(a.Guest.LaunchTemplate.WorkerPools[0].SmallCloudConfig)
 	if err != nil {
 		t.Errorf("unexpected error decoding SmallCloudConfig %v", err)
 	}
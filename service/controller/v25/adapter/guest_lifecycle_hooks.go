@@ -1,9 +1,15 @@
 package adapter
 
-import "github.com/giantswarm/aws-operator/service/controller/v25/key"
+import (
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
 
 type GuestLifecycleHooksAdapter struct {
-	Worker GuestLifecycleHooksAdapterWorker
+	// Workers holds the lifecycle hooks of all worker pools. The first worker
+	// pool is always the default worker pool defined by the CR spec.
+	Workers []GuestLifecycleHooksAdapterWorker
 }
 
 type GuestLifecycleHooksAdapterWorker struct {
@@ -16,12 +22,34 @@ type GuestLifecycleHooksAdapterASG struct {
 }
 
 type GuestLifecycleHooksAdapterLifecycleHook struct {
-	Name string
+	Name         string
+	ResourceName string
 }
 
 func (a *GuestLifecycleHooksAdapter) Adapt(config Config) error {
-	a.Worker.ASG.Ref = key.WorkerASGRef
-	a.Worker.LifecycleHook.Name = key.NodeDrainerLifecycleHookName
+	a.Workers = append(a.Workers, newWorkerPoolLifecycleHook(""))
+
+	pools, err := key.WorkerPools(config.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	for _, p := range pools {
+		a.Workers = append(a.Workers, newWorkerPoolLifecycleHook(p.Name))
+	}
 
 	return nil
 }
+
+func newWorkerPoolLifecycleHook(name string) GuestLifecycleHooksAdapterWorker {
+	w := GuestLifecycleHooksAdapterWorker{
+		ASG: GuestLifecycleHooksAdapterASG{
+			Ref: key.WorkerPoolASGRef(name),
+		},
+		LifecycleHook: GuestLifecycleHooksAdapterLifecycleHook{
+			Name:         key.NodeDrainerLifecycleHookName,
+			ResourceName: key.WorkerPoolLifecycleHookResourceName(name),
+		},
+	}
+
+	return w
+}
@@ -14,6 +14,18 @@ const (
 	healthCheckInterval           = 5
 	healthCheckTimeout            = 3
 	healthCheckUnhealthyThreshold = 2
+
+	// networkHealthCheckInterval is the health check interval of NLB target
+	// groups, which only support 10 or 30 seconds.
+	networkHealthCheckInterval = 10
+)
+
+const (
+	// Logical ID prefixes of the NLB listeners and target groups.
+	apiResourceName          = "Api"
+	etcdResourceName         = "Etcd"
+	ingressHTTPResourceName  = "IngressHTTP"
+	ingressHTTPSResourceName = "IngressHTTPS"
 )
 
 type GuestLoadBalancersAdapter struct {
@@ -22,6 +34,7 @@ type GuestLoadBalancersAdapter struct {
 	APIElbPortsToOpen                []GuestLoadBalancersAdapterPortPair
 	APIElbScheme                     string
 	APIElbSecurityGroupID            string
+	APIElbSubnets                    []string
 	EtcdElbHealthCheckTarget         string
 	EtcdElbName                      string
 	EtcdElbPortsToOpen               []GuestLoadBalancersAdapterPortPair
@@ -31,11 +44,14 @@ type GuestLoadBalancersAdapter struct {
 	ELBHealthCheckInterval           int
 	ELBHealthCheckTimeout            int
 	ELBHealthCheckUnhealthyThreshold int
+	ExistingVPC                      bool
 	IngressElbHealthCheckTarget      string
 	IngressElbName                   string
 	IngressElbPortsToOpen            []GuestLoadBalancersAdapterPortPair
 	IngressElbScheme                 string
-	MasterInstanceResourceName       string
+	MasterInstanceResourceNames      []string
+	NetworkLoadBalancersEnabled      bool
+	NLBHealthCheckInterval           int
 	PublicSubnets                    []string
 	PrivateSubnets                   []string
 }
@@ -48,6 +64,13 @@ func (a *GuestLoadBalancersAdapter) Adapt(cfg Config) error {
 		}
 	}
 
+	lbType, err := key.LoadBalancerType(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	a.NetworkLoadBalancersEnabled = lbType == key.LoadBalancerTypeNetwork
+	a.ExistingVPC = key.IsExistingVPC(cfg.CustomObject)
+
 	// API load balancer settings.
 	apiElbName, err := key.LoadBalancerName(cfg.CustomObject.Spec.Cluster.Kubernetes.API.Domain, cfg.CustomObject)
 	if err != nil {
@@ -60,9 +83,22 @@ func (a *GuestLoadBalancersAdapter) Adapt(cfg Config) error {
 		{
 			PortELB:      key.KubernetesAPISecurePort(cfg.CustomObject),
 			PortInstance: key.KubernetesAPISecurePort(cfg.CustomObject),
+			ResourceName: apiResourceName,
 		},
 	}
-	a.APIElbScheme = externalELBScheme
+
+	privateAPI, err := key.PrivateAPI(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// The API load balancer of tenant clusters with a private API is internal
+	// and only reachable from within the VPC and the networks connected to it.
+	if privateAPI {
+		a.APIElbScheme = internalELBScheme
+	} else {
+		a.APIElbScheme = externalELBScheme
+	}
 
 	// etcd load balancer settings.
 	etcdElbName, err := key.LoadBalancerName(key.EtcdDomain(cfg.CustomObject), cfg.CustomObject)
@@ -76,6 +112,7 @@ func (a *GuestLoadBalancersAdapter) Adapt(cfg Config) error {
 		{
 			PortELB:      key.EtcdPort(cfg.CustomObject),
 			PortInstance: key.EtcdPort(cfg.CustomObject),
+			ResourceName: etcdResourceName,
 		},
 	}
 	a.EtcdElbScheme = internalELBScheme
@@ -90,13 +127,14 @@ func (a *GuestLoadBalancersAdapter) Adapt(cfg Config) error {
 	a.IngressElbName = ingressElbName
 	a.IngressElbPortsToOpen = []GuestLoadBalancersAdapterPortPair{
 		{
-			PortELB: httpsPort,
-
+			PortELB:      httpsPort,
 			PortInstance: key.IngressControllerSecurePort(cfg.CustomObject),
+			ResourceName: ingressHTTPSResourceName,
 		},
 		{
 			PortELB:      httpPort,
 			PortInstance: key.IngressControllerInsecurePort(cfg.CustomObject),
+			ResourceName: ingressHTTPResourceName,
 		},
 	}
 	a.IngressElbScheme = externalELBScheme
@@ -106,13 +144,23 @@ func (a *GuestLoadBalancersAdapter) Adapt(cfg Config) error {
 	a.ELBHealthCheckInterval = healthCheckInterval
 	a.ELBHealthCheckTimeout = healthCheckTimeout
 	a.ELBHealthCheckUnhealthyThreshold = healthCheckUnhealthyThreshold
-	a.MasterInstanceResourceName = cfg.StackState.MasterInstanceResourceName
+	a.NLBHealthCheckInterval = networkHealthCheckInterval
+
+	for _, m := range cfg.StackState.Masters {
+		a.MasterInstanceResourceNames = append(a.MasterInstanceResourceNames, m.InstanceResourceName)
+	}
 
 	for i := 0; i < len(key.StatusAvailabilityZones(cfg.CustomObject)); i++ {
 		a.PublicSubnets = append(a.PublicSubnets, key.PublicSubnetName(i))
 		a.PrivateSubnets = append(a.PrivateSubnets, key.PrivateSubnetName(i))
 	}
 
+	if privateAPI {
+		a.APIElbSubnets = a.PrivateSubnets
+	} else {
+		a.APIElbSubnets = a.PublicSubnets
+	}
+
 	return nil
 }
 
@@ -121,8 +169,20 @@ type GuestLoadBalancersAdapterPortPair struct {
 	PortELB int
 	// PortInstance is the port on the instance the ELB forwards traffic to.
 	PortInstance int
+	// ResourceName is the logical ID prefix of the NLB listener and target
+	// group of the port pair.
+	ResourceName string
 }
 
 func heathCheckTarget(port int) string {
 	return fmt.Sprintf("TCP:%d", port)
 }
+
+// ingressTargetGroupResourceNames returns the logical IDs of the NLB target
+// groups the worker ASGs register their instances with.
+func ingressTargetGroupResourceNames() []string {
+	return []string{
+		ingressHTTPSResourceName + "TargetGroup",
+		ingressHTTPResourceName + "TargetGroup",
+	}
+}
@@ -96,6 +96,7 @@ func TestAdapterLoadBalancersRegularFields(t *testing.T) {
 				{
 					PortELB:      443,
 					PortInstance: 443,
+					ResourceName: "Api",
 				},
 			},
 			expectedAPIElbScheme: "internet-facing",
@@ -104,6 +105,7 @@ func TestAdapterLoadBalancersRegularFields(t *testing.T) {
 				{
 					PortELB:      2379,
 					PortInstance: 2379,
+					ResourceName: "Etcd",
 				},
 			},
 			expectedEtcdElbScheme:                    "internal",
@@ -117,10 +119,12 @@ func TestAdapterLoadBalancersRegularFields(t *testing.T) {
 				{
 					PortELB:      443,
 					PortInstance: 30011,
+					ResourceName: "IngressHTTPS",
 				},
 				{
 					PortELB:      80,
 					PortInstance: 30010,
+					ResourceName: "IngressHTTP",
 				},
 			},
 			expectedIngressElbScheme: "internet-facing",
@@ -198,3 +202,74 @@ func TestAdapterLoadBalancersRegularFields(t *testing.T) {
 		})
 	}
 }
+
+func TestAdapterLoadBalancersPrivateAPI(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description           string
+		annotations           map[string]string
+		expectedAPIElbScheme  string
+		expectedAPIElbSubnets []string
+	}{
+		{
+			description:           "public API",
+			annotations:           nil,
+			expectedAPIElbScheme:  "internet-facing",
+			expectedAPIElbSubnets: []string{"PublicSubnet"},
+		},
+		{
+			description: "private API",
+			annotations: map[string]string{
+				key.PrivateAPIAnnotation: "true",
+			},
+			expectedAPIElbScheme:  "internal",
+			expectedAPIElbSubnets: []string{"PrivateSubnet"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{
+				Spec: v1alpha1.AWSConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+						Etcd: v1alpha1.ClusterEtcd{
+							Domain: "etcd.test-cluster.aws.giantswarm.io",
+						},
+						Kubernetes: v1alpha1.ClusterKubernetes{
+							API: v1alpha1.ClusterKubernetesAPI{
+								Domain: "api.test-cluster.aws.giantswarm.io",
+							},
+							IngressController: v1alpha1.ClusterKubernetesIngressController{
+								Domain: "ingress.test-cluster.aws.giantswarm.io",
+							},
+						},
+					},
+				},
+				Status: v1alpha1.AWSConfigStatus{
+					AWS: v1alpha1.AWSConfigStatusAWS{
+						AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+							{
+								Name: "eu-central-1a",
+							},
+						},
+					},
+				},
+			}
+			customObject.SetAnnotations(tc.annotations)
+
+			a := Adapter{}
+			err := a.Guest.LoadBalancers.Adapt(Config{CustomObject: customObject})
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if a.Guest.LoadBalancers.APIElbScheme != tc.expectedAPIElbScheme {
+				t.Fatalf("APIElbScheme == %q, want %q", a.Guest.LoadBalancers.APIElbScheme, tc.expectedAPIElbScheme)
+			}
+			if !reflect.DeepEqual(a.Guest.LoadBalancers.APIElbSubnets, tc.expectedAPIElbSubnets) {
+				t.Fatalf("APIElbSubnets == %#v, want %#v", a.Guest.LoadBalancers.APIElbSubnets, tc.expectedAPIElbSubnets)
+			}
+		})
+	}
+}
@@ -18,6 +18,12 @@ type GuestNATGatewayAdapter struct {
 }
 
 func (a *GuestNATGatewayAdapter) Adapt(cfg Config) error {
+	// Existing VPCs come with their own egress setup, so there are no NAT
+	// gateways to manage.
+	if key.IsExistingVPC(cfg.CustomObject) {
+		return nil
+	}
+
 	for i := 0; i < len(key.StatusAvailabilityZones(cfg.CustomObject)); i++ {
 		gw := Gateway{
 			ClusterID:             key.ClusterID(cfg.CustomObject),
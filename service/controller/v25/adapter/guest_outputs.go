@@ -1,23 +1,67 @@
 package adapter
 
 import (
+	"strings"
+
+	"github.com/giantswarm/microerror"
+
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 type GuestOutputsAdapter struct {
-	Master         GuestOutputsAdapterMaster
-	Worker         GuestOutputsAdapterWorker
-	Route53Enabled bool
-	VersionBundle  GuestOutputsAdapterVersionBundle
+	EncryptionKeyRotation string
+	ExistingVPC           bool
+	Master                GuestOutputsAdapterMaster
+	Worker                GuestOutputsAdapterWorker
+	Route53Enabled        bool
+	// TransitGatewayEnabled is true in case the VPC is attached to a transit
+	// gateway instead of being peered with the control plane VPC.
+	TransitGatewayEnabled bool
+	VersionBundle         GuestOutputsAdapterVersionBundle
 }
 
 func (a *GuestOutputsAdapter) Adapt(config Config) error {
+	a.EncryptionKeyRotation = config.StackState.EncryptionKeyRotation
+	a.ExistingVPC = key.IsExistingVPC(config.CustomObject)
 	a.Route53Enabled = config.Route53Enabled
-	a.Master.DockerVolume.ResourceName = config.StackState.DockerVolumeResourceName
-	a.Master.ImageID = config.StackState.MasterImageID
-	a.Master.Instance.ResourceName = config.StackState.MasterInstanceResourceName
-	a.Master.Instance.Type = config.StackState.MasterInstanceType
+	a.TransitGatewayEnabled = config.TransitGatewayID != ""
+
+	masters := config.StackState.Masters
+	a.Master.Count = len(masters)
+	a.Master.EtcdMemberCount = key.StackEtcdMemberCount(config.StackState.MasterEtcdMemberCount, len(masters))
 	a.Master.CloudConfig.Version = config.StackState.MasterCloudConfigVersion
+	a.Master.EtcdRestoreSnapshot = config.StackState.MasterEtcdRestoreSnapshot
+	a.Master.EtcdVolumeEncryption = config.StackState.MasterEtcdVolumeEncryption
+	for idx, m := range masters {
+		i := GuestOutputsAdapterMasterInstance{
+			DockerVolumeResourceName: GuestOutputsAdapterOutput{
+				Key:   key.MasterOutputKey(key.DockerVolumeResourceNameKey, idx),
+				Value: m.DockerVolumeResourceName,
+			},
+			ImageID: GuestOutputsAdapterOutput{
+				Key:   key.MasterOutputKey(key.MasterImageIDKey, idx),
+				Value: m.ImageID,
+			},
+			ResourceName: GuestOutputsAdapterOutput{
+				Key:   key.MasterOutputKey(key.MasterInstanceResourceNameKey, idx),
+				Value: m.InstanceResourceName,
+			},
+			Type: GuestOutputsAdapterOutput{
+				Key:   key.MasterOutputKey(key.MasterInstanceTypeKey, idx),
+				Value: m.InstanceType,
+			},
+			VersionBundleVersion: GuestOutputsAdapterOutput{
+				Key:   key.MasterOutputKey(key.MasterVersionBundleVersionKey, idx),
+				Value: m.VersionBundleVersion,
+			},
+			VolumeEncryption: GuestOutputsAdapterOutput{
+				Key:   key.MasterOutputKey(key.MasterVolumeEncryptionKey, idx),
+				Value: m.VolumeEncryption,
+			},
+		}
+
+		a.Master.Instances = append(a.Master.Instances, i)
+	}
 
 	a.Worker.ASG.Ref = key.WorkerASGRef
 	a.Worker.CloudConfig.Version = config.StackState.WorkerCloudConfigVersion
@@ -25,29 +69,79 @@ func (a *GuestOutputsAdapter) Adapt(config Config) error {
 	a.Worker.ImageID = config.StackState.WorkerImageID
 	a.Worker.InstanceType = config.StackState.WorkerInstanceType
 
+	{
+		d, err := key.WorkerInstanceDistribution(config.CustomObject)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		a.Worker.InstanceDistribution = key.InstanceDistributionString(d)
+	}
+
+	pools, err := key.WorkerPools(config.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	var poolNames []string
+	for _, p := range pools {
+		o := GuestOutputsAdapterWorkerPool{
+			ASGName: GuestOutputsAdapterOutput{
+				Key:   key.WorkerPoolOutputKey(key.WorkerASGNameKey, p.Name),
+				Value: key.WorkerPoolASGRef(p.Name),
+			},
+			DockerVolumeSizeGB: GuestOutputsAdapterOutput{
+				Key:   key.WorkerPoolOutputKey(key.WorkerDockerVolumeSizeKey, p.Name),
+				Value: key.WorkerPoolDockerVolumeSizeGB(p),
+			},
+			InstanceDistribution: GuestOutputsAdapterOutput{
+				Key:   key.WorkerPoolOutputKey(key.WorkerInstanceDistributionKey, p.Name),
+				Value: key.InstanceDistributionString(p.InstanceDistribution),
+			},
+			InstanceType: GuestOutputsAdapterOutput{
+				Key:   key.WorkerPoolOutputKey(key.WorkerInstanceTypeKey, p.Name),
+				Value: p.InstanceType,
+			},
+		}
+
+		a.Worker.Pools = append(a.Worker.Pools, o)
+		poolNames = append(poolNames, p.Name)
+	}
+	a.Worker.PoolNames = strings.Join(poolNames, ",")
+
 	a.VersionBundle.Version = config.StackState.VersionBundleVersion
 
 	return nil
 }
 
 type GuestOutputsAdapterMaster struct {
-	ImageID      string
-	Instance     GuestOutputsAdapterMasterInstance
-	CloudConfig  GuestOutputsAdapterMasterCloudConfig
-	DockerVolume GuestOutputsAdapterMasterDockerVolume
+	CloudConfig          GuestOutputsAdapterMasterCloudConfig
+	Count                int
+	EtcdMemberCount      int
+	EtcdRestoreSnapshot  string
+	EtcdVolumeEncryption string
+	Instances            []GuestOutputsAdapterMasterInstance
 }
 
+// GuestOutputsAdapterMasterInstance holds the outputs of a single master. The
+// output keys of the first master are not indexed, to stay compatible with
+// tenant clusters having a single master.
 type GuestOutputsAdapterMasterInstance struct {
-	ResourceName string
-	Type         string
+	DockerVolumeResourceName GuestOutputsAdapterOutput
+	ImageID                  GuestOutputsAdapterOutput
+	ResourceName             GuestOutputsAdapterOutput
+	Type                     GuestOutputsAdapterOutput
+	VersionBundleVersion     GuestOutputsAdapterOutput
+	// VolumeEncryption is only rendered for masters created with encrypted
+	// volumes, since output values must not be empty.
+	VolumeEncryption GuestOutputsAdapterOutput
 }
 
 type GuestOutputsAdapterMasterCloudConfig struct {
 	Version string
 }
 
-type GuestOutputsAdapterMasterDockerVolume struct {
-	ResourceName string
+type GuestOutputsAdapterOutput struct {
+	Key   string
+	Value string
 }
 
 type GuestOutputsAdapterWorker struct {
@@ -55,7 +149,24 @@ type GuestOutputsAdapterWorker struct {
 	CloudConfig        GuestOutputsAdapterWorkerCloudConfig
 	DockerVolumeSizeGB string
 	ImageID            string
-	InstanceType       string
+	// InstanceDistribution is the instance distribution of the default worker
+	// pool as returned by key.InstanceDistributionString.
+	InstanceDistribution string
+	InstanceType         string
+	// PoolNames is the comma separated list of the names of all named worker
+	// pools. It is empty in case there are no named worker pools.
+	PoolNames string
+	Pools     []GuestOutputsAdapterWorkerPool
+}
+
+// GuestOutputsAdapterWorkerPool holds the outputs of a single named worker
+// pool. The value of the ASG name output is the cloud formation resource name
+// of the ASG, which gets referenced in the template.
+type GuestOutputsAdapterWorkerPool struct {
+	ASGName              GuestOutputsAdapterOutput
+	DockerVolumeSizeGB   GuestOutputsAdapterOutput
+	InstanceDistribution GuestOutputsAdapterOutput
+	InstanceType         GuestOutputsAdapterOutput
 }
 
 type GuestOutputsAdapterWorkerASG struct {
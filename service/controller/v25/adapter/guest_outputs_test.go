@@ -75,3 +75,81 @@ func Test_CloudFormation_Adapter_Outputs_WorkerCloudConfigVersion(t *testing.T)
 		})
 	}
 }
+
+func Test_CloudFormation_Adapter_Outputs_MasterInstances(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		Description           string
+		Config                Config
+		ExpectedCount         int
+		ExpectedTypeKeys      []string
+		ExpectedResourceNames []string
+	}{
+		{
+			Description: "case 0 single master",
+			Config: Config{
+				CustomObject: v1alpha1.AWSConfig{},
+				StackState: StackState{
+					Masters: []StackStateMaster{
+						{
+							InstanceResourceName: "MasterInstance",
+							InstanceType:         "m4.large",
+						},
+					},
+				},
+			},
+			ExpectedCount:         1,
+			ExpectedTypeKeys:      []string{"MasterInstanceType"},
+			ExpectedResourceNames: []string{"MasterInstance"},
+		},
+		{
+			Description: "case 1 multiple masters get indexed output keys",
+			Config: Config{
+				CustomObject: v1alpha1.AWSConfig{},
+				StackState: StackState{
+					Masters: []StackStateMaster{
+						{
+							InstanceResourceName: "MasterInstance",
+						},
+						{
+							InstanceResourceName: "MasterInstance01",
+						},
+						{
+							InstanceResourceName: "MasterInstanceabcde02",
+						},
+					},
+				},
+			},
+			ExpectedCount:         3,
+			ExpectedTypeKeys:      []string{"MasterInstanceType", "MasterInstanceType01", "MasterInstanceType02"},
+			ExpectedResourceNames: []string{"MasterInstance", "MasterInstance01", "MasterInstanceabcde02"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			a := &GuestOutputsAdapter{}
+
+			err := a.Adapt(tc.Config)
+			if err != nil {
+				t.Fatalf("expected %#v got %#v", nil, err)
+			}
+
+			if a.Master.Count != tc.ExpectedCount {
+				t.Fatalf("expected %d got %d", tc.ExpectedCount, a.Master.Count)
+			}
+			if len(a.Master.Instances) != tc.ExpectedCount {
+				t.Fatalf("expected %d got %d", tc.ExpectedCount, len(a.Master.Instances))
+			}
+
+			for i, m := range a.Master.Instances {
+				if m.Type.Key != tc.ExpectedTypeKeys[i] {
+					t.Fatalf("expected %s got %s", tc.ExpectedTypeKeys[i], m.Type.Key)
+				}
+				if m.ResourceName.Value != tc.ExpectedResourceNames[i] {
+					t.Fatalf("expected %s got %s", tc.ExpectedResourceNames[i], m.ResourceName.Value)
+				}
+			}
+		})
+	}
+}
@@ -1,21 +1,71 @@
 package adapter
 
-import "github.com/giantswarm/aws-operator/service/controller/v25/key"
+import (
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
 
 type GuestRecordSetsAdapter struct {
-	BaseDomain                 string
-	EtcdDomain                 string
-	ClusterID                  string
-	MasterInstanceResourceName string
-	Route53Enabled             bool
+	APIHostedZoneResourceName       string
+	APILoadBalancerResourceName     string
+	BaseDomain                      string
+	EtcdDomain                      string
+	EtcdLoadBalancerResourceName    string
+	ClusterID                       string
+	IngressLoadBalancerResourceName string
+	// LoadBalancerHostedZoneIDAttribute is the attribute of the load balancer
+	// resources holding the ID of their hosted zone, which is named differently
+	// for classic ELBs and NLBs.
+	LoadBalancerHostedZoneIDAttribute string
+	MasterInstanceResourceName        string
+	PrivateAPIEnabled                 bool
+	PrivateAPIHostedZoneName          string
+	Region                            string
+	Route53Enabled                    bool
 }
 
 func (a *GuestRecordSetsAdapter) Adapt(config Config) error {
 	a.BaseDomain = key.BaseDomain(config.CustomObject)
 	a.EtcdDomain = key.EtcdDomain(config.CustomObject)
 	a.ClusterID = key.ClusterID(config.CustomObject)
-	a.MasterInstanceResourceName = config.StackState.MasterInstanceResourceName
+	if len(config.StackState.Masters) > 0 {
+		a.MasterInstanceResourceName = config.StackState.Masters[0].InstanceResourceName
+	}
+	a.Region = key.Region(config.CustomObject)
 	a.Route53Enabled = config.Route53Enabled
 
+	privateAPI, err := key.PrivateAPI(config.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// The API record of tenant clusters with a private API lives in a private
+	// hosted zone of its own.
+	if privateAPI {
+		a.APIHostedZoneResourceName = "PrivateAPIHostedZone"
+		a.PrivateAPIEnabled = true
+		a.PrivateAPIHostedZoneName = key.PrivateAPIHostedZoneName(config.CustomObject)
+	} else {
+		a.APIHostedZoneResourceName = "HostedZone"
+	}
+
+	lbType, err := key.LoadBalancerType(config.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if lbType == key.LoadBalancerTypeNetwork {
+		a.APILoadBalancerResourceName = "ApiNetworkLoadBalancer"
+		a.EtcdLoadBalancerResourceName = "EtcdNetworkLoadBalancer"
+		a.IngressLoadBalancerResourceName = "IngressNetworkLoadBalancer"
+		a.LoadBalancerHostedZoneIDAttribute = "CanonicalHostedZoneID"
+	} else {
+		a.APILoadBalancerResourceName = "ApiLoadBalancer"
+		a.EtcdLoadBalancerResourceName = "EtcdLoadBalancer"
+		a.IngressLoadBalancerResourceName = "IngressLoadBalancer"
+		a.LoadBalancerHostedZoneIDAttribute = "CanonicalHostedZoneNameID"
+	}
+
 	return nil
 }
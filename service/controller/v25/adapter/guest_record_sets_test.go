@@ -4,17 +4,24 @@ import (
 	"testing"
 
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 func TestAdapterRecordSetsRegularFields(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
-		description            string
-		customObject           v1alpha1.AWSConfig
-		route53Enabled         bool
-		expectedBaseDomain     string
-		expectedClusterID      string
-		expectedRoute53Enabled bool
+		description                               string
+		customObject                              v1alpha1.AWSConfig
+		route53Enabled                            bool
+		expectedAPIHostedZoneResourceName         string
+		expectedAPILoadBalancerResourceName       string
+		expectedBaseDomain                        string
+		expectedClusterID                         string
+		expectedLoadBalancerHostedZoneIDAttribute string
+		expectedPrivateAPIHostedZoneName          string
+		expectedRoute53Enabled                    bool
 	}{
 		{
 			description: "basic matching, all fields present",
@@ -44,10 +51,72 @@ func TestAdapterRecordSetsRegularFields(t *testing.T) {
 					},
 				},
 			},
-			route53Enabled:         true,
-			expectedRoute53Enabled: true,
-			expectedClusterID:      "test-cluster",
-			expectedBaseDomain:     "installation.aws.eu-central-1.gigantic.io",
+			route53Enabled:                            true,
+			expectedRoute53Enabled:                    true,
+			expectedAPIHostedZoneResourceName:         "HostedZone",
+			expectedAPILoadBalancerResourceName:       "ApiLoadBalancer",
+			expectedClusterID:                         "test-cluster",
+			expectedBaseDomain:                        "installation.aws.eu-central-1.gigantic.io",
+			expectedLoadBalancerHostedZoneIDAttribute: "CanonicalHostedZoneNameID",
+		},
+		{
+			description: "network load balancers",
+			customObject: v1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						key.LoadBalancerTypeAnnotation: key.LoadBalancerTypeNetwork,
+					},
+				},
+				Spec: v1alpha1.AWSConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+					},
+					AWS: v1alpha1.AWSConfigSpecAWS{
+						HostedZones: v1alpha1.AWSConfigSpecAWSHostedZones{
+							API: v1alpha1.AWSConfigSpecAWSHostedZonesZone{
+								Name: "installation.aws.eu-central-1.gigantic.io",
+							},
+						},
+					},
+				},
+			},
+			route53Enabled:                            true,
+			expectedRoute53Enabled:                    true,
+			expectedAPIHostedZoneResourceName:         "HostedZone",
+			expectedAPILoadBalancerResourceName:       "ApiNetworkLoadBalancer",
+			expectedClusterID:                         "test-cluster",
+			expectedBaseDomain:                        "installation.aws.eu-central-1.gigantic.io",
+			expectedLoadBalancerHostedZoneIDAttribute: "CanonicalHostedZoneID",
+		},
+		{
+			description: "private API",
+			customObject: v1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						key.PrivateAPIAnnotation: "true",
+					},
+				},
+				Spec: v1alpha1.AWSConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+					},
+					AWS: v1alpha1.AWSConfigSpecAWS{
+						HostedZones: v1alpha1.AWSConfigSpecAWSHostedZones{
+							API: v1alpha1.AWSConfigSpecAWSHostedZonesZone{
+								Name: "installation.aws.eu-central-1.gigantic.io",
+							},
+						},
+					},
+				},
+			},
+			route53Enabled:                            true,
+			expectedRoute53Enabled:                    true,
+			expectedAPIHostedZoneResourceName:         "PrivateAPIHostedZone",
+			expectedAPILoadBalancerResourceName:       "ApiLoadBalancer",
+			expectedClusterID:                         "test-cluster",
+			expectedBaseDomain:                        "installation.aws.eu-central-1.gigantic.io",
+			expectedLoadBalancerHostedZoneIDAttribute: "CanonicalHostedZoneNameID",
+			expectedPrivateAPIHostedZoneName:          "api.test-cluster.k8s.installation.aws.eu-central-1.gigantic.io",
 		},
 	}
 
@@ -69,6 +138,18 @@ func TestAdapterRecordSetsRegularFields(t *testing.T) {
 			if a.Guest.RecordSets.ClusterID != tc.expectedClusterID {
 				t.Fatalf("ClusterID == %q, want %q", a.Guest.RecordSets.ClusterID, tc.expectedClusterID)
 			}
+			if a.Guest.RecordSets.APIHostedZoneResourceName != tc.expectedAPIHostedZoneResourceName {
+				t.Fatalf("APIHostedZoneResourceName == %q, want %q", a.Guest.RecordSets.APIHostedZoneResourceName, tc.expectedAPIHostedZoneResourceName)
+			}
+			if a.Guest.RecordSets.APILoadBalancerResourceName != tc.expectedAPILoadBalancerResourceName {
+				t.Fatalf("APILoadBalancerResourceName == %q, want %q", a.Guest.RecordSets.APILoadBalancerResourceName, tc.expectedAPILoadBalancerResourceName)
+			}
+			if a.Guest.RecordSets.LoadBalancerHostedZoneIDAttribute != tc.expectedLoadBalancerHostedZoneIDAttribute {
+				t.Fatalf("LoadBalancerHostedZoneIDAttribute == %q, want %q", a.Guest.RecordSets.LoadBalancerHostedZoneIDAttribute, tc.expectedLoadBalancerHostedZoneIDAttribute)
+			}
+			if a.Guest.RecordSets.PrivateAPIHostedZoneName != tc.expectedPrivateAPIHostedZoneName {
+				t.Fatalf("PrivateAPIHostedZoneName == %q, want %q", a.Guest.RecordSets.PrivateAPIHostedZoneName, tc.expectedPrivateAPIHostedZoneName)
+			}
 			if a.Guest.RecordSets.Route53Enabled != tc.expectedRoute53Enabled {
 				t.Fatalf("Route53Enabled == %v, want %v", a.Guest.RecordSets.Route53Enabled, tc.expectedRoute53Enabled)
 			}
@@ -1,23 +1,41 @@
 package adapter
 
 import (
+	"github.com/giantswarm/microerror"
+
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 type RouteTableName struct {
-	ResourceName        string
-	TagName             string
-	VPCPeeringRouteName string
+	EgressOnlyInternetGatewayRouteName string
+	ResourceName                       string
+	TagName                            string
+	TransitGatewayRouteName            string
+	VPCPeeringRouteName                string
 }
 
 type GuestRouteTablesAdapter struct {
+	ExistingVPC            bool
 	HostClusterCIDR        string
+	IPv6                   bool
 	PublicRouteTableName   RouteTableName
 	PrivateRouteTableNames []RouteTableName
+	// TransitGatewayID is the ID of the transit gateway the private subnets
+	// route the traffic to the control plane VPC through. The traffic is routed
+	// through the VPC peering connection when empty.
+	TransitGatewayID string
 }
 
 func (r *GuestRouteTablesAdapter) Adapt(cfg Config) error {
+	ipv6, err := key.IPv6(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.ExistingVPC = key.IsExistingVPC(cfg.CustomObject)
 	r.HostClusterCIDR = cfg.ControlPlaneVPCCidr
+	r.IPv6 = ipv6
+	r.TransitGatewayID = cfg.TransitGatewayID
 	r.PublicRouteTableName = RouteTableName{
 		ResourceName: "PublicRouteTable",
 		TagName:      key.RouteTableName(cfg.CustomObject, suffixPublic, 0),
@@ -25,9 +43,11 @@ func (r *GuestRouteTablesAdapter) Adapt(cfg Config) error {
 
 	for i := 0; i < len(key.StatusAvailabilityZones(cfg.CustomObject)); i++ {
 		rtName := RouteTableName{
-			ResourceName:        key.PrivateRouteTableName(i),
-			TagName:             key.RouteTableName(cfg.CustomObject, suffixPrivate, i),
-			VPCPeeringRouteName: key.VPCPeeringRouteName(i),
+			EgressOnlyInternetGatewayRouteName: key.EgressOnlyInternetGatewayRouteName(i),
+			ResourceName:                       key.PrivateRouteTableName(i),
+			TagName:                            key.RouteTableName(cfg.CustomObject, suffixPrivate, i),
+			TransitGatewayRouteName:            key.TransitGatewayRouteName(i),
+			VPCPeeringRouteName:                key.VPCPeeringRouteName(i),
 		}
 		r.PrivateRouteTableNames = append(r.PrivateRouteTableNames, rtName)
 	}
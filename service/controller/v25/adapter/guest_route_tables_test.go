@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 func TestAdapterRouteTablesRegularFields(t *testing.T) {
@@ -14,8 +17,10 @@ func TestAdapterRouteTablesRegularFields(t *testing.T) {
 		customObject                   v1alpha1.AWSConfig
 		expectedError                  bool
 		expectedHostClusterCIDR        string
+		expectedIPv6                   bool
 		expectedPublicRouteTableName   RouteTableName
 		expectedPrivateRouteTableNames []RouteTableName
+		transitGatewayID               string
 	}{
 		{
 			description: "basic matching, all fields present",
@@ -49,14 +54,93 @@ func TestAdapterRouteTablesRegularFields(t *testing.T) {
 			},
 			expectedPrivateRouteTableNames: []RouteTableName{
 				{
-					ResourceName:        "PrivateRouteTable",
-					TagName:             "test-cluster-private",
-					VPCPeeringRouteName: "VPCPeeringRoute",
+					EgressOnlyInternetGatewayRouteName: "EgressOnlyInternetGatewayRoute00",
+					ResourceName:                       "PrivateRouteTable",
+					TagName:                            "test-cluster-private",
+					TransitGatewayRouteName:            "TransitGatewayRoute00",
+					VPCPeeringRouteName:                "VPCPeeringRoute",
+				},
+				{
+					EgressOnlyInternetGatewayRouteName: "EgressOnlyInternetGatewayRoute01",
+					ResourceName:                       "PrivateRouteTable01",
+					TagName:                            "test-cluster-private01",
+					TransitGatewayRouteName:            "TransitGatewayRoute01",
+					VPCPeeringRouteName:                "VPCPeeringRoute01",
+				},
+			},
+		},
+		{
+			description: "transit gateway",
+			customObject: v1alpha1.AWSConfig{
+				Spec: v1alpha1.AWSConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+					},
+				},
+				Status: v1alpha1.AWSConfigStatus{
+					AWS: v1alpha1.AWSConfigStatusAWS{
+						AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+							v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+								Name: "eu-central-1a",
+							},
+						},
+					},
 				},
+			},
+			expectedError:           false,
+			expectedHostClusterCIDR: "10.0.0.0/16",
+			expectedPublicRouteTableName: RouteTableName{
+				ResourceName: "PublicRouteTable",
+				TagName:      "test-cluster-public",
+			},
+			expectedPrivateRouteTableNames: []RouteTableName{
 				{
-					ResourceName:        "PrivateRouteTable01",
-					TagName:             "test-cluster-private01",
-					VPCPeeringRouteName: "VPCPeeringRoute01",
+					EgressOnlyInternetGatewayRouteName: "EgressOnlyInternetGatewayRoute00",
+					ResourceName:                       "PrivateRouteTable",
+					TagName:                            "test-cluster-private",
+					TransitGatewayRouteName:            "TransitGatewayRoute00",
+					VPCPeeringRouteName:                "VPCPeeringRoute",
+				},
+			},
+			transitGatewayID: "tgw-123",
+		},
+		{
+			description: "dual-stack",
+			customObject: v1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						key.IPv6Annotation: "true",
+					},
+				},
+				Spec: v1alpha1.AWSConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+					},
+				},
+				Status: v1alpha1.AWSConfigStatus{
+					AWS: v1alpha1.AWSConfigStatusAWS{
+						AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+							v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+								Name: "eu-central-1a",
+							},
+						},
+					},
+				},
+			},
+			expectedError:           false,
+			expectedHostClusterCIDR: "10.0.0.0/16",
+			expectedIPv6:            true,
+			expectedPublicRouteTableName: RouteTableName{
+				ResourceName: "PublicRouteTable",
+				TagName:      "test-cluster-public",
+			},
+			expectedPrivateRouteTableNames: []RouteTableName{
+				{
+					EgressOnlyInternetGatewayRouteName: "EgressOnlyInternetGatewayRoute00",
+					ResourceName:                       "PrivateRouteTable",
+					TagName:                            "test-cluster-private",
+					TransitGatewayRouteName:            "TransitGatewayRoute00",
+					VPCPeeringRouteName:                "VPCPeeringRoute",
 				},
 			},
 		},
@@ -69,6 +153,7 @@ func TestAdapterRouteTablesRegularFields(t *testing.T) {
 			cfg := Config{
 				ControlPlaneVPCCidr: tc.expectedHostClusterCIDR,
 				CustomObject:        tc.customObject,
+				TransitGatewayID:    tc.transitGatewayID,
 			}
 			err := a.Guest.RouteTables.Adapt(cfg)
 			if tc.expectedError && err == nil {
@@ -83,6 +168,14 @@ func TestAdapterRouteTablesRegularFields(t *testing.T) {
 				t.Errorf("unexpected HostClusterCIDR, got %q, want %q", a.Guest.RouteTables.HostClusterCIDR, tc.expectedHostClusterCIDR)
 			}
 
+			if a.Guest.RouteTables.IPv6 != tc.expectedIPv6 {
+				t.Errorf("unexpected IPv6, got %t, want %t", a.Guest.RouteTables.IPv6, tc.expectedIPv6)
+			}
+
+			if a.Guest.RouteTables.TransitGatewayID != tc.transitGatewayID {
+				t.Errorf("unexpected TransitGatewayID, got %q, want %q", a.Guest.RouteTables.TransitGatewayID, tc.transitGatewayID)
+			}
+
 			if !reflect.DeepEqual(a.Guest.RouteTables.PublicRouteTableName, tc.expectedPublicRouteTableName) {
 				t.Errorf("unexpected PublicRouteTableName, got %q, want %q", a.Guest.RouteTables.PublicRouteTableName, tc.expectedPublicRouteTableName)
 			}
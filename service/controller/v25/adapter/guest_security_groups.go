@@ -22,7 +22,8 @@ const (
 	allProtocols = "-1"
 	tcpProtocol  = "tcp"
 
-	defaultCIDR = "0.0.0.0/0"
+	defaultCIDR     = "0.0.0.0/0"
+	defaultIPv6CIDR = "::/0"
 
 	ingressSecurityGroupName = "IngressSecurityGroup"
 )
@@ -37,14 +38,44 @@ type GuestSecurityGroupsAdapter struct {
 	IngressSecurityGroupRules []securityGroupRule
 	EtcdELBSecurityGroupName  string
 	EtcdELBSecurityGroupRules []securityGroupRule
+	ExistingVPC               bool
+	// IPv6 is true in case the tenant cluster uses dual-stack networking. The
+	// security groups then depend on the VPC's IPv6 CIDR block, since rules
+	// may reference it.
+	IPv6 bool
+	// IngressNATGatewayPorts and WorkerNATGatewayPorts are the ports of the
+	// ingress and worker security groups the tenant cluster's own NAT
+	// gateways are allowed to access in case the ingress load balancer is
+	// whitelisted. The addresses of the NAT gateways are only known within the
+	// cloud formation stack.
+	IngressNATGatewayPorts []int
+	WorkerNATGatewayPorts  []int
+	// VPCEndpointSecurityGroupName and VPCEndpointSecurityGroupRules describe
+	// the security group of the VPC interface endpoints. The security group is
+	// only created in case the VPC gets interface endpoints.
+	VPCEndpointSecurityGroupName  string
+	VPCEndpointSecurityGroupRules []securityGroupRule
 }
 
 func (s *GuestSecurityGroupsAdapter) Adapt(cfg Config) error {
+	s.ExistingVPC = key.IsExistingVPC(cfg.CustomObject)
+
 	masterRules, err := s.getMasterRules(cfg, cfg.ControlPlaneVPCCidr)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
+	lbType, err := key.LoadBalancerType(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	ingressWhitelist, err := key.IngressWhitelist(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	ingressSourceCIDRs := getIngressSourceCIDRs(cfg, ingressWhitelist)
+
 	s.APIWhitelistEnabled = cfg.APIWhitelist.Enabled
 
 	s.MasterSecurityGroupName = key.SecurityGroupName(cfg.CustomObject, key.KindMaster)
@@ -53,11 +84,49 @@ func (s *GuestSecurityGroupsAdapter) Adapt(cfg Config) error {
 	s.WorkerSecurityGroupName = key.SecurityGroupName(cfg.CustomObject, key.KindWorker)
 	s.WorkerSecurityGroupRules = s.getWorkerRules(cfg.CustomObject, cfg.ControlPlaneVPCCidr)
 
+	// NLBs do not have security groups and preserve the IPs of their clients.
+	// The traffic the classic ELBs' security groups allow must then be allowed
+	// by the security groups of the instances the NLBs forward traffic to.
+	if lbType == key.LoadBalancerTypeNetwork {
+		s.MasterSecurityGroupRules = append(s.MasterSecurityGroupRules, s.getMasterNetworkLoadBalancerRules(cfg)...)
+		s.WorkerSecurityGroupRules = append(s.WorkerSecurityGroupRules, s.getWorkerNetworkLoadBalancerRules(cfg.CustomObject, ingressSourceCIDRs)...)
+	}
+
 	s.IngressSecurityGroupName = key.SecurityGroupName(cfg.CustomObject, key.KindIngress)
-	s.IngressSecurityGroupRules = s.getIngressRules(cfg.CustomObject)
+	s.IngressSecurityGroupRules = s.getIngressRules(ingressSourceCIDRs)
+
+	if len(ingressWhitelist) > 0 {
+		if lbType == key.LoadBalancerTypeNetwork {
+			s.WorkerNATGatewayPorts = []int{
+				key.IngressControllerSecurePort(cfg.CustomObject),
+				key.IngressControllerInsecurePort(cfg.CustomObject),
+			}
+		} else {
+			s.IngressNATGatewayPorts = []int{httpPort, httpsPort}
+		}
+	}
 
 	s.EtcdELBSecurityGroupName = key.SecurityGroupName(cfg.CustomObject, key.KindEtcd)
-	s.EtcdELBSecurityGroupRules = s.getEtcdRules(cfg.CustomObject, cfg.ControlPlaneVPCCidr)
+	s.EtcdELBSecurityGroupRules = s.getEtcdRules(cfg, cfg.ControlPlaneVPCCidr)
+
+	if !s.ExistingVPC && len(cfg.VPCEndpoints) > 0 {
+		s.VPCEndpointSecurityGroupName = key.SecurityGroupName(cfg.CustomObject, key.KindVPCEndpoint)
+		s.VPCEndpointSecurityGroupRules = s.getVPCEndpointRules(cfg)
+	}
+
+	ipv6, err := key.IPv6(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if ipv6 {
+		vpcCIDR := key.StatusNetworkCIDR(cfg.CustomObject)
+
+		s.IPv6 = true
+		s.MasterSecurityGroupRules = withIPv6Rules(s.MasterSecurityGroupRules, vpcCIDR)
+		s.WorkerSecurityGroupRules = withIPv6Rules(s.WorkerSecurityGroupRules, vpcCIDR)
+		s.IngressSecurityGroupRules = withIPv6Rules(s.IngressSecurityGroupRules, vpcCIDR)
+		s.EtcdELBSecurityGroupRules = withIPv6Rules(s.EtcdELBSecurityGroupRules, vpcCIDR)
+	}
 
 	return nil
 }
@@ -166,30 +235,91 @@ func (s *GuestSecurityGroupsAdapter) getWorkerRules(customObject v1alpha1.AWSCon
 	}
 }
 
-func (s *GuestSecurityGroupsAdapter) getIngressRules(customObject v1alpha1.AWSConfig) []securityGroupRule {
-	return []securityGroupRule{
-		{
-			Description: "Allow all http traffic to the ingress load balancer.",
-			Port:        httpPort,
-			Protocol:    tcpProtocol,
-			SourceCIDR:  defaultCIDR,
-		},
+func (s *GuestSecurityGroupsAdapter) getMasterNetworkLoadBalancerRules(cfg Config) []securityGroupRule {
+	rules := []securityGroupRule{
 		{
-			Description: "Allow all https traffic to the ingress load balancer.",
-			Port:        httpsPort,
+			Description: "Allow etcd traffic and health checks from the VPC to the etcd network load balancer targets.",
+			Port:        etcdPort,
 			Protocol:    tcpProtocol,
-			SourceCIDR:  defaultCIDR,
+			SourceCIDR:  key.StatusNetworkCIDR(cfg.CustomObject),
 		},
 	}
+
+	return append(rules, getHostClusterNATGatewayRules(cfg, etcdPort)...)
 }
 
-func (s *GuestSecurityGroupsAdapter) getEtcdRules(customObject v1alpha1.AWSConfig, hostClusterCIDR string) []securityGroupRule {
-	return []securityGroupRule{
+func (s *GuestSecurityGroupsAdapter) getWorkerNetworkLoadBalancerRules(customObject v1alpha1.AWSConfig, sourceCIDRs []string) []securityGroupRule {
+	var rules []securityGroupRule
+
+	for _, cidr := range sourceCIDRs {
+		rules = append(rules, []securityGroupRule{
+			{
+				Description: "Allow all traffic to the ingress controller port 443 of the ingress network load balancer targets.",
+				Port:        key.IngressControllerSecurePort(customObject),
+				Protocol:    tcpProtocol,
+				SourceCIDR:  cidr,
+			},
+			{
+				Description: "Allow all traffic to the ingress controller port 80 of the ingress network load balancer targets.",
+				Port:        key.IngressControllerInsecurePort(customObject),
+				Protocol:    tcpProtocol,
+				SourceCIDR:  cidr,
+			},
+		}...)
+	}
+
+	// The health checks of the NLB come from within the VPC. They have to be
+	// allowed separately in case the traffic is restricted to a whitelist.
+	if len(sourceCIDRs) != 1 || sourceCIDRs[0] != defaultCIDR {
+		rules = append(rules, []securityGroupRule{
+			{
+				Description: "Allow health checks from the VPC to the ingress controller port 443 of the ingress network load balancer targets.",
+				Port:        key.IngressControllerSecurePort(customObject),
+				Protocol:    tcpProtocol,
+				SourceCIDR:  key.StatusNetworkCIDR(customObject),
+			},
+			{
+				Description: "Allow health checks from the VPC to the ingress controller port 80 of the ingress network load balancer targets.",
+				Port:        key.IngressControllerInsecurePort(customObject),
+				Protocol:    tcpProtocol,
+				SourceCIDR:  key.StatusNetworkCIDR(customObject),
+			},
+		}...)
+	}
+
+	return rules
+}
+
+func (s *GuestSecurityGroupsAdapter) getIngressRules(sourceCIDRs []string) []securityGroupRule {
+	var rules []securityGroupRule
+
+	for _, cidr := range sourceCIDRs {
+		rules = append(rules, []securityGroupRule{
+			{
+				Description: "Allow all http traffic to the ingress load balancer.",
+				Port:        httpPort,
+				Protocol:    tcpProtocol,
+				SourceCIDR:  cidr,
+			},
+			{
+				Description: "Allow all https traffic to the ingress load balancer.",
+				Port:        httpsPort,
+				Protocol:    tcpProtocol,
+				SourceCIDR:  cidr,
+			},
+		}...)
+	}
+
+	return rules
+}
+
+func (s *GuestSecurityGroupsAdapter) getEtcdRules(cfg Config, hostClusterCIDR string) []securityGroupRule {
+	rules := []securityGroupRule{
 		{
 			Description: "Allow all etcd traffic from the VPC to the etcd load balancer.",
 			Port:        etcdPort,
 			Protocol:    tcpProtocol,
-			SourceCIDR:  defaultCIDR,
+			SourceCIDR:  key.StatusNetworkCIDR(cfg.CustomObject),
 		},
 		{
 			Description: "Allow traffic from control plane to etcd port for backup and metrics.",
@@ -198,6 +328,36 @@ func (s *GuestSecurityGroupsAdapter) getEtcdRules(customObject v1alpha1.AWSConfi
 			SourceCIDR:  hostClusterCIDR,
 		},
 	}
+
+	return append(rules, getHostClusterNATGatewayRules(cfg, etcdPort)...)
+}
+
+func (s *GuestSecurityGroupsAdapter) getVPCEndpointRules(cfg Config) []securityGroupRule {
+	return []securityGroupRule{
+		{
+			Description: "Allow https traffic from the VPC to the VPC interface endpoints.",
+			Port:        httpsPort,
+			Protocol:    tcpProtocol,
+			SourceCIDR:  key.StatusNetworkCIDR(cfg.CustomObject),
+		},
+	}
+}
+
+// getIngressSourceCIDRs returns the CIDRs allowed to access the ingress load
+// balancer. Without whitelist all traffic is allowed. Otherwise the control
+// plane NAT gateways are allowed in addition to the whitelisted CIDRs, so that
+// the control plane is still able to reach the tenant cluster's ingress.
+func getIngressSourceCIDRs(cfg Config, whitelist []string) []string {
+	if len(whitelist) == 0 {
+		return []string{defaultCIDR}
+	}
+
+	cidrs := append([]string{}, whitelist...)
+	for _, r := range getHostClusterNATGatewayRules(cfg, httpsPort) {
+		cidrs = append(cidrs, r.SourceCIDR)
+	}
+
+	return cidrs
 }
 
 type securityGroupRule struct {
@@ -205,7 +365,41 @@ type securityGroupRule struct {
 	Port                int
 	Protocol            string
 	SourceCIDR          string
+	SourceIPv6CIDR      string
 	SourceSecurityGroup string
+	// SourceVPCIPv6CIDR is true for rules allowing traffic from the VPC's
+	// Amazon-provided IPv6 CIDR block. The block is only known within the cloud
+	// formation stack.
+	SourceVPCIPv6CIDR bool
+}
+
+// withIPv6Rules returns the given rules together with an IPv6 rule for every
+// rule allowing all IPv4 traffic or traffic from the VPC CIDR, so that the
+// same traffic is allowed for both address families of dual-stack tenant
+// clusters.
+func withIPv6Rules(rules []securityGroupRule, vpcCIDR string) []securityGroupRule {
+	var ipv6Rules []securityGroupRule
+
+	for _, r := range rules {
+		ipv6Rule := securityGroupRule{
+			Description: r.Description,
+			Port:        r.Port,
+			Protocol:    r.Protocol,
+		}
+
+		switch {
+		case r.SourceCIDR == defaultCIDR:
+			ipv6Rule.SourceIPv6CIDR = defaultIPv6CIDR
+		case vpcCIDR != "" && r.SourceCIDR == vpcCIDR:
+			ipv6Rule.SourceVPCIPv6CIDR = true
+		default:
+			continue
+		}
+
+		ipv6Rules = append(ipv6Rules, ipv6Rule)
+	}
+
+	return append(rules, ipv6Rules...)
 }
 
 func getKubernetesAPIRules(cfg Config, hostClusterCIDR string) ([]securityGroupRule, error) {
@@ -241,10 +435,7 @@ func getKubernetesAPIRules(cfg Config, hostClusterCIDR string) ([]securityGroupR
 		}
 
 		// Whitelist public EIPs of the host cluster NAT gateways.
-		hostClusterNATGatewayRules, err := getHostClusterNATGatewayRules(cfg)
-		if err != nil {
-			return []securityGroupRule{}, microerror.Mask(err)
-		}
+		hostClusterNATGatewayRules := getHostClusterNATGatewayRules(cfg, key.KubernetesAPISecurePort(cfg.CustomObject))
 
 		for _, gatewayRule := range hostClusterNATGatewayRules {
 			rules = append(rules, gatewayRule)
@@ -266,13 +457,13 @@ func getKubernetesAPIRules(cfg Config, hostClusterCIDR string) ([]securityGroupR
 	}
 }
 
-func getHostClusterNATGatewayRules(cfg Config) ([]securityGroupRule, error) {
+func getHostClusterNATGatewayRules(cfg Config, port int) []securityGroupRule {
 	var gatewayRules []securityGroupRule
 
 	for _, address := range cfg.ControlPlaneNATGatewayAddresses {
 		gatewayRule := securityGroupRule{
 			Description: "Allow traffic from gateways.",
-			Port:        key.KubernetesAPISecurePort(cfg.CustomObject),
+			Port:        port,
 			Protocol:    tcpProtocol,
 			SourceCIDR:  fmt.Sprintf("%s/32", *address.PublicIp),
 		}
@@ -280,5 +471,5 @@ func getHostClusterNATGatewayRules(cfg Config) ([]securityGroupRule, error) {
 		gatewayRules = append(gatewayRules, gatewayRule)
 	}
 
-	return gatewayRules, nil
+	return gatewayRules
 }
@@ -7,6 +7,9 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 func TestAdapterSecurityGroupsRegularFields(t *testing.T) {
@@ -520,3 +523,464 @@ func TestAdapterSecurityGroupsKubernetesAPIRules(t *testing.T) {
 		})
 	}
 }
+
+func TestAdapterSecurityGroupsNetworkLoadBalancerRules(t *testing.T) {
+	t.Parallel()
+	customObject := v1alpha1.AWSConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				key.LoadBalancerTypeAnnotation: key.LoadBalancerTypeNetwork,
+			},
+		},
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "test-cluster",
+				Kubernetes: v1alpha1.ClusterKubernetes{
+					IngressController: v1alpha1.ClusterKubernetesIngressController{
+						SecurePort:   30010,
+						InsecurePort: 30011,
+					},
+				},
+			},
+		},
+		Status: v1alpha1.AWSConfigStatus{
+			Cluster: v1alpha1.StatusCluster{
+				Network: v1alpha1.StatusClusterNetwork{
+					CIDR: "10.1.0.0/24",
+				},
+			},
+		},
+	}
+
+	a := Adapter{}
+	cfg := Config{
+		ControlPlaneVPCCidr: "10.0.0.0/16",
+		CustomObject:        customObject,
+	}
+	err := a.Guest.SecurityGroups.Adapt(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	masterRule := securityGroupRule{
+		Description: "Allow etcd traffic and health checks from the VPC to the etcd network load balancer targets.",
+		Port:        2379,
+		Protocol:    "tcp",
+		SourceCIDR:  "10.1.0.0/24",
+	}
+	if !containsSecurityGroupRule(a.Guest.SecurityGroups.MasterSecurityGroupRules, masterRule) {
+		t.Fatalf("expected master rule %v in %v", masterRule, a.Guest.SecurityGroups.MasterSecurityGroupRules)
+	}
+
+	for _, port := range []int{30010, 30011} {
+		found := false
+		for _, r := range a.Guest.SecurityGroups.WorkerSecurityGroupRules {
+			if r.Port == port && r.SourceCIDR == "0.0.0.0/0" {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected worker rule for port %d from 0.0.0.0/0 in %v", port, a.Guest.SecurityGroups.WorkerSecurityGroupRules)
+		}
+	}
+}
+
+func containsSecurityGroupRule(rules []securityGroupRule, rule securityGroupRule) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestAdapterSecurityGroupsIngressWhitelist(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description                    string
+		loadBalancerType               string
+		expectedIngressRules           []securityGroupRule
+		expectedIngressNATGatewayPorts []int
+		expectedWorkerRules            []securityGroupRule
+		expectedWorkerNATGatewayPorts  []int
+	}{
+		{
+			description:      "classic load balancers",
+			loadBalancerType: key.LoadBalancerTypeClassic,
+			expectedIngressRules: []securityGroupRule{
+				{
+					Description: "Allow all http traffic to the ingress load balancer.",
+					Port:        80,
+					Protocol:    "tcp",
+					SourceCIDR:  "172.16.0.0/12",
+				},
+				{
+					Description: "Allow all https traffic to the ingress load balancer.",
+					Port:        443,
+					Protocol:    "tcp",
+					SourceCIDR:  "172.16.0.0/12",
+				},
+				{
+					Description: "Allow all http traffic to the ingress load balancer.",
+					Port:        80,
+					Protocol:    "tcp",
+					SourceCIDR:  "203.0.113.1/32",
+				},
+				{
+					Description: "Allow all https traffic to the ingress load balancer.",
+					Port:        443,
+					Protocol:    "tcp",
+					SourceCIDR:  "203.0.113.1/32",
+				},
+			},
+			expectedIngressNATGatewayPorts: []int{80, 443},
+		},
+		{
+			description:      "network load balancers",
+			loadBalancerType: key.LoadBalancerTypeNetwork,
+			expectedWorkerRules: []securityGroupRule{
+				{
+					Description: "Allow all traffic to the ingress controller port 443 of the ingress network load balancer targets.",
+					Port:        30010,
+					Protocol:    "tcp",
+					SourceCIDR:  "172.16.0.0/12",
+				},
+				{
+					Description: "Allow all traffic to the ingress controller port 443 of the ingress network load balancer targets.",
+					Port:        30010,
+					Protocol:    "tcp",
+					SourceCIDR:  "203.0.113.1/32",
+				},
+				{
+					Description: "Allow health checks from the VPC to the ingress controller port 443 of the ingress network load balancer targets.",
+					Port:        30010,
+					Protocol:    "tcp",
+					SourceCIDR:  "10.1.0.0/24",
+				},
+			},
+			expectedWorkerNATGatewayPorts: []int{30010, 30011},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						key.IngressWhitelistAnnotation: "172.16.0.0/12",
+						key.LoadBalancerTypeAnnotation: tc.loadBalancerType,
+					},
+				},
+				Spec: v1alpha1.AWSConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+						Kubernetes: v1alpha1.ClusterKubernetes{
+							IngressController: v1alpha1.ClusterKubernetesIngressController{
+								SecurePort:   30010,
+								InsecurePort: 30011,
+							},
+						},
+					},
+				},
+				Status: v1alpha1.AWSConfigStatus{
+					Cluster: v1alpha1.StatusCluster{
+						Network: v1alpha1.StatusClusterNetwork{
+							CIDR: "10.1.0.0/24",
+						},
+					},
+				},
+			}
+
+			a := Adapter{}
+			cfg := Config{
+				ControlPlaneNATGatewayAddresses: []*ec2.Address{
+					{
+						PublicIp: aws.String("203.0.113.1"),
+					},
+				},
+				ControlPlaneVPCCidr: "10.0.0.0/16",
+				CustomObject:        customObject,
+			}
+			err := a.Guest.SecurityGroups.Adapt(cfg)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if tc.expectedIngressRules != nil && !reflect.DeepEqual(a.Guest.SecurityGroups.IngressSecurityGroupRules, tc.expectedIngressRules) {
+				t.Fatalf("expected ingress rules %v, got %v", tc.expectedIngressRules, a.Guest.SecurityGroups.IngressSecurityGroupRules)
+			}
+			if !reflect.DeepEqual(a.Guest.SecurityGroups.IngressNATGatewayPorts, tc.expectedIngressNATGatewayPorts) {
+				t.Fatalf("expected ingress NAT gateway ports %v, got %v", tc.expectedIngressNATGatewayPorts, a.Guest.SecurityGroups.IngressNATGatewayPorts)
+			}
+			for _, r := range tc.expectedWorkerRules {
+				if !containsSecurityGroupRule(a.Guest.SecurityGroups.WorkerSecurityGroupRules, r) {
+					t.Fatalf("expected worker rule %v in %v", r, a.Guest.SecurityGroups.WorkerSecurityGroupRules)
+				}
+			}
+			if !reflect.DeepEqual(a.Guest.SecurityGroups.WorkerNATGatewayPorts, tc.expectedWorkerNATGatewayPorts) {
+				t.Fatalf("expected worker NAT gateway ports %v, got %v", tc.expectedWorkerNATGatewayPorts, a.Guest.SecurityGroups.WorkerNATGatewayPorts)
+			}
+
+			for _, r := range append(a.Guest.SecurityGroups.IngressSecurityGroupRules, a.Guest.SecurityGroups.WorkerSecurityGroupRules...) {
+				if r.SourceCIDR == "0.0.0.0/0" {
+					t.Fatalf("expected no rule from 0.0.0.0/0, got %v", r)
+				}
+			}
+		})
+	}
+}
+
+func TestAdapterSecurityGroupsEtcdRules(t *testing.T) {
+	t.Parallel()
+	customObject := v1alpha1.AWSConfig{
+		Status: v1alpha1.AWSConfigStatus{
+			Cluster: v1alpha1.StatusCluster{
+				Network: v1alpha1.StatusClusterNetwork{
+					CIDR: "10.1.0.0/24",
+				},
+			},
+		},
+	}
+
+	a := Adapter{}
+	cfg := Config{
+		ControlPlaneNATGatewayAddresses: []*ec2.Address{
+			{
+				PublicIp: aws.String("203.0.113.1"),
+			},
+		},
+		ControlPlaneVPCCidr: "10.0.0.0/16",
+		CustomObject:        customObject,
+	}
+	err := a.Guest.SecurityGroups.Adapt(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var sources []string
+	for _, r := range a.Guest.SecurityGroups.EtcdELBSecurityGroupRules {
+		if r.Port != 2379 {
+			t.Fatalf("expected etcd port, got %v", r)
+		}
+		sources = append(sources, r.SourceCIDR)
+	}
+
+	expectedSources := []string{"10.1.0.0/24", "10.0.0.0/16", "203.0.113.1/32"}
+	if !reflect.DeepEqual(sources, expectedSources) {
+		t.Fatalf("expected etcd rule sources %v, got %v", expectedSources, sources)
+	}
+}
+
+func TestAdapterSecurityGroupsIPv6Rules(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description          string
+		annotations          map[string]string
+		expectedIngressRules []securityGroupRule
+		expectedMasterRules  []securityGroupRule
+		expectedEtcdRules    []securityGroupRule
+	}{
+		{
+			description: "IPv4 only",
+			annotations: nil,
+			expectedIngressRules: []securityGroupRule{
+				{
+					Description: "Allow all http traffic to the ingress load balancer.",
+					Port:        80,
+					Protocol:    "tcp",
+					SourceCIDR:  "0.0.0.0/0",
+				},
+				{
+					Description: "Allow all https traffic to the ingress load balancer.",
+					Port:        443,
+					Protocol:    "tcp",
+					SourceCIDR:  "0.0.0.0/0",
+				},
+			},
+		},
+		{
+			description: "dual-stack",
+			annotations: map[string]string{
+				key.IPv6Annotation: "true",
+			},
+			expectedIngressRules: []securityGroupRule{
+				{
+					Description: "Allow all http traffic to the ingress load balancer.",
+					Port:        80,
+					Protocol:    "tcp",
+					SourceCIDR:  "0.0.0.0/0",
+				},
+				{
+					Description: "Allow all https traffic to the ingress load balancer.",
+					Port:        443,
+					Protocol:    "tcp",
+					SourceCIDR:  "0.0.0.0/0",
+				},
+				{
+					Description:    "Allow all http traffic to the ingress load balancer.",
+					Port:           80,
+					Protocol:       "tcp",
+					SourceIPv6CIDR: "::/0",
+				},
+				{
+					Description:    "Allow all https traffic to the ingress load balancer.",
+					Port:           443,
+					Protocol:       "tcp",
+					SourceIPv6CIDR: "::/0",
+				},
+			},
+			expectedMasterRules: []securityGroupRule{
+				{
+					Description:    "Allow all traffic to the master instance.",
+					Port:           443,
+					Protocol:       "tcp",
+					SourceIPv6CIDR: "::/0",
+				},
+			},
+			expectedEtcdRules: []securityGroupRule{
+				{
+					Description:       "Allow all etcd traffic from the VPC to the etcd load balancer.",
+					Port:              2379,
+					Protocol:          "tcp",
+					SourceVPCIPv6CIDR: true,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+				Spec: v1alpha1.AWSConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+						Kubernetes: v1alpha1.ClusterKubernetes{
+							API: v1alpha1.ClusterKubernetesAPI{
+								SecurePort: 443,
+							},
+						},
+					},
+				},
+				Status: v1alpha1.AWSConfigStatus{
+					Cluster: v1alpha1.StatusCluster{
+						Network: v1alpha1.StatusClusterNetwork{
+							CIDR: "10.1.0.0/24",
+						},
+					},
+				},
+			}
+
+			a := Adapter{}
+			cfg := Config{
+				ControlPlaneVPCCidr: "10.0.0.0/16",
+				CustomObject:        customObject,
+			}
+			err := a.Guest.SecurityGroups.Adapt(cfg)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if !reflect.DeepEqual(a.Guest.SecurityGroups.IngressSecurityGroupRules, tc.expectedIngressRules) {
+				t.Fatalf("expected ingress rules %v, got %v", tc.expectedIngressRules, a.Guest.SecurityGroups.IngressSecurityGroupRules)
+			}
+			for _, r := range tc.expectedMasterRules {
+				if !containsSecurityGroupRule(a.Guest.SecurityGroups.MasterSecurityGroupRules, r) {
+					t.Fatalf("expected master rule %v in %v", r, a.Guest.SecurityGroups.MasterSecurityGroupRules)
+				}
+			}
+			for _, r := range tc.expectedEtcdRules {
+				if !containsSecurityGroupRule(a.Guest.SecurityGroups.EtcdELBSecurityGroupRules, r) {
+					t.Fatalf("expected etcd rule %v in %v", r, a.Guest.SecurityGroups.EtcdELBSecurityGroupRules)
+				}
+			}
+			for _, r := range a.Guest.SecurityGroups.WorkerSecurityGroupRules {
+				if r.SourceIPv6CIDR != "" || r.SourceVPCIPv6CIDR {
+					t.Fatalf("expected no IPv6 worker rule, got %v", r)
+				}
+			}
+			if a.Guest.SecurityGroups.IPv6 != (len(tc.annotations) > 0) {
+				t.Fatalf("expected IPv6 %t, got %t", len(tc.annotations) > 0, a.Guest.SecurityGroups.IPv6)
+			}
+		})
+	}
+}
+
+func TestAdapterSecurityGroupsVPCEndpointRules(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description       string
+		annotations       map[string]string
+		vpcEndpoints      []string
+		expectedGroupName string
+		expectedRules     []securityGroupRule
+	}{
+		{
+			description:  "no VPC endpoints",
+			vpcEndpoints: nil,
+		},
+		{
+			description:       "VPC endpoints",
+			vpcEndpoints:      []string{"ec2", "ecr.api"},
+			expectedGroupName: "test-cluster-vpc-endpoint",
+			expectedRules: []securityGroupRule{
+				{
+					Description: "Allow https traffic from the VPC to the VPC interface endpoints.",
+					Port:        443,
+					Protocol:    "tcp",
+					SourceCIDR:  "10.1.0.0/24",
+				},
+			},
+		},
+		{
+			description: "VPC endpoints in an existing VPC",
+			annotations: map[string]string{
+				key.ExistingVPCIDAnnotation: "vpc-1234",
+			},
+			vpcEndpoints: []string{"ec2", "ecr.api"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+				Spec: v1alpha1.AWSConfigSpec{
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+					},
+				},
+				Status: v1alpha1.AWSConfigStatus{
+					Cluster: v1alpha1.StatusCluster{
+						Network: v1alpha1.StatusClusterNetwork{
+							CIDR: "10.1.0.0/24",
+						},
+					},
+				},
+			}
+
+			a := Adapter{}
+			cfg := Config{
+				ControlPlaneVPCCidr: "10.0.0.0/16",
+				CustomObject:        customObject,
+				VPCEndpoints:        tc.vpcEndpoints,
+			}
+			err := a.Guest.SecurityGroups.Adapt(cfg)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if a.Guest.SecurityGroups.VPCEndpointSecurityGroupName != tc.expectedGroupName {
+				t.Fatalf("expected security group name %q, got %q", tc.expectedGroupName, a.Guest.SecurityGroups.VPCEndpointSecurityGroupName)
+			}
+			if !reflect.DeepEqual(a.Guest.SecurityGroups.VPCEndpointSecurityGroupRules, tc.expectedRules) {
+				t.Fatalf("expected rules %v, got %v", tc.expectedRules, a.Guest.SecurityGroups.VPCEndpointSecurityGroupRules)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 type Subnet struct {
 	AvailabilityZone      string
 	CIDR                  string
+	ID                    string
+	IPv6CIDRIndex         int
 	Name                  string
 	MapPublicIPOnLaunch   bool
 	RouteTableAssociation RouteTableAssociation
@@ -22,8 +24,17 @@ type RouteTableAssociation struct {
 }
 
 type GuestSubnetsAdapter struct {
-	PublicSubnets  []Subnet
-	PrivateSubnets []Subnet
+	// Existing is true in case the tenant cluster is created in an existing VPC.
+	// The subnets are then passed as template parameters instead of being
+	// created.
+	Existing bool
+	// IPv6 is true in case the tenant cluster uses dual-stack networking. Every
+	// subnet then gets the /64 with its IPv6CIDRIndex out of the IPv6SubnetCount
+	// /64s of the VPC's IPv6 CIDR block.
+	IPv6            bool
+	IPv6SubnetCount int
+	PublicSubnets   []Subnet
+	PrivateSubnets  []Subnet
 }
 
 func (s *GuestSubnetsAdapter) Adapt(cfg Config) error {
@@ -39,11 +50,29 @@ func (s *GuestSubnetsAdapter) Adapt(cfg Config) error {
 		}
 	}
 
+	// The existing subnets are listed in the order of the names of their
+	// availability zones, which is the order of the sorted zones.
+	privateIDs, publicIDs, err := key.ExistingSubnetIDs(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	s.Existing = key.IsExistingVPC(cfg.CustomObject)
+	s.IPv6, err = key.IPv6(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	s.IPv6SubnetCount = 2 * len(zones)
+	if s.Existing && len(privateIDs) != len(zones) {
+		return microerror.Maskf(invalidConfigError, "expected %d existing subnets per type, got %d", len(zones), len(privateIDs))
+	}
+
 	for i, az := range zones {
 		snetName := key.PublicSubnetName(i)
 		snet := Subnet{
 			AvailabilityZone:    az.Name,
 			CIDR:                az.Subnet.Public.CIDR,
+			ID:                  existingSubnetID(publicIDs, i),
+			IPv6CIDRIndex:       i,
 			Name:                snetName,
 			MapPublicIPOnLaunch: false,
 			RouteTableAssociation: RouteTableAssociation{
@@ -58,6 +87,8 @@ func (s *GuestSubnetsAdapter) Adapt(cfg Config) error {
 		snet = Subnet{
 			AvailabilityZone:    az.Name,
 			CIDR:                az.Subnet.Private.CIDR,
+			ID:                  existingSubnetID(privateIDs, i),
+			IPv6CIDRIndex:       len(zones) + i,
 			Name:                snetName,
 			MapPublicIPOnLaunch: false,
 			RouteTableAssociation: RouteTableAssociation{
@@ -71,3 +102,11 @@ func (s *GuestSubnetsAdapter) Adapt(cfg Config) error {
 
 	return nil
 }
+
+func existingSubnetID(ids []string, i int) string {
+	if i >= len(ids) {
+		return ""
+	}
+
+	return ids[i]
+}
@@ -73,6 +73,7 @@ func TestAdapterSubnetsRegularFields(t *testing.T) {
 				{
 					AvailabilityZone: "eu-west-1b",
 					CIDR:             "10.100.1.0/25",
+					IPv6CIDRIndex:    1,
 					Name:             "PublicSubnet01",
 					RouteTableAssociation: RouteTableAssociation{
 						Name:           "PublicSubnetRouteTableAssociation01",
@@ -83,6 +84,7 @@ func TestAdapterSubnetsRegularFields(t *testing.T) {
 				{
 					AvailabilityZone: "eu-west-1c",
 					CIDR:             "10.100.3.0/25",
+					IPv6CIDRIndex:    2,
 					Name:             "PublicSubnet02",
 					RouteTableAssociation: RouteTableAssociation{
 						Name:           "PublicSubnetRouteTableAssociation02",
@@ -95,6 +97,7 @@ func TestAdapterSubnetsRegularFields(t *testing.T) {
 				{
 					AvailabilityZone: "eu-west-1a",
 					CIDR:             "10.100.2.128/25",
+					IPv6CIDRIndex:    3,
 					Name:             "PrivateSubnet",
 					RouteTableAssociation: RouteTableAssociation{
 						Name:           "PrivateSubnetRouteTableAssociation",
@@ -105,6 +108,7 @@ func TestAdapterSubnetsRegularFields(t *testing.T) {
 				{
 					AvailabilityZone: "eu-west-1b",
 					CIDR:             "10.100.1.128/25",
+					IPv6CIDRIndex:    4,
 					Name:             "PrivateSubnet01",
 					RouteTableAssociation: RouteTableAssociation{
 						Name:           "PrivateSubnetRouteTableAssociation01",
@@ -115,6 +119,7 @@ func TestAdapterSubnetsRegularFields(t *testing.T) {
 				{
 					AvailabilityZone: "eu-west-1c",
 					CIDR:             "10.100.3.128/25",
+					IPv6CIDRIndex:    5,
 					Name:             "PrivateSubnet02",
 					RouteTableAssociation: RouteTableAssociation{
 						Name:           "PrivateSubnetRouteTableAssociation02",
@@ -157,3 +162,142 @@ func TestAdapterSubnetsRegularFields(t *testing.T) {
 		})
 	}
 }
+
+func TestAdapterSubnetsExistingVPC(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name               string
+		annotations        map[string]string
+		expectedExisting   bool
+		expectedPublicIDs  []string
+		expectedPrivateIDs []string
+		errorMatcher       func(error) bool
+	}{
+		{
+			name:               "case 0: subnets are created without existing VPC",
+			annotations:        nil,
+			expectedExisting:   false,
+			expectedPublicIDs:  []string{"", ""},
+			expectedPrivateIDs: []string{"", ""},
+		},
+		{
+			name: "case 1: existing subnets are assigned in the order of their availability zones",
+			annotations: map[string]string{
+				"aws-operator.giantswarm.io/vpc-id":             "vpc-1",
+				"aws-operator.giantswarm.io/private-subnet-ids": "subnet-private-a,subnet-private-b",
+				"aws-operator.giantswarm.io/public-subnet-ids":  "subnet-public-a,subnet-public-b",
+			},
+			expectedExisting:   true,
+			expectedPublicIDs:  []string{"subnet-public-a", "subnet-public-b"},
+			expectedPrivateIDs: []string{"subnet-private-a", "subnet-private-b"},
+		},
+		{
+			name: "case 2: error when the number of existing subnets does not match the availability zones",
+			annotations: map[string]string{
+				"aws-operator.giantswarm.io/vpc-id":             "vpc-1",
+				"aws-operator.giantswarm.io/private-subnet-ids": "subnet-private-a",
+				"aws-operator.giantswarm.io/public-subnet-ids":  "subnet-public-a",
+			},
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := Adapter{}
+
+			customObject := v1alpha1.AWSConfig{
+				Status: v1alpha1.AWSConfigStatus{
+					AWS: v1alpha1.AWSConfigStatusAWS{
+						AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+							{Name: "eu-west-1b"},
+							{Name: "eu-west-1a"},
+						},
+					},
+				},
+			}
+			customObject.SetAnnotations(tc.annotations)
+
+			err := a.Guest.Subnets.Adapt(Config{CustomObject: customObject})
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			if a.Guest.Subnets.Existing != tc.expectedExisting {
+				t.Fatalf("got Existing %t, expected %t", a.Guest.Subnets.Existing, tc.expectedExisting)
+			}
+
+			var publicIDs, privateIDs []string
+			for _, s := range a.Guest.Subnets.PublicSubnets {
+				publicIDs = append(publicIDs, s.ID)
+			}
+			for _, s := range a.Guest.Subnets.PrivateSubnets {
+				privateIDs = append(privateIDs, s.ID)
+			}
+
+			if !reflect.DeepEqual(publicIDs, tc.expectedPublicIDs) {
+				t.Fatalf("got public subnet IDs %#v, expected %#v", publicIDs, tc.expectedPublicIDs)
+			}
+			if !reflect.DeepEqual(privateIDs, tc.expectedPrivateIDs) {
+				t.Fatalf("got private subnet IDs %#v, expected %#v", privateIDs, tc.expectedPrivateIDs)
+			}
+		})
+	}
+}
+
+func TestAdapterSubnetsIPv6(t *testing.T) {
+	t.Parallel()
+	a := Adapter{}
+
+	customObject := v1alpha1.AWSConfig{
+		Status: v1alpha1.AWSConfigStatus{
+			AWS: v1alpha1.AWSConfigStatusAWS{
+				AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+					{Name: "eu-west-1b"},
+					{Name: "eu-west-1a"},
+					{Name: "eu-west-1c"},
+				},
+			},
+		},
+	}
+	customObject.SetAnnotations(map[string]string{
+		"aws-operator.giantswarm.io/ipv6": "true",
+	})
+
+	err := a.Guest.Subnets.Adapt(Config{CustomObject: customObject})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	if !a.Guest.Subnets.IPv6 {
+		t.Fatalf("got IPv6 false, expected true")
+	}
+	if a.Guest.Subnets.IPv6SubnetCount != 6 {
+		t.Fatalf("got IPv6SubnetCount %d, expected 6", a.Guest.Subnets.IPv6SubnetCount)
+	}
+
+	var indexes []int
+	for _, s := range a.Guest.Subnets.PublicSubnets {
+		indexes = append(indexes, s.IPv6CIDRIndex)
+	}
+	for _, s := range a.Guest.Subnets.PrivateSubnets {
+		indexes = append(indexes, s.IPv6CIDRIndex)
+	}
+
+	expected := []int{0, 1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(indexes, expected) {
+		t.Fatalf("got IPv6 CIDR indexes %#v, expected %#v", indexes, expected)
+	}
+}
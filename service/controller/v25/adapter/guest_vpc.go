@@ -1,30 +1,61 @@
 package adapter
 
 import (
+	"github.com/giantswarm/microerror"
+
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 type GuestVPCAdapter struct {
-	CidrBlock        string
-	ClusterID        string
-	InstallationName string
-	HostAccountID    string
-	PeerVPCID        string
-	PeerRoleArn      string
-	Region           string
-	RegionARN        string
-	RouteTableNames  []RouteTableName
+	CidrBlock          string
+	ClusterID          string
+	ExistingVPCID      string
+	InstallationName   string
+	InterfaceEndpoints []VPCInterfaceEndpoint
+	HostAccountID      string
+	IPv6               bool
+	PeerVPCID          string
+	PeerRoleArn        string
+	PrivateSubnetNames []string
+	Region             string
+	RegionARN          string
+	RouteTableNames    []RouteTableName
+	TransitGatewayID   string
+}
+
+// VPCInterfaceEndpoint is an interface endpoint with private DNS the tenant
+// cluster nodes reach an AWS service through instead of the NAT gateways.
+type VPCInterfaceEndpoint struct {
+	Name        string
+	ServiceName string
 }
 
 func (v *GuestVPCAdapter) Adapt(cfg Config) error {
+	ipv6, err := key.IPv6(cfg.CustomObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
 	v.CidrBlock = key.StatusNetworkCIDR(cfg.CustomObject)
 	v.ClusterID = key.ClusterID(cfg.CustomObject)
+	v.ExistingVPCID = key.ExistingVPCID(cfg.CustomObject)
 	v.InstallationName = cfg.InstallationName
+	if !key.IsExistingVPC(cfg.CustomObject) {
+		for _, service := range cfg.VPCEndpoints {
+			e := VPCInterfaceEndpoint{
+				Name:        key.VPCInterfaceEndpointName(service),
+				ServiceName: key.VPCInterfaceEndpointServiceName(cfg.CustomObject, service),
+			}
+			v.InterfaceEndpoints = append(v.InterfaceEndpoints, e)
+		}
+	}
 	v.HostAccountID = cfg.ControlPlaneAccountID
+	v.IPv6 = ipv6
 	v.PeerVPCID = key.PeerID(cfg.CustomObject)
 	v.Region = key.Region(cfg.CustomObject)
 	v.RegionARN = key.RegionARN(cfg.CustomObject)
 	v.PeerRoleArn = cfg.ControlPlanePeerRoleARN
+	v.TransitGatewayID = cfg.TransitGatewayID
 
 	PublicRouteTable := RouteTableName{
 		ResourceName: key.PublicRouteTableName(0),
@@ -39,6 +70,7 @@ func (v *GuestVPCAdapter) Adapt(cfg Config) error {
 			VPCPeeringRouteName: key.VPCPeeringRouteName(i),
 		}
 		v.RouteTableNames = append(v.RouteTableNames, rtName)
+		v.PrivateSubnetNames = append(v.PrivateSubnetNames, key.PrivateSubnetName(i))
 	}
 
 	return nil
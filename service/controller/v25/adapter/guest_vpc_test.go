@@ -0,0 +1,85 @@
+package adapter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func TestAdapterVPCInterfaceEndpoints(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description       string
+		annotations       map[string]string
+		vpcEndpoints      []string
+		expectedEndpoints []VPCInterfaceEndpoint
+	}{
+		{
+			description:       "no VPC endpoints",
+			vpcEndpoints:      nil,
+			expectedEndpoints: nil,
+		},
+		{
+			description:  "VPC endpoints",
+			vpcEndpoints: []string{"ec2", "ecr.api", "elasticloadbalancing"},
+			expectedEndpoints: []VPCInterfaceEndpoint{
+				{
+					Name:        "VPCInterfaceEndpointEc2",
+					ServiceName: "com.amazonaws.eu-central-1.ec2",
+				},
+				{
+					Name:        "VPCInterfaceEndpointEcrApi",
+					ServiceName: "com.amazonaws.eu-central-1.ecr.api",
+				},
+				{
+					Name:        "VPCInterfaceEndpointElasticloadbalancing",
+					ServiceName: "com.amazonaws.eu-central-1.elasticloadbalancing",
+				},
+			},
+		},
+		{
+			description: "VPC endpoints are not managed in existing VPCs",
+			annotations: map[string]string{
+				key.ExistingVPCIDAnnotation: "vpc-1234",
+			},
+			vpcEndpoints:      []string{"ec2"},
+			expectedEndpoints: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+				Spec: v1alpha1.AWSConfigSpec{
+					AWS: v1alpha1.AWSConfigSpecAWS{
+						Region: "eu-central-1",
+					},
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+					},
+				},
+			}
+
+			a := Adapter{}
+			cfg := Config{
+				CustomObject: customObject,
+				VPCEndpoints: tc.vpcEndpoints,
+			}
+			err := a.Guest.VPC.Adapt(cfg)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if !reflect.DeepEqual(a.Guest.VPC.InterfaceEndpoints, tc.expectedEndpoints) {
+				t.Fatalf("expected interface endpoints %v, got %v", tc.expectedEndpoints, a.Guest.VPC.InterfaceEndpoints)
+			}
+		})
+	}
+}
@@ -20,6 +20,9 @@ const (
 	logEBSVolumeMountPoint = "/dev/xvdf"
 	// kubeletEBSVolumeMountPoint is the path for mounting the log EBS volume
 	kubeletEBSVolumeMountPoint = "/dev/xvdg"
+	// rootEBSVolumeMountPoint is the device name of the root EBS volume of the
+	// Container Linux images.
+	rootEBSVolumeMountPoint = "/dev/xvda"
 
 	// Subnet keys
 	subnetDescription = "description"
@@ -60,15 +63,35 @@ type Hydrater func(config Config) error
 type StackState struct {
 	Name string
 
-	DockerVolumeResourceName   string
-	MasterImageID              string
-	MasterInstanceType         string
-	MasterInstanceResourceName string
+	// Masters holds the state of every single master. Masters can differ from
+	// each other while they are replaced one after another. All masters,
+	// including the first one, are derived from Masters only.
+	Masters []StackStateMaster
+	// MasterEtcdMemberCount is the number of Masters which are etcd members.
+	// It is one less than the number of Masters while a rolling master update
+	// added a surge master. All Masters are etcd members in case it is zero.
+	MasterEtcdMemberCount int
 	// TODO the cloud config versions shouldn't be injected here. These should
 	// actually always only be the ones the operator has hard coded. No other
 	// version should be used here ever.
 	MasterCloudConfigVersion string
+	// MasterInstanceMonitoring applies to all masters alike.
 	MasterInstanceMonitoring bool
+	// MasterEtcdRestoreSnapshot is the S3 object key of the etcd backup the
+	// masters restore their etcd volumes from. It is empty in case no restore
+	// is requested.
+	MasterEtcdRestoreSnapshot string
+	// MasterEtcdVolumeEncryption is the ARN of the KMS key the etcd volumes of
+	// the masters are encrypted with, or key.VolumeEncryptionDefault. The etcd
+	// volumes keep the encryption they were created with, because changing it
+	// would replace them. It is empty for tenant clusters created before all
+	// volumes got encrypted.
+	MasterEtcdVolumeEncryption string
+
+	// EncryptionKeyRotation is the ID of the encryption key rotation the nodes
+	// are created for. It is empty in case the encryption key was never
+	// rotated.
+	EncryptionKeyRotation string
 
 	// TODO the cloud config versions shouldn't be injected here. These should
 	// actually always only be the ones the operator has hard coded. No other
@@ -83,10 +106,30 @@ type StackState struct {
 	WorkerInstanceType        string
 	WorkerMax                 int
 	WorkerMin                 int
+	// WorkerPoolsDesired holds the current desired capacity of the ASGs of the
+	// named worker pools, where the map keys are worker pool names.
+	WorkerPoolsDesired map[string]int
+	// WorkerVolumeEncryption is the ARN of the KMS key the volumes of the
+	// workers are encrypted with, or key.VolumeEncryptionDefault.
+	WorkerVolumeEncryption string
 
 	VersionBundleVersion string
 }
 
+// StackStateMaster is the state of a single master instance in the tenant
+// cluster's control plane cloud formation stack.
+type StackStateMaster struct {
+	DockerVolumeResourceName string
+	ImageID                  string
+	InstanceResourceName     string
+	InstanceType             string
+	VersionBundleVersion     string
+	// VolumeEncryption is the ARN of the KMS key the root, docker and log
+	// volumes of the master are encrypted with, or key.VolumeEncryptionDefault.
+	// It is empty for masters created before all volumes got encrypted.
+	VolumeEncryption string
+}
+
 // SmallCloudconfigConfig represents the data structure required for executing
 // the small cloudconfig template.
 type SmallCloudconfigConfig struct {
@@ -7,39 +7,68 @@ import (
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter"
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter/ssm"
 	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter/vault"
 )
 
 type baseExtension struct {
-	customObject  v1alpha1.AWSConfig
-	encrypter     encrypter.Interface
-	encryptionKey string
+	customObject   v1alpha1.AWSConfig
+	encrypter      encrypter.Interface
+	encryptionKey  string
+	registryDomain string
 }
 
 func (e *baseExtension) templateData() templateData {
 	var encrypterType string
 	var vaultAddress string
-	v, ok := e.encrypter.(*vault.Encrypter)
-	if ok {
+	switch v := e.encrypter.(type) {
+	case *vault.Encrypter:
 		encrypterType = encrypter.VaultBackend
 		vaultAddress = v.Address()
-	} else {
+	case *ssm.Encrypter:
+		encrypterType = encrypter.SSMBackend
+	default:
 		encrypterType = encrypter.KMSBackend
 	}
 	data := templateData{
-		AWSConfigSpec: e.customObject.Spec,
-		EncrypterType: encrypterType,
-		VaultAddress:  vaultAddress,
-		EncryptionKey: e.encryptionKey,
+		AWSConfigSpec:  e.customObject.Spec,
+		EncrypterType:  encrypterType,
+		VaultAddress:   vaultAddress,
+		EncryptionKey:  e.encryptionKey,
+		RegistryDomain: e.registryDomain,
 	}
 
 	return data
 }
 
-func (e *baseExtension) encrypt(ctx context.Context, data []byte) ([]byte, error) {
+// isKMSEncrypter returns whether the tenant cluster assets are encrypted with
+// the KMS key of the tenant cluster, in which case the API server encrypts
+// secrets through the KMS plugin as well.
+func isKMSEncrypter(e encrypter.Interface) bool {
+	switch e.(type) {
+	case *vault.Encrypter, *ssm.Encrypter:
+		return false
+	default:
+		return true
+	}
+}
+
+// assetEncryptionKey returns the encryption key the asset of the given name is
+// encrypted with. The SSM encrypter stores every asset in its own parameter
+// within the parameter path of the tenant cluster.
+func assetEncryptionKey(e encrypter.Interface, encryptionKey, name string) string {
+	if _, ok := e.(*ssm.Encrypter); ok {
+		return ssm.ParameterName(encryptionKey, name)
+	}
+
+	return encryptionKey
+}
+
+// encrypt encrypts the given data of the asset of the given name.
+func (e *baseExtension) encrypt(ctx context.Context, name string, data []byte) ([]byte, error) {
 	var encrypted []byte
 	{
-		e, err := e.encrypter.Encrypt(ctx, e.encryptionKey, string(data))
+		e, err := e.encrypter.Encrypt(ctx, assetEncryptionKey(e.encrypter, e.encryptionKey, name), string(data))
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
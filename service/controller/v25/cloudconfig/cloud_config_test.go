@@ -49,7 +49,7 @@ func Test_Service_CloudConfig_NewMasterTemplate(t *testing.T) {
 		if err != nil {
 			t.Fatalf("expected %#v got %#v", nil, err)
 		}
-		template, err := ccService.NewMasterTemplate(ctx, tc.CustomObject, certs.Cluster{}, tc.ClusterKeys)
+		template, err := ccService.NewMasterTemplate(ctx, tc.CustomObject, certs.Cluster{}, certs.TLS{}, tc.ClusterKeys)
 		if err != nil {
 			t.Fatalf("expected %#v got %#v", nil, err)
 		}
@@ -65,7 +65,10 @@ func Test_Service_CloudConfig_NewMasterTemplate(t *testing.T) {
 			"/etc/kubernetes/ssl/etcd/client-crt.pem.enc",
 			"/etc/kubernetes/ssl/etcd/client-key.pem.enc",
 			"decrypt-tls-assets.service",
-			"a2luZDogRW5jcnlwdGlvbkNvbmZpZwphcGlWZXJzaW9uOiB2MQpyZXNvdXJjZXM6CiAgLSByZXNvdXJjZXM6CiAgICAtIHNlY3JldHMKICAgIHByb3ZpZGVyczoKICAgIC0gYWVzY2JjOgogICAgICAgIGtleXM6CiAgICAgICAgLSBuYW1lOiBrZXkxCiAgICAgICAgICBzZWNyZXQ6IGZla2hmaXdvaXFob2lmaHdxZWZvaXF3ZWZvaWtxaHdlZgogICAgLSBpZGVudGl0eToge30=",
+			"etcd-backup.timer",
+			"/etc/kubernetes/manifests/aws-encryption-provider.yaml",
+			"reencrypt-secrets.service",
+			"a2luZDogRW5jcnlwdGlvbkNvbmZpZwphcGlWZXJzaW9uOiB2MQpyZXNvdXJjZXM6CiAgLSByZXNvdXJjZXM6CiAgICAtIHNlY3JldHMKICAgIHByb3ZpZGVyczoKICAgIC0ga21zOgogICAgICAgIG5hbWU6IGF3cy1lbmNyeXB0aW9uLXByb3ZpZGVyCiAgICAgICAgZW5kcG9pbnQ6IHVuaXg6Ly8vdmFyL3J1bi9rbXNwbHVnaW4vc29ja2V0LnNvY2sKICAgICAgICBjYWNoZXNpemU6IDEwMDAKICAgICAgICB0aW1lb3V0OiAzcwogICAgLSBhZXNjYmM6CiAgICAgICAga2V5czoKICAgICAgICAtIG5hbWU6IGtleTEKICAgICAgICAgIHNlY3JldDogZmVraGZpd29pcWhvaWZod3FlZm9pcXdlZm9pa3Fod2VmCiAgICAtIGlkZW50aXR5OiB7fQ==",
 		}
 		for _, expectedString := range expectedStrings {
 			if !strings.Contains(template, expectedString) {
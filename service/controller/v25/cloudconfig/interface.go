@@ -6,9 +6,12 @@ import (
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/certs"
 	"github.com/giantswarm/randomkeys"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 type Interface interface {
-	NewMasterTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, clusterKeys randomkeys.Cluster) (string, error)
+	NewMasterTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, etcdPeerCert certs.TLS, clusterKeys randomkeys.Cluster) (string, error)
 	NewWorkerTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster) (string, error)
+	NewWorkerPoolTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, pool key.WorkerPool) (string, error)
 }
@@ -3,6 +3,8 @@ package cloudconfig
 import (
 	"context"
 	"encoding/base64"
+	"path"
+	"strings"
 
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/certs"
@@ -12,12 +14,14 @@ import (
 
 	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
 	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter/vault"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 	"github.com/giantswarm/aws-operator/service/controller/v25/templates/cloudconfig"
 )
 
 // NewMasterTemplate generates a new master cloud config template and returns it
-// as a string.
-func (c *CloudConfig) NewMasterTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, clusterKeys randomkeys.Cluster) (string, error) {
+// as a string. The etcd peer certificate is only used by tenant clusters with
+// multiple masters.
+func (c *CloudConfig) NewMasterTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, etcdPeerCert certs.TLS, clusterKeys randomkeys.Cluster) (string, error) {
 	var err error
 
 	cc, err := controllercontext.FromContext(ctx)
@@ -33,9 +37,10 @@ func (c *CloudConfig) NewMasterTemplate(ctx context.Context, customObject v1alph
 	var params k8scloudconfig.Params
 	{
 		be := baseExtension{
-			customObject:  customObject,
-			encrypter:     c.encrypter,
-			encryptionKey: cc.Status.TenantCluster.Encryption.Key,
+			customObject:   customObject,
+			encrypter:      c.encrypter,
+			encryptionKey:  cc.Status.TenantCluster.Encryption.Key,
+			registryDomain: c.registryDomain,
 		}
 
 		params = k8scloudconfig.DefaultParams()
@@ -51,9 +56,20 @@ func (c *CloudConfig) NewMasterTemplate(ctx context.Context, customObject v1alph
 			ctlCtx:        cc,
 
 			ClusterCerts:     clusterCerts,
+			EtcdPeerCert:     etcdPeerCert,
 			RandomKeyTmplSet: randomKeyTmplSet,
 		}
 		params.Hyperkube.Apiserver.Pod.CommandExtraArgs = c.k8sAPIExtraArgs
+		// The API server reaches the KMS plugin through its unix socket.
+		if isKMSEncrypter(c.encrypter) {
+			params.Hyperkube.Apiserver.Pod.HyperkubePodHostExtraMounts = []k8scloudconfig.HyperkubePodHostMount{
+				{
+					Name:     "kmsplugin",
+					Path:     "/var/run/kmsplugin",
+					ReadOnly: false,
+				},
+			}
+		}
 		params.Hyperkube.Kubelet.Docker.CommandExtraArgs = c.k8sKubeletExtraArgs
 		params.RegistryDomain = c.registryDomain
 		params.SSOPublicKey = c.SSOPublicKey
@@ -101,6 +117,7 @@ type MasterExtension struct {
 	ctlCtx *controllercontext.Context
 
 	ClusterCerts     certs.Cluster
+	EtcdPeerCert     certs.TLS
 	RandomKeyTmplSet RandomKeyTmplSet
 }
 
@@ -197,10 +214,144 @@ func (e *MasterExtension) Files() ([]k8scloudconfig.FileAsset, error) {
 		},
 	}
 
+	// The API server encrypts secrets through the KMS plugin in case the tenant
+	// cluster assets are encrypted with KMS. Secrets written before are
+	// re-encrypted once the API server is up.
+	if isKMSEncrypter(e.encrypter) {
+		kmsPluginMeta := []k8scloudconfig.FileMetadata{
+			{
+				AssetContent: cloudconfig.KMSPluginManifest,
+				Path:         "/etc/kubernetes/manifests/aws-encryption-provider.yaml",
+				Owner: k8scloudconfig.Owner{
+					User:  FileOwnerUser,
+					Group: FileOwnerGroup,
+				},
+				Permissions: 0644,
+			},
+			{
+				AssetContent: cloudconfig.ReencryptSecretsScript,
+				Path:         "/opt/bin/reencrypt-secrets",
+				Owner: k8scloudconfig.Owner{
+					User:  FileOwnerUser,
+					Group: FileOwnerGroup,
+				},
+				Permissions: FilePermission,
+			},
+		}
+
+		filesMeta = append(filesMeta, kmsPluginMeta...)
+	}
+
+	// Tenant clusters with multiple masters run stacked etcd members on all of
+	// their masters.
+	masterCount := key.EtcdMemberCount(e.customObject, e.ctlCtx.Status.TenantCluster.MasterInstance.EtcdMemberCount)
+
+	var etcdInitialCluster string
+	var etcdSurgeMember string
+	if masterCount > 1 {
+		var err error
+		etcdInitialCluster, err = key.EtcdInitialCluster(e.customObject, masterCount)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		etcdSurgeMember, err = key.EtcdSurgeMember(e.customObject, masterCount)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		stackedEtcdMeta := []k8scloudconfig.FileMetadata{
+			{
+				AssetContent: cloudconfig.StackedEtcdScript,
+				Path:         "/opt/bin/stacked-etcd",
+				Owner: k8scloudconfig.Owner{
+					User:  FileOwnerUser,
+					Group: FileOwnerGroup,
+				},
+				Permissions: FilePermission,
+			},
+			{
+				AssetContent: cloudconfig.EtcdSurgeMemberScript,
+				Path:         "/opt/bin/etcd-surge-member",
+				Owner: k8scloudconfig.Owner{
+					User:  FileOwnerUser,
+					Group: FileOwnerGroup,
+				},
+				Permissions: FilePermission,
+			},
+			{
+				AssetContent: cloudconfig.StackedEtcdDropIn,
+				Path:         "/etc/systemd/system/etcd3.service.d/10-stacked-etcd.conf",
+				Owner: k8scloudconfig.Owner{
+					User:  FileOwnerUser,
+					Group: FileOwnerGroup,
+				},
+				Permissions: 0644,
+			},
+		}
+
+		filesMeta = append(filesMeta, stackedEtcdMeta...)
+	}
+
+	// Masters take periodic etcd backups into the S3 bucket of the tenant
+	// cluster. They restore their etcd volumes from the configured etcd backup
+	// before etcd starts.
+	{
+		etcdBackupMeta := []k8scloudconfig.FileMetadata{
+			{
+				AssetContent: cloudconfig.EtcdBackupScript,
+				Path:         "/opt/bin/etcd-backup",
+				Owner: k8scloudconfig.Owner{
+					User:  FileOwnerUser,
+					Group: FileOwnerGroup,
+				},
+				Permissions: FilePermission,
+			},
+		}
+
+		filesMeta = append(filesMeta, etcdBackupMeta...)
+	}
+
+	etcdRestoreSnapshot, err := key.EtcdRestoreSnapshot(e.customObject)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if etcdRestoreSnapshot != "" {
+		etcdRestoreMeta := []k8scloudconfig.FileMetadata{
+			{
+				AssetContent: cloudconfig.EtcdRestoreScript,
+				Path:         "/opt/bin/etcd-restore",
+				Owner: k8scloudconfig.Owner{
+					User:  FileOwnerUser,
+					Group: FileOwnerGroup,
+				},
+				Permissions: FilePermission,
+			},
+			{
+				AssetContent: cloudconfig.EtcdRestoreDropIn,
+				Path:         "/etc/systemd/system/etcd3.service.d/20-etcd-restore.conf",
+				Owner: k8scloudconfig.Owner{
+					User:  FileOwnerUser,
+					Group: FileOwnerGroup,
+				},
+				Permissions: 0644,
+			},
+		}
+
+		filesMeta = append(filesMeta, etcdRestoreMeta...)
+	}
+
 	certsMeta := []k8scloudconfig.FileMetadata{}
 	{
 		certFiles := certs.NewFilesClusterMaster(e.ClusterCerts)
 
+		// The stacked etcd members reach each other through the fixed private
+		// IPs of the masters, which only the etcd peer certificate has as IP
+		// SANs.
+		if masterCount > 1 {
+			certFiles = append(certFiles, newFilesEtcdPeer(e.EtcdPeerCert)...)
+		}
+
 		for _, f := range certFiles {
 			// TODO We should just pass ctx to Files.
 			//
@@ -208,7 +359,7 @@ func (e *MasterExtension) Files() ([]k8scloudconfig.FileAsset, error) {
 			//
 			ctx = controllercontext.NewContext(ctx, *e.ctlCtx)
 
-			data, err := e.encrypt(ctx, f.Data)
+			data, err := e.encrypt(ctx, path.Join("master", f.AbsolutePath), f.Data)
 			if err != nil {
 				return nil, microerror.Mask(err)
 			}
@@ -227,9 +378,24 @@ func (e *MasterExtension) Files() ([]k8scloudconfig.FileAsset, error) {
 		}
 	}
 
+	lbType, err := key.LoadBalancerType(e.customObject)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	var fileAssets []k8scloudconfig.FileAsset
 
 	data := e.templateData()
+	// The AWS CLI image is run with docker, which does not understand the
+	// transport prefix used for rkt.
+	data.AWSCliImage = strings.TrimPrefix(key.AWSCliContainerRegistry(e.customObject), "docker://")
+	data.EtcdBackupBucket = key.BucketName(e.customObject, e.ctlCtx.Status.TenantCluster.AWSAccountID)
+	data.EtcdBackupPrefix = key.EtcdBackupPrefix
+	data.EtcdInitialCluster = etcdInitialCluster
+	data.EtcdRestoreSnapshot = etcdRestoreSnapshot
+	data.EtcdSurgeMember = etcdSurgeMember
+	data.IngressControllerUseProxyProtocol = lbType == key.LoadBalancerTypeClassic
+	data.MasterCount = masterCount
 
 	for _, fm := range filesMeta {
 		c, err := k8scloudconfig.RenderFileAssetContent(fm.AssetContent, data)
@@ -307,6 +473,34 @@ func (e *MasterExtension) Units() ([]k8scloudconfig.UnitAsset, error) {
 			Name:         "var-log.mount",
 			Enabled:      true,
 		},
+		{
+			AssetContent: cloudconfig.EtcdBackupService,
+			Name:         "etcd-backup.service",
+			Enabled:      false,
+		},
+		{
+			AssetContent: cloudconfig.EtcdBackupTimer,
+			Name:         "etcd-backup.timer",
+			Enabled:      true,
+		},
+	}
+
+	// The surge master of rolling master updates leaves the etcd cluster when
+	// it shuts down.
+	if key.EtcdMemberCount(e.customObject, e.ctlCtx.Status.TenantCluster.MasterInstance.EtcdMemberCount) > 1 {
+		unitsMeta = append(unitsMeta, k8scloudconfig.UnitMetadata{
+			AssetContent: cloudconfig.EtcdSurgeMemberLeaveService,
+			Name:         "etcd-surge-member-leave.service",
+			Enabled:      true,
+		})
+	}
+
+	if isKMSEncrypter(e.encrypter) {
+		unitsMeta = append(unitsMeta, k8scloudconfig.UnitMetadata{
+			AssetContent: cloudconfig.ReencryptSecretsService,
+			Name:         "reencrypt-secrets.service",
+			Enabled:      true,
+		})
 	}
 
 	var newUnits []k8scloudconfig.UnitAsset
@@ -333,3 +527,20 @@ func (e *MasterExtension) VerbatimSections() []k8scloudconfig.VerbatimSection {
 
 	return newSections
 }
+
+func newFilesEtcdPeer(tls certs.TLS) certs.Files {
+	return certs.Files{
+		{
+			AbsolutePath: "/etc/kubernetes/ssl/etcd/peer-ca.pem",
+			Data:         tls.CA,
+		},
+		{
+			AbsolutePath: "/etc/kubernetes/ssl/etcd/peer-crt.pem",
+			Data:         tls.Crt,
+		},
+		{
+			AbsolutePath: "/etc/kubernetes/ssl/etcd/peer-key.pem",
+			Data:         tls.Key,
+		},
+	}
+}
@@ -15,7 +15,12 @@ import (
 func renderRandomKeyTmplSet(ctx context.Context, encrypter encrypter.Interface, key string, clusterKeys randomkeys.Cluster) (RandomKeyTmplSet, error) {
 	var randomKeyTmplSet RandomKeyTmplSet
 	{
-		tmpl, err := template.New("encryption-config").Parse(cloudconfig.EncryptionConfig)
+		encryptionConfig := cloudconfig.EncryptionConfig
+		if isKMSEncrypter(encrypter) {
+			encryptionConfig = cloudconfig.KMSEncryptionConfig
+		}
+
+		tmpl, err := template.New("encryption-config").Parse(encryptionConfig)
 		if err != nil {
 			return RandomKeyTmplSet{}, microerror.Mask(err)
 		}
@@ -29,7 +34,7 @@ func renderRandomKeyTmplSet(ctx context.Context, encrypter encrypter.Interface,
 			return RandomKeyTmplSet{}, microerror.Mask(err)
 		}
 
-		enc, err := encrypter.Encrypt(ctx, key, buf.String())
+		enc, err := encrypter.Encrypt(ctx, assetEncryptionKey(encrypter, key, "master/etc/kubernetes/encryption/k8s-encryption-config.yaml"), buf.String())
 		if err != nil {
 			return RandomKeyTmplSet{}, microerror.Mask(err)
 		}
@@ -6,7 +6,20 @@ import "github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 // AWSConfigSpec.
 type templateData struct {
 	v1alpha1.AWSConfigSpec
-	EncrypterType string
-	VaultAddress  string
-	EncryptionKey string
+	AWSCliImage         string
+	EncrypterType       string
+	VaultAddress        string
+	EncryptionKey       string
+	EtcdBackupBucket    string
+	EtcdBackupPrefix    string
+	EtcdInitialCluster  string
+	EtcdRestoreSnapshot string
+	EtcdSurgeMember     string
+	// IngressControllerUseProxyProtocol is true for tenant clusters with
+	// classic ELBs, which pass the client IP by means of the proxy protocol.
+	IngressControllerUseProxyProtocol bool
+	// MasterCount is the number of masters of the tenant cluster, not counting
+	// the surge master of rolling updates.
+	MasterCount    int
+	RegistryDomain string
 }
@@ -3,6 +3,7 @@ package cloudconfig
 import (
 	"context"
 	"encoding/base64"
+	"path"
 
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/certs"
@@ -10,12 +11,33 @@ import (
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 	"github.com/giantswarm/aws-operator/service/controller/v25/templates/cloudconfig"
 )
 
 // NewWorkerTemplate generates a new worker cloud config template and returns it
 // as a string.
 func (c *CloudConfig) NewWorkerTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster) (string, error) {
+	return c.newWorkerTemplate(ctx, customObject, clusterCerts, c.k8sKubeletExtraArgs)
+}
+
+// NewWorkerPoolTemplate generates a new worker cloud config template for the
+// given named worker pool and returns it as a string. Nodes of the worker pool
+// get labeled with the worker pool name and the worker pool's labels, and are
+// registered with the worker pool's taints.
+func (c *CloudConfig) NewWorkerPoolTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, pool key.WorkerPool) (string, error) {
+	cr := *customObject.DeepCopy()
+	cr.Spec.Cluster.Kubernetes.Kubelet.Labels = key.WorkerPoolKubeletLabels(customObject, pool)
+
+	kubeletExtraArgs := append([]string{}, c.k8sKubeletExtraArgs...)
+	if key.WorkerPoolTaintsArg(pool) != "" {
+		kubeletExtraArgs = append(kubeletExtraArgs, key.WorkerPoolTaintsArg(pool))
+	}
+
+	return c.newWorkerTemplate(ctx, cr, clusterCerts, kubeletExtraArgs)
+}
+
+func (c *CloudConfig) newWorkerTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, kubeletExtraArgs []string) (string, error) {
 	var err error
 
 	cc, err := controllercontext.FromContext(ctx)
@@ -26,9 +48,10 @@ func (c *CloudConfig) NewWorkerTemplate(ctx context.Context, customObject v1alph
 	var params k8scloudconfig.Params
 	{
 		be := baseExtension{
-			customObject:  customObject,
-			encrypter:     c.encrypter,
-			encryptionKey: cc.Status.TenantCluster.Encryption.Key,
+			customObject:   customObject,
+			encrypter:      c.encrypter,
+			encryptionKey:  cc.Status.TenantCluster.Encryption.Key,
+			registryDomain: c.registryDomain,
 		}
 
 		// Default registry, kubernetes, etcd images etcd.
@@ -42,7 +65,7 @@ func (c *CloudConfig) NewWorkerTemplate(ctx context.Context, customObject v1alph
 
 			ClusterCerts: clusterCerts,
 		}
-		params.Hyperkube.Kubelet.Docker.CommandExtraArgs = c.k8sKubeletExtraArgs
+		params.Hyperkube.Kubelet.Docker.CommandExtraArgs = kubeletExtraArgs
 		params.RegistryDomain = c.registryDomain
 		params.SSOPublicKey = c.SSOPublicKey
 
@@ -124,7 +147,7 @@ func (e *WorkerExtension) Files() ([]k8scloudconfig.FileAsset, error) {
 			//
 			ctx = controllercontext.NewContext(ctx, *e.ctlCtx)
 
-			data, err := e.encrypt(ctx, f.Data)
+			data, err := e.encrypt(ctx, path.Join("worker", f.AbsolutePath), f.Data)
 			if err != nil {
 				return nil, microerror.Mask(err)
 			}
@@ -26,6 +26,7 @@ import (
 	"github.com/giantswarm/aws-operator/service/controller/v25/detection"
 	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter"
 	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter/kms"
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter/ssm"
 	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter/vault"
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/accountid"
@@ -33,19 +34,24 @@ import (
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/bridgezone"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/cpf"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/cpi"
+	"github.com/giantswarm/aws-operator/service/controller/v25/resource/deletionprotection"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/ebsvolume"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/encryption"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/endpoints"
+	"github.com/giantswarm/aws-operator/service/controller/v25/resource/etcdbackup"
+	"github.com/giantswarm/aws-operator/service/controller/v25/resource/etcdpeercert"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/ipam"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/loadbalancer"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/migration"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/namespace"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/natgatewayaddresses"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/peerrolearn"
+	"github.com/giantswarm/aws-operator/service/controller/v25/resource/privatehostedzone"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/routetable"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/s3bucket"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/s3object"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/service"
+	"github.com/giantswarm/aws-operator/service/controller/v25/resource/stackrecovery"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/tccp"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/tccpoutputs"
 	"github.com/giantswarm/aws-operator/service/controller/v25/resource/tccpsubnet"
@@ -80,8 +86,11 @@ type ClusterResourceSetConfig struct {
 	IncludeTags                bool
 	IgnitionPath               string
 	InstallationName           string
-	IPAMNetworkRange           net.IPNet
+	IPAMNetworkRanges          []net.IPNet
 	DeleteLoggingBucket        bool
+	EBSEncryptionKey           string
+	EBSSnapshotEnabled         bool
+	EBSSnapshotRetentionDays   int
 	OIDC                       cloudconfig.OIDCConfig
 	ProjectName                string
 	Route53Enabled             bool
@@ -89,6 +98,9 @@ type ClusterResourceSetConfig struct {
 	PodInfraContainerImage     string
 	RegistryDomain             string
 	SSOPublicKey               string
+	TransitGatewayID           string
+	TransitGatewayRouteTableID string
+	VPCEndpoints               []string
 	VaultAddress               string
 }
 
@@ -150,6 +162,17 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
+	case encrypter.SSMBackend:
+		c := &ssm.EncrypterConfig{
+			Logger: config.Logger,
+
+			InstallationName: config.InstallationName,
+		}
+
+		encrypterObject, err = ssm.NewEncrypter(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
 	default:
 		return nil, microerror.Maskf(invalidConfigError, "unknown encrypter backend %q", config.EncrypterBackend)
 	}
@@ -185,6 +208,19 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 		}
 	}
 
+	var deletionProtectionResource controller.Resource
+	{
+		c := deletionprotection.Config{
+			G8sClient: config.G8sClient,
+			Logger:    config.Logger,
+		}
+
+		deletionProtectionResource, err = deletionprotection.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var accountIDResource controller.Resource
 	{
 		c := accountid.Config{
@@ -210,11 +246,43 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 		}
 	}
 
+	var certsSearcher certs.Interface
+	{
+		c := certs.Config{
+			K8sClient: config.K8sClient,
+			Logger:    config.Logger,
+
+			WatchTimeout: 5 * time.Second,
+		}
+
+		certsSearcher, err = certs.NewSearcher(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var tenantCluster tenantcluster.Interface
+	{
+		c := tenantcluster.Config{
+			CertsSearcher: certsSearcher,
+			Logger:        config.Logger,
+
+			CertID: certs.APICert,
+		}
+
+		tenantCluster, err = tenantcluster.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var encryptionResource controller.Resource
 	{
 		c := encryption.Config{
-			Encrypter: encrypterObject,
-			Logger:    config.Logger,
+			Encrypter:     encrypterObject,
+			G8sClient:     config.G8sClient,
+			Logger:        config.Logger,
+			TenantCluster: tenantCluster,
 		}
 
 		encryptionResource, err = encryption.New(c)
@@ -240,11 +308,12 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 	{
 		c := ipam.Config{
 			G8sClient: config.G8sClient,
+			K8sClient: config.K8sClient,
 			Logger:    config.Logger,
 
 			AllocatedSubnetMaskBits: config.GuestSubnetMaskBits,
 			AvailabilityZones:       config.GuestAvailabilityZones,
-			NetworkRange:            config.IPAMNetworkRange,
+			NetworkRanges:           config.IPAMNetworkRanges,
 		}
 
 		ipamResource, err = ipam.New(c)
@@ -269,6 +338,20 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 		}
 	}
 
+	var privateHostedZoneResource controller.Resource
+	{
+		c := privatehostedzone.Config{
+			Logger: config.Logger,
+
+			Route53Enabled: config.Route53Enabled,
+		}
+
+		privateHostedZoneResource, err = privatehostedzone.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var s3BucketResource controller.Resource
 	{
 		c := s3bucket.Config{
@@ -291,6 +374,33 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 		}
 	}
 
+	var etcdBackupResource controller.Resource
+	{
+		c := etcdbackup.Config{
+			Logger: config.Logger,
+		}
+
+		etcdBackupResource, err = etcdbackup.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	var etcdPeerCertResource controller.Resource
+	{
+		c := etcdpeercert.Config{
+			G8sClient: config.G8sClient,
+			Logger:    config.Logger,
+
+			ProjectName: config.ProjectName,
+		}
+
+		etcdPeerCertResource, err = etcdpeercert.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	var s3ObjectResource controller.Resource
 	{
 		c := s3object.Config{
@@ -327,6 +437,10 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 	{
 		c := ebsvolume.Config{
 			Logger: config.Logger,
+
+			InstallationName:      config.InstallationName,
+			SnapshotOnDelete:      config.EBSSnapshotEnabled,
+			SnapshotRetentionDays: config.EBSSnapshotRetentionDays,
 		}
 
 		ebsVolumeResource, err = ebsvolume.New(c)
@@ -340,14 +454,18 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 		c := tccp.Config{
 			APIWhitelist:         config.APIWhitelist,
 			EncrypterRoleManager: encrypterRoleManager,
+			K8sClient:            config.K8sClient,
 			Logger:               config.Logger,
 
 			Detection:          detectionService,
+			EBSEncryptionKey:   config.EBSEncryptionKey,
 			EncrypterBackend:   config.EncrypterBackend,
 			InstallationName:   config.InstallationName,
 			InstanceMonitoring: config.AdvancedMonitoringEC2,
 			PublicRouteTables:  config.RouteTables,
 			Route53Enabled:     config.Route53Enabled,
+			TransitGatewayID:   config.TransitGatewayID,
+			VPCEndpoints:       config.VPCEndpoints,
 		}
 
 		tccpResource, err = tccp.New(c)
@@ -387,9 +505,11 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 		c := cpf.Config{
 			Logger: config.Logger,
 
-			EncrypterBackend: config.EncrypterBackend,
-			InstallationName: config.InstallationName,
-			Route53Enabled:   config.Route53Enabled,
+			EncrypterBackend:           config.EncrypterBackend,
+			InstallationName:           config.InstallationName,
+			Route53Enabled:             config.Route53Enabled,
+			TransitGatewayID:           config.TransitGatewayID,
+			TransitGatewayRouteTableID: config.TransitGatewayRouteTableID,
 		}
 
 		cpfResource, err = cpf.New(c)
@@ -404,6 +524,7 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 			Logger: config.Logger,
 
 			InstallationName: config.InstallationName,
+			TransitGatewayID: config.TransitGatewayID,
 		}
 
 		cpiResource, err = cpi.New(c)
@@ -506,31 +627,14 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 		}
 	}
 
-	var certsSearcher certs.Interface
+	var stackRecoveryResource controller.Resource
 	{
-		c := certs.Config{
-			K8sClient: config.K8sClient,
+		c := stackrecovery.Config{
+			G8sClient: config.G8sClient,
 			Logger:    config.Logger,
-
-			WatchTimeout: 5 * time.Second,
-		}
-
-		certsSearcher, err = certs.NewSearcher(c)
-		if err != nil {
-			return nil, microerror.Mask(err)
 		}
-	}
 
-	var tenantCluster tenantcluster.Interface
-	{
-		c := tenantcluster.Config{
-			CertsSearcher: certsSearcher,
-			Logger:        config.Logger,
-
-			CertID: certs.APICert,
-		}
-
-		tenantCluster, err = tenantcluster.New(c)
+		stackRecoveryResource, err = stackrecovery.New(c)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -581,11 +685,15 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 	}
 
 	resources := []controller.Resource{
+		// deletionProtectionResource has to be executed first, so no other
+		// resource deletes anything of a protected tenant cluster.
+		deletionProtectionResource,
 		accountIDResource,
 		natGatewayAddressesResource,
 		peerRoleARNResource,
 		routeTableResource,
 		vpcCIDRResource,
+		stackRecoveryResource,
 		tccpOutputsResource,
 		tccpSubnetResource,
 		workerASGNameResource,
@@ -594,8 +702,11 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 		migrationResource,
 		ipamResource,
 		bridgeZoneResource,
+		privateHostedZoneResource,
 		encryptionResource,
 		s3BucketResource,
+		etcdBackupResource,
+		etcdPeerCertResource,
 		s3ObjectResource,
 		loadBalancerResource,
 		ebsVolumeResource,
@@ -643,12 +754,11 @@ func NewClusterResourceSet(config ClusterResourceSetConfig) (*controller.Resourc
 	initCtxFunc := func(ctx context.Context, obj interface{}) (context.Context, error) {
 		var tenantClusterAWSClients aws.Clients
 		{
-			arn, err := credential.GetARN(config.K8sClient, obj)
+			role, err := credential.GetRole(config.K8sClient, obj)
 			if err != nil {
 				return nil, microerror.Mask(err)
 			}
-			c := config.HostAWSConfig
-			c.RoleARN = arn
+			c := role.AWSConfig(config.HostAWSConfig)
 
 			tenantClusterAWSClients, err = aws.NewClients(c)
 			if err != nil {
@@ -10,3 +10,20 @@ type ContextStatusTenantClusterTCCPASG struct {
 func (a ContextStatusTenantClusterTCCPASG) IsEmpty() bool {
 	return a.DesiredCapacity == 0 && a.MaxSize == 0 && a.MinSize == 0
 }
+
+// WorkerASGNames returns the names of the ASGs of all worker pools known so
+// far, starting with the ASG of the default worker pool.
+func (t ContextStatusTenantClusterTCCP) WorkerASGNames() []string {
+	var names []string
+
+	if t.ASG.Name != "" {
+		names = append(names, t.ASG.Name)
+	}
+	for _, p := range t.WorkerPools {
+		if p.ASG.Name != "" {
+			names = append(names, p.ASG.Name)
+		}
+	}
+
+	return names
+}
@@ -39,6 +39,7 @@ type ContextStatusTenantCluster struct {
 	Encryption            ContextStatusTenantClusterEncryption
 	HostedZoneNameServers string
 	MasterInstance        ContextStatusTenantClusterMasterInstance
+	Masters               []ContextStatusTenantClusterMaster
 	TCCP                  ContextStatusTenantClusterTCCP
 	VersionBundleVersion  string
 	WorkerInstance        ContextStatusTenantClusterWorkerInstance
@@ -46,14 +47,45 @@ type ContextStatusTenantCluster struct {
 
 type ContextStatusTenantClusterEncryption struct {
 	Key string
+	// Rotation is the ID of the encryption key rotation the nodes of the tenant
+	// cluster's control plane cloud formation stack were created for. It is
+	// empty in case the encryption key was never rotated.
+	Rotation string
 }
 
 type ContextStatusTenantClusterMasterInstance struct {
+	// Count is the number of master instances managed by the tenant cluster's
+	// control plane cloud formation stack.
+	Count                    int
+	DockerVolumeResourceName string
+	// EtcdMemberCount is the number of masters which are etcd members. It is
+	// one less than Count while a rolling master update added a surge master.
+	EtcdMemberCount int
+	// EtcdRestoreSnapshot is the S3 object key of the etcd backup the masters
+	// were restored from. It is empty in case no restore was requested.
+	EtcdRestoreSnapshot string
+	// EtcdVolumeEncryption is the ARN of the KMS key the etcd volumes of the
+	// masters are encrypted with, or key.VolumeEncryptionDefault. It is empty
+	// for tenant clusters created before all volumes got encrypted.
+	EtcdVolumeEncryption string
+	Image                string
+	ResourceName         string
+	Type                 string
+	CloudConfigVersion   string
+}
+
+// ContextStatusTenantClusterMaster holds the state of a single master as found
+// in the outputs of the tenant cluster's control plane cloud formation stack.
+type ContextStatusTenantClusterMaster struct {
 	DockerVolumeResourceName string
 	Image                    string
 	ResourceName             string
 	Type                     string
-	CloudConfigVersion       string
+	VersionBundleVersion     string
+	// VolumeEncryption is the ARN of the KMS key the volumes of the master are
+	// encrypted with, or key.VolumeEncryptionDefault. It is empty for masters
+	// created before all volumes got encrypted.
+	VolumeEncryption string
 }
 
 type ContextStatusTenantClusterTCCP struct {
@@ -62,16 +94,30 @@ type ContextStatusTenantClusterTCCP struct {
 	RouteTables     []*ec2.RouteTable
 	Subnets         []*ec2.Subnet
 	VPC             ContextStatusTenantClusterTCCPVPC
+	WorkerPools     []ContextStatusTenantClusterTCCPWorkerPool
+}
+
+// ContextStatusTenantClusterTCCPWorkerPool holds the state of a single named
+// worker pool. The default worker pool is tracked in
+// ContextStatusTenantClusterTCCP.ASG.
+type ContextStatusTenantClusterTCCPWorkerPool struct {
+	ASG                  ContextStatusTenantClusterTCCPASG
+	DockerVolumeSizeGB   string
+	InstanceDistribution string
+	InstanceType         string
+	Name                 string
 }
 
 type ContextStatusTenantClusterTCCPVPC struct {
-	ID                  string
-	PeeringConnectionID string
+	ID                         string
+	PeeringConnectionID        string
+	TransitGatewayAttachmentID string
 }
 
 type ContextStatusTenantClusterWorkerInstance struct {
-	DockerVolumeSizeGB string
-	CloudConfigVersion string
-	Image              string
-	Type               string
+	DockerVolumeSizeGB   string
+	CloudConfigVersion   string
+	Image                string
+	InstanceDistribution string
+	Type                 string
 }
@@ -1,49 +1,108 @@
 package credential
 
 import (
+	"encoding/json"
+	"regexp"
+	"time"
+
 	"github.com/giantswarm/microerror"
 	"k8s.io/api/core/v1"
 	apismetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
+	clientaws "github.com/giantswarm/aws-operator/client/aws"
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 const (
 	// awsOperatorArnKey is the key in the Secret under which the ARN for the aws-operator role is held.
 	awsOperatorArnKey = "aws.awsoperator.arn"
+	// awsOperatorExternalIDKey is the key in the Secret under which the
+	// optional external ID required by the trust policy of the aws-operator
+	// role is held.
+	awsOperatorExternalIDKey = "aws.awsoperator.externalid"
+	// awsOperatorSessionDurationKey is the key in the Secret under which the
+	// optional duration of the aws-operator role sessions is held, e.g. 1h.
+	awsOperatorSessionDurationKey = "aws.awsoperator.sessionduration"
+	// awsOperatorSessionNameKey is the key in the Secret under which the
+	// optional name of the aws-operator role sessions is held.
+	awsOperatorSessionNameKey = "aws.awsoperator.sessionname"
+	// awsOperatorSessionPolicyKey is the key in the Secret under which the
+	// optional IAM policy restricting the aws-operator role sessions is held.
+	awsOperatorSessionPolicyKey = "aws.awsoperator.sessionpolicy"
+)
+
+const (
+	// minSessionDuration and maxSessionDuration are the bounds of the role
+	// session duration accepted by STS. Durations above the maximum session
+	// duration configured for the role are rejected by STS.
+	minSessionDuration = 15 * time.Minute
+	maxSessionDuration = 12 * time.Hour
+
+	// maxExternalIDLength is the maximum length of external IDs accepted by
+	// STS. It exceeds the maximum repeat count of Go regular expressions.
+	maxExternalIDLength = 1224
+)
+
+var (
+	externalIDRegexp  = regexp.MustCompile(`^[\w+=,.@:/-]{2,}$`)
+	sessionNameRegexp = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
 )
 
-func GetARN(k8sClient kubernetes.Interface, obj interface{}) (string, error) {
+// Role holds the settings for assuming the aws-operator role of a tenant
+// cluster account.
+type Role struct {
+	ARN             string
+	ExternalID      string
+	SessionDuration time.Duration
+	SessionName     string
+	SessionPolicy   string
+}
+
+// AWSConfig returns the given AWS config with the role settings applied, so
+// that the clients created with it assume the role.
+func (r Role) AWSConfig(c clientaws.Config) clientaws.Config {
+	c.RoleARN = r.ARN
+	c.RoleExternalID = r.ExternalID
+	c.RoleSessionDuration = r.SessionDuration
+	c.RoleSessionName = r.SessionName
+	c.RoleSessionPolicy = r.SessionPolicy
+
+	return c
+}
+
+// GetRole returns the settings for assuming the aws-operator role held in the
+// credential Secret of the given custom object.
+func GetRole(k8sClient kubernetes.Interface, obj interface{}) (Role, error) {
 	credential, err := readCredential(k8sClient, obj)
 	if err != nil {
-		return "", microerror.Mask(err)
+		return Role{}, microerror.Mask(err)
 	}
 
-	arn, err := getARN(credential)
+	role, err := getRole(credential)
 	if err != nil {
-		return "", microerror.Mask(err)
+		return Role{}, microerror.Mask(err)
 	}
 
-	return arn, nil
+	return role, nil
 }
 
-// GetDefaultARN is used only by the bridgezone resource. It should be removed
-// when the resource is removed.
-func GetDefaultARN(k8sClient kubernetes.Interface) (string, error) {
-	ns := "giantswarm"
-	name := "credential-default"
-	credential, err := k8sClient.CoreV1().Secrets(ns).Get(name, apismetav1.GetOptions{})
+// GetDefaultRole returns the settings for assuming the aws-operator role held
+// in the default credential Secret. It is used only by the bridgezone resource
+// and for collecting metrics of tenant clusters which do not have a credential
+// Secret.
+func GetDefaultRole(k8sClient kubernetes.Interface) (Role, error) {
+	credential, err := readDefaultCredential(k8sClient)
 	if err != nil {
-		return "", microerror.Mask(err)
+		return Role{}, microerror.Mask(err)
 	}
 
-	arn, err := getARN(credential)
+	role, err := getRole(credential)
 	if err != nil {
-		return "", microerror.Mask(err)
+		return Role{}, microerror.Mask(err)
 	}
 
-	return arn, nil
+	return role, nil
 }
 
 func getARN(credential *v1.Secret) (string, error) {
@@ -55,6 +114,44 @@ func getARN(credential *v1.Secret) (string, error) {
 	return string(arn), nil
 }
 
+func getRole(credential *v1.Secret) (Role, error) {
+	arn, err := getARN(credential)
+	if err != nil {
+		return Role{}, microerror.Mask(err)
+	}
+
+	role := Role{
+		ARN:           arn,
+		ExternalID:    string(credential.Data[awsOperatorExternalIDKey]),
+		SessionName:   string(credential.Data[awsOperatorSessionNameKey]),
+		SessionPolicy: string(credential.Data[awsOperatorSessionPolicyKey]),
+	}
+
+	if role.ExternalID != "" && (!externalIDRegexp.MatchString(role.ExternalID) || len(role.ExternalID) > maxExternalIDLength) {
+		return Role{}, microerror.Maskf(invalidConfigError, "%#q must match %#q and must not be longer than %d characters", awsOperatorExternalIDKey, externalIDRegexp.String(), maxExternalIDLength)
+	}
+	if role.SessionName != "" && !sessionNameRegexp.MatchString(role.SessionName) {
+		return Role{}, microerror.Maskf(invalidConfigError, "%#q must match %#q", awsOperatorSessionNameKey, sessionNameRegexp.String())
+	}
+	if role.SessionPolicy != "" && !json.Valid([]byte(role.SessionPolicy)) {
+		return Role{}, microerror.Maskf(invalidConfigError, "%#q must be a JSON document", awsOperatorSessionPolicyKey)
+	}
+
+	if v, ok := credential.Data[awsOperatorSessionDurationKey]; ok {
+		d, err := time.ParseDuration(string(v))
+		if err != nil {
+			return Role{}, microerror.Maskf(invalidConfigError, "%#q must be a duration, found %#q", awsOperatorSessionDurationKey, string(v))
+		}
+		if d < minSessionDuration || d > maxSessionDuration {
+			return Role{}, microerror.Maskf(invalidConfigError, "%#q must be between %s and %s, found %s", awsOperatorSessionDurationKey, minSessionDuration, maxSessionDuration, d)
+		}
+
+		role.SessionDuration = d
+	}
+
+	return role, nil
+}
+
 func readCredential(k8sClient kubernetes.Interface, obj interface{}) (*v1.Secret, error) {
 	customObject, err := key.ToCustomObject(obj)
 	if err != nil {
@@ -78,3 +175,14 @@ func readCredential(k8sClient kubernetes.Interface, obj interface{}) (*v1.Secret
 
 	return credential, nil
 }
+
+func readDefaultCredential(k8sClient kubernetes.Interface) (*v1.Secret, error) {
+	ns := "giantswarm"
+	name := "credential-default"
+	credential, err := k8sClient.CoreV1().Secrets(ns).Get(name, apismetav1.GetOptions{})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return credential, nil
+}
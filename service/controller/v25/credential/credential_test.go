@@ -0,0 +1,119 @@
+package credential
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+)
+
+func Test_getRole(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description   string
+		data          map[string]string
+		expectedRole  Role
+		expectedError func(error) bool
+	}{
+		{
+			description: "arn only",
+			data: map[string]string{
+				awsOperatorArnKey: "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator",
+			},
+			expectedRole: Role{
+				ARN: "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator",
+			},
+		},
+		{
+			description: "all settings",
+			data: map[string]string{
+				awsOperatorArnKey:             "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator",
+				awsOperatorExternalIDKey:      "d8b4a1c0-5e2f-4c3a-9b7d-1f6e8a2c4b90",
+				awsOperatorSessionDurationKey: "1h",
+				awsOperatorSessionNameKey:     "aws-operator",
+				awsOperatorSessionPolicyKey:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`,
+			},
+			expectedRole: Role{
+				ARN:             "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator",
+				ExternalID:      "d8b4a1c0-5e2f-4c3a-9b7d-1f6e8a2c4b90",
+				SessionDuration: time.Hour,
+				SessionName:     "aws-operator",
+				SessionPolicy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`,
+			},
+		},
+		{
+			description: "missing arn",
+			data: map[string]string{
+				awsOperatorExternalIDKey: "d8b4a1c0-5e2f-4c3a-9b7d-1f6e8a2c4b90",
+			},
+			expectedError: IsArnNotFoundError,
+		},
+		{
+			description: "external ID with whitespace",
+			data: map[string]string{
+				awsOperatorArnKey:        "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator",
+				awsOperatorExternalIDKey: "external id",
+			},
+			expectedError: IsInvalidConfig,
+		},
+		{
+			description: "malformed session duration",
+			data: map[string]string{
+				awsOperatorArnKey:             "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator",
+				awsOperatorSessionDurationKey: "3600",
+			},
+			expectedError: IsInvalidConfig,
+		},
+		{
+			description: "session duration too short",
+			data: map[string]string{
+				awsOperatorArnKey:             "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator",
+				awsOperatorSessionDurationKey: "5m",
+			},
+			expectedError: IsInvalidConfig,
+		},
+		{
+			description: "session name too long",
+			data: map[string]string{
+				awsOperatorArnKey:         "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator",
+				awsOperatorSessionNameKey: "aws-operator-aws-operator-aws-operator-aws-operator-aws-operator-",
+			},
+			expectedError: IsInvalidConfig,
+		},
+		{
+			description: "malformed session policy",
+			data: map[string]string{
+				awsOperatorArnKey:           "arn:aws:iam::123456789012:role/GiantSwarmAWSOperator",
+				awsOperatorSessionPolicyKey: `{"Version":`,
+			},
+			expectedError: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			secret := &v1.Secret{
+				Data: map[string][]byte{},
+			}
+			for k, v := range tc.data {
+				secret.Data[k] = []byte(v)
+			}
+
+			role, err := getRole(secret)
+			if tc.expectedError != nil {
+				if !tc.expectedError(err) {
+					t.Fatalf("unexpected error %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(role, tc.expectedRole) {
+				t.Fatalf("expected role %#v, got %#v", tc.expectedRole, role)
+			}
+		})
+	}
+}
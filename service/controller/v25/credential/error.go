@@ -28,3 +28,12 @@ var credentialNamespaceEmpty = &microerror.Error{
 func IsCredentialNamespaceEmptyError(err error) bool {
 	return microerror.Cause(err) == credentialNamespaceEmpty
 }
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
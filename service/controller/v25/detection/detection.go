@@ -2,6 +2,7 @@ package detection
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
@@ -38,6 +39,7 @@ func New(config Config) (*Detection, error) {
 //
 //     The tenant cluster's scaling max changes.
 //     The tenant cluster's scaling min changes.
+//     Any named worker pool's scaling max or min changes.
 //
 func (d *Detection) ShouldScale(ctx context.Context, cr v1alpha1.AWSConfig) (bool, error) {
 	cc, err := controllercontext.FromContext(ctx)
@@ -54,15 +56,42 @@ func (d *Detection) ShouldScale(ctx context.Context, cr v1alpha1.AWSConfig) (boo
 		return true, nil
 	}
 
+	pools, err := key.WorkerPools(cr)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	for _, p := range pools {
+		c, ok := currentWorkerPool(cc, p.Name)
+		if !ok || c.ASG.IsEmpty() {
+			continue
+		}
+
+		if c.ASG.MaxSize != p.Max {
+			d.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("detected the tenant cluster should scale due to scaling max changes of worker pool %#q", p.Name))
+			return true, nil
+		}
+		if c.ASG.MinSize != p.Min {
+			d.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("detected the tenant cluster should scale due to scaling min changes of worker pool %#q", p.Name))
+			return true, nil
+		}
+	}
+
 	return false, nil
 }
 
 // ShouldUpdate determines whether the reconciled tenant cluster should be
 // updated. A tenant cluster is only allowed to update in the following cases.
 //
-//     The master node's instance type changes.
+//     Any master's instance type or version differs from the desired one.
+//     The surge master of a rolling master update is still there.
+//     A new etcd backup is selected to restore the masters from.
+//     The encryption key of the tenant cluster got rotated.
 //     The worker node's docker volume size changes.
 //     The worker node's instance type changes.
+//     The worker node's instance distribution changes.
+//     Named worker pools are added or removed.
+//     Any named worker pool's docker volume size, instance type or instance
+//     distribution changes.
 //     The tenant cluster's version changes.
 //
 func (d *Detection) ShouldUpdate(ctx context.Context, cr v1alpha1.AWSConfig) (bool, error) {
@@ -71,8 +100,34 @@ func (d *Detection) ShouldUpdate(ctx context.Context, cr v1alpha1.AWSConfig) (bo
 		return false, microerror.Mask(err)
 	}
 
-	if cc.Status.TenantCluster.MasterInstance.Type != key.MasterInstanceType(cr) {
-		d.logger.LogCtx(ctx, "level", "debug", "message", "detected the tenant cluster should update due to master instance type changes")
+	for i, m := range cc.Status.TenantCluster.Masters {
+		if m.Type != key.MasterInstanceTypeByIndex(cr, i) {
+			d.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("detected the tenant cluster should update due to instance type changes of master %d", i))
+			return true, nil
+		}
+		if m.VersionBundleVersion != key.VersionBundleVersion(cr) {
+			d.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("detected the tenant cluster should update due to version bundle version changes of master %d", i))
+			return true, nil
+		}
+	}
+	if len(cc.Status.TenantCluster.Masters) > key.EtcdMemberCount(cr, cc.Status.TenantCluster.MasterInstance.EtcdMemberCount) {
+		d.logger.LogCtx(ctx, "level", "debug", "message", "detected the tenant cluster should update due to the surge master of a rolling master update")
+		return true, nil
+	}
+	{
+		// Removing the etcd restore snapshot does not cause an update, since the
+		// masters already run with the restored etcd data.
+		s, err := key.EtcdRestoreSnapshot(cr)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		if s != "" && cc.Status.TenantCluster.MasterInstance.EtcdRestoreSnapshot != s {
+			d.logger.LogCtx(ctx, "level", "debug", "message", "detected the tenant cluster should update due to etcd restore snapshot changes")
+			return true, nil
+		}
+	}
+	if cc.Status.TenantCluster.Encryption.Rotation != key.EncryptionKeyGeneration(cr) {
+		d.logger.LogCtx(ctx, "level", "debug", "message", "detected the tenant cluster should update due to encryption key rotation")
 		return true, nil
 	}
 	if cc.Status.TenantCluster.WorkerInstance.DockerVolumeSizeGB != key.WorkerDockerVolumeSizeGB(cr) {
@@ -83,6 +138,45 @@ func (d *Detection) ShouldUpdate(ctx context.Context, cr v1alpha1.AWSConfig) (bo
 		d.logger.LogCtx(ctx, "level", "debug", "message", "detected the tenant cluster should update due to worker instance type changes")
 		return true, nil
 	}
+	{
+		id, err := key.WorkerInstanceDistribution(cr)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		if cc.Status.TenantCluster.WorkerInstance.InstanceDistribution != key.InstanceDistributionString(id) {
+			d.logger.LogCtx(ctx, "level", "debug", "message", "detected the tenant cluster should update due to worker instance distribution changes")
+			return true, nil
+		}
+	}
+
+	pools, err := key.WorkerPools(cr)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	if len(pools) != len(cc.Status.TenantCluster.TCCP.WorkerPools) {
+		d.logger.LogCtx(ctx, "level", "debug", "message", "detected the tenant cluster should update due to worker pool changes")
+		return true, nil
+	}
+	for _, p := range pools {
+		c, ok := currentWorkerPool(cc, p.Name)
+		if !ok {
+			d.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("detected the tenant cluster should update due to new worker pool %#q", p.Name))
+			return true, nil
+		}
+
+		if c.DockerVolumeSizeGB != key.WorkerPoolDockerVolumeSizeGB(p) {
+			d.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("detected the tenant cluster should update due to docker volume size changes of worker pool %#q", p.Name))
+			return true, nil
+		}
+		if c.InstanceType != p.InstanceType {
+			d.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("detected the tenant cluster should update due to instance type changes of worker pool %#q", p.Name))
+			return true, nil
+		}
+		if c.InstanceDistribution != key.InstanceDistributionString(p.InstanceDistribution) {
+			d.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("detected the tenant cluster should update due to instance distribution changes of worker pool %#q", p.Name))
+			return true, nil
+		}
+	}
 	if cc.Status.TenantCluster.VersionBundleVersion != key.VersionBundleVersion(cr) {
 		d.logger.LogCtx(ctx, "level", "debug", "message", "detected the tenant cluster should update due to version bundle version changes")
 		return true, nil
@@ -90,3 +184,13 @@ func (d *Detection) ShouldUpdate(ctx context.Context, cr v1alpha1.AWSConfig) (bo
 
 	return false, nil
 }
+
+func currentWorkerPool(cc *controllercontext.Context, name string) (controllercontext.ContextStatusTenantClusterTCCPWorkerPool, bool) {
+	for _, p := range cc.Status.TenantCluster.TCCP.WorkerPools {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return controllercontext.ContextStatusTenantClusterTCCPWorkerPool{}, false
+}
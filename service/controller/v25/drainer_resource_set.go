@@ -131,12 +131,11 @@ func NewDrainerResourceSet(config DrainerResourceSetConfig) (*controller.Resourc
 	initCtxFunc := func(ctx context.Context, obj interface{}) (context.Context, error) {
 		var tenantClusterAWSClients aws.Clients
 		{
-			arn, err := credential.GetARN(config.K8sClient, obj)
+			role, err := credential.GetRole(config.K8sClient, obj)
 			if err != nil {
 				return nil, microerror.Mask(err)
 			}
-			c := config.HostAWSConfig
-			c.RoleARN = arn
+			c := role.AWSConfig(config.HostAWSConfig)
 
 			tenantClusterAWSClients, err = aws.NewClients(c)
 			if err != nil {
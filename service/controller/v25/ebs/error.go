@@ -40,3 +40,20 @@ func IsVolumeNotFound(err error) bool {
 
 	return false
 }
+
+// IsSnapshotNotFound asserts snapshot not found error from upstream's API code.
+func IsSnapshotNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if !ok {
+		return false
+	}
+	if aerr.Code() == "InvalidSnapshot.NotFound" {
+		return true
+	}
+
+	return false
+}
@@ -19,10 +19,22 @@ type ebsVolumeMock struct {
 	tags        []*ec2.Tag
 }
 
+func (e *EC2ClientMock) CreateSnapshot(*ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	return nil, nil
+}
+
+func (e *EC2ClientMock) DeleteSnapshot(*ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
+	return nil, nil
+}
+
 func (e *EC2ClientMock) DeleteVolume(*ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error) {
 	return nil, nil
 }
 
+func (e *EC2ClientMock) DescribeSnapshotsPages(input *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool) error {
+	return nil
+}
+
 func (e *EC2ClientMock) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
 	output := &ec2.DescribeVolumesOutput{}
 	volumes := []*ec2.Volume{}
@@ -0,0 +1,111 @@
+package ebs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/pkg/awstags"
+)
+
+const (
+	// snapshotOwnerSelf restricts snapshot lookups to the snapshots owned by
+	// the AWS account of the client.
+	snapshotOwnerSelf = "self"
+)
+
+// CreateSnapshot requests a snapshot of an EBS volume tagged with the given
+// tags and returns the ID of the snapshot. The snapshot is completed
+// asynchronously.
+func (e *EBS) CreateSnapshot(ctx context.Context, volumeID string, description string, tags map[string]string) (string, error) {
+	e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("requesting snapshot of EBS volume %#q", volumeID))
+
+	i := &ec2.CreateSnapshotInput{
+		Description: aws.String(description),
+		TagSpecifications: []*ec2.TagSpecification{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeSnapshot),
+				Tags:         awstags.NewEC2(tags),
+			},
+		},
+		VolumeId: aws.String(volumeID),
+	}
+
+	o, err := e.client.CreateSnapshot(i)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("requested snapshot %#q of EBS volume %#q", *o.SnapshotId, volumeID))
+
+	return *o.SnapshotId, nil
+}
+
+// DeleteSnapshot deletes an EBS snapshot.
+func (e *EBS) DeleteSnapshot(ctx context.Context, snapshotID string) error {
+	e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleting EBS snapshot %#q", snapshotID))
+
+	i := &ec2.DeleteSnapshotInput{
+		SnapshotId: aws.String(snapshotID),
+	}
+
+	_, err := e.client.DeleteSnapshot(i)
+	if IsSnapshotNotFound(err) {
+		// Fall through.
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleted EBS snapshot %#q", snapshotID))
+
+	return nil
+}
+
+// ListSnapshots lists the EBS snapshots owned by the AWS account which are
+// tagged with all of the given tags.
+func (e *EBS) ListSnapshots(tags map[string]string) ([]Snapshot, error) {
+	var snapshots []Snapshot
+
+	i := &ec2.DescribeSnapshotsInput{
+		OwnerIds: []*string{
+			aws.String(snapshotOwnerSelf),
+		},
+	}
+	for k, v := range tags {
+		f := &ec2.Filter{
+			Name: aws.String(fmt.Sprintf("tag:%s", k)),
+			Values: []*string{
+				aws.String(v),
+			},
+		}
+		i.Filters = append(i.Filters, f)
+	}
+
+	err := e.client.DescribeSnapshotsPages(i, func(o *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+		for _, s := range o.Snapshots {
+			t := map[string]string{}
+			for _, tag := range s.Tags {
+				t[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+
+			snapshot := Snapshot{
+				SnapshotID: aws.StringValue(s.SnapshotId),
+				State:      aws.StringValue(s.State),
+				Tags:       t,
+				VolumeID:   aws.StringValue(s.VolumeId),
+			}
+
+			snapshots = append(snapshots, snapshot)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return snapshots, nil
+}
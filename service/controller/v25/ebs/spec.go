@@ -9,6 +9,12 @@ import (
 
 // Interface describes the methods provided by the helm client.
 type Interface interface {
+	// CreateSnapshot requests a snapshot of an EBS volume tagged with the given
+	// tags and returns the ID of the snapshot. The snapshot is completed
+	// asynchronously.
+	CreateSnapshot(ctx context.Context, volumeID string, description string, tags map[string]string) (string, error)
+	// DeleteSnapshot deletes an EBS snapshot.
+	DeleteSnapshot(ctx context.Context, snapshotID string) error
 	// DeleteVolume deletes an EBS volume with retry logic.
 	DeleteVolume(ctx context.Context, volumeID string) error
 	// DetachVolume detaches an EBS volume. If force is specified data loss may
@@ -19,11 +25,17 @@ type Interface interface {
 	// persistentVolume is true then any Persistent Volumes associated with the
 	// cluster will be returned.
 	ListVolumes(customObject v1alpha1.AWSConfig, filterFuncs ...func(t *ec2.Tag) bool) ([]Volume, error)
+	// ListSnapshots lists the EBS snapshots owned by the AWS account which are
+	// tagged with all of the given tags.
+	ListSnapshots(tags map[string]string) ([]Snapshot, error)
 }
 
 // EC2Client describes the methods required to be implemented by an EC2 AWS client.
 type EC2Client interface {
+	CreateSnapshot(*ec2.CreateSnapshotInput) (*ec2.Snapshot, error)
+	DeleteSnapshot(*ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error)
 	DeleteVolume(*ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error)
+	DescribeSnapshotsPages(*ec2.DescribeSnapshotsInput, func(*ec2.DescribeSnapshotsOutput, bool) bool) error
 	DescribeVolumes(*ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
 	DetachVolume(*ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error)
 	StopInstances(*ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error)
@@ -41,3 +53,11 @@ type VolumeAttachment struct {
 	Device     string
 	InstanceID string
 }
+
+// Snapshot is an EBS snapshot of an EBS volume.
+type Snapshot struct {
+	SnapshotID string
+	State      string
+	Tags       map[string]string
+	VolumeID   string
+}
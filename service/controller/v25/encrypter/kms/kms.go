@@ -2,6 +2,7 @@ package kms
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/kms"
@@ -14,6 +15,12 @@ import (
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
+const (
+	// retiredKeyPendingWindowInDays is the number of days until retired keys
+	// get deleted.
+	retiredKeyPendingWindowInDays = 30
+)
+
 type Encrypter struct {
 	logger micrologger.Logger
 
@@ -251,6 +258,146 @@ func (e *Encrypter) IsKeyNotFound(err error) bool {
 	return IsKeyNotFound(err) || IsKeyScheduledForDeletion(err)
 }
 
+// RetireEncryptionKey schedules the deletion of the given previous key. The
+// pending window of the deletion allows to cancel it in case data encrypted
+// with the previous key turns out to be still needed.
+func (e *Encrypter) RetireEncryptionKey(ctx context.Context, cr v1alpha1.AWSConfig, previousKey string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	{
+		e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding out previous encryption key %#q", previousKey))
+
+		i := &kms.DescribeKeyInput{
+			KeyId: aws.String(previousKey),
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.KMS.DescribeKey(i)
+		if IsKeyNotFound(err) {
+			e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("did not find previous encryption key %#q", previousKey))
+			return nil
+
+		} else if err != nil {
+			return microerror.Mask(err)
+
+		} else if o.KeyMetadata.DeletionDate != nil {
+			e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("previous encryption key %#q is already scheduled for deletion", previousKey))
+			return nil
+		}
+
+		e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found previous encryption key %#q", previousKey))
+	}
+
+	{
+		e.logger.LogCtx(ctx, "level", "info", "message", fmt.Sprintf("scheduling deletion of previous encryption key %#q", previousKey))
+
+		i := &kms.ScheduleKeyDeletionInput{
+			KeyId:               aws.String(previousKey),
+			PendingWindowInDays: aws.Int64(retiredKeyPendingWindowInDays),
+		}
+
+		_, err = cc.Client.TenantCluster.AWS.KMS.ScheduleKeyDeletion(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		e.logger.LogCtx(ctx, "level", "info", "message", fmt.Sprintf("scheduled deletion of previous encryption key %#q", previousKey))
+	}
+
+	return nil
+}
+
+// CurrentEncryptionKey returns the ARN of the key the key alias of the tenant
+// cluster points to.
+func (e *Encrypter) CurrentEncryptionKey(ctx context.Context, cr v1alpha1.AWSConfig) (string, error) {
+	o, err := e.describeKey(ctx, cr)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return *o.KeyMetadata.Arn, nil
+}
+
+// RotateEncryptionKey creates a new key and points the key alias of the tenant
+// cluster to it, unless the alias already points to another key than the given
+// previous one. AWS rotates the key material of the keys yearly on its own,
+// which does not require any re-encryption. This rotation replaces the key as
+// a whole instead.
+func (e *Encrypter) RotateEncryptionKey(ctx context.Context, cr v1alpha1.AWSConfig, previousKey string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	{
+		e.logger.LogCtx(ctx, "level", "debug", "message", "finding out encryption key")
+
+		currentKey, err := e.CurrentEncryptionKey(ctx, cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		e.logger.LogCtx(ctx, "level", "debug", "message", "found encryption key")
+
+		if currentKey != previousKey {
+			e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("encryption key is already rotated from previous encryption key %#q", previousKey))
+			return nil
+		}
+	}
+
+	var keyID *string
+	{
+		e.logger.LogCtx(ctx, "level", "debug", "message", "creating encryption key")
+
+		i := &kms.CreateKeyInput{
+			Tags: awstags.NewKMS(key.ClusterTags(cr, e.installationName)),
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.KMS.CreateKey(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		keyID = o.KeyMetadata.KeyId
+
+		e.logger.LogCtx(ctx, "level", "debug", "message", "created encryption key")
+	}
+
+	{
+		e.logger.LogCtx(ctx, "level", "debug", "message", "enabling encryption key rotation")
+
+		i := &kms.EnableKeyRotationInput{
+			KeyId: keyID,
+		}
+
+		_, err = cc.Client.TenantCluster.AWS.KMS.EnableKeyRotation(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		e.logger.LogCtx(ctx, "level", "debug", "message", "enabled encryption key rotation")
+	}
+
+	{
+		e.logger.LogCtx(ctx, "level", "debug", "message", "updating encryption key alias")
+
+		i := &kms.UpdateAliasInput{
+			AliasName:   aws.String(keyAlias(cr)),
+			TargetKeyId: keyID,
+		}
+
+		_, err = cc.Client.TenantCluster.AWS.KMS.UpdateAlias(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		e.logger.LogCtx(ctx, "level", "debug", "message", "updated encryption key alias")
+	}
+
+	return nil
+}
+
 func (k *Encrypter) describeKey(ctx context.Context, cr v1alpha1.AWSConfig) (*kms.DescribeKeyOutput, error) {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
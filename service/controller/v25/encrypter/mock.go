@@ -16,6 +16,10 @@ func (e *EncrypterMock) Encrypt(ctx context.Context, key, plaintext string) (str
 	return plaintext, nil
 }
 
+func (e *EncrypterMock) CurrentEncryptionKey(context.Context, v1alpha1.AWSConfig) (string, error) {
+	return e.KeyID, nil
+}
+
 func (e *EncrypterMock) EncryptionKey(ctx context.Context, customObject v1alpha1.AWSConfig) (string, error) {
 	return "", nil
 }
@@ -28,6 +32,14 @@ func (e *EncrypterMock) EnsureDeletedEncryptionKey(context.Context, v1alpha1.AWS
 	return nil
 }
 
+func (e *EncrypterMock) RetireEncryptionKey(context.Context, v1alpha1.AWSConfig, string) error {
+	return nil
+}
+
+func (e *EncrypterMock) RotateEncryptionKey(context.Context, v1alpha1.AWSConfig, string) error {
+	return nil
+}
+
 func (e *EncrypterMock) IsKeyNotFound(err error) bool {
 	return false
 }
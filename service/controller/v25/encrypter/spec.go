@@ -8,6 +8,7 @@ import (
 
 const (
 	KMSBackend   = "kms"
+	SSMBackend   = "ssm"
 	VaultBackend = "vault"
 )
 
@@ -25,6 +26,16 @@ type Encrypter interface {
 type Resource interface {
 	EnsureCreatedEncryptionKey(context.Context, v1alpha1.AWSConfig) error
 	EnsureDeletedEncryptionKey(context.Context, v1alpha1.AWSConfig) error
+	// CurrentEncryptionKey returns the key or key version data is currently
+	// encrypted with. It is recorded before rotating the encryption key, so
+	// that an interrupted rotation can be resumed without rotating twice.
+	CurrentEncryptionKey(context.Context, v1alpha1.AWSConfig) (string, error)
+	// RotateEncryptionKey makes a new key the one used for encryption in case
+	// the given previous key is still the one used. Data encrypted with the
+	// previous key can still be decrypted until RetireEncryptionKey retires the
+	// previous key.
+	RotateEncryptionKey(ctx context.Context, customObject v1alpha1.AWSConfig, previousKey string) error
+	RetireEncryptionKey(ctx context.Context, customObject v1alpha1.AWSConfig, previousKey string) error
 }
 
 type RoleManager interface {
@@ -0,0 +1,35 @@
+package ssm
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var notSupportedError = &microerror.Error{
+	Kind: "notSupportedError",
+}
+
+// IsNotSupported asserts notSupportedError.
+func IsNotSupported(err error) bool {
+	return microerror.Cause(err) == notSupportedError
+}
+
+// IsParameterNotFound asserts ssm.ErrCodeParameterNotFound.
+func IsParameterNotFound(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok && aerr.Code() == ssm.ErrCodeParameterNotFound {
+		return true
+	}
+
+	return false
+}
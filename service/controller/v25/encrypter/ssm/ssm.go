@@ -0,0 +1,241 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+
+	"github.com/giantswarm/aws-operator/pkg/awstags"
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+const (
+	// deleteParametersBatchSize is the maximum number of parameters a single
+	// DeleteParameters request accepts.
+	deleteParametersBatchSize = 10
+)
+
+// Encrypter stores the assets of the tenant cluster as SecureString
+// parameters in the Parameter Store of the tenant cluster account instead of
+// encrypting them. The "encrypted" assets put into the cloud config are the
+// names of the parameters, which the nodes fetch using their instance
+// profile. Every fetch is recorded in CloudTrail.
+type Encrypter struct {
+	logger micrologger.Logger
+
+	installationName string
+}
+
+type EncrypterConfig struct {
+	Logger micrologger.Logger
+
+	InstallationName string
+}
+
+func NewEncrypter(c *EncrypterConfig) (*Encrypter, error) {
+	if c.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", c)
+	}
+
+	if c.InstallationName == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.InstallationName must not be empty", c)
+	}
+
+	e := &Encrypter{
+		logger: c.Logger,
+
+		installationName: c.InstallationName,
+	}
+
+	return e, nil
+}
+
+// ParameterName returns the name of the parameter the given asset is stored in
+// within the given parameter path. Every asset has its own parameter, which
+// gets overwritten when the asset changes, so that no stale parameters are
+// left behind.
+func ParameterName(parameterPath, asset string) string {
+	return path.Join(parameterPath, asset)
+}
+
+// Encrypt stores the given plaintext in the parameter of the given name and
+// returns the name of the parameter. The parameter is only written in case it
+// does not exist or holds a different value, since PutParameter requests are
+// heavily rate limited. The assets fit into standard parameters, which hold
+// values of up to 4KB.
+func (e *Encrypter) Encrypt(ctx context.Context, key, plaintext string) (string, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	var exists bool
+	{
+		i := &ssm.GetParameterInput{
+			Name:           aws.String(key),
+			WithDecryption: aws.Bool(true),
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.SSM.GetParameter(i)
+		if IsParameterNotFound(err) {
+			// The parameter gets created below.
+		} else if err != nil {
+			return "", microerror.Mask(err)
+		} else if aws.StringValue(o.Parameter.Value) == plaintext {
+			return key, nil
+		} else {
+			exists = true
+		}
+	}
+
+	{
+		i := &ssm.PutParameterInput{
+			Name:      aws.String(key),
+			Overwrite: aws.Bool(exists),
+			Type:      aws.String(ssm.ParameterTypeSecureString),
+			Value:     aws.String(plaintext),
+		}
+
+		_, err = cc.Client.TenantCluster.AWS.SSM.PutParameter(i)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+	}
+
+	if !exists {
+		i := &ssm.AddTagsToResourceInput{
+			ResourceId:   aws.String(key),
+			ResourceType: aws.String(ssm.ResourceTypeForTaggingParameter),
+			Tags:         awstags.NewSSM(e.parameterTags(key)),
+		}
+
+		_, err = cc.Client.TenantCluster.AWS.SSM.AddTagsToResource(i)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+	}
+
+	return key, nil
+}
+
+// EncryptionKey returns the parameter path the assets of the tenant cluster are
+// stored under. SecureString parameters are encrypted with the AWS managed SSM
+// key of the tenant cluster account, so there is no key to manage.
+func (e *Encrypter) EncryptionKey(ctx context.Context, cr v1alpha1.AWSConfig) (string, error) {
+	return key.SSMParameterPath(cr), nil
+}
+
+func (e *Encrypter) EnsureCreatedEncryptionKey(ctx context.Context, cr v1alpha1.AWSConfig) error {
+	return nil
+}
+
+// EnsureDeletedEncryptionKey deletes all parameters stored within the parameter
+// path of the tenant cluster.
+func (e *Encrypter) EnsureDeletedEncryptionKey(ctx context.Context, cr v1alpha1.AWSConfig) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	p := key.SSMParameterPath(cr)
+
+	var names []*string
+	{
+		e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding parameters within path %#q", p))
+
+		i := &ssm.GetParametersByPathInput{
+			Path:      aws.String(p),
+			Recursive: aws.Bool(true),
+		}
+
+		err = cc.Client.TenantCluster.AWS.SSM.GetParametersByPathPages(i, func(o *ssm.GetParametersByPathOutput, lastPage bool) bool {
+			for _, param := range o.Parameters {
+				names = append(names, param.Name)
+			}
+			return true
+		})
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d parameters within path %#q", len(names), p))
+	}
+
+	if len(names) == 0 {
+		e.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
+	{
+		e.logger.LogCtx(ctx, "level", "info", "message", fmt.Sprintf("deleting parameters within path %#q", p))
+
+		for len(names) > 0 {
+			n := deleteParametersBatchSize
+			if len(names) < n {
+				n = len(names)
+			}
+
+			i := &ssm.DeleteParametersInput{
+				Names: names[:n],
+			}
+
+			_, err = cc.Client.TenantCluster.AWS.SSM.DeleteParameters(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			names = names[n:]
+		}
+
+		e.logger.LogCtx(ctx, "level", "info", "message", fmt.Sprintf("deleted parameters within path %#q", p))
+	}
+
+	return nil
+}
+
+func (e *Encrypter) IsKeyNotFound(err error) bool {
+	return IsParameterNotFound(err)
+}
+
+// RetireEncryptionKey is not supported, since the parameters are encrypted
+// with the AWS managed SSM key of the tenant cluster account.
+func (e *Encrypter) RetireEncryptionKey(ctx context.Context, cr v1alpha1.AWSConfig, previousKey string) error {
+	return microerror.Maskf(notSupportedError, "encryption key rotation with the SSM encrypter")
+}
+
+// CurrentEncryptionKey is not supported, since the parameters are encrypted
+// with the AWS managed SSM key of the tenant cluster account.
+func (e *Encrypter) CurrentEncryptionKey(ctx context.Context, cr v1alpha1.AWSConfig) (string, error) {
+	return "", microerror.Maskf(notSupportedError, "encryption key rotation with the SSM encrypter")
+}
+
+// RotateEncryptionKey is not supported, since the parameters are encrypted
+// with the AWS managed SSM key of the tenant cluster account, which AWS rotates
+// on its own.
+func (e *Encrypter) RotateEncryptionKey(ctx context.Context, cr v1alpha1.AWSConfig, previousKey string) error {
+	return microerror.Maskf(notSupportedError, "encryption key rotation with the SSM encrypter")
+}
+
+// parameterTags returns the tags of the parameter of the given name, which is
+// stored within the parameter path of its tenant cluster.
+func (e *Encrypter) parameterTags(name string) map[string]string {
+	tags := map[string]string{
+		key.InstallationTagName: e.installationName,
+	}
+
+	// The parameter path of a tenant cluster is /giantswarm/<cluster-id>.
+	parts := strings.Split(strings.TrimPrefix(name, "/"), "/")
+	if len(parts) > 1 {
+		tags[key.ClusterTagName] = parts[1]
+	}
+
+	return tags
+}
@@ -0,0 +1,207 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+
+	awsclient "github.com/giantswarm/aws-operator/client/aws"
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+)
+
+type ssmClientMock struct {
+	ssmiface.SSMAPI
+
+	deleted    [][]string
+	parameters map[string]string
+	puts       int
+	tags       map[string]map[string]string
+}
+
+func (m *ssmClientMock) AddTagsToResource(i *ssm.AddTagsToResourceInput) (*ssm.AddTagsToResourceOutput, error) {
+	tags := map[string]string{}
+	for _, t := range i.Tags {
+		tags[*t.Key] = *t.Value
+	}
+	m.tags[*i.ResourceId] = tags
+	return &ssm.AddTagsToResourceOutput{}, nil
+}
+
+func (m *ssmClientMock) DeleteParameters(i *ssm.DeleteParametersInput) (*ssm.DeleteParametersOutput, error) {
+	m.deleted = append(m.deleted, aws.StringValueSlice(i.Names))
+	for _, n := range i.Names {
+		delete(m.parameters, *n)
+	}
+	return &ssm.DeleteParametersOutput{}, nil
+}
+
+func (m *ssmClientMock) GetParameter(i *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	v, ok := m.parameters[*i.Name]
+	if !ok {
+		return nil, awserr.New(ssm.ErrCodeParameterNotFound, "parameter not found", nil)
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssm.Parameter{Name: i.Name, Value: aws.String(v)}}, nil
+}
+
+func (m *ssmClientMock) GetParametersByPathPages(i *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool) error {
+	o := &ssm.GetParametersByPathOutput{}
+	for n := range m.parameters {
+		if strings.HasPrefix(n, *i.Path+"/") {
+			o.Parameters = append(o.Parameters, &ssm.Parameter{Name: aws.String(n)})
+		}
+	}
+	fn(o, true)
+	return nil
+}
+
+func (m *ssmClientMock) PutParameter(i *ssm.PutParameterInput) (*ssm.PutParameterOutput, error) {
+	_, ok := m.parameters[*i.Name]
+	if ok && !*i.Overwrite {
+		return nil, awserr.New(ssm.ErrCodeParameterAlreadyExists, "parameter already exists", nil)
+	}
+	m.puts++
+	m.parameters[*i.Name] = *i.Value
+	return &ssm.PutParameterOutput{}, nil
+}
+
+func Test_Encrypter(t *testing.T) {
+	cr := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "test-cluster",
+			},
+		},
+	}
+
+	e, err := NewEncrypter(&EncrypterConfig{
+		Logger: microloggertest.New(),
+
+		InstallationName: "test-installation",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	m := &ssmClientMock{
+		parameters: map[string]string{
+			"/giantswarm/other-cluster/abc": "other",
+		},
+		tags: map[string]map[string]string{},
+	}
+
+	cc := controllercontext.Context{
+		Client: controllercontext.ContextClient{
+			TenantCluster: controllercontext.ContextClientTenantCluster{
+				AWS: awsclient.Clients{
+					SSM: m,
+				},
+			},
+		},
+	}
+	ctx := controllercontext.NewContext(context.Background(), cc)
+
+	p, err := e.EncryptionKey(ctx, cr)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if p != "/giantswarm/test-cluster" {
+		t.Fatalf("expected parameter path %#q, got %#q", "/giantswarm/test-cluster", p)
+	}
+
+	// Storing the same plaintext twice must result in a single request. Eleven
+	// parameters make the deletion below use two batches.
+	for i := 0; i < 11; i++ {
+		name := ParameterName(p, fmt.Sprintf("master/asset-%d", i))
+		for j := 0; j < 2; j++ {
+			n, err := e.Encrypt(ctx, name, fmt.Sprintf("plaintext-%d", i))
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			if n != name {
+				t.Fatalf("expected parameter %#q, got %#q", name, n)
+			}
+		}
+	}
+	if m.puts != 11 {
+		t.Fatalf("expected 11 parameters to be put, got %d", m.puts)
+	}
+
+	name := "/giantswarm/test-cluster/master/asset-0"
+	if m.parameters[name] != "plaintext-0" {
+		t.Fatalf("expected parameter %#q to hold %#q, got %#q", name, "plaintext-0", m.parameters[name])
+	}
+	expectedTags := map[string]string{
+		"giantswarm.io/cluster":      "test-cluster",
+		"giantswarm.io/installation": "test-installation",
+	}
+	if !reflect.DeepEqual(m.tags[name], expectedTags) {
+		t.Fatalf("expected parameter %#q to be tagged with %#v, got %#v", name, expectedTags, m.tags[name])
+	}
+
+	// Changing the plaintext must overwrite the parameter instead of leaving a
+	// stale one behind.
+	_, err = e.Encrypt(ctx, name, "changed")
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if m.puts != 12 || m.parameters[name] != "changed" || len(m.parameters) != 12 {
+		t.Fatalf("expected parameter %#q to be overwritten, got %#v", name, m.parameters)
+	}
+
+	// Parameters deleted by someone else must be put again.
+	delete(m.parameters, name)
+	_, err = e.Encrypt(ctx, name, "changed")
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if m.puts != 13 || m.parameters[name] != "changed" {
+		t.Fatalf("expected parameter %#q to be put again, got %#v", name, m.parameters)
+	}
+
+	err = e.EnsureDeletedEncryptionKey(ctx, cr)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if len(m.deleted) != 2 || len(m.deleted[0]) != 10 || len(m.deleted[1]) != 1 {
+		t.Fatalf("expected parameters to be deleted in batches of 10, got %#v", m.deleted)
+	}
+	if !reflect.DeepEqual(m.parameters, map[string]string{"/giantswarm/other-cluster/abc": "other"}) {
+		t.Fatalf("expected parameters of other clusters to be kept, got %#v", m.parameters)
+	}
+}
+
+func Test_Encrypter_Rotation(t *testing.T) {
+	e, err := NewEncrypter(&EncrypterConfig{
+		Logger: microloggertest.New(),
+
+		InstallationName: "test-installation",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	ctx := context.Background()
+	cr := v1alpha1.AWSConfig{}
+
+	_, err = e.CurrentEncryptionKey(ctx, cr)
+	if !IsNotSupported(err) {
+		t.Fatalf("expected not supported error, got %#v", err)
+	}
+	err = e.RotateEncryptionKey(ctx, cr, "")
+	if !IsNotSupported(err) {
+		t.Fatalf("expected not supported error, got %#v", err)
+	}
+	err = e.RetireEncryptionKey(ctx, cr, "")
+	if !IsNotSupported(err) {
+		t.Fatalf("expected not supported error, got %#v", err)
+	}
+}
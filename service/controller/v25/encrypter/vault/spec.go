@@ -70,3 +70,15 @@ type AWSAuthRole struct {
 type KeyConfigPayload struct {
 	DeletionAllowed bool `json:"deletion_allowed"`
 }
+
+type KeyMinDecryptionVersionPayload struct {
+	MinDecryptionVersion int `json:"min_decryption_version"`
+}
+
+type KeyResponse struct {
+	Data KeyResponseData `json:"data"`
+}
+
+type KeyResponseData struct {
+	LatestVersion int `json:"latest_version"`
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -386,6 +387,142 @@ func (e *Encrypter) IsKeyNotFound(err error) bool {
 	return IsKeyNotFound(err)
 }
 
+// RetireEncryptionKey prevents the given previous version of the transit key
+// and all versions before from being used for decryption.
+func (e *Encrypter) RetireEncryptionKey(ctx context.Context, customObject v1alpha1.AWSConfig, previousKey string) error {
+	err := e.ensureToken()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	previousVersion, err := strconv.Atoi(previousKey)
+	if err != nil {
+		return microerror.Maskf(invalidConfigError, "previous key must be a key version, found %#q", previousKey)
+	}
+
+	{
+		e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("retiring encryption key version %d", previousVersion))
+
+		key := e.keyName(customObject)
+		path := transitKeysConfigPath(key)
+		payload := &KeyMinDecryptionVersionPayload{
+			MinDecryptionVersion: previousVersion + 1,
+		}
+
+		req, err := e.newPayloadRequest(path, payload)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		resp, err := e.httpClient.Do(req)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			body, _ := ioutil.ReadAll(resp.Body)
+			return microerror.Maskf(invalidHTTPStatusCodeError, "want 204, got %d, response body: %q", resp.StatusCode, body)
+		}
+
+		e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("retired encryption key version %d", previousVersion))
+	}
+
+	return nil
+}
+
+// CurrentEncryptionKey returns the latest version of the transit key, which is
+// the one Vault encrypts with.
+func (e *Encrypter) CurrentEncryptionKey(ctx context.Context, customObject v1alpha1.AWSConfig) (string, error) {
+	err := e.ensureToken()
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	key := e.keyName(customObject)
+	path := transitKeysPath(key)
+
+	req, err := e.newRequest("GET", path)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", microerror.Mask(keyNotFoundError)
+	} else if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return "", microerror.Maskf(invalidHTTPStatusCodeError, "want 200, got %d, response body: %q", resp.StatusCode, body)
+	}
+
+	keyResp := &KeyResponse{}
+	err = json.NewDecoder(resp.Body).Decode(keyResp)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return strconv.Itoa(keyResp.Data.LatestVersion), nil
+}
+
+// RotateEncryptionKey creates a new version of the transit key, unless the
+// latest version already differs from the given previous version. Vault
+// encrypts with the latest version of the transit key.
+func (e *Encrypter) RotateEncryptionKey(ctx context.Context, customObject v1alpha1.AWSConfig, previousKey string) error {
+	err := e.ensureToken()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	{
+		e.logger.LogCtx(ctx, "level", "debug", "message", "finding out encryption key version")
+
+		currentVersion, err := e.CurrentEncryptionKey(ctx, customObject)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found encryption key version %s", currentVersion))
+
+		if currentVersion != previousKey {
+			e.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("encryption key is already rotated from previous encryption key version %s", previousKey))
+			return nil
+		}
+	}
+
+	{
+		e.logger.LogCtx(ctx, "level", "debug", "message", "rotating encryption key")
+
+		key := e.keyName(customObject)
+		path := transitKeysRotatePath(key)
+		payload := &struct{}{}
+
+		req, err := e.newPayloadRequest(path, payload)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		resp, err := e.httpClient.Do(req)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			body, _ := ioutil.ReadAll(resp.Body)
+			return microerror.Maskf(invalidHTTPStatusCodeError, "want 204, got %d, response body: %q", resp.StatusCode, body)
+		}
+
+		e.logger.LogCtx(ctx, "level", "debug", "message", "rotated encryption key")
+	}
+
+	return nil
+}
+
 func (e *Encrypter) Decrypt(key, ciphertext string) (string, error) {
 	err := e.ensureToken()
 	if err != nil {
@@ -636,3 +773,7 @@ func transitKeysConfigPath(key string) string {
 func transitKeysPath(key string) string {
 	return path.Join("transit", "keys", key)
 }
+
+func transitKeysRotatePath(key string) string {
+	return path.Join("transit", "keys", key, "rotate")
+}
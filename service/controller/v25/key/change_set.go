@@ -0,0 +1,79 @@
+package key
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+const (
+	// ChangeSetApprovalRequiredAnnotation enables the manual approval of
+	// updates of the tenant cluster's control plane cloud formation stack. When
+	// set to "true", change sets are only executed once their name is put into
+	// the ChangeSetApprovedAnnotation.
+	ChangeSetApprovalRequiredAnnotation = "aws-operator.giantswarm.io/change-set-approval-required"
+	// ChangeSetApprovedAnnotation holds the name of the change set a human
+	// approved for execution.
+	ChangeSetApprovedAnnotation = "aws-operator.giantswarm.io/change-set-approved"
+)
+
+const (
+	// ChangeSetPreviewEventReason is the reason of the CR events holding the
+	// JSON encoded preview of a newly created change set of the tenant
+	// cluster's control plane cloud formation stack. See ChangeSetPreview.
+	ChangeSetPreviewEventReason = "ChangeSetCreated"
+)
+
+const (
+	// ChangeSetNamePrefix is the prefix of the names of all change sets the
+	// operator creates for the tenant cluster's control plane cloud formation
+	// stack.
+	ChangeSetNamePrefix = "tccp-"
+)
+
+// ChangeSetPreview is the resource level diff of a change set.
+type ChangeSetPreview struct {
+	Changes []ChangeSetPreviewChange `json:"changes"`
+	Name    string                   `json:"name"`
+}
+
+// ChangeSetPreviewChange is the change of a single resource of a change set.
+// Replacement is one of "True", "False" or "Conditional", as reported by Cloud
+// Formation.
+type ChangeSetPreviewChange struct {
+	Action            string `json:"action"`
+	LogicalResourceID string `json:"logicalResourceID"`
+	Replacement       string `json:"replacement,omitempty"`
+	ResourceType      string `json:"resourceType"`
+}
+
+// ChangeSetApprovalRequired returns true in case change sets of the tenant
+// cluster's control plane cloud formation stack must be approved before they
+// get executed.
+func ChangeSetApprovalRequired(customObject v1alpha1.AWSConfig) bool {
+	v, err := strconv.ParseBool(customObject.GetAnnotations()[ChangeSetApprovalRequiredAnnotation])
+	if err != nil {
+		return false
+	}
+
+	return v
+}
+
+// ChangeSetApproved returns true in case the change set with the given name is
+// approved for execution.
+func ChangeSetApproved(customObject v1alpha1.AWSConfig, name string) bool {
+	return customObject.GetAnnotations()[ChangeSetApprovedAnnotation] == name
+}
+
+// ChangeSetName returns the name of the change set applying the given template
+// body. The name is derived from the template body and the version bundle
+// version so that the same update always results in the same change set.
+func ChangeSetName(customObject v1alpha1.AWSConfig, templateBody string) string {
+	h := sha256.New()
+	h.Write([]byte(VersionBundleVersion(customObject)))
+	h.Write([]byte(templateBody))
+
+	return fmt.Sprintf("%s%x", ChangeSetNamePrefix, h.Sum(nil)[:8])
+}
@@ -0,0 +1,98 @@
+package key
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_ChangeSetApproval(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description              string
+		annotations              map[string]string
+		expectedApprovalRequired bool
+		expectedApproved         bool
+	}{
+		{
+			description:              "no annotations",
+			annotations:              nil,
+			expectedApprovalRequired: false,
+			expectedApproved:         false,
+		},
+		{
+			description: "approval required but not approved",
+			annotations: map[string]string{
+				ChangeSetApprovalRequiredAnnotation: "true",
+			},
+			expectedApprovalRequired: true,
+			expectedApproved:         false,
+		},
+		{
+			description: "approval required and other change set approved",
+			annotations: map[string]string{
+				ChangeSetApprovalRequiredAnnotation: "true",
+				ChangeSetApprovedAnnotation:         "tccp-other",
+			},
+			expectedApprovalRequired: true,
+			expectedApproved:         false,
+		},
+		{
+			description: "approval required and approved",
+			annotations: map[string]string{
+				ChangeSetApprovalRequiredAnnotation: "true",
+				ChangeSetApprovedAnnotation:         "tccp-0123456789abcdef",
+			},
+			expectedApprovalRequired: true,
+			expectedApproved:         true,
+		},
+		{
+			description: "malformed approval required annotation",
+			annotations: map[string]string{
+				ChangeSetApprovalRequiredAnnotation: "yes please",
+			},
+			expectedApprovalRequired: false,
+			expectedApproved:         false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			if ChangeSetApprovalRequired(customObject) != tc.expectedApprovalRequired {
+				t.Fatalf("expected approval required %t, got %t", tc.expectedApprovalRequired, !tc.expectedApprovalRequired)
+			}
+			if ChangeSetApproved(customObject, "tccp-0123456789abcdef") != tc.expectedApproved {
+				t.Fatalf("expected approved %t, got %t", tc.expectedApproved, !tc.expectedApproved)
+			}
+		})
+	}
+}
+
+func Test_ChangeSetName(t *testing.T) {
+	t.Parallel()
+	customObject := v1alpha1.AWSConfig{}
+	customObject.Spec.VersionBundle.Version = "1.0.0"
+
+	name := ChangeSetName(customObject, "template")
+
+	// Change set names must start with a letter and only contain alphanumeric
+	// characters and hyphens.
+	if !regexp.MustCompile("^[a-zA-Z][-a-zA-Z0-9]*$").MatchString(name) {
+		t.Fatalf("expected valid change set name, got %#q", name)
+	}
+	if ChangeSetName(customObject, "template") != name {
+		t.Fatalf("expected same change set name for same template")
+	}
+	if ChangeSetName(customObject, "other template") == name {
+		t.Fatalf("expected different change set name for different template")
+	}
+
+	customObject.Spec.VersionBundle.Version = "2.0.0"
+	if ChangeSetName(customObject, "template") == name {
+		t.Fatalf("expected different change set name for different version bundle version")
+	}
+}
@@ -0,0 +1,32 @@
+package key
+
+import (
+	"strconv"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// DeletionProtectionAnnotation protects a tenant cluster from being deleted
+	// when set to "true". The operator then refuses to delete any resource of
+	// the tenant cluster and enables the termination protection of its TCCP
+	// cloud formation stack.
+	DeletionProtectionAnnotation = "aws-operator.giantswarm.io/deletion-protection"
+)
+
+// DeletionProtection returns whether the tenant cluster is protected from
+// being deleted.
+func DeletionProtection(customObject v1alpha1.AWSConfig) (bool, error) {
+	v, ok := customObject.GetAnnotations()[DeletionProtectionAnnotation]
+	if !ok || v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, microerror.Maskf(invalidConfigError, "annotation %#q: %s", DeletionProtectionAnnotation, err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,65 @@
+package key
+
+import (
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_DeletionProtection(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description       string
+		annotations       map[string]string
+		expectedProtected bool
+		expectedError     bool
+	}{
+		{
+			description:       "not protected by default",
+			annotations:       nil,
+			expectedProtected: false,
+		},
+		{
+			description: "protected",
+			annotations: map[string]string{
+				DeletionProtectionAnnotation: "true",
+			},
+			expectedProtected: true,
+		},
+		{
+			description: "explicitly not protected",
+			annotations: map[string]string{
+				DeletionProtectionAnnotation: "false",
+			},
+			expectedProtected: false,
+		},
+		{
+			description: "malformed",
+			annotations: map[string]string{
+				DeletionProtectionAnnotation: "yes please",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			protected, err := DeletionProtection(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			if protected != tc.expectedProtected {
+				t.Fatalf("expected %t, got %t", tc.expectedProtected, protected)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package key
+
+import (
+	"regexp"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// EBSEncryptionKeyAnnotation holds the ARN of the customer managed KMS key
+	// the EBS volumes of a tenant cluster are encrypted with. It overrides the
+	// key configured for the installation. Volumes are encrypted with the
+	// default EBS key of the tenant cluster account in case neither is set.
+	EBSEncryptionKeyAnnotation = "aws-operator.giantswarm.io/ebs-encryption-key"
+)
+
+const (
+	// VolumeEncryptionDefault means EBS volumes are encrypted with the default
+	// EBS key of the tenant cluster account.
+	VolumeEncryptionDefault = "default"
+)
+
+const (
+	EtcdVolumeEncryptionKey   = "EtcdVolumeEncryption"
+	MasterVolumeEncryptionKey = "MasterVolumeEncryption"
+)
+
+// ebsEncryptionKeyRegexp matches the ARNs of KMS keys. Key IDs and aliases are
+// not supported by launch templates.
+var ebsEncryptionKeyRegexp = regexp.MustCompile("^arn:aws[a-z-]*:kms:[a-z0-9-]+:[0-9]{12}:key/[a-zA-Z0-9-]+$")
+
+// IsEBSEncryptionKey returns true in case the given value is the ARN of a KMS
+// key EBS volumes can be encrypted with.
+func IsEBSEncryptionKey(v string) bool {
+	return ebsEncryptionKeyRegexp.MatchString(v)
+}
+
+// VolumeEncryption returns the ARN of the KMS key new EBS volumes of the
+// tenant cluster are encrypted with. The given default key is the one
+// configured for the installation. VolumeEncryptionDefault is returned in case
+// neither the tenant cluster nor the installation configure a key.
+func VolumeEncryption(customObject v1alpha1.AWSConfig, defaultKey string) (string, error) {
+	v := customObject.GetAnnotations()[EBSEncryptionKeyAnnotation]
+	if v == "" {
+		v = defaultKey
+	}
+	if v == "" {
+		return VolumeEncryptionDefault, nil
+	}
+
+	if !IsEBSEncryptionKey(v) {
+		return "", microerror.Maskf(invalidConfigError, "annotation %#q must be the ARN of a KMS key, found %#q", EBSEncryptionKeyAnnotation, v)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,75 @@
+package key
+
+import (
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_VolumeEncryption(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description              string
+		annotations              map[string]string
+		defaultKey               string
+		expectedVolumeEncryption string
+		expectedError            bool
+	}{
+		{
+			description:              "default EBS key",
+			annotations:              nil,
+			defaultKey:               "",
+			expectedVolumeEncryption: VolumeEncryptionDefault,
+		},
+		{
+			description:              "key of the installation",
+			annotations:              nil,
+			defaultKey:               "arn:aws:kms:eu-central-1:000000000000:key/12345678-1234-1234-1234-123456789012",
+			expectedVolumeEncryption: "arn:aws:kms:eu-central-1:000000000000:key/12345678-1234-1234-1234-123456789012",
+		},
+		{
+			description: "key of the tenant cluster overrides the key of the installation",
+			annotations: map[string]string{
+				EBSEncryptionKeyAnnotation: "arn:aws-cn:kms:cn-north-1:000000000000:key/abcdef",
+			},
+			defaultKey:               "arn:aws:kms:eu-central-1:000000000000:key/12345678-1234-1234-1234-123456789012",
+			expectedVolumeEncryption: "arn:aws-cn:kms:cn-north-1:000000000000:key/abcdef",
+		},
+		{
+			description: "key alias",
+			annotations: map[string]string{
+				EBSEncryptionKeyAnnotation: "arn:aws:kms:eu-central-1:000000000000:alias/ebs",
+			},
+			expectedError: true,
+		},
+		{
+			description: "key ID",
+			annotations: map[string]string{
+				EBSEncryptionKeyAnnotation: "12345678-1234-1234-1234-123456789012",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			v, err := VolumeEncryption(customObject, tc.defaultKey)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if v != tc.expectedVolumeEncryption {
+				t.Fatalf("expected volume encryption %#q, got %#q", tc.expectedVolumeEncryption, v)
+			}
+		})
+	}
+}
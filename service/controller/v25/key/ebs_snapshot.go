@@ -0,0 +1,58 @@
+package key
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// EBSSnapshotOnDeleteAnnotation enables or disables snapshotting the etcd
+	// and persistent volumes of a tenant cluster before they are deleted
+	// together with the tenant cluster. It overrides the installation wide
+	// default.
+	EBSSnapshotOnDeleteAnnotation = "aws-operator.giantswarm.io/ebs-snapshot-on-delete"
+)
+
+const (
+	// EBSSnapshotExpirationTagName is used to tag EBS snapshots with the time
+	// after which they get deleted, formatted as RFC 3339.
+	EBSSnapshotExpirationTagName = "giantswarm.io/snapshot-expiration"
+)
+
+// EBSSnapshotOnDelete returns whether the etcd and persistent volumes of the
+// tenant cluster are snapshotted before they are deleted. The given default
+// applies in case the tenant cluster does not define it.
+func EBSSnapshotOnDelete(customObject v1alpha1.AWSConfig, defaultEnabled bool) (bool, error) {
+	v, ok := customObject.GetAnnotations()[EBSSnapshotOnDeleteAnnotation]
+	if !ok || v == "" {
+		return defaultEnabled, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, microerror.Maskf(invalidConfigError, "annotation %#q: %s", EBSSnapshotOnDeleteAnnotation, err)
+	}
+
+	return b, nil
+}
+
+// EBSSnapshotExpired returns whether an EBS snapshot is expired according to
+// the value of its expiration tag. Snapshots with malformed expiration tags
+// are never considered expired.
+func EBSSnapshotExpired(expiration string, now time.Time) bool {
+	t, err := time.Parse(time.RFC3339, expiration)
+	if err != nil {
+		return false
+	}
+
+	return now.After(t)
+}
+
+// EBSSnapshotExpiration returns the value of the expiration tag of EBS
+// snapshots taken at the given time and kept for the given number of days.
+func EBSSnapshotExpiration(now time.Time, retentionDays int) string {
+	return now.UTC().Add(time.Duration(retentionDays) * 24 * time.Hour).Format(time.RFC3339)
+}
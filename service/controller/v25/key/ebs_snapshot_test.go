@@ -0,0 +1,113 @@
+package key
+
+import (
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_EBSSnapshotOnDelete(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description     string
+		annotations     map[string]string
+		defaultEnabled  bool
+		expectedEnabled bool
+		expectedError   bool
+	}{
+		{
+			description:     "default disabled",
+			annotations:     nil,
+			defaultEnabled:  false,
+			expectedEnabled: false,
+		},
+		{
+			description:     "default enabled",
+			annotations:     nil,
+			defaultEnabled:  true,
+			expectedEnabled: true,
+		},
+		{
+			description: "opt in",
+			annotations: map[string]string{
+				EBSSnapshotOnDeleteAnnotation: "true",
+			},
+			defaultEnabled:  false,
+			expectedEnabled: true,
+		},
+		{
+			description: "opt out",
+			annotations: map[string]string{
+				EBSSnapshotOnDeleteAnnotation: "false",
+			},
+			defaultEnabled:  true,
+			expectedEnabled: false,
+		},
+		{
+			description: "malformed",
+			annotations: map[string]string{
+				EBSSnapshotOnDeleteAnnotation: "sure",
+			},
+			defaultEnabled: true,
+			expectedError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			enabled, err := EBSSnapshotOnDelete(customObject, tc.defaultEnabled)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			if enabled != tc.expectedEnabled {
+				t.Fatalf("expected %t, got %t", tc.expectedEnabled, enabled)
+			}
+		})
+	}
+}
+
+func Test_EBSSnapshotExpired(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description     string
+		expiration      string
+		expectedExpired bool
+	}{
+		{
+			description:     "retention window not over",
+			expiration:      EBSSnapshotExpiration(now, 1),
+			expectedExpired: false,
+		},
+		{
+			description:     "retention window over",
+			expiration:      EBSSnapshotExpiration(now.Add(-48*time.Hour), 1),
+			expectedExpired: true,
+		},
+		{
+			description:     "malformed expiration",
+			expiration:      "tomorrow",
+			expectedExpired: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			expired := EBSSnapshotExpired(tc.expiration, now)
+			if expired != tc.expectedExpired {
+				t.Fatalf("expected %t, got %t", tc.expectedExpired, expired)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package key
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// EncryptionKeyRotationAnnotation holds the ID of the encryption key
+	// rotation requested for a tenant cluster. Setting or changing it rotates
+	// the encryption key, re-uploads the encrypted cloud configs, replaces all
+	// nodes and finally retires the previous encryption key.
+	EncryptionKeyRotationAnnotation = "aws-operator.giantswarm.io/encryption-key-rotation"
+	// EncryptionKeyRotationStateAnnotation holds the JSON encoded state of the
+	// latest encryption key rotation of a tenant cluster. It is managed by the
+	// encryption resource.
+	EncryptionKeyRotationStateAnnotation = "aws-operator.giantswarm.io/encryption-key-rotation-state"
+)
+
+const (
+	// EncryptionKeyRotationPhaseRotating means the rotation was started and
+	// the previous key got recorded, but the encryption key might not be
+	// rotated yet.
+	EncryptionKeyRotationPhaseRotating = "Rotating"
+	// EncryptionKeyRotationPhaseKeyRotated means the encryption key got
+	// rotated and the nodes are about to be replaced.
+	EncryptionKeyRotationPhaseKeyRotated = "KeyRotated"
+	// EncryptionKeyRotationPhaseCompleted means all nodes got replaced, the
+	// secrets got re-encrypted and the previous encryption key got retired.
+	EncryptionKeyRotationPhaseCompleted = "Completed"
+)
+
+const (
+	EncryptionKeyRotationKey = "EncryptionKeyRotation"
+)
+
+// encryptionKeyRotationRegexp restricts encryption key rotation IDs to values
+// which are safe to be used in S3 object keys.
+var encryptionKeyRotationRegexp = regexp.MustCompile("^[a-z0-9]([a-z0-9-]*[a-z0-9])?$")
+
+// EncryptionKeyRotationState is the state of an encryption key rotation. The
+// previous key is the key or key version which gets retired once the rotation
+// completes.
+type EncryptionKeyRotationState struct {
+	ID          string `json:"id"`
+	Phase       string `json:"phase"`
+	PreviousKey string `json:"previousKey,omitempty"`
+}
+
+// EncryptionKeyRotation returns the ID of the encryption key rotation
+// requested for the tenant cluster. The empty string is returned in case no
+// rotation is requested.
+func EncryptionKeyRotation(customObject v1alpha1.AWSConfig) (string, error) {
+	v := customObject.GetAnnotations()[EncryptionKeyRotationAnnotation]
+	if v == "" {
+		return "", nil
+	}
+
+	if !encryptionKeyRotationRegexp.MatchString(v) {
+		return "", microerror.Maskf(invalidConfigError, "annotation %#q must match %#q, found %#q", EncryptionKeyRotationAnnotation, encryptionKeyRotationRegexp.String(), v)
+	}
+
+	return v, nil
+}
+
+// EncryptionKeyRotationStatus returns the state of the latest encryption key
+// rotation of the tenant cluster. The zero value is returned in case the
+// encryption key was never rotated.
+func EncryptionKeyRotationStatus(customObject v1alpha1.AWSConfig) (EncryptionKeyRotationState, error) {
+	v := customObject.GetAnnotations()[EncryptionKeyRotationStateAnnotation]
+	if v == "" {
+		return EncryptionKeyRotationState{}, nil
+	}
+
+	var s EncryptionKeyRotationState
+	err := json.Unmarshal([]byte(v), &s)
+	if err != nil {
+		return EncryptionKeyRotationState{}, microerror.Maskf(invalidConfigError, "annotation %#q: %s", EncryptionKeyRotationStateAnnotation, err)
+	}
+
+	if !encryptionKeyRotationRegexp.MatchString(s.ID) {
+		return EncryptionKeyRotationState{}, microerror.Maskf(invalidConfigError, "annotation %#q must hold an ID matching %#q, found %#q", EncryptionKeyRotationStateAnnotation, encryptionKeyRotationRegexp.String(), s.ID)
+	}
+
+	return s, nil
+}
+
+// EncryptionKeyGeneration returns the ID of the encryption key rotation the
+// cloud configs of the tenant cluster are encrypted for. The empty string is
+// returned in case the encryption key was never rotated. The generation is
+// part of the S3 object keys of the cloud configs, so that rotating the
+// encryption key changes the user data of all nodes. Invalid states are
+// reported by the encryption resource, which runs before any resource using
+// the generation.
+func EncryptionKeyGeneration(customObject v1alpha1.AWSConfig) string {
+	s, err := EncryptionKeyRotationStatus(customObject)
+	if err != nil {
+		return ""
+	}
+
+	return s.ID
+}
@@ -0,0 +1,140 @@
+package key
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_EncryptionKeyRotation(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description   string
+		annotations   map[string]string
+		expectedID    string
+		expectedError bool
+	}{
+		{
+			description: "no rotation",
+			annotations: nil,
+			expectedID:  "",
+		},
+		{
+			description: "rotation",
+			annotations: map[string]string{
+				EncryptionKeyRotationAnnotation: "2019-05-01",
+			},
+			expectedID: "2019-05-01",
+		},
+		{
+			description: "rotation ID with slashes",
+			annotations: map[string]string{
+				EncryptionKeyRotationAnnotation: "../master",
+			},
+			expectedError: true,
+		},
+		{
+			description: "rotation ID with upper case letters",
+			annotations: map[string]string{
+				EncryptionKeyRotationAnnotation: "Rotation",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			id, err := EncryptionKeyRotation(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if id != tc.expectedID {
+				t.Fatalf("expected rotation ID %#q, got %#q", tc.expectedID, id)
+			}
+		})
+	}
+}
+
+func Test_EncryptionKeyRotationStatus(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description        string
+		annotations        map[string]string
+		expectedState      EncryptionKeyRotationState
+		expectedGeneration string
+		expectedError      bool
+	}{
+		{
+			description:        "never rotated",
+			annotations:        nil,
+			expectedState:      EncryptionKeyRotationState{},
+			expectedGeneration: "",
+		},
+		{
+			description: "rotation in progress",
+			annotations: map[string]string{
+				EncryptionKeyRotationStateAnnotation: `{"id":"2019-05-01","phase":"KeyRotated","previousKey":"1"}`,
+			},
+			expectedState: EncryptionKeyRotationState{
+				ID:          "2019-05-01",
+				Phase:       EncryptionKeyRotationPhaseKeyRotated,
+				PreviousKey: "1",
+			},
+			expectedGeneration: "2019-05-01",
+		},
+		{
+			description: "malformed state",
+			annotations: map[string]string{
+				EncryptionKeyRotationStateAnnotation: `{"id":`,
+			},
+			expectedGeneration: "",
+			expectedError:      true,
+		},
+		{
+			description: "state with invalid ID",
+			annotations: map[string]string{
+				EncryptionKeyRotationStateAnnotation: `{"id":"../master","phase":"KeyRotated"}`,
+			},
+			expectedGeneration: "",
+			expectedError:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			g := EncryptionKeyGeneration(customObject)
+			if g != tc.expectedGeneration {
+				t.Fatalf("expected generation %#q, got %#q", tc.expectedGeneration, g)
+			}
+
+			s, err := EncryptionKeyRotationStatus(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(s, tc.expectedState) {
+				t.Fatalf("expected state %#v, got %#v", tc.expectedState, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package key
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// EtcdBackupRetentionDaysAnnotation holds the number of days etcd backups
+	// of a tenant cluster are kept in its S3 bucket. See
+	// DefaultEtcdBackupRetentionDays.
+	EtcdBackupRetentionDaysAnnotation = "aws-operator.giantswarm.io/etcd-backup-retention-days"
+	// EtcdRestoreSnapshotAnnotation holds the S3 object key of the etcd backup
+	// the masters of a tenant cluster restore their etcd volumes from. Setting
+	// or changing it replaces all masters. The etcd volumes get recreated with
+	// the current EBS encryption in case they are not encrypted yet.
+	EtcdRestoreSnapshotAnnotation = "aws-operator.giantswarm.io/etcd-restore-snapshot"
+)
+
+const (
+	// DefaultEtcdBackupRetentionDays is the number of days etcd backups are
+	// kept in case the tenant cluster does not define it.
+	DefaultEtcdBackupRetentionDays = 7
+	// EtcdBackupLifecycleRuleID is the ID of the S3 bucket lifecycle rule
+	// expiring etcd backups.
+	EtcdBackupLifecycleRuleID = "ExpirationEtcdBackups"
+	// EtcdBackupPrefix is the S3 object key prefix of all etcd backups in the
+	// S3 bucket of a tenant cluster.
+	EtcdBackupPrefix = "etcd-backups/"
+)
+
+const (
+	EtcdRestoreSnapshotKey = "EtcdRestoreSnapshot"
+)
+
+// etcdRestoreSnapshotRegexp restricts etcd restore snapshots to S3 object keys
+// below the etcd backup prefix, which are safe to be used in scripts.
+var etcdRestoreSnapshotRegexp = regexp.MustCompile("^" + EtcdBackupPrefix + "[a-zA-Z0-9._-]+$")
+
+// EtcdBackupRetentionDays returns the number of days etcd backups of the
+// tenant cluster are kept.
+func EtcdBackupRetentionDays(customObject v1alpha1.AWSConfig) (int, error) {
+	v, ok := customObject.GetAnnotations()[EtcdBackupRetentionDaysAnnotation]
+	if !ok || v == "" {
+		return DefaultEtcdBackupRetentionDays, nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, microerror.Maskf(invalidConfigError, "annotation %#q: %s", EtcdBackupRetentionDaysAnnotation, err)
+	}
+	if i < 1 {
+		return 0, microerror.Maskf(invalidConfigError, "annotation %#q must be at least 1, found %d", EtcdBackupRetentionDaysAnnotation, i)
+	}
+
+	return i, nil
+}
+
+// EtcdRestoreSnapshot returns the S3 object key of the etcd backup the masters
+// of the tenant cluster restore their etcd volumes from. The empty string is
+// returned in case no restore is requested.
+func EtcdRestoreSnapshot(customObject v1alpha1.AWSConfig) (string, error) {
+	v := customObject.GetAnnotations()[EtcdRestoreSnapshotAnnotation]
+	if v == "" {
+		return "", nil
+	}
+
+	if !etcdRestoreSnapshotRegexp.MatchString(v) {
+		return "", microerror.Maskf(invalidConfigError, "annotation %#q must match %#q, found %#q", EtcdRestoreSnapshotAnnotation, etcdRestoreSnapshotRegexp.String(), v)
+	}
+
+	return v, nil
+}
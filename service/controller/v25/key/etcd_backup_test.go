@@ -0,0 +1,125 @@
+package key
+
+import (
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_EtcdBackupRetentionDays(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description   string
+		annotations   map[string]string
+		expectedDays  int
+		expectedError bool
+	}{
+		{
+			description:  "default retention",
+			annotations:  nil,
+			expectedDays: DefaultEtcdBackupRetentionDays,
+		},
+		{
+			description: "custom retention",
+			annotations: map[string]string{
+				EtcdBackupRetentionDaysAnnotation: "30",
+			},
+			expectedDays: 30,
+		},
+		{
+			description: "zero retention",
+			annotations: map[string]string{
+				EtcdBackupRetentionDaysAnnotation: "0",
+			},
+			expectedError: true,
+		},
+		{
+			description: "malformed retention",
+			annotations: map[string]string{
+				EtcdBackupRetentionDaysAnnotation: "a week",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			days, err := EtcdBackupRetentionDays(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if days != tc.expectedDays {
+				t.Fatalf("expected %d days, got %d", tc.expectedDays, days)
+			}
+		})
+	}
+}
+
+func Test_EtcdRestoreSnapshot(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description      string
+		annotations      map[string]string
+		expectedSnapshot string
+		expectedError    bool
+	}{
+		{
+			description:      "no restore",
+			annotations:      nil,
+			expectedSnapshot: "",
+		},
+		{
+			description: "valid snapshot",
+			annotations: map[string]string{
+				EtcdRestoreSnapshotAnnotation: "etcd-backups/20190102T030405Z.db",
+			},
+			expectedSnapshot: "etcd-backups/20190102T030405Z.db",
+		},
+		{
+			description: "snapshot outside of the backup prefix",
+			annotations: map[string]string{
+				EtcdRestoreSnapshotAnnotation: "version/1.0.0/cloudconfig/master",
+			},
+			expectedError: true,
+		},
+		{
+			description: "snapshot with shell characters",
+			annotations: map[string]string{
+				EtcdRestoreSnapshotAnnotation: "etcd-backups/$(reboot).db",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			snapshot, err := EtcdRestoreSnapshot(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if snapshot != tc.expectedSnapshot {
+				t.Fatalf("expected snapshot %#q, got %#q", tc.expectedSnapshot, snapshot)
+			}
+		})
+	}
+}
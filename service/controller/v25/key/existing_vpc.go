@@ -0,0 +1,76 @@
+package key
+
+import (
+	"strings"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// ExistingVPCIDAnnotation is the ID of an existing VPC the tenant cluster is
+	// created in. The TCCP cloud formation stack then does not manage any
+	// network resources and deleting the tenant cluster leaves the VPC and its
+	// subnets in place.
+	ExistingVPCIDAnnotation = "aws-operator.giantswarm.io/vpc-id"
+	// ExistingPrivateSubnetIDsAnnotation is the comma separated list of the IDs
+	// of the existing private subnets the tenant cluster uses, one per
+	// availability zone, ordered by the name of their availability zone.
+	ExistingPrivateSubnetIDsAnnotation = "aws-operator.giantswarm.io/private-subnet-ids"
+	// ExistingPublicSubnetIDsAnnotation is the comma separated list of the IDs of
+	// the existing public subnets the tenant cluster uses, one per availability
+	// zone, ordered by the name of their availability zone.
+	ExistingPublicSubnetIDsAnnotation = "aws-operator.giantswarm.io/public-subnet-ids"
+)
+
+// ExistingVPCID returns the ID of the existing VPC the tenant cluster is
+// created in. The ID is empty in case the tenant cluster manages its own VPC.
+func ExistingVPCID(customObject v1alpha1.AWSConfig) string {
+	return strings.TrimSpace(customObject.GetAnnotations()[ExistingVPCIDAnnotation])
+}
+
+// IsExistingVPC returns whether the tenant cluster is created in an existing
+// VPC.
+func IsExistingVPC(customObject v1alpha1.AWSConfig) bool {
+	return ExistingVPCID(customObject) != ""
+}
+
+// ExistingSubnetIDs returns the IDs of the existing private and public subnets
+// of the tenant cluster. The private and public subnets with the same index
+// are located in the same availability zone. No subnets are returned in case
+// the tenant cluster manages its own VPC.
+func ExistingSubnetIDs(customObject v1alpha1.AWSConfig) ([]string, []string, error) {
+	if !IsExistingVPC(customObject) {
+		return nil, nil, nil
+	}
+
+	private := splitIDs(customObject.GetAnnotations()[ExistingPrivateSubnetIDsAnnotation])
+	if len(private) == 0 {
+		return nil, nil, microerror.Maskf(invalidConfigError, "annotation %#q must not be empty when %#q is set", ExistingPrivateSubnetIDsAnnotation, ExistingVPCIDAnnotation)
+	}
+	public := splitIDs(customObject.GetAnnotations()[ExistingPublicSubnetIDsAnnotation])
+	if len(public) == 0 {
+		return nil, nil, microerror.Maskf(invalidConfigError, "annotation %#q must not be empty when %#q is set", ExistingPublicSubnetIDsAnnotation, ExistingVPCIDAnnotation)
+	}
+
+	if len(private) != len(public) {
+		return nil, nil, microerror.Maskf(invalidConfigError, "annotations %#q and %#q must list the same number of subnets", ExistingPrivateSubnetIDsAnnotation, ExistingPublicSubnetIDsAnnotation)
+	}
+
+	return private, public, nil
+}
+
+func splitIDs(v string) []string {
+	var ids []string
+
+	for _, id := range strings.Split(v, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
@@ -0,0 +1,92 @@
+package key
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_ExistingSubnetIDs(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description     string
+		annotations     map[string]string
+		expectedVPC     bool
+		expectedPrivate []string
+		expectedPublic  []string
+		expectedError   bool
+	}{
+		{
+			description: "no existing VPC by default",
+			annotations: nil,
+			expectedVPC: false,
+		},
+		{
+			description: "subnets are ignored without existing VPC",
+			annotations: map[string]string{
+				ExistingPrivateSubnetIDsAnnotation: "subnet-1",
+				ExistingPublicSubnetIDsAnnotation:  "subnet-2",
+			},
+			expectedVPC: false,
+		},
+		{
+			description: "existing VPC with subnets in two availability zones",
+			annotations: map[string]string{
+				ExistingVPCIDAnnotation:            "vpc-1",
+				ExistingPrivateSubnetIDsAnnotation: "subnet-1, subnet-2",
+				ExistingPublicSubnetIDsAnnotation:  "subnet-3,subnet-4,",
+			},
+			expectedVPC:     true,
+			expectedPrivate: []string{"subnet-1", "subnet-2"},
+			expectedPublic:  []string{"subnet-3", "subnet-4"},
+		},
+		{
+			description: "existing VPC without public subnets",
+			annotations: map[string]string{
+				ExistingVPCIDAnnotation:            "vpc-1",
+				ExistingPrivateSubnetIDsAnnotation: "subnet-1",
+			},
+			expectedVPC:   true,
+			expectedError: true,
+		},
+		{
+			description: "existing VPC with different number of private and public subnets",
+			annotations: map[string]string{
+				ExistingVPCIDAnnotation:            "vpc-1",
+				ExistingPrivateSubnetIDsAnnotation: "subnet-1,subnet-2",
+				ExistingPublicSubnetIDsAnnotation:  "subnet-3",
+			},
+			expectedVPC:   true,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			if IsExistingVPC(customObject) != tc.expectedVPC {
+				t.Fatalf("expected existing VPC %t, got %t", tc.expectedVPC, IsExistingVPC(customObject))
+			}
+
+			private, public, err := ExistingSubnetIDs(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			if !reflect.DeepEqual(private, tc.expectedPrivate) {
+				t.Fatalf("expected private subnets %#v, got %#v", tc.expectedPrivate, private)
+			}
+			if !reflect.DeepEqual(public, tc.expectedPublic) {
+				t.Fatalf("expected public subnets %#v, got %#v", tc.expectedPublic, public)
+			}
+		})
+	}
+}
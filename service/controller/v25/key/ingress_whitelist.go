@@ -0,0 +1,39 @@
+package key
+
+import (
+	"net"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// IngressWhitelistAnnotation restricts the traffic to the ingress load
+	// balancer of a tenant cluster to the given comma separated list of CIDRs.
+	// All traffic is allowed in case the annotation is not set.
+	IngressWhitelistAnnotation = "aws-operator.giantswarm.io/ingress-whitelist"
+)
+
+// IngressWhitelist returns the CIDRs allowed to access the ingress load
+// balancer of the tenant cluster. An empty list means all traffic is allowed.
+func IngressWhitelist(customObject v1alpha1.AWSConfig) ([]string, error) {
+	v := customObject.GetAnnotations()[IngressWhitelistAnnotation]
+
+	var cidrs []string
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		_, _, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "annotation %#q: %s", IngressWhitelistAnnotation, err)
+		}
+
+		cidrs = append(cidrs, s)
+	}
+
+	return cidrs, nil
+}
@@ -0,0 +1,66 @@
+package key
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_IngressWhitelist(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description   string
+		annotations   map[string]string
+		expectedCIDRs []string
+		expectedError bool
+	}{
+		{
+			description:   "not whitelisted by default",
+			annotations:   nil,
+			expectedCIDRs: nil,
+		},
+		{
+			description: "single CIDR",
+			annotations: map[string]string{
+				IngressWhitelistAnnotation: "172.16.0.0/12",
+			},
+			expectedCIDRs: []string{"172.16.0.0/12"},
+		},
+		{
+			description: "multiple CIDRs with spaces and empty items",
+			annotations: map[string]string{
+				IngressWhitelistAnnotation: "172.16.0.0/12, 203.0.113.10/32,,",
+			},
+			expectedCIDRs: []string{"172.16.0.0/12", "203.0.113.10/32"},
+		},
+		{
+			description: "malformed CIDR",
+			annotations: map[string]string{
+				IngressWhitelistAnnotation: "172.16.0.0/12,203.0.113.10",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			cidrs, err := IngressWhitelist(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			if !reflect.DeepEqual(cidrs, tc.expectedCIDRs) {
+				t.Fatalf("expected %#v, got %#v", tc.expectedCIDRs, cidrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package key
+
+import (
+	"strconv"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// IPv6Annotation enables dual-stack networking for a tenant cluster when set
+	// to "true". The VPC then gets an Amazon-provided IPv6 CIDR block, every
+	// subnet gets a /64 of it and the private subnets reach the internet via an
+	// egress-only internet gateway. IPv6 is not supported for tenant clusters
+	// created in an existing VPC.
+	IPv6Annotation = "aws-operator.giantswarm.io/ipv6"
+)
+
+// IPv6 returns whether the tenant cluster uses dual-stack networking.
+func IPv6(customObject v1alpha1.AWSConfig) (bool, error) {
+	v, ok := customObject.GetAnnotations()[IPv6Annotation]
+	if !ok || v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, microerror.Maskf(invalidConfigError, "annotation %#q: %s", IPv6Annotation, err)
+	}
+
+	if b && IsExistingVPC(customObject) {
+		return false, microerror.Maskf(invalidConfigError, "annotation %#q must not be set together with %#q", IPv6Annotation, ExistingVPCIDAnnotation)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,74 @@
+package key
+
+import (
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_IPv6(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description   string
+		annotations   map[string]string
+		expectedIPv6  bool
+		expectedError bool
+	}{
+		{
+			description:  "IPv4 only by default",
+			annotations:  nil,
+			expectedIPv6: false,
+		},
+		{
+			description: "dual-stack",
+			annotations: map[string]string{
+				IPv6Annotation: "true",
+			},
+			expectedIPv6: true,
+		},
+		{
+			description: "explicitly IPv4 only in an existing VPC",
+			annotations: map[string]string{
+				ExistingVPCIDAnnotation: "vpc-1",
+				IPv6Annotation:          "false",
+			},
+			expectedIPv6: false,
+		},
+		{
+			description: "dual-stack in an existing VPC",
+			annotations: map[string]string{
+				ExistingVPCIDAnnotation: "vpc-1",
+				IPv6Annotation:          "true",
+			},
+			expectedError: true,
+		},
+		{
+			description: "malformed",
+			annotations: map[string]string{
+				IPv6Annotation: "dual",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			ipv6, err := IPv6(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			if ipv6 != tc.expectedIPv6 {
+				t.Fatalf("expected IPv6 %t, got %t", tc.expectedIPv6, ipv6)
+			}
+		})
+	}
+}
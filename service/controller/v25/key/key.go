@@ -3,11 +3,14 @@ package key
 import (
 	"crypto/sha1"
 	"fmt"
+	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/certs"
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/aws-operator/service/controller/v25/templates/cloudconfig"
@@ -35,6 +38,10 @@ const (
 	// InstallationTagName is used for AWS resource tagging.
 	InstallationTagName = "giantswarm.io/installation"
 
+	// MasterIndexTagName is used to tag master instances with their index in
+	// the list of masters.
+	MasterIndexTagName = "giantswarm.io/master-index"
+
 	// OrganizationTagName is used for AWS resource tagging.
 	OrganizationTagName = "giantswarm.io/organization"
 
@@ -48,19 +55,60 @@ const (
 	LogDeliveryURI = "uri=http://acs.amazonaws.com/groups/s3/LogDelivery"
 
 	InstanceIDAnnotation = "aws-operator.giantswarm.io/instance"
+	// ASGNameAnnotation is used to transport the name of the ASG of a drained
+	// instance, since every worker pool has its own ASG.
+	ASGNameAnnotation = "aws-operator.giantswarm.io/asg"
+	// SpotInterruptionAnnotation marks drainer configs of spot instances which
+	// received a spot interruption notice. EC2 terminates these instances on
+	// its own, so there is no lifecycle action to complete once they are
+	// drained.
+	SpotInterruptionAnnotation = "aws-operator.giantswarm.io/spot-interruption"
+
+	// MasterUpdateStrategyAnnotation selects how the masters of a tenant
+	// cluster get replaced on updates. See MasterUpdateStrategyRecreate and
+	// MasterUpdateStrategyRolling.
+	MasterUpdateStrategyAnnotation = "aws-operator.giantswarm.io/master-update-strategy"
+	// MasterUpdateStrategyRecreate replaces all masters at once. This is the
+	// default.
+	MasterUpdateStrategyRecreate = "recreate"
+	// MasterUpdateStrategyRolling replaces one master at a time and only
+	// continues with the next master when all masters are healthy again.
+	MasterUpdateStrategyRolling = "rolling"
+
+	// StackRecoveryAttemptsAnnotation holds the JSON encoded number of recovery
+	// attempts per cloud formation stack of a tenant cluster. It is managed by
+	// the stackrecovery resource.
+	StackRecoveryAttemptsAnnotation = "aws-operator.giantswarm.io/stack-recovery-attempts"
 
 	chinaAWSCliContainerRegistry   = "docker://registry-intl.cn-shanghai.aliyuncs.com/giantswarm/awscli:latest"
 	defaultAWSCliContainerRegistry = "quay.io/coreos/awscli:025a357f05242fdad6a81e8a6b520098aa65a600"
 	defaultDockerVolumeSizeGB      = "100"
+
+	// etcdPeerPort is the port etcd listens on for peer traffic.
+	etcdPeerPort = 2380
+	// masterPrivateIPOffset is the offset of the fixed master private IPs
+	// within the private subnet of their availability zone. AWS reserves the
+	// first four IPs of each subnet.
+	masterPrivateIPOffset = 10
+)
+
+const (
+	// EtcdPeerCert is the certificate the stacked etcd members of tenant
+	// clusters with multiple masters use for peer traffic. Other than the etcd
+	// server certificate it has the fixed private IPs of the masters as IP SANs.
+	EtcdPeerCert certs.Cert = "etcd-peer"
 )
 
 const (
 	DockerVolumeResourceNameKey   = "DockerVolumeResourceName"
+	MasterCountKey                = "MasterCount"
+	MasterEtcdMemberCountKey      = "MasterEtcdMemberCount"
 	MasterImageIDKey              = "MasterImageID"
 	MasterInstanceResourceNameKey = "MasterInstanceResourceName"
 	MasterInstanceTypeKey         = "MasterInstanceType"
 	MasterInstanceMonitoring      = "Monitoring"
 	MasterCloudConfigVersionKey   = "MasterCloudConfigVersion"
+	MasterVersionBundleVersionKey = "MasterVersionBundleVersion"
 	VersionBundleVersionKey       = "VersionBundleVersion"
 	WorkerCountKey                = "WorkerCount"
 	WorkerMaxKey                  = "WorkerMax"
@@ -93,10 +141,11 @@ const (
 )
 
 const (
-	KindMaster  = "master"
-	KindIngress = "ingress"
-	KindWorker  = "worker"
-	KindEtcd    = "etcd-elb"
+	KindMaster      = "master"
+	KindIngress     = "ingress"
+	KindWorker      = "worker"
+	KindEtcd        = "etcd-elb"
+	KindVPCEndpoint = "vpc-endpoint"
 )
 
 func ClusterAPIEndpoint(customObject v1alpha1.AWSConfig) string {
@@ -127,7 +176,16 @@ func BucketName(customObject v1alpha1.AWSConfig, accountID string) string {
 //     /version/3.4.0/cloudconfig/v_3_2_5/master
 //     /version/3.4.0/cloudconfig/v_3_2_5/worker
 //
+// Tenant clusters which rotated their encryption key have the ID of the
+// latest rotation in the path.
+//
+//     /version/3.4.0/cloudconfig/v_3_2_5/rotation-1/master
+//
 func BucketObjectName(customObject v1alpha1.AWSConfig, role string) string {
+	if g := EncryptionKeyGeneration(customObject); g != "" {
+		return fmt.Sprintf("version/%s/cloudconfig/%s/%s/%s", VersionBundleVersion(customObject), CloudConfigVersion, g, role)
+	}
+
 	return fmt.Sprintf("version/%s/cloudconfig/%s/%s", VersionBundleVersion(customObject), CloudConfigVersion, role)
 }
 
@@ -151,7 +209,7 @@ func CloudFormationGuestTemplates() []string {
 		tccp.IAMPolicies,
 		tccp.Instance,
 		tccp.InternetGateway,
-		tccp.LaunchConfiguration,
+		tccp.LaunchTemplate,
 		tccp.LoadBalancers,
 		tccp.Main,
 		tccp.NatGateway,
@@ -211,6 +269,13 @@ func DockerVolumeResourceName(customObject v1alpha1.AWSConfig) string {
 	return getResourcenameWithTimeHash("DockerVolume", customObject)
 }
 
+// DockerVolumeResourceNameReplacing returns the CloudFormation resource name of
+// the docker volume replacing the docker volumes with the given resource names.
+// See MasterInstanceResourceNameReplacing.
+func DockerVolumeResourceNameReplacing(customObject v1alpha1.AWSConfig, replaced ...string) string {
+	return getResourcenameWithHash("DockerVolume", customObject, replaced)
+}
+
 func DockerVolumeName(customObject v1alpha1.AWSConfig) string {
 	return fmt.Sprintf("%s-docker", ClusterID(customObject))
 }
@@ -219,10 +284,18 @@ func EtcdVolumeName(customObject v1alpha1.AWSConfig) string {
 	return fmt.Sprintf("%s-etcd", ClusterID(customObject))
 }
 
+func EtcdVolumeResourceName(idx int) string {
+	return indexedResourceName("EtcdVolume", idx)
+}
+
 func LogVolumeName(customObject v1alpha1.AWSConfig) string {
 	return fmt.Sprintf("%s-log", ClusterID(customObject))
 }
 
+func LogVolumeResourceName(idx int) string {
+	return indexedResourceName("LogVolume", idx)
+}
+
 func EC2ServiceDomain(customObject v1alpha1.AWSConfig) string {
 	domain := "ec2.amazonaws.com"
 
@@ -285,6 +358,90 @@ func EtcdPort(customObject v1alpha1.AWSConfig) int {
 	return 2379
 }
 
+// EtcdInitialCluster returns the etcd initial cluster flag value for the given
+// number of masters, e.g.
+//
+//     etcd0=https://10.1.1.10:2380,etcd1=https://10.1.2.10:2380,etcd2=https://10.1.3.10:2380
+//
+func EtcdInitialCluster(customObject v1alpha1.AWSConfig, masterCount int) (string, error) {
+	var members []string
+
+	for i := 0; i < masterCount; i++ {
+		ip, err := MasterPrivateIP(customObject, i)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		members = append(members, fmt.Sprintf("%s=https://%s:%d", EtcdMemberName(i), ip, etcdPeerPort))
+	}
+
+	return strings.Join(members, ","), nil
+}
+
+func EtcdMemberName(idx int) string {
+	return fmt.Sprintf("etcd%d", idx)
+}
+
+// EtcdMemberCount returns the number of stacked etcd members of the tenant
+// cluster. The number of etcd members of existing tenant clusters is the one
+// their control plane cloud formation stack was created with, which is given
+// as stackEtcdMemberCount. New tenant clusters get the number of masters of
+// their spec. The surge master added during rolling master updates is not
+// counted, since it only joins the etcd cluster temporarily.
+func EtcdMemberCount(customObject v1alpha1.AWSConfig, stackEtcdMemberCount int) int {
+	if stackEtcdMemberCount > 0 {
+		return stackEtcdMemberCount
+	}
+
+	return MasterReplicas(customObject)
+}
+
+// StackEtcdMemberCount returns the number of etcd members of the given number
+// of masters of a cloud formation stack. All masters are etcd members in case
+// the given etcd member count is zero.
+func StackEtcdMemberCount(etcdMemberCount, masterCount int) int {
+	if etcdMemberCount > 0 {
+		return etcdMemberCount
+	}
+
+	return masterCount
+}
+
+// EtcdPeerIPSANs returns the IP SANs of the etcd peer certificate for the
+// given number of etcd members. These are the fixed private IPs the etcd
+// members use to reach each other, including the one of the surge master added
+// during rolling master updates.
+func EtcdPeerIPSANs(customObject v1alpha1.AWSConfig, memberCount int) ([]string, error) {
+	var ips []string
+
+	for i := 0; i <= memberCount; i++ {
+		ip, err := MasterPrivateIP(customObject, i)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
+
+// EtcdSurgeMember returns the etcd member of the surge master added during
+// rolling master updates of a tenant cluster with the given number of etcd
+// members, e.g.
+//
+//     etcd3=https://10.1.1.11:2380
+//
+// The surge master gets the index following the ones of the etcd members.
+func EtcdSurgeMember(customObject v1alpha1.AWSConfig, memberCount int) (string, error) {
+	ip, err := MasterPrivateIP(customObject, memberCount)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return fmt.Sprintf("%s=https://%s:%d", EtcdMemberName(memberCount), ip, etcdPeerPort), nil
+}
+
 // LoadBalancerName produces a unique name for the load balancer.
 // It takes the domain name, extracts the first subdomain, and combines it with the cluster name.
 func LoadBalancerName(domainName string, cluster v1alpha1.AWSConfig) (string, error) {
@@ -320,15 +477,115 @@ func MainHostPostStackName(customObject v1alpha1.AWSConfig) string {
 	return fmt.Sprintf("cluster-%s-host-main", clusterID)
 }
 
+// MasterAvailabilityZone returns the availability zone the master with the
+// given index is placed in. Masters are spread across the availability zones
+// of the tenant cluster in round robin fashion, ordered by zone name. The
+// returned index is the index of the zone and thus of its subnets.
+func MasterAvailabilityZone(customObject v1alpha1.AWSConfig, idx int) (v1alpha1.AWSConfigStatusAWSAvailabilityZone, int, error) {
+	zones := sortedStatusAvailabilityZones(customObject)
+	if len(zones) < 1 {
+		return v1alpha1.AWSConfigStatusAWSAvailabilityZone{}, 0, microerror.Maskf(notFoundError, "availability zones")
+	}
+
+	i := idx % len(zones)
+
+	return zones[i], i, nil
+}
+
 func MasterCount(customObject v1alpha1.AWSConfig) int {
 	return len(customObject.Spec.AWS.Masters)
 }
 
+// MasterUpdateStrategy returns the master update strategy configured for the
+// tenant cluster. It defaults to MasterUpdateStrategyRecreate.
+func MasterUpdateStrategy(customObject v1alpha1.AWSConfig) string {
+	if customObject.GetAnnotations()[MasterUpdateStrategyAnnotation] == MasterUpdateStrategyRolling {
+		return MasterUpdateStrategyRolling
+	}
+
+	return MasterUpdateStrategyRecreate
+}
+
+// MasterReplicas returns the number of master instances of the tenant
+// cluster. Tenant clusters without masters in their spec get a single master,
+// as they always did.
+func MasterReplicas(customObject v1alpha1.AWSConfig) int {
+	if MasterCount(customObject) < 1 {
+		return 1
+	}
+
+	return MasterCount(customObject)
+}
+
+// MasterNetworkInterfaceResourceName returns the CloudFormation resource name
+// of the network interface holding the fixed private IP of the master with the
+// given index.
+func MasterNetworkInterfaceResourceName(idx int) string {
+	return indexedResourceName("MasterNetworkInterface", idx)
+}
+
+// MasterOutputKey returns the key of the cloud formation stack output of the
+// master with the given index, e.g. MasterInstanceType for the first master
+// and MasterInstanceType01 for the second master.
+func MasterOutputKey(outputKey string, idx int) string {
+	return indexedResourceName(outputKey, idx)
+}
+
+// MasterPrivateIP returns the fixed private IP of the master with the given
+// index. The IP is taken from the private subnet of the master's availability
+// zone, so it stays the same when the master instance gets replaced.
+func MasterPrivateIP(customObject v1alpha1.AWSConfig, idx int) (string, error) {
+	zone, _, err := MasterAvailabilityZone(customObject, idx)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	_, subnet, err := net.ParseCIDR(zone.Subnet.Private.CIDR)
+	if err != nil {
+		return "", microerror.Maskf(invalidConfigError, "private subnet CIDR %#q of availability zone %#q: %s", zone.Subnet.Private.CIDR, zone.Name, err)
+	}
+
+	ip := subnet.IP.To4()
+	if ip == nil {
+		return "", microerror.Maskf(invalidConfigError, "private subnet CIDR %#q of availability zone %#q must be IPv4", zone.Subnet.Private.CIDR, zone.Name)
+	}
+
+	// Masters sharing an availability zone get consecutive IPs.
+	offset := masterPrivateIPOffset + idx/len(StatusAvailabilityZones(customObject))
+
+	n := uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+	n += uint32(offset)
+
+	privateIP := net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	if !subnet.Contains(privateIP) {
+		return "", microerror.Maskf(invalidConfigError, "private subnet CIDR %#q of availability zone %#q is too small for master %d", zone.Subnet.Private.CIDR, zone.Name, idx)
+	}
+
+	return privateIP.String(), nil
+}
+
+// MasterDockerVolumeResourceName returns the CloudFormation resource name of
+// the docker volume of the master with the given index, based on the docker
+// volume resource name of the first master.
+func MasterDockerVolumeResourceName(resourceName string, idx int) string {
+	return indexedResourceName(resourceName, idx)
+}
+
 func MasterImageID(customObject v1alpha1.AWSConfig) string {
+	return MasterImageIDByIndex(customObject, 0)
+}
+
+// MasterImageIDByIndex returns the image ID of the master with the given
+// index. Masters without their own image ID fall back to the one of the first
+// master.
+func MasterImageIDByIndex(customObject v1alpha1.AWSConfig, idx int) string {
 	var imageID string
 
-	if len(customObject.Spec.AWS.Masters) > 0 {
-		imageID = customObject.Spec.AWS.Masters[0].ImageID
+	if len(customObject.Spec.AWS.Masters) > idx {
+		imageID = customObject.Spec.AWS.Masters[idx].ImageID
+	}
+	if imageID == "" && idx > 0 {
+		imageID = MasterImageIDByIndex(customObject, 0)
 	}
 
 	return imageID
@@ -338,6 +595,22 @@ func MasterInstanceResourceName(customObject v1alpha1.AWSConfig) string {
 	return getResourcenameWithTimeHash("MasterInstance", customObject)
 }
 
+// MasterInstanceResourceNameReplacing returns the CloudFormation resource name
+// of the master instance replacing the master instances with the given
+// resource names. The name is derived from the replaced resource names, so it
+// is the same in every reconciliation until the replacement got executed. This
+// keeps the change set of the replacement, and with it its approval, stable.
+func MasterInstanceResourceNameReplacing(customObject v1alpha1.AWSConfig, replaced ...string) string {
+	return getResourcenameWithHash("MasterInstance", customObject, replaced)
+}
+
+// MasterInstanceResourceNameByIndex returns the CloudFormation resource name
+// of the master instance with the given index, based on the instance resource
+// name of the first master.
+func MasterInstanceResourceNameByIndex(resourceName string, idx int) string {
+	return indexedResourceName(resourceName, idx)
+}
+
 func MasterInstanceName(customObject v1alpha1.AWSConfig) string {
 	clusterID := ClusterID(customObject)
 
@@ -345,10 +618,20 @@ func MasterInstanceName(customObject v1alpha1.AWSConfig) string {
 }
 
 func MasterInstanceType(customObject v1alpha1.AWSConfig) string {
+	return MasterInstanceTypeByIndex(customObject, 0)
+}
+
+// MasterInstanceTypeByIndex returns the instance type of the master with the
+// given index. Masters without their own instance type fall back to the one of
+// the first master.
+func MasterInstanceTypeByIndex(customObject v1alpha1.AWSConfig, idx int) string {
 	var instanceType string
 
-	if len(customObject.Spec.AWS.Masters) > 0 {
-		instanceType = customObject.Spec.AWS.Masters[0].InstanceType
+	if len(customObject.Spec.AWS.Masters) > idx {
+		instanceType = customObject.Spec.AWS.Masters[idx].InstanceType
+	}
+	if instanceType == "" && idx > 0 {
+		instanceType = MasterInstanceTypeByIndex(customObject, 0)
 	}
 
 	return instanceType
@@ -528,10 +811,34 @@ func SpecAvailabilityZones(customObject v1alpha1.AWSConfig) int {
 	return customObject.Spec.AWS.AvailabilityZones
 }
 
+// SSMParameterPath returns the Parameter Store hierarchy the SSM encrypter
+// stores the assets of the tenant cluster under.
+func SSMParameterPath(customObject v1alpha1.AWSConfig) string {
+	return fmt.Sprintf("/giantswarm/%s", ClusterID(customObject))
+}
+
+// SSMParameterPathARN returns the ARN matching all parameters within
+// SSMParameterPath. It is used to grant the tenant cluster nodes read access
+// to their assets.
+func SSMParameterPathARN(customObject v1alpha1.AWSConfig, accountID string) string {
+	return fmt.Sprintf("arn:%s:ssm:%s:%s:parameter%s/*", RegionARN(customObject), Region(customObject), accountID, SSMParameterPath(customObject))
+}
+
 func StatusAvailabilityZones(customObject v1alpha1.AWSConfig) []v1alpha1.AWSConfigStatusAWSAvailabilityZone {
 	return customObject.Status.AWS.AvailabilityZones
 }
 
+func sortedStatusAvailabilityZones(customObject v1alpha1.AWSConfig) []v1alpha1.AWSConfigStatusAWSAvailabilityZone {
+	zones := make([]v1alpha1.AWSConfigStatusAWSAvailabilityZone, len(StatusAvailabilityZones(customObject)))
+	copy(zones, StatusAvailabilityZones(customObject))
+
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Name < zones[j].Name
+	})
+
+	return zones
+}
+
 // StatusNetworkCIDR returns the allocated tenant cluster subnet CIDR.
 func StatusNetworkCIDR(customObject v1alpha1.AWSConfig) string {
 	return customObject.Status.Cluster.Network.CIDR
@@ -626,6 +933,44 @@ func VPCPeeringRouteName(idx int) string {
 	return fmt.Sprintf("VPCPeeringRoute%02d", idx)
 }
 
+func TransitGatewayRouteName(idx int) string {
+	return fmt.Sprintf("TransitGatewayRoute%02d", idx)
+}
+
+// TransitGatewayARN returns the ARN of the transit gateway with the given ID
+// owned by the control plane account with the given ID.
+func TransitGatewayARN(customObject v1alpha1.AWSConfig, accountID string, transitGatewayID string) string {
+	return fmt.Sprintf("arn:%s:ec2:%s:%s:transit-gateway/%s", RegionARN(customObject), Region(customObject), accountID, transitGatewayID)
+}
+
+// TransitGatewayResourceShareName returns the name of the RAM resource share
+// the transit gateway is shared with the tenant cluster's account by.
+func TransitGatewayResourceShareName(customObject v1alpha1.AWSConfig) string {
+	return fmt.Sprintf("%s-transit-gateway", ClusterID(customObject))
+}
+
+func EgressOnlyInternetGatewayRouteName(idx int) string {
+	return fmt.Sprintf("EgressOnlyInternetGatewayRoute%02d", idx)
+}
+
+// VPCInterfaceEndpointName returns the resource name of the interface endpoint
+// of the given AWS service, e.g. VPCInterfaceEndpointEcrApi for ecr.api.
+func VPCInterfaceEndpointName(service string) string {
+	name := "VPCInterfaceEndpoint"
+
+	for _, p := range strings.FieldsFunc(service, func(r rune) bool { return r == '.' || r == '-' }) {
+		name += strings.Title(p)
+	}
+
+	return name
+}
+
+// VPCInterfaceEndpointServiceName returns the service name of the interface
+// endpoint of the given AWS service in the region of the tenant cluster.
+func VPCInterfaceEndpointServiceName(customObject v1alpha1.AWSConfig, service string) string {
+	return fmt.Sprintf("com.amazonaws.%s.%s", Region(customObject), service)
+}
+
 func WorkerCount(customObject v1alpha1.AWSConfig) int {
 	return len(customObject.Spec.AWS.Workers)
 }
@@ -735,6 +1080,34 @@ func ImageID(customObject v1alpha1.AWSConfig) (string, error) {
 	return imageID, nil
 }
 
+// indexedResourceName returns the given CloudFormation resource name suffixed
+// with the given index. Since CloudFormation cannot recognize resource
+// renaming, the non-indexed resource name is used for the first index.
+func indexedResourceName(resourceName string, idx int) string {
+	if idx < 1 {
+		return resourceName
+	}
+	return fmt.Sprintf("%s%02d", resourceName, idx)
+}
+
+// getResourcenameWithHash returns the string compared from specific prefix,
+// the hash of the given inputs and cluster ID.
+func getResourcenameWithHash(prefix string, customObject v1alpha1.AWSConfig, inputs []string) string {
+	clusterID := strings.Replace(ClusterID(customObject), "-", "", -1)
+
+	h := sha1.New()
+	for _, i := range inputs {
+		h.Write([]byte(i))
+		h.Write([]byte{0})
+	}
+	hash := fmt.Sprintf("%x", h.Sum(nil))[0:5]
+
+	upperHash := strings.ToUpper(hash)
+	upperClusterID := strings.ToUpper(clusterID)
+
+	return fmt.Sprintf("%s%s%s", prefix, upperClusterID, upperHash)
+}
+
 // getResourcenameWithTimeHash returns the string compared from specific prefix,
 // time hash and cluster ID.
 func getResourcenameWithTimeHash(prefix string, customObject v1alpha1.AWSConfig) string {
@@ -618,6 +618,143 @@ func Test_MasterInstanceType(t *testing.T) {
 	}
 }
 
+func Test_MasterInstanceTypeByIndex(t *testing.T) {
+	t.Parallel()
+	customObject := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			AWS: v1alpha1.AWSConfigSpecAWS{
+				Masters: []v1alpha1.AWSConfigSpecAWSNode{
+					{
+						InstanceType: "m4.large",
+					},
+					{
+						InstanceType: "m4.xlarge",
+					},
+					{},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		idx                  int
+		expectedInstanceType string
+	}{
+		{
+			idx:                  0,
+			expectedInstanceType: "m4.large",
+		},
+		{
+			idx:                  1,
+			expectedInstanceType: "m4.xlarge",
+		},
+		{
+			idx:                  2,
+			expectedInstanceType: "m4.large",
+		},
+		{
+			idx:                  3,
+			expectedInstanceType: "m4.large",
+		},
+	}
+
+	for _, tc := range tests {
+		instanceType := MasterInstanceTypeByIndex(customObject, tc.idx)
+		if instanceType != tc.expectedInstanceType {
+			t.Fatalf("Expected master instance type %s for index %d but was %s", tc.expectedInstanceType, tc.idx, instanceType)
+		}
+	}
+}
+
+func Test_MasterInstanceResourceNameReplacing(t *testing.T) {
+	t.Parallel()
+
+	customObject := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "test-cluster",
+			},
+		},
+	}
+
+	n1 := MasterInstanceResourceNameReplacing(customObject, "MasterInstanceTESTCLUSTERAAAAA")
+	n2 := MasterInstanceResourceNameReplacing(customObject, "MasterInstanceTESTCLUSTERAAAAA")
+	n3 := MasterInstanceResourceNameReplacing(customObject, n1)
+
+	if !strings.HasPrefix(n1, "MasterInstanceTESTCLUSTER") {
+		t.Fatalf("expected %s to have prefix %s", n1, "MasterInstanceTESTCLUSTER")
+	}
+	if n1 != n2 {
+		t.Fatalf("expected %s to equal %s", n1, n2)
+	}
+	if n1 == n3 {
+		t.Fatalf("expected %s to differ from %s", n1, n3)
+	}
+}
+
+func Test_MasterInstanceResourceNameByIndex(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		idx                  int
+		expectedResourceName string
+	}{
+		{
+			idx:                  0,
+			expectedResourceName: "MasterInstanceabcde",
+		},
+		{
+			idx:                  1,
+			expectedResourceName: "MasterInstanceabcde01",
+		},
+		{
+			idx:                  12,
+			expectedResourceName: "MasterInstanceabcde12",
+		},
+	}
+
+	for _, tc := range tests {
+		resourceName := MasterInstanceResourceNameByIndex("MasterInstanceabcde", tc.idx)
+		if resourceName != tc.expectedResourceName {
+			t.Fatalf("Expected master instance resource name %s for index %d but was %s", tc.expectedResourceName, tc.idx, resourceName)
+		}
+	}
+}
+
+func Test_MasterUpdateStrategy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		annotations      map[string]string
+		expectedStrategy string
+	}{
+		{
+			annotations:      nil,
+			expectedStrategy: MasterUpdateStrategyRecreate,
+		},
+		{
+			annotations: map[string]string{
+				MasterUpdateStrategyAnnotation: "rolling",
+			},
+			expectedStrategy: MasterUpdateStrategyRolling,
+		},
+		{
+			annotations: map[string]string{
+				MasterUpdateStrategyAnnotation: "unknown",
+			},
+			expectedStrategy: MasterUpdateStrategyRecreate,
+		},
+	}
+
+	for _, tc := range tests {
+		customObject := v1alpha1.AWSConfig{}
+		customObject.SetAnnotations(tc.annotations)
+
+		strategy := MasterUpdateStrategy(customObject)
+		if strategy != tc.expectedStrategy {
+			t.Fatalf("Expected master update strategy %s but was %s", tc.expectedStrategy, strategy)
+		}
+	}
+}
+
 func Test_Region(t *testing.T) {
 	t.Parallel()
 	expectedRegion := "eu-central-1"
@@ -1268,6 +1405,44 @@ func Test_PeerAccessRoleName(t *testing.T) {
 	}
 }
 
+func Test_TransitGatewayARN(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		description string
+		region      string
+		expectedARN string
+	}{
+		{
+			description: "eu region",
+			region:      "eu-central-1",
+			expectedARN: "arn:aws:ec2:eu-central-1:123456789012:transit-gateway/tgw-0123456789abcdef0",
+		},
+		{
+			description: "china region",
+			region:      "cn-north-1",
+			expectedARN: "arn:aws-cn:ec2:cn-north-1:123456789012:transit-gateway/tgw-0123456789abcdef0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{
+				Spec: v1alpha1.AWSConfigSpec{
+					AWS: v1alpha1.AWSConfigSpecAWS{
+						Region: tc.region,
+					},
+				},
+			}
+
+			actual := TransitGatewayARN(customObject, "123456789012", "tgw-0123456789abcdef0")
+			if actual != tc.expectedARN {
+				t.Fatalf("expected ARN %q but was %q", tc.expectedARN, actual)
+			}
+		})
+	}
+}
+
 func Test_MasterCount(t *testing.T) {
 	t.Parallel()
 	customObject := v1alpha1.AWSConfig{
@@ -1292,6 +1467,92 @@ func Test_MasterCount(t *testing.T) {
 	}
 }
 
+func Test_EtcdPeerIPSANs(t *testing.T) {
+	t.Parallel()
+	customObject := v1alpha1.AWSConfig{
+		Status: v1alpha1.AWSConfigStatus{
+			AWS: v1alpha1.AWSConfigStatusAWS{
+				AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+					{
+						Name: "eu-central-1b",
+						Subnet: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnet{
+							Private: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPrivate{
+								CIDR: "10.1.1.0/25",
+							},
+						},
+					},
+					{
+						Name: "eu-central-1a",
+						Subnet: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnet{
+							Private: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPrivate{
+								CIDR: "10.1.0.0/25",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := []string{
+		"10.1.0.10",
+		"10.1.1.10",
+		"10.1.0.11",
+		"10.1.1.11",
+	}
+	actual, err := EtcdPeerIPSANs(customObject, 3)
+	if err != nil {
+		t.Fatalf("expected %#v got %#v", nil, err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected etcd peer IP SANs %#v but was %#v", expected, actual)
+	}
+}
+
+func Test_EtcdMemberCount(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description          string
+		customObject         v1alpha1.AWSConfig
+		stackEtcdMemberCount int
+		expectedCount        int
+	}{
+		{
+			description: "new tenant cluster uses the masters of its spec",
+			customObject: v1alpha1.AWSConfig{
+				Spec: v1alpha1.AWSConfigSpec{
+					AWS: v1alpha1.AWSConfigSpecAWS{
+						Masters: []v1alpha1.AWSConfigSpecAWSNode{{}, {}, {}},
+					},
+				},
+			},
+			stackEtcdMemberCount: 0,
+			expectedCount:        3,
+		},
+		{
+			description: "existing tenant cluster uses the etcd members of its stack",
+			customObject: v1alpha1.AWSConfig{
+				Spec: v1alpha1.AWSConfigSpec{
+					AWS: v1alpha1.AWSConfigSpecAWS{
+						Masters: []v1alpha1.AWSConfigSpecAWSNode{{}, {}, {}, {}, {}},
+					},
+				},
+			},
+			stackEtcdMemberCount: 3,
+			expectedCount:        3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			actual := EtcdMemberCount(tc.customObject, tc.stackEtcdMemberCount)
+			if actual != tc.expectedCount {
+				t.Fatalf("Expected etcd member count %d but was %d", tc.expectedCount, actual)
+			}
+		})
+	}
+}
+
 func Test_PrivateSubnetCIDR(t *testing.T) {
 	t.Parallel()
 	customObject := v1alpha1.AWSConfig{
@@ -1475,6 +1736,48 @@ func Test_RegionARN(t *testing.T) {
 	}
 }
 
+func Test_SSMParameterPathARN(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		description string
+		region      string
+		expectedARN string
+	}{
+		{
+			description: "eu region",
+			region:      "eu-central-1",
+			expectedARN: "arn:aws:ssm:eu-central-1:123456789012:parameter/giantswarm/test-cluster/*",
+		},
+		{
+			description: "china region",
+			region:      "cn-north-1",
+			expectedARN: "arn:aws-cn:ssm:cn-north-1:123456789012:parameter/giantswarm/test-cluster/*",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{
+				Spec: v1alpha1.AWSConfigSpec{
+					AWS: v1alpha1.AWSConfigSpecAWS{
+						Region: tc.region,
+					},
+					Cluster: v1alpha1.Cluster{
+						ID: "test-cluster",
+					},
+				},
+			}
+
+			actual := SSMParameterPathARN(customObject, "123456789012")
+
+			if actual != tc.expectedARN {
+				t.Fatalf("Expected parameter path ARN %q but was %q", tc.expectedARN, actual)
+			}
+		})
+	}
+}
+
 func Test_MasterRoleARN(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -1598,3 +1901,53 @@ func Test_getResourcenameWithTimeHash_Format(t *testing.T) {
 		t.Fatalf("expected %s to not contain dashes", n)
 	}
 }
+
+func Test_VPCInterfaceEndpointName(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		service      string
+		expectedName string
+	}{
+		{
+			service:      "ec2",
+			expectedName: "VPCInterfaceEndpointEc2",
+		},
+		{
+			service:      "ecr.api",
+			expectedName: "VPCInterfaceEndpointEcrApi",
+		},
+		{
+			service:      "execute-api",
+			expectedName: "VPCInterfaceEndpointExecuteApi",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.service, func(t *testing.T) {
+			name := VPCInterfaceEndpointName(tc.service)
+			if name != tc.expectedName {
+				t.Fatalf("expected %q got %q", tc.expectedName, name)
+			}
+		})
+	}
+}
+
+func Test_BucketObjectName_EncryptionKeyRotation(t *testing.T) {
+	t.Parallel()
+	customObject := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			VersionBundle: v1alpha1.AWSConfigSpecVersionBundle{
+				Version: "0.1.0",
+			},
+		},
+	}
+	customObject.SetAnnotations(map[string]string{
+		EncryptionKeyRotationStateAnnotation: `{"id":"rotation-1","phase":"KeyRotated"}`,
+	})
+
+	e := fmt.Sprintf("version/0.1.0/cloudconfig/%s/rotation-1/master", CloudConfigVersion)
+	a := BucketObjectName(customObject, "master")
+	if e != a {
+		t.Fatalf("expected %s got %s", e, a)
+	}
+}
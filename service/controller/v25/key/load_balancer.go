@@ -0,0 +1,36 @@
+package key
+
+import (
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// LoadBalancerTypeAnnotation selects the type of the API, etcd and ingress
+	// load balancers of a tenant cluster. See LoadBalancerTypeClassic and
+	// LoadBalancerTypeNetwork. Changing it replaces the load balancers.
+	LoadBalancerTypeAnnotation = "aws-operator.giantswarm.io/load-balancer-type"
+)
+
+const (
+	// LoadBalancerTypeClassic are classic ELBs. This is the default.
+	LoadBalancerTypeClassic = "classic"
+	// LoadBalancerTypeNetwork are NLBs forwarding to target groups of the
+	// masters and the worker ASGs.
+	LoadBalancerTypeNetwork = "network"
+)
+
+// LoadBalancerType returns the type of the API, etcd and ingress load
+// balancers of the tenant cluster.
+func LoadBalancerType(customObject v1alpha1.AWSConfig) (string, error) {
+	v := customObject.GetAnnotations()[LoadBalancerTypeAnnotation]
+
+	switch v {
+	case "":
+		return LoadBalancerTypeClassic, nil
+	case LoadBalancerTypeClassic, LoadBalancerTypeNetwork:
+		return v, nil
+	}
+
+	return "", microerror.Maskf(invalidConfigError, "annotation %#q must be %#q or %#q, found %#q", LoadBalancerTypeAnnotation, LoadBalancerTypeClassic, LoadBalancerTypeNetwork, v)
+}
@@ -0,0 +1,65 @@
+package key
+
+import (
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_LoadBalancerType(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description   string
+		annotations   map[string]string
+		expectedType  string
+		expectedError bool
+	}{
+		{
+			description:  "classic by default",
+			annotations:  nil,
+			expectedType: LoadBalancerTypeClassic,
+		},
+		{
+			description: "classic",
+			annotations: map[string]string{
+				LoadBalancerTypeAnnotation: "classic",
+			},
+			expectedType: LoadBalancerTypeClassic,
+		},
+		{
+			description: "network",
+			annotations: map[string]string{
+				LoadBalancerTypeAnnotation: "network",
+			},
+			expectedType: LoadBalancerTypeNetwork,
+		},
+		{
+			description: "unknown",
+			annotations: map[string]string{
+				LoadBalancerTypeAnnotation: "application",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			lbType, err := LoadBalancerType(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			if lbType != tc.expectedType {
+				t.Fatalf("expected %#q, got %#q", tc.expectedType, lbType)
+			}
+		})
+	}
+}
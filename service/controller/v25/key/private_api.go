@@ -0,0 +1,42 @@
+package key
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// PrivateAPIAnnotation makes the Kubernetes API of a tenant cluster
+	// private when set to "true". Its load balancer is internal then and its
+	// DNS record lives in a private hosted zone associated with the tenant
+	// cluster VPC and the control plane VPC. The API is then only reachable
+	// via VPC peering, PrivateLink or a Transit Gateway.
+	PrivateAPIAnnotation = "aws-operator.giantswarm.io/private-api"
+)
+
+// PrivateAPI returns whether the Kubernetes API of the tenant cluster is
+// private.
+func PrivateAPI(customObject v1alpha1.AWSConfig) (bool, error) {
+	v, ok := customObject.GetAnnotations()[PrivateAPIAnnotation]
+	if !ok || v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, microerror.Maskf(invalidConfigError, "annotation %#q: %s", PrivateAPIAnnotation, err)
+	}
+
+	return b, nil
+}
+
+// PrivateAPIHostedZoneName returns the name of the private hosted zone of the
+// tenant cluster's Kubernetes API. The zone only holds the API record, so that
+// it does not shadow the other records of the tenant cluster's public hosted
+// zone in the VPCs it is associated with.
+func PrivateAPIHostedZoneName(customObject v1alpha1.AWSConfig) string {
+	return fmt.Sprintf("api.%s.k8s.%s", ClusterID(customObject), BaseDomain(customObject))
+}
@@ -0,0 +1,65 @@
+package key
+
+import (
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_PrivateAPI(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description     string
+		annotations     map[string]string
+		expectedPrivate bool
+		expectedError   bool
+	}{
+		{
+			description:     "public by default",
+			annotations:     nil,
+			expectedPrivate: false,
+		},
+		{
+			description: "private",
+			annotations: map[string]string{
+				PrivateAPIAnnotation: "true",
+			},
+			expectedPrivate: true,
+		},
+		{
+			description: "explicitly public",
+			annotations: map[string]string{
+				PrivateAPIAnnotation: "false",
+			},
+			expectedPrivate: false,
+		},
+		{
+			description: "malformed",
+			annotations: map[string]string{
+				PrivateAPIAnnotation: "yes please",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			private, err := PrivateAPI(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			if private != tc.expectedPrivate {
+				t.Fatalf("expected %t, got %t", tc.expectedPrivate, private)
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package key
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// WorkerPoolsAnnotation holds the JSON encoded list of named worker pools
+	// of a tenant cluster. Named worker pools are managed in addition to the
+	// default worker pool defined by the CR spec. See WorkerPool.
+	WorkerPoolsAnnotation = "aws-operator.giantswarm.io/worker-pools"
+	// WorkerPoolASGNamesAnnotation holds the JSON encoded mapping of worker
+	// pool names to the names of their ASGs. It is managed by the workerasgname
+	// resource.
+	WorkerPoolASGNamesAnnotation = "aws-operator.giantswarm.io/worker-pool-asg-names"
+	// WorkerPoolLabel is the node label identifying the worker pool of a node.
+	WorkerPoolLabel = "giantswarm.io/worker-pool"
+
+	// WorkerInstanceTypesAnnotation holds the comma separated list of
+	// additional instance types of the default worker pool.
+	WorkerInstanceTypesAnnotation = "aws-operator.giantswarm.io/worker-instance-types"
+	// WorkerOnDemandBaseCapacityAnnotation holds the number of on-demand
+	// instances of the default worker pool.
+	WorkerOnDemandBaseCapacityAnnotation = "aws-operator.giantswarm.io/worker-on-demand-base-capacity"
+	// WorkerSpotPercentageAnnotation holds the percentage of spot instances of
+	// the default worker pool above the on-demand base capacity.
+	WorkerSpotPercentageAnnotation = "aws-operator.giantswarm.io/worker-spot-percentage"
+)
+
+const (
+	WorkerASGNameKey              = "WorkerASGName"
+	WorkerInstanceDistributionKey = "WorkerInstanceDistribution"
+	WorkerPoolNamesKey            = "WorkerPoolNames"
+)
+
+var workerPoolNameRegexp = regexp.MustCompile("^[a-z0-9]{1,16}$")
+
+// InstanceDistribution defines how the instances of a worker pool are spread
+// across instance types and purchase options. The zero value means on-demand
+// instances of the worker pool's instance type only.
+type InstanceDistribution struct {
+	// InstanceTypes are additional instance types the ASG may launch besides
+	// the worker pool's instance type.
+	InstanceTypes []string `json:"instanceTypes,omitempty"`
+	// OnDemandBaseCapacity is the number of instances always launched as
+	// on-demand instances.
+	OnDemandBaseCapacity int `json:"onDemandBaseCapacity,omitempty"`
+	// SpotPercentage is the percentage of instances above the on-demand base
+	// capacity launched as spot instances.
+	SpotPercentage int `json:"spotPercentage,omitempty"`
+}
+
+// WorkerPool is a named group of workers sharing the same configuration. Each
+// named worker pool gets its own ASG, launch template and lifecycle hook
+// in the tenant cluster's control plane cloud formation stack.
+type WorkerPool struct {
+	InstanceDistribution
+
+	DockerVolumeSizeGB int               `json:"dockerVolumeSizeGB,omitempty"`
+	InstanceType       string            `json:"instanceType,omitempty"`
+	Labels             map[string]string `json:"labels,omitempty"`
+	Max                int               `json:"max"`
+	Min                int               `json:"min"`
+	Name               string            `json:"name"`
+	Taints             []string          `json:"taints,omitempty"`
+}
+
+// InstanceDistributionString returns the given instance distribution in the
+// form it is tracked in the stack outputs.
+func InstanceDistributionString(d InstanceDistribution) string {
+	return fmt.Sprintf("%d:%d:%s", d.OnDemandBaseCapacity, d.SpotPercentage, strings.Join(d.InstanceTypes, ","))
+}
+
+// InstanceTypeOverrides returns the instance types the ASG of a worker pool
+// with the given instance type and instance distribution may launch. The
+// empty list is returned in case the worker pool only uses its own instance
+// type.
+func InstanceTypeOverrides(instanceType string, d InstanceDistribution) []string {
+	if len(d.InstanceTypes) == 0 {
+		return nil
+	}
+
+	overrides := []string{instanceType}
+	for _, t := range d.InstanceTypes {
+		if t == instanceType {
+			continue
+		}
+		overrides = append(overrides, t)
+	}
+
+	return overrides
+}
+
+// WorkerInstanceDistribution returns the instance distribution of the default
+// worker pool as defined in the CR annotations.
+func WorkerInstanceDistribution(customObject v1alpha1.AWSConfig) (InstanceDistribution, error) {
+	var d InstanceDistribution
+
+	annotations := customObject.GetAnnotations()
+
+	if v := annotations[WorkerInstanceTypesAnnotation]; v != "" {
+		for _, t := range strings.Split(v, ",") {
+			t = strings.TrimSpace(t)
+			if t != "" {
+				d.InstanceTypes = append(d.InstanceTypes, t)
+			}
+		}
+	}
+
+	if v := annotations[WorkerOnDemandBaseCapacityAnnotation]; v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return InstanceDistribution{}, microerror.Maskf(invalidConfigError, "annotation %#q: %s", WorkerOnDemandBaseCapacityAnnotation, err)
+		}
+		d.OnDemandBaseCapacity = i
+	}
+
+	if v := annotations[WorkerSpotPercentageAnnotation]; v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return InstanceDistribution{}, microerror.Maskf(invalidConfigError, "annotation %#q: %s", WorkerSpotPercentageAnnotation, err)
+		}
+		d.SpotPercentage = i
+	}
+
+	err := validateInstanceDistribution(d)
+	if err != nil {
+		return InstanceDistribution{}, microerror.Mask(err)
+	}
+
+	return d, nil
+}
+
+// WorkerPoolASGNames returns the mapping of worker pool names to ASG names
+// as persisted in the CR annotations.
+func WorkerPoolASGNames(customObject v1alpha1.AWSConfig) (map[string]string, error) {
+	names := map[string]string{}
+
+	v, ok := customObject.GetAnnotations()[WorkerPoolASGNamesAnnotation]
+	if !ok || v == "" {
+		return names, nil
+	}
+
+	err := json.Unmarshal([]byte(v), &names)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "annotation %#q: %s", WorkerPoolASGNamesAnnotation, err)
+	}
+
+	return names, nil
+}
+
+// WorkerPoolASGRef returns the cloud formation resource name of the ASG of the
+// given worker pool. The empty name refers to the default worker pool.
+func WorkerPoolASGRef(name string) string {
+	return fmt.Sprintf("%sAutoScalingGroup", WorkerPoolASGType(name))
+}
+
+// WorkerPoolASGType returns the prefix of the cloud formation resource names
+// of the ASG and launch template of the given worker pool. The empty name
+// refers to the default worker pool.
+func WorkerPoolASGType(name string) string {
+	if name == "" {
+		return KindWorker
+	}
+
+	return fmt.Sprintf("%sPool%s", KindWorker, strings.Title(name))
+}
+
+// WorkerPoolDockerVolumeSizeGB returns the docker volume size of the given
+// worker pool as it is tracked in the stack outputs.
+func WorkerPoolDockerVolumeSizeGB(pool WorkerPool) string {
+	return strconv.Itoa(pool.DockerVolumeSizeGB)
+}
+
+// WorkerPoolKubeletLabels returns the node labels of the given worker pool,
+// appended to the kubelet labels of the tenant cluster.
+func WorkerPoolKubeletLabels(customObject v1alpha1.AWSConfig, pool WorkerPool) string {
+	var labels []string
+
+	if customObject.Spec.Cluster.Kubernetes.Kubelet.Labels != "" {
+		labels = append(labels, customObject.Spec.Cluster.Kubernetes.Kubelet.Labels)
+	}
+	labels = append(labels, fmt.Sprintf("%s=%s", WorkerPoolLabel, pool.Name))
+
+	var keys []string
+	for k := range pool.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, pool.Labels[k]))
+	}
+
+	return strings.Join(labels, ",")
+}
+
+// WorkerPoolLifecycleHookResourceName returns the cloud formation resource
+// name of the node drainer lifecycle hook of the given worker pool. The hook
+// itself is always named NodeDrainerLifecycleHookName, since lifecycle hook
+// names only have to be unique within their ASG.
+func WorkerPoolLifecycleHookResourceName(name string) string {
+	return fmt.Sprintf("%s%s", NodeDrainerLifecycleHookName, strings.Title(name))
+}
+
+// WorkerPoolOutputKey returns the cloud formation output key of the given
+// worker pool. The empty name refers to the default worker pool, which keeps
+// the plain output keys.
+func WorkerPoolOutputKey(outputKey string, name string) string {
+	return fmt.Sprintf("%s%s", outputKey, strings.Title(name))
+}
+
+// WorkerPoolRole returns the role used to name the cloud config S3 object of
+// the given worker pool. The empty name refers to the default worker pool.
+func WorkerPoolRole(name string) string {
+	if name == "" {
+		return KindWorker
+	}
+
+	return fmt.Sprintf("%s-%s", KindWorker, name)
+}
+
+// WorkerPools returns the named worker pools of the tenant cluster as defined
+// in the worker pools annotation. Instance type and docker volume size default
+// to the ones of the default worker pool.
+func WorkerPools(customObject v1alpha1.AWSConfig) ([]WorkerPool, error) {
+	v, ok := customObject.GetAnnotations()[WorkerPoolsAnnotation]
+	if !ok || v == "" {
+		return nil, nil
+	}
+
+	var pools []WorkerPool
+	err := json.Unmarshal([]byte(v), &pools)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "annotation %#q: %s", WorkerPoolsAnnotation, err)
+	}
+
+	defaultDockerVolumeSizeGB, err := strconv.Atoi(WorkerDockerVolumeSizeGB(customObject))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	seen := map[string]bool{}
+	for i, p := range pools {
+		if !workerPoolNameRegexp.MatchString(p.Name) {
+			return nil, microerror.Maskf(invalidConfigError, "worker pool name %#q must match %#q", p.Name, workerPoolNameRegexp.String())
+		}
+		if seen[p.Name] {
+			return nil, microerror.Maskf(invalidConfigError, "worker pool name %#q must be unique", p.Name)
+		}
+		seen[p.Name] = true
+
+		if p.Min <= 0 {
+			return nil, microerror.Maskf(invalidConfigError, "at least 1 worker required for worker pool %#q, found %d", p.Name, p.Min)
+		}
+		if p.Max < p.Min {
+			return nil, microerror.Maskf(invalidConfigError, "maximum number of workers (%d) of worker pool %#q is smaller than minimum number of workers (%d)", p.Max, p.Name, p.Min)
+		}
+
+		err = validateInstanceDistribution(p.InstanceDistribution)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "worker pool %#q: %s", p.Name, err)
+		}
+		err = validateWorkerPoolLabels(p.Labels)
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "worker pool %#q: %s", p.Name, err)
+		}
+		for _, t := range p.Taints {
+			err = validateWorkerPoolTaint(t)
+			if err != nil {
+				return nil, microerror.Maskf(invalidConfigError, "worker pool %#q: %s", p.Name, err)
+			}
+		}
+
+		if p.DockerVolumeSizeGB <= 0 {
+			pools[i].DockerVolumeSizeGB = defaultDockerVolumeSizeGB
+		}
+		if p.InstanceType == "" {
+			pools[i].InstanceType = WorkerInstanceType(customObject)
+		}
+	}
+
+	return pools, nil
+}
+
+// WorkerPoolTaintsArg returns the kubelet argument registering the taints of
+// the given worker pool. The empty string is returned in case the worker pool
+// has no taints.
+func WorkerPoolTaintsArg(pool WorkerPool) string {
+	if len(pool.Taints) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("--register-with-taints=%s", strings.Join(pool.Taints, ","))
+}
+
+func validateInstanceDistribution(d InstanceDistribution) error {
+	if d.OnDemandBaseCapacity < 0 {
+		return microerror.Maskf(invalidConfigError, "on-demand base capacity must not be negative, found %d", d.OnDemandBaseCapacity)
+	}
+	if d.SpotPercentage < 0 || d.SpotPercentage > 100 {
+		return microerror.Maskf(invalidConfigError, "spot percentage must be between 0 and 100, found %d", d.SpotPercentage)
+	}
+
+	return nil
+}
+
+// validateWorkerPoolLabels ensures the given labels are valid Kubernetes
+// labels. They end up in the kubelet's --node-labels flag, so anything else
+// would break the kubelet of the worker pool or inject other flags.
+func validateWorkerPoolLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if k == WorkerPoolLabel {
+			return microerror.Maskf(invalidConfigError, "label %#q is managed by the operator", k)
+		}
+		if errs := validation.IsQualifiedName(k); len(errs) != 0 {
+			return microerror.Maskf(invalidConfigError, "label key %#q is invalid: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) != 0 {
+			return microerror.Maskf(invalidConfigError, "value %#q of label %#q is invalid: %s", v, k, strings.Join(errs, "; "))
+		}
+	}
+
+	return nil
+}
+
+// validateWorkerPoolTaint ensures the given taint is a valid Kubernetes taint
+// in the form key=value:effect or key:effect as expected by the kubelet's
+// --register-with-taints flag.
+func validateWorkerPoolTaint(taint string) error {
+	i := strings.LastIndex(taint, ":")
+	if i == -1 {
+		return microerror.Maskf(invalidConfigError, "taint %#q must be in the form key=value:effect", taint)
+	}
+
+	keyValue, effect := taint[:i], taint[i+1:]
+	switch effect {
+	case "NoExecute", "NoSchedule", "PreferNoSchedule":
+	default:
+		return microerror.Maskf(invalidConfigError, "effect of taint %#q must be %#q, %#q or %#q, found %#q", taint, "NoExecute", "NoSchedule", "PreferNoSchedule", effect)
+	}
+
+	k, v := keyValue, ""
+	if j := strings.Index(keyValue, "="); j != -1 {
+		k, v = keyValue[:j], keyValue[j+1:]
+	}
+	if errs := validation.IsQualifiedName(k); len(errs) != 0 {
+		return microerror.Maskf(invalidConfigError, "key of taint %#q is invalid: %s", taint, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsValidLabelValue(v); len(errs) != 0 {
+		return microerror.Maskf(invalidConfigError, "value of taint %#q is invalid: %s", taint, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
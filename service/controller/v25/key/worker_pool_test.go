@@ -0,0 +1,367 @@
+package key
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_WorkerPools(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description   string
+		annotations   map[string]string
+		expectedError bool
+		expectedPools []WorkerPool
+	}{
+		{
+			description:   "no worker pools annotation",
+			annotations:   nil,
+			expectedPools: nil,
+		},
+		{
+			description: "defaults from the default worker pool",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"taints":["gpu=true:NoSchedule"]}]`,
+			},
+			expectedPools: []WorkerPool{
+				{
+					DockerVolumeSizeGB: 50,
+					InstanceType:       "m4.xlarge",
+					Max:                3,
+					Min:                1,
+					Name:               "gpu",
+					Taints:             []string{"gpu=true:NoSchedule"},
+				},
+			},
+		},
+		{
+			description: "explicit instance type and docker volume size",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"big","instanceType":"m5.4xlarge","dockerVolumeSizeGB":200,"min":2,"max":2}]`,
+			},
+			expectedPools: []WorkerPool{
+				{
+					DockerVolumeSizeGB: 200,
+					InstanceType:       "m5.4xlarge",
+					Max:                2,
+					Min:                2,
+					Name:               "big",
+				},
+			},
+		},
+		{
+			description: "invalid name",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"GPU_pool","min":1,"max":3}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "duplicate names",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3},{"name":"gpu","min":1,"max":3}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "max smaller than min",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":3,"max":1}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "labels and taints",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"labels":{"example.com/gpu":"true","tier":""},"taints":["example.com/gpu=true:NoSchedule","dedicated:NoExecute"]}]`,
+			},
+			expectedPools: []WorkerPool{
+				{
+					DockerVolumeSizeGB: 50,
+					InstanceType:       "m4.xlarge",
+					Labels: map[string]string{
+						"example.com/gpu": "true",
+						"tier":            "",
+					},
+					Max:    3,
+					Min:    1,
+					Name:   "gpu",
+					Taints: []string{"example.com/gpu=true:NoSchedule", "dedicated:NoExecute"},
+				},
+			},
+		},
+		{
+			description: "label key injecting kubelet flags",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"labels":{"gpu --allow-privileged":"true"}}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "label value containing a comma",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"labels":{"gpu":"true,other=label"}}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "label value too long",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"labels":{"gpu":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "label overriding the worker pool label",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"labels":{"giantswarm.io/worker-pool":"other"}}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "taint without effect",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"taints":["gpu=true"]}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "taint with unknown effect",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"taints":["gpu=true:NoWay"]}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "taint with invalid key",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"taints":["-gpu=true:NoSchedule"]}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "taint with invalid value",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"taints":["gpu=true --v=9:NoSchedule"]}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "taints smuggled into one entry",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":"gpu","min":1,"max":3,"taints":["gpu=true:NoSchedule,other=true:NoSchedule"]}]`,
+			},
+			expectedError: true,
+		},
+		{
+			description: "malformed json",
+			annotations: map[string]string{
+				WorkerPoolsAnnotation: `[{"name":`,
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{
+				Spec: v1alpha1.AWSConfigSpec{
+					AWS: v1alpha1.AWSConfigSpecAWS{
+						Workers: []v1alpha1.AWSConfigSpecAWSNode{
+							{
+								DockerVolumeSizeGB: 50,
+								InstanceType:       "m4.xlarge",
+							},
+						},
+					},
+				},
+			}
+			customObject.SetAnnotations(tc.annotations)
+
+			pools, err := WorkerPools(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(pools, tc.expectedPools) {
+				t.Fatalf("expected worker pools %#v, got %#v", tc.expectedPools, pools)
+			}
+		})
+	}
+}
+
+func Test_WorkerPoolKubeletLabels(t *testing.T) {
+	t.Parallel()
+	customObject := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				Kubernetes: v1alpha1.ClusterKubernetes{
+					Kubelet: v1alpha1.ClusterKubernetesKubelet{
+						Labels: "giantswarm.io/provider=aws",
+					},
+				},
+			},
+		},
+	}
+	pool := WorkerPool{
+		Labels: map[string]string{
+			"b": "2",
+			"a": "1",
+		},
+		Name: "gpu",
+	}
+
+	expected := "giantswarm.io/provider=aws,giantswarm.io/worker-pool=gpu,a=1,b=2"
+	labels := WorkerPoolKubeletLabels(customObject, pool)
+	if labels != expected {
+		t.Fatalf("expected labels %q, got %q", expected, labels)
+	}
+}
+
+func Test_WorkerPoolResourceNames(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name                  string
+		expectedASGRef        string
+		expectedLifecycleHook string
+		expectedOutputKey     string
+		expectedRole          string
+	}{
+		{
+			name:                  "",
+			expectedASGRef:        WorkerASGRef,
+			expectedLifecycleHook: NodeDrainerLifecycleHookName,
+			expectedOutputKey:     WorkerASGNameKey,
+			expectedRole:          KindWorker,
+		},
+		{
+			name:                  "gpu",
+			expectedASGRef:        "workerPoolGpuAutoScalingGroup",
+			expectedLifecycleHook: "NodeDrainerGpu",
+			expectedOutputKey:     "WorkerASGNameGpu",
+			expectedRole:          "worker-gpu",
+		},
+	}
+
+	for _, tc := range testCases {
+		if WorkerPoolASGRef(tc.name) != tc.expectedASGRef {
+			t.Fatalf("expected ASG ref %q, got %q", tc.expectedASGRef, WorkerPoolASGRef(tc.name))
+		}
+		if WorkerPoolLifecycleHookResourceName(tc.name) != tc.expectedLifecycleHook {
+			t.Fatalf("expected lifecycle hook resource name %q, got %q", tc.expectedLifecycleHook, WorkerPoolLifecycleHookResourceName(tc.name))
+		}
+		if WorkerPoolOutputKey(WorkerASGNameKey, tc.name) != tc.expectedOutputKey {
+			t.Fatalf("expected output key %q, got %q", tc.expectedOutputKey, WorkerPoolOutputKey(WorkerASGNameKey, tc.name))
+		}
+		if WorkerPoolRole(tc.name) != tc.expectedRole {
+			t.Fatalf("expected role %q, got %q", tc.expectedRole, WorkerPoolRole(tc.name))
+		}
+	}
+}
+
+func Test_WorkerInstanceDistribution(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description          string
+		annotations          map[string]string
+		expectedError        bool
+		expectedDistribution InstanceDistribution
+	}{
+		{
+			description:          "no annotations means on-demand only",
+			annotations:          nil,
+			expectedDistribution: InstanceDistribution{},
+		},
+		{
+			description: "spot with on-demand base capacity and instance types",
+			annotations: map[string]string{
+				WorkerInstanceTypesAnnotation:        "m5.xlarge, m4.xlarge",
+				WorkerOnDemandBaseCapacityAnnotation: "2",
+				WorkerSpotPercentageAnnotation:       "75",
+			},
+			expectedDistribution: InstanceDistribution{
+				InstanceTypes:        []string{"m5.xlarge", "m4.xlarge"},
+				OnDemandBaseCapacity: 2,
+				SpotPercentage:       75,
+			},
+		},
+		{
+			description: "spot percentage out of range",
+			annotations: map[string]string{
+				WorkerSpotPercentageAnnotation: "101",
+			},
+			expectedError: true,
+		},
+		{
+			description: "malformed on-demand base capacity",
+			annotations: map[string]string{
+				WorkerOnDemandBaseCapacityAnnotation: "two",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			customObject := v1alpha1.AWSConfig{}
+			customObject.SetAnnotations(tc.annotations)
+
+			d, err := WorkerInstanceDistribution(customObject)
+			if tc.expectedError {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("expected invalid config error, got %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(d, tc.expectedDistribution) {
+				t.Fatalf("expected instance distribution %#v, got %#v", tc.expectedDistribution, d)
+			}
+		})
+	}
+}
+
+func Test_InstanceTypeOverrides(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		description       string
+		instanceType      string
+		distribution      InstanceDistribution
+		expectedOverrides []string
+	}{
+		{
+			description:       "no additional instance types",
+			instanceType:      "m4.xlarge",
+			distribution:      InstanceDistribution{},
+			expectedOverrides: nil,
+		},
+		{
+			description:  "instance type comes first and is not duplicated",
+			instanceType: "m4.xlarge",
+			distribution: InstanceDistribution{
+				InstanceTypes: []string{"m5.xlarge", "m4.xlarge"},
+			},
+			expectedOverrides: []string{"m4.xlarge", "m5.xlarge"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			overrides := InstanceTypeOverrides(tc.instanceType, tc.distribution)
+			if !reflect.DeepEqual(overrides, tc.expectedOverrides) {
+				t.Fatalf("expected overrides %#v, got %#v", tc.expectedOverrides, overrides)
+			}
+		})
+	}
+}
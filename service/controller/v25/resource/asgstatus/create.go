@@ -30,86 +30,118 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		return nil
 	}
 
+	desiredCapacity, maxSize, minSize, err := r.findASGSizes(ctx, workerASGName)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", "updating status with desired capacity")
+
+		newObj, err := r.g8sClient.ProviderV1alpha1().AWSConfigs(cr.GetNamespace()).Get(cr.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if newObj.Status.Cluster.Scaling.DesiredCapacity != desiredCapacity {
+			newObj.Status.Cluster.Scaling.DesiredCapacity = desiredCapacity
+			_, err = r.g8sClient.ProviderV1alpha1().AWSConfigs(newObj.GetNamespace()).UpdateStatus(newObj)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", "updated status with desired capacity")
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
+			reconciliationcanceledcontext.SetCanceled(ctx)
+
+			return nil
+		} else {
+			r.logger.LogCtx(ctx, "level", "debug", "message", "did not update status with desired capacity")
+		}
+	}
+
+	{
+		cc.Status.TenantCluster.TCCP.ASG.DesiredCapacity = desiredCapacity
+		cc.Status.TenantCluster.TCCP.ASG.MaxSize = maxSize
+		cc.Status.TenantCluster.TCCP.ASG.MinSize = minSize
+	}
+
+	for i, p := range cc.Status.TenantCluster.TCCP.WorkerPools {
+		if p.ASG.Name == "" {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("ASG name of worker pool %#q is not available yet", p.Name))
+			continue
+		}
+
+		desiredCapacity, maxSize, minSize, err := r.findASGSizes(ctx, p.ASG.Name)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		cc.Status.TenantCluster.TCCP.WorkerPools[i].ASG.DesiredCapacity = desiredCapacity
+		cc.Status.TenantCluster.TCCP.WorkerPools[i].ASG.MaxSize = maxSize
+		cc.Status.TenantCluster.TCCP.WorkerPools[i].ASG.MinSize = minSize
+	}
+
+	return nil
+}
+
+// findASGSizes returns the desired capacity, max size and min size of the ASG
+// with the given name.
+func (r *Resource) findASGSizes(ctx context.Context, asgName string) (int, int, int, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return 0, 0, 0, microerror.Mask(err)
+	}
+
 	var asg *autoscaling.Group
 	{
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding ASG %#q", workerASGName))
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding ASG %#q", asgName))
 
 		i := &autoscaling.DescribeAutoScalingGroupsInput{
 			AutoScalingGroupNames: []*string{
-				&workerASGName,
+				&asgName,
 			},
 		}
 		o, err := cc.Client.TenantCluster.AWS.AutoScaling.DescribeAutoScalingGroups(i)
 		if err != nil {
-			return microerror.Mask(err)
+			return 0, 0, 0, microerror.Mask(err)
 		}
 
 		if len(o.AutoScalingGroups) != 1 {
-			return microerror.Maskf(executionFailedError, "there must be one item for ASG %#q", workerASGName)
+			return 0, 0, 0, microerror.Maskf(executionFailedError, "there must be one item for ASG %#q", asgName)
 		}
 		asg = o.AutoScalingGroups[0]
 
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found ASG %#q", workerASGName))
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found ASG %#q", asgName))
 	}
 
 	var desiredCapacity int
 	{
 		if asg.DesiredCapacity == nil {
-			return microerror.Maskf(executionFailedError, "desired capacity must not be empty for ASG %#q", workerASGName)
+			return 0, 0, 0, microerror.Maskf(executionFailedError, "desired capacity must not be empty for ASG %#q", asgName)
 		}
 		desiredCapacity = int(*asg.DesiredCapacity)
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("desired capacity of %#q is %d", workerASGName, desiredCapacity))
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("desired capacity of %#q is %d", asgName, desiredCapacity))
 	}
 
 	var maxSize int
 	{
 		if asg.MaxSize == nil {
-			return microerror.Maskf(executionFailedError, "max size must not be empty for ASG %#q", workerASGName)
+			return 0, 0, 0, microerror.Maskf(executionFailedError, "max size must not be empty for ASG %#q", asgName)
 		}
 		maxSize = int(*asg.MaxSize)
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("max size of %#q is %d", workerASGName, maxSize))
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("max size of %#q is %d", asgName, maxSize))
 	}
 
 	var minSize int
 	{
 		if asg.MinSize == nil {
-			return microerror.Maskf(executionFailedError, "min size must not be empty for ASG %#q", workerASGName)
+			return 0, 0, 0, microerror.Maskf(executionFailedError, "min size must not be empty for ASG %#q", asgName)
 		}
 		minSize = int(*asg.MinSize)
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("min size of %#q is %d", workerASGName, minSize))
-	}
-
-	{
-		r.logger.LogCtx(ctx, "level", "debug", "message", "updating status with desired capacity")
-
-		newObj, err := r.g8sClient.ProviderV1alpha1().AWSConfigs(cr.GetNamespace()).Get(cr.GetName(), metav1.GetOptions{})
-		if err != nil {
-			return microerror.Mask(err)
-		}
-
-		if newObj.Status.Cluster.Scaling.DesiredCapacity != desiredCapacity {
-			newObj.Status.Cluster.Scaling.DesiredCapacity = desiredCapacity
-			_, err = r.g8sClient.ProviderV1alpha1().AWSConfigs(newObj.GetNamespace()).UpdateStatus(newObj)
-			if err != nil {
-				return microerror.Mask(err)
-			}
-
-			r.logger.LogCtx(ctx, "level", "debug", "message", "updated status with desired capacity")
-
-			r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
-			reconciliationcanceledcontext.SetCanceled(ctx)
-
-			return nil
-		} else {
-			r.logger.LogCtx(ctx, "level", "debug", "message", "did not update status with desired capacity")
-		}
-	}
-
-	{
-		cc.Status.TenantCluster.TCCP.ASG.DesiredCapacity = desiredCapacity
-		cc.Status.TenantCluster.TCCP.ASG.MaxSize = maxSize
-		cc.Status.TenantCluster.TCCP.ASG.MinSize = minSize
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("min size of %#q is %d", asgName, minSize))
 	}
 
-	return nil
+	return desiredCapacity, maxSize, minSize, nil
 }
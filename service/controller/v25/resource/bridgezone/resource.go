@@ -271,13 +271,12 @@ func (r *Resource) route53Clients(ctx context.Context) (guest, defaultGuest *rou
 
 	// defaultGuest
 	{
-		arn, err := credential.GetDefaultARN(r.k8sClient)
+		role, err := credential.GetDefaultRole(r.k8sClient)
 		if err != nil {
 			return nil, nil, microerror.Mask(err)
 		}
 
-		c := r.hostAWSConfig
-		c.RoleARN = arn
+		c := role.AWSConfig(r.hostAWSConfig)
 
 		newClients, err := clientaws.NewClients(c)
 		if err != nil {
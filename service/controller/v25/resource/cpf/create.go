@@ -24,9 +24,18 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
-	{
-		if cc.Status.TenantCluster.TCCP.VPC.PeeringConnectionID == "" {
-			r.logger.LogCtx(ctx, "level", "debug", "message", "did not find the VPC Peering Connection ID in the controller context")
+	// Tenant clusters created in an existing VPC are not peered with the control
+	// plane VPC. There is nothing to finalize then, unless the record sets have
+	// to be managed.
+	if key.IsExistingVPC(cr) {
+		if !r.route53Enabled {
+			r.logger.LogCtx(ctx, "level", "debug", "message", "tenant cluster uses an existing VPC and route53 is disabled")
+			r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+			return nil
+		}
+	} else {
+		if cc.Status.TenantCluster.TCCP.VPC.PeeringConnectionID == "" && cc.Status.TenantCluster.TCCP.VPC.TransitGatewayAttachmentID == "" {
+			r.logger.LogCtx(ctx, "level", "debug", "message", "did not find the VPC Peering Connection ID or the Transit Gateway Attachment ID in the controller context")
 			r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
 			return nil
 		}
@@ -77,9 +86,15 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 				return microerror.Mask(err)
 			}
 
+			transitGateway, err := r.newTransitGatewayParams(ctx, cr)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
 			params = &template.ParamsMain{
-				RecordSets:  recordSets,
-				RouteTables: routeTables,
+				RecordSets:     recordSets,
+				RouteTables:    routeTables,
+				TransitGateway: transitGateway,
 			}
 		}
 
@@ -152,6 +167,7 @@ func (r *Resource) newPrivateRoutes(ctx context.Context, cr v1alpha1.AWSConfig)
 				// The peer connection id is fetched from the cloud formation stack
 				// outputs in the stackoutput resource.
 				PeerConnectionID: cc.Status.TenantCluster.TCCP.VPC.PeeringConnectionID,
+				TransitGatewayID: r.routeTransitGatewayID(cc),
 			}
 
 			routes = append(routes, route)
@@ -182,6 +198,7 @@ func (r *Resource) newPublicRoutes(ctx context.Context, cr v1alpha1.AWSConfig) (
 			// The peer connection id is fetched from the cloud formation stack
 			// outputs in the stackoutput resource.
 			PeerConnectionID: cc.Status.TenantCluster.TCCP.VPC.PeeringConnectionID,
+			TransitGatewayID: r.routeTransitGatewayID(cc),
 		}
 
 		routes = append(routes, route)
@@ -210,6 +227,12 @@ func (r *Resource) newRecordSetsParams(ctx context.Context, cr v1alpha1.AWSConfi
 }
 
 func (r *Resource) newRouteTablesParams(ctx context.Context, cr v1alpha1.AWSConfig) (*template.ParamsMainRouteTables, error) {
+	// There is no peering connection to route through in case the tenant
+	// cluster is created in an existing VPC.
+	if key.IsExistingVPC(cr) {
+		return &template.ParamsMainRouteTables{}, nil
+	}
+
 	privateRoutes, err := r.newPrivateRoutes(ctx, cr)
 	if err != nil {
 		return nil, microerror.Mask(err)
@@ -230,3 +253,30 @@ func (r *Resource) newRouteTablesParams(ctx context.Context, cr v1alpha1.AWSConf
 
 	return routeTables, nil
 }
+
+func (r *Resource) newTransitGatewayParams(ctx context.Context, cr v1alpha1.AWSConfig) (*template.ParamsMainTransitGateway, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	transitGateway := &template.ParamsMainTransitGateway{}
+
+	if cc.Status.TenantCluster.TCCP.VPC.TransitGatewayAttachmentID != "" {
+		transitGateway.AttachmentID = cc.Status.TenantCluster.TCCP.VPC.TransitGatewayAttachmentID
+		transitGateway.RouteTableID = r.transitGatewayRouteTableID
+	}
+
+	return transitGateway, nil
+}
+
+// routeTransitGatewayID returns the ID of the transit gateway the routes to the
+// tenant cluster go through. The ID is empty in case the tenant cluster is
+// peered with the control plane VPC.
+func (r *Resource) routeTransitGatewayID(cc *controllercontext.Context) string {
+	if cc.Status.TenantCluster.TCCP.VPC.TransitGatewayAttachmentID == "" {
+		return ""
+	}
+
+	return r.transitGatewayID
+}
@@ -1,6 +1,8 @@
 package cpf
 
 import (
+	"strings"
+
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
@@ -21,6 +23,14 @@ type Config struct {
 	EncrypterBackend string
 	InstallationName string
 	Route53Enabled   bool
+	// TransitGatewayID is the ID of the transit gateway the control plane routes
+	// the traffic to tenant clusters attached to it through.
+	TransitGatewayID string
+	// TransitGatewayRouteTableID is the ID of the transit gateway route table
+	// the attachments of tenant clusters are associated with and propagate
+	// their routes to. The attachments are left to the default route table of
+	// the transit gateway when empty.
+	TransitGatewayRouteTableID string
 }
 
 // Resource implements the CPF resource, which stands for Control Plane
@@ -29,9 +39,11 @@ type Config struct {
 type Resource struct {
 	logger micrologger.Logger
 
-	encrypterBackend string
-	installationName string
-	route53Enabled   bool
+	encrypterBackend           string
+	installationName           string
+	route53Enabled             bool
+	transitGatewayID           string
+	transitGatewayRouteTableID string
 }
 
 func New(config Config) (*Resource, error) {
@@ -42,13 +54,21 @@ func New(config Config) (*Resource, error) {
 	if config.EncrypterBackend == "" {
 		return nil, microerror.Maskf(invalidConfigError, "%T.EncrypterBackend must not be empty", config)
 	}
+	if config.TransitGatewayRouteTableID != "" && config.TransitGatewayID == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.TransitGatewayID must not be empty when %T.TransitGatewayRouteTableID is set", config, config)
+	}
+	if config.TransitGatewayRouteTableID != "" && !strings.HasPrefix(config.TransitGatewayRouteTableID, "tgw-rtb-") {
+		return nil, microerror.Maskf(invalidConfigError, "%T.TransitGatewayRouteTableID must start with %#q", config, "tgw-rtb-")
+	}
 
 	r := &Resource{
 		logger: config.Logger,
 
-		encrypterBackend: config.EncrypterBackend,
-		installationName: config.InstallationName,
-		route53Enabled:   config.Route53Enabled,
+		encrypterBackend:           config.EncrypterBackend,
+		installationName:           config.InstallationName,
+		route53Enabled:             config.Route53Enabled,
+		transitGatewayID:           config.TransitGatewayID,
+		transitGatewayRouteTableID: config.TransitGatewayRouteTableID,
 	}
 
 	return r, nil
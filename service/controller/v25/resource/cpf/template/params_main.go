@@ -2,6 +2,7 @@ package template
 
 // ParamsMain is the data structure for the Control Plane Finalizer template.
 type ParamsMain struct {
-	RecordSets  *ParamsMainRecordSets
-	RouteTables *ParamsMainRouteTables
+	RecordSets     *ParamsMainRecordSets
+	RouteTables    *ParamsMainRouteTables
+	TransitGateway *ParamsMainTransitGateway
 }
@@ -9,4 +9,5 @@ type ParamsMainRouteTablesRoute struct {
 	RouteTableID     string
 	CidrBlock        string
 	PeerConnectionID string
+	TransitGatewayID string
 }
@@ -0,0 +1,6 @@
+package template
+
+type ParamsMainTransitGateway struct {
+	AttachmentID string
+	RouteTableID string
+}
@@ -11,6 +11,7 @@ func Render(v interface{}) (string, error) {
 		TemplateMain,
 		TemplateMainRecordSets,
 		TemplateMainRouteTables,
+		TemplateMainTransitGateway,
 	}
 
 	s, err := templates.Render(l, v)
@@ -62,3 +62,61 @@ func Test_Controller_Resource_CPF_Template_Render(t *testing.T) {
 		}
 	}
 }
+
+func Test_Controller_Resource_CPF_Template_Render_TransitGateway(t *testing.T) {
+	var err error
+
+	var params *ParamsMain
+	{
+		recordSets := &ParamsMainRecordSets{
+			BaseDomain:     "BaseDomain",
+			Route53Enabled: true,
+		}
+		routeTables := &ParamsMainRouteTables{
+			PrivateRoutes: []ParamsMainRouteTablesRoute{
+				ParamsMainRouteTablesRoute{
+					TransitGatewayID: "TransitGatewayID",
+				},
+			},
+		}
+		transitGateway := &ParamsMainTransitGateway{
+			AttachmentID: "AttachmentID",
+			RouteTableID: "RouteTableID",
+		}
+
+		params = &ParamsMain{
+			RecordSets:     recordSets,
+			RouteTables:    routeTables,
+			TransitGateway: transitGateway,
+		}
+	}
+
+	var templateBody string
+	{
+		templateBody, err = Render(params)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	{
+		expected := "TransitGatewayId: TransitGatewayID"
+		if !strings.Contains(templateBody, expected) {
+			t.Fatal("expected", "match", "got", "none")
+		}
+	}
+
+	{
+		unexpected := "VpcPeeringConnectionId:"
+		if strings.Contains(templateBody, unexpected) {
+			t.Fatal("expected", "none", "got", "match")
+		}
+	}
+
+	{
+		expected := "TransitGatewayRouteTablePropagation:"
+		if !strings.Contains(templateBody, expected) {
+			t.Fatal("expected", "match", "got", "none")
+		}
+	}
+}
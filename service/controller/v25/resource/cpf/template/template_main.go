@@ -7,5 +7,6 @@ Description: Control Plane Finalizer Cloud Formation Stack.
 Resources:
   {{template "record_sets" .}}
   {{template "route_tables" .}}
+  {{template "transit_gateway" .}}
 {{end}}
 `
@@ -8,7 +8,11 @@ const TemplateMainRouteTables = `
     Properties:
       RouteTableId: {{$r.RouteTableID}}
       DestinationCidrBlock: {{$r.CidrBlock}}
+      {{- if $r.TransitGatewayID }}
+      TransitGatewayId: {{$r.TransitGatewayID}}
+      {{- else }}
       VpcPeeringConnectionId: {{$r.PeerConnectionID}}
+      {{- end }}
   {{end}}
 
   {{ range $i, $r := .RouteTables.PublicRoutes }}
@@ -17,7 +21,11 @@ const TemplateMainRouteTables = `
     Properties:
       RouteTableId: {{$r.RouteTableID}}
       DestinationCidrBlock: {{$r.CidrBlock}}
+      {{- if $r.TransitGatewayID }}
+      TransitGatewayId: {{$r.TransitGatewayID}}
+      {{- else }}
       VpcPeeringConnectionId: {{$r.PeerConnectionID}}
+      {{- end }}
   {{ end }}
 {{ end }}
 `
@@ -0,0 +1,20 @@
+package template
+
+const TemplateMainTransitGateway = `
+{{ define "transit_gateway" }}
+{{ with .TransitGateway }}
+{{ if .RouteTableID }}
+  TransitGatewayRouteTableAssociation:
+    Type: AWS::EC2::TransitGatewayRouteTableAssociation
+    Properties:
+      TransitGatewayAttachmentId: {{ .AttachmentID }}
+      TransitGatewayRouteTableId: {{ .RouteTableID }}
+  TransitGatewayRouteTablePropagation:
+    Type: AWS::EC2::TransitGatewayRouteTablePropagation
+    Properties:
+      TransitGatewayAttachmentId: {{ .AttachmentID }}
+      TransitGatewayRouteTableId: {{ .RouteTableID }}
+{{ end }}
+{{ end }}
+{{ end }}
+`
@@ -57,12 +57,32 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		r.logger.LogCtx(ctx, "level", "debug", "message", "did not find the tenant cluster's control plane initializer cloud formation stack")
 	}
 
+	// New tenant clusters are attached to the transit gateway instead of being
+	// peered with the control plane VPC, so the peer role is not needed. The
+	// transit gateway only has to be shared with the tenant cluster's account
+	// in case it differs from the control plane account.
+	if r.transitGatewayID != "" && cc.Status.TenantCluster.AWSAccountID == cc.Status.ControlPlane.AWSAccountID {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "not creating the tenant cluster's control plane initializer cloud formation stack")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "tenant cluster is attached to a transit gateway of its own account")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
 	var templateBody string
 	{
 		r.logger.LogCtx(ctx, "level", "debug", "message", "computing the template of the tenant cluster's control plane initializer cloud formation stack")
 
 		var params *template.ParamsMain
-		{
+		if r.transitGatewayID != "" {
+			transitGateway, err := r.newTransitGatewayParams(ctx, cr)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			params = &template.ParamsMain{
+				TransitGateway: transitGateway,
+			}
+		} else {
 			iamRoles, err := r.newIAMRolesParams(ctx, cr)
 			if err != nil {
 				return microerror.Mask(err)
@@ -139,3 +159,24 @@ func (r *Resource) newIAMRolesParams(ctx context.Context, cr v1alpha1.AWSConfig)
 
 	return iamRoles, nil
 }
+
+func (r *Resource) newTransitGatewayParams(ctx context.Context, cr v1alpha1.AWSConfig) (*template.ParamsMainTransitGateway, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	transitGateway := &template.ParamsMainTransitGateway{
+		ARN:               key.TransitGatewayARN(cr, cc.Status.ControlPlane.AWSAccountID, r.transitGatewayID),
+		ResourceShareName: key.TransitGatewayResourceShareName(cr),
+		Tenant: template.ParamsMainTransitGatewayTenant{
+			AWS: template.ParamsMainTransitGatewayTenantAWS{
+				Account: template.ParamsMainTransitGatewayTenantAWSAccount{
+					ID: cc.Status.TenantCluster.AWSAccountID,
+				},
+			},
+		},
+	}
+
+	return transitGateway, nil
+}
@@ -1,6 +1,8 @@
 package cpi
 
 import (
+	"strings"
+
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
@@ -19,26 +21,39 @@ type Config struct {
 	Logger micrologger.Logger
 
 	InstallationName string
+	// TransitGatewayID is the ID of the transit gateway new tenant clusters are
+	// attached to. The peer role is not needed then. Instead the transit gateway
+	// is shared with the tenant cluster's account via RAM. The share is only
+	// accepted automatically when both accounts are part of the same AWS
+	// Organization with resource sharing enabled, which is why external
+	// principals are not allowed.
+	TransitGatewayID string
 }
 
 // Resource implements the CPI resource, which stands for Control Plane
 // Initializer. This was formerly known as the host pre stack. We manage a
-// dedicated CF stack for the IAM role and VPC Peering setup.
+// dedicated CF stack for the IAM role and VPC Peering setup, or for sharing the
+// transit gateway with the tenant cluster's account.
 type Resource struct {
 	logger micrologger.Logger
 
 	installationName string
+	transitGatewayID string
 }
 
 func New(config Config) (*Resource, error) {
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
+	if config.TransitGatewayID != "" && !strings.HasPrefix(config.TransitGatewayID, "tgw-") {
+		return nil, microerror.Maskf(invalidConfigError, "%T.TransitGatewayID must start with %#q", config, "tgw-")
+	}
 
 	r := &Resource{
 		logger: config.Logger,
 
 		installationName: config.InstallationName,
+		transitGatewayID: config.TransitGatewayID,
 	}
 
 	return r, nil
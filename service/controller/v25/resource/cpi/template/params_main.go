@@ -2,5 +2,6 @@ package template
 
 // ParamsMain is the data structure for the Control Plane Initializer template.
 type ParamsMain struct {
-	IAMRoles *ParamsMainIAMRoles
+	IAMRoles       *ParamsMainIAMRoles
+	TransitGateway *ParamsMainTransitGateway
 }
@@ -0,0 +1,21 @@
+package template
+
+type ParamsMainTransitGateway struct {
+	// ARN is the ARN of the transit gateway shared with the tenant cluster's
+	// account.
+	ARN               string
+	ResourceShareName string
+	Tenant            ParamsMainTransitGatewayTenant
+}
+
+type ParamsMainTransitGatewayTenant struct {
+	AWS ParamsMainTransitGatewayTenantAWS
+}
+
+type ParamsMainTransitGatewayTenantAWS struct {
+	Account ParamsMainTransitGatewayTenantAWSAccount
+}
+
+type ParamsMainTransitGatewayTenantAWSAccount struct {
+	ID string
+}
@@ -10,6 +10,7 @@ func Render(v interface{}) (string, error) {
 	l := []string{
 		TemplateMain,
 		TemplateMainIAMRoles,
+		TemplateMainTransitGateway,
 	}
 
 	s, err := templates.Render(l, v)
@@ -48,3 +48,62 @@ func Test_Controller_Resource_CPI_Template_Render(t *testing.T) {
 		}
 	}
 }
+
+func Test_Controller_Resource_CPI_Template_Render_TransitGateway(t *testing.T) {
+	var err error
+
+	var params *ParamsMain
+	{
+		transitGateway := &ParamsMainTransitGateway{
+			ARN:               "TransitGatewayARN",
+			ResourceShareName: "ResourceShareName",
+			Tenant: ParamsMainTransitGatewayTenant{
+				AWS: ParamsMainTransitGatewayTenantAWS{
+					Account: ParamsMainTransitGatewayTenantAWSAccount{
+						ID: "TenantAWSAccountID",
+					},
+				},
+			},
+		}
+
+		params = &ParamsMain{
+			TransitGateway: transitGateway,
+		}
+	}
+
+	var templateBody string
+	{
+		templateBody, err = Render(params)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	{
+		expected := "Type: 'AWS::RAM::ResourceShare'"
+		if !strings.Contains(templateBody, expected) {
+			t.Fatal("expected", "match", "got", "none")
+		}
+	}
+
+	{
+		expected := "- 'TransitGatewayARN'"
+		if !strings.Contains(templateBody, expected) {
+			t.Fatal("expected", "match", "got", "none")
+		}
+	}
+
+	{
+		expected := "- 'TenantAWSAccountID'"
+		if !strings.Contains(templateBody, expected) {
+			t.Fatal("expected", "match", "got", "none")
+		}
+	}
+
+	{
+		unexpected := "PeerRole"
+		if strings.Contains(templateBody, unexpected) {
+			t.Fatal("expected", "none", "got", "match")
+		}
+	}
+}
@@ -5,6 +5,9 @@ const TemplateMain = `
 AWSTemplateFormatVersion: 2010-09-09
 Description: Control Plane Initializer Cloud Formation Stack.
 Resources:
+  {{- if .IAMRoles }}
   {{template "iam_roles" .}}
+  {{- end }}
+  {{template "transit_gateway" .}}
 {{end}}
 `
@@ -0,0 +1,17 @@
+package template
+
+const TemplateMainTransitGateway = `
+{{ define "transit_gateway" }}
+{{- with .TransitGateway }}
+  TransitGatewayResourceShare:
+    Type: 'AWS::RAM::ResourceShare'
+    Properties:
+      Name: {{ .ResourceShareName }}
+      AllowExternalPrincipals: false
+      Principals:
+        - '{{ .Tenant.AWS.Account.ID }}'
+      ResourceArns:
+        - '{{ .ARN }}'
+{{- end }}
+{{end}}
+`
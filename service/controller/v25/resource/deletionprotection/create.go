@@ -0,0 +1,40 @@
+package deletionprotection
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+// EnsureCreated validates the deletion protection of the tenant cluster, so a
+// malformed annotation is noticed before the tenant cluster gets deleted. Once
+// the tenant cluster is not protected anymore, the condition reporting a
+// refused deletion is removed from the CR status.
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	protected, err := key.DeletionProtection(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !protected && hasCondition(cr.Status.Cluster.Resources, conditionDeletionRefused) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "updating CR status")
+
+		cr.Status.Cluster.Resources = withoutCondition(cr.Status.Cluster.Resources, conditionDeletionRefused)
+
+		_, err = r.g8sClient.ProviderV1alpha1().AWSConfigs(cr.Namespace).UpdateStatus(&cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", "updated CR status")
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package deletionprotection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/apiextensions/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func Test_Resource_EnsureCreated_DeletionRefused(t *testing.T) {
+	testCases := []struct {
+		name              string
+		annotations       map[string]string
+		expectedCondition bool
+	}{
+		{
+			name:              "case 0: condition is removed once the tenant cluster is not protected anymore",
+			annotations:       nil,
+			expectedCondition: false,
+		},
+		{
+			name: "case 1: condition is kept while the tenant cluster is protected",
+			annotations: map[string]string{
+				key.DeletionProtectionAnnotation: "true",
+			},
+			expectedCondition: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := &v1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+					Name:        "test-cluster",
+					Namespace:   "default",
+				},
+			}
+			cr.Status.Cluster.Resources = withCondition(nil, newCondition(conditionDeletionRefused))
+
+			g8sClient := fake.NewSimpleClientset(cr)
+
+			r, err := New(Config{
+				G8sClient: g8sClient,
+				Logger:    microloggertest.New(),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			err = r.EnsureCreated(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			updated, err := g8sClient.ProviderV1alpha1().AWSConfigs("default").Get("test-cluster", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if hasCondition(updated.Status.Cluster.Resources, conditionDeletionRefused) != tc.expectedCondition {
+				t.Fatalf("expected condition %#q to be reported %t", conditionDeletionRefused, tc.expectedCondition)
+			}
+		})
+	}
+}
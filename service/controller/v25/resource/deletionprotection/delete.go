@@ -0,0 +1,57 @@
+package deletionprotection
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/controller/context/finalizerskeptcontext"
+	"github.com/giantswarm/operatorkit/controller/context/reconciliationcanceledcontext"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// A malformed annotation is treated as protected. Guessing wrong here would
+	// delete the tenant cluster for good.
+	protected, err := key.DeletionProtection(cr)
+	if key.IsInvalidConfig(err) {
+		r.logger.LogCtx(ctx, "level", "warning", "message", "treating the tenant cluster as protected from deletion", "stack", fmt.Sprintf("%#v", err))
+		protected = true
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !protected {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "the tenant cluster is not protected from deletion")
+		return nil
+	}
+
+	r.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("refusing to delete the tenant cluster because of annotation %#q", key.DeletionProtectionAnnotation))
+
+	if !hasCondition(cr.Status.Cluster.Resources, conditionDeletionRefused) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "updating CR status")
+
+		cr.Status.Cluster.Resources = withCondition(cr.Status.Cluster.Resources, newCondition(conditionDeletionRefused))
+
+		_, err = r.g8sClient.ProviderV1alpha1().AWSConfigs(cr.Namespace).UpdateStatus(&cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", "updated CR status")
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "keeping finalizers")
+	finalizerskeptcontext.SetKept(ctx)
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
+	reconciliationcanceledcontext.SetCanceled(ctx)
+
+	return nil
+}
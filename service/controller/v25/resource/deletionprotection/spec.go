@@ -0,0 +1,92 @@
+package deletionprotection
+
+import (
+	"time"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+const (
+	// conditionDeletionRefused is reported while the deletion of a protected
+	// tenant cluster is refused.
+	conditionDeletionRefused = "DeletionRefused"
+)
+
+func hasCondition(resources []v1alpha1.StatusClusterResource, condition string) bool {
+	for _, r := range resources {
+		if r.Name != Name {
+			continue
+		}
+
+		for _, c := range r.Conditions {
+			if c.Type == condition {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func newCondition(condition string) v1alpha1.StatusClusterResourceCondition {
+	return v1alpha1.StatusClusterResourceCondition{
+		LastTransitionTime: v1alpha1.DeepCopyTime{Time: time.Now()},
+		Status:             "True",
+		Type:               condition,
+	}
+}
+
+// withCondition returns the given resources with the given condition reported
+// for the deletion protection resource, replacing any condition of the same
+// type.
+func withCondition(resources []v1alpha1.StatusClusterResource, condition v1alpha1.StatusClusterResourceCondition) []v1alpha1.StatusClusterResource {
+	idx := -1
+	for i, r := range resources {
+		if r.Name == Name {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		resources = append(resources, v1alpha1.StatusClusterResource{Name: Name})
+		idx = len(resources) - 1
+	}
+
+	var kept []v1alpha1.StatusClusterResourceCondition
+	for _, c := range resources[idx].Conditions {
+		if c.Type != condition.Type {
+			kept = append(kept, c)
+		}
+	}
+	resources[idx].Conditions = append(kept, condition)
+
+	return resources
+}
+
+// withoutCondition returns the given resources without the given condition of
+// the deletion protection resource. The deletion protection resource is
+// removed altogether once it has no conditions left.
+func withoutCondition(resources []v1alpha1.StatusClusterResource, condition string) []v1alpha1.StatusClusterResource {
+	var kept []v1alpha1.StatusClusterResource
+
+	for _, r := range resources {
+		if r.Name != Name {
+			kept = append(kept, r)
+			continue
+		}
+
+		var conditions []v1alpha1.StatusClusterResourceCondition
+		for _, c := range r.Conditions {
+			if c.Type != condition {
+				conditions = append(conditions, c)
+			}
+		}
+
+		if len(conditions) > 0 {
+			r.Conditions = conditions
+			kept = append(kept, r)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,136 @@
+package deletionprotection
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_withCondition(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		resources []v1alpha1.StatusClusterResource
+	}{
+		{
+			name:      "case 0: no resources",
+			resources: nil,
+		},
+		{
+			name: "case 1: other resources are kept",
+			resources: []v1alpha1.StatusClusterResource{
+				{
+					Name: "stackrecoveryv25",
+					Conditions: []v1alpha1.StatusClusterResourceCondition{
+						{Status: "True", Type: "TCCPRecovered"},
+					},
+				},
+			},
+		},
+		{
+			name: "case 2: existing condition is replaced",
+			resources: []v1alpha1.StatusClusterResource{
+				{
+					Name: Name,
+					Conditions: []v1alpha1.StatusClusterResourceCondition{
+						{Status: "True", Type: conditionDeletionRefused},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			others := len(tc.resources)
+			if hasCondition(tc.resources, conditionDeletionRefused) {
+				others--
+			}
+
+			resources := withCondition(tc.resources, newCondition(conditionDeletionRefused))
+
+			if !hasCondition(resources, conditionDeletionRefused) {
+				t.Fatalf("expected condition %#q", conditionDeletionRefused)
+			}
+			if len(resources) != others+1 {
+				t.Fatalf("expected %d resources, got %d", others+1, len(resources))
+			}
+			for _, r := range resources {
+				if r.Name == Name && len(r.Conditions) != 1 {
+					t.Fatalf("expected 1 condition, got %d", len(r.Conditions))
+				}
+			}
+		})
+	}
+}
+
+func Test_withoutCondition(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name              string
+		resources         []v1alpha1.StatusClusterResource
+		expectedResources []v1alpha1.StatusClusterResource
+	}{
+		{
+			name:              "case 0: no resources",
+			resources:         nil,
+			expectedResources: nil,
+		},
+		{
+			name: "case 1: other resources are kept",
+			resources: []v1alpha1.StatusClusterResource{
+				{
+					Name: "stackrecoveryv25",
+					Conditions: []v1alpha1.StatusClusterResourceCondition{
+						{Status: "True", Type: conditionDeletionRefused},
+					},
+				},
+				{
+					Name: Name,
+					Conditions: []v1alpha1.StatusClusterResourceCondition{
+						{Status: "True", Type: conditionDeletionRefused},
+					},
+				},
+			},
+			expectedResources: []v1alpha1.StatusClusterResource{
+				{
+					Name: "stackrecoveryv25",
+					Conditions: []v1alpha1.StatusClusterResourceCondition{
+						{Status: "True", Type: conditionDeletionRefused},
+					},
+				},
+			},
+		},
+		{
+			name: "case 2: other conditions are kept",
+			resources: []v1alpha1.StatusClusterResource{
+				{
+					Name: Name,
+					Conditions: []v1alpha1.StatusClusterResourceCondition{
+						{Status: "True", Type: "Other"},
+						{Status: "True", Type: conditionDeletionRefused},
+					},
+				},
+			},
+			expectedResources: []v1alpha1.StatusClusterResource{
+				{
+					Name: Name,
+					Conditions: []v1alpha1.StatusClusterResourceCondition{
+						{Status: "True", Type: "Other"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := withoutCondition(tc.resources, conditionDeletionRefused)
+
+			if !reflect.DeepEqual(resources, tc.expectedResources) {
+				t.Fatalf("expected resources %#v, got %#v", tc.expectedResources, resources)
+			}
+		})
+	}
+}
@@ -17,8 +17,15 @@ import (
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
+const (
+	// spotStatusMarkedForTermination is the status code of spot instance
+	// requests whose instances received a spot interruption notice.
+	spotStatusMarkedForTermination = "marked-for-termination"
+)
+
 // EnsureCreated creates DrainerConfigs for ASG instances in terminating/wait
-// state then lets node-operator to do its job.
+// state and for spot instances marked for termination, then lets
+// node-operator to do its job.
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 	customObject, err := key.ToCustomObject(obj)
 	if err != nil {
@@ -30,21 +37,17 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		return microerror.Mask(err)
 	}
 
-	workerASGName := cc.Status.TenantCluster.TCCP.ASG.Name
-	if workerASGName == "" {
-		r.logger.LogCtx(ctx, "level", "debug", "message", "worker ASG name is not available yet")
+	workerASGNames := cc.Status.TenantCluster.TCCP.WorkerASGNames()
+	if len(workerASGNames) == 0 {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "worker ASG names are not available yet")
 		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
 		return nil
 	}
 
-	var instances []*autoscaling.Instance
+	var groups []*autoscaling.Group
 	{
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding the guest cluster nodes being in state %#q", autoscaling.LifecycleStateTerminatingWait))
-
 		i := &autoscaling.DescribeAutoScalingGroupsInput{
-			AutoScalingGroupNames: []*string{
-				aws.String(workerASGName),
-			},
+			AutoScalingGroupNames: aws.StringSlice(workerASGNames),
 		}
 
 		o, err := cc.Client.TenantCluster.AWS.AutoScaling.DescribeAutoScalingGroups(i)
@@ -52,30 +55,87 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 			return microerror.Mask(err)
 		}
 
-		for _, g := range o.AutoScalingGroups {
+		groups = o.AutoScalingGroups
+	}
+
+	// instances maps the IDs of the instances to be drained to the names of
+	// their ASGs.
+	instances := map[string]string{}
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding the guest cluster nodes being in state %#q", autoscaling.LifecycleStateTerminatingWait))
+
+		for _, g := range groups {
 			for _, i := range g.Instances {
 				if *i.LifecycleState == autoscaling.LifecycleStateTerminatingWait {
-					instances = append(instances, i)
+					instances[*i.InstanceId] = *g.AutoScalingGroupName
 				}
 			}
 		}
 
-		if len(instances) == 0 {
-			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("did not find the guest cluster nodes being in state %#q", autoscaling.LifecycleStateTerminatingWait))
-			r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
-			return nil
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d guest cluster nodes being in state %#q", len(instances), autoscaling.LifecycleStateTerminatingWait))
+	}
+
+	var spotInstances map[string]string
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", "finding the guest cluster spot instances marked for termination")
+
+		spotInstances, err = r.spotInstancesMarkedForTermination(ctx, groups)
+		if err != nil {
+			return microerror.Mask(err)
 		}
 
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d guest cluster nodes being in state %#q", len(instances), autoscaling.LifecycleStateTerminatingWait))
+		for id, asgName := range spotInstances {
+			instances[id] = asgName
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d guest cluster spot instances marked for termination", len(spotInstances)))
+	}
+
+	if len(instances) == 0 {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "did not find guest cluster nodes to drain")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
+	// drainedInstances are the IDs of the instances which already have drainer
+	// configs. Spot instances marked for termination stay in service until EC2
+	// terminates them, so they would otherwise be looked up and drained again in
+	// every reconciliation.
+	drainedInstances := map[string]bool{}
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", "finding drainer configs for the guest cluster")
+
+		n := customObject.GetNamespace()
+		o := metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", key.ClusterIDLabel, key.ClusterID(customObject)),
+		}
+
+		drainerConfigs, err := r.g8sClient.CoreV1alpha1().DrainerConfigs(n).List(o)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, c := range drainerConfigs.Items {
+			if id := c.GetAnnotations()[key.InstanceIDAnnotation]; id != "" {
+				drainedInstances[id] = true
+			}
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d drainer configs for the guest cluster", len(drainerConfigs.Items)))
 	}
 
 	{
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("ensuring drainer configs for %d guest cluster nodes being in state %#q", len(instances), autoscaling.LifecycleStateTerminatingWait))
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("ensuring drainer configs for %d guest cluster nodes", len(instances)))
+
+		for instanceID, asgName := range instances {
+			if drainedInstances[instanceID] {
+				r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found drainer config for guest cluster node %#q", instanceID))
+				continue
+			}
 
-		for _, instance := range instances {
-			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding drainer config for guest cluster nodes %#q", *instance.InstanceId))
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding drainer config for guest cluster nodes %#q", instanceID))
 
-			privateDNS, err := r.privateDNSForInstance(ctx, *instance.InstanceId)
+			privateDNS, err := r.privateDNSForInstance(ctx, instanceID)
 			if err != nil {
 				return microerror.Mask(err)
 			}
@@ -86,8 +146,8 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 				// chance to gather the drainer configs here. The operator then did its
 				// job already and we only have to deal with the edge case situation. So
 				// we just stop here and move on with the other instances.
-				r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("no private DNS for instance %#q", *instance.InstanceId))
-				r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("not draining instance %#q", *instance.InstanceId))
+				r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("no private DNS for instance %#q", instanceID))
+				r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("not draining instance %#q", instanceID))
 				continue
 			}
 
@@ -96,9 +156,11 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 
 			_, err = r.g8sClient.CoreV1alpha1().DrainerConfigs(n).Get(privateDNS, o)
 			if errors.IsNotFound(err) {
-				r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("did not find drainer config for guest cluster node %#q", *instance.InstanceId))
+				r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("did not find drainer config for guest cluster node %#q", instanceID))
 
-				err := r.createDrainerConfig(ctx, customObject, *instance.InstanceId, privateDNS)
+				_, spot := spotInstances[instanceID]
+
+				err := r.createDrainerConfig(ctx, customObject, instanceID, privateDNS, asgName, spot)
 				if err != nil {
 					return microerror.Mask(err)
 				}
@@ -107,22 +169,27 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 				return microerror.Mask(err)
 			}
 
-			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found drainer config for guest cluster node %#q", *instance.InstanceId))
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found drainer config for guest cluster node %#q", instanceID))
 		}
 
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("ensured drainer configs for %d guest cluster nodes being in state %#q", len(instances), autoscaling.LifecycleStateTerminatingWait))
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("ensured drainer configs for %d guest cluster nodes", len(instances)))
 	}
 
 	return nil
 }
 
-func (r *Resource) createDrainerConfig(ctx context.Context, customObject providerv1alpha1.AWSConfig, instanceID, privateDNS string) error {
+// createDrainerConfig creates the drainer config of the given instance. Spot
+// instances marked for termination are annotated with
+// key.SpotInterruptionAnnotation, since they are not held back by a lifecycle
+// hook.
+func (r *Resource) createDrainerConfig(ctx context.Context, customObject providerv1alpha1.AWSConfig, instanceID, privateDNS, asgName string, spot bool) error {
 	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("creating drainer config for guest cluster nodes %#q", instanceID))
 
 	n := customObject.GetNamespace()
 	c := &corev1alpha1.DrainerConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
+				key.ASGNameAnnotation:    asgName,
 				key.InstanceIDAnnotation: instanceID,
 			},
 			Labels: map[string]string{
@@ -148,6 +215,10 @@ func (r *Resource) createDrainerConfig(ctx context.Context, customObject provide
 		},
 	}
 
+	if spot {
+		c.Annotations[key.SpotInterruptionAnnotation] = "true"
+	}
+
 	_, err := r.g8sClient.CoreV1alpha1().DrainerConfigs(n).Create(c)
 	if err != nil {
 		return microerror.Mask(err)
@@ -157,6 +228,66 @@ func (r *Resource) createDrainerConfig(ctx context.Context, customObject provide
 	return nil
 }
 
+// spotInstancesMarkedForTermination returns the IDs of the in service spot
+// instances of the given ASGs, which received a spot interruption notice,
+// mapped to the names of their ASGs. EC2 terminates these instances two
+// minutes after the notice, so draining them is best effort.
+func (r *Resource) spotInstancesMarkedForTermination(ctx context.Context, groups []*autoscaling.Group) (map[string]string, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	asgNames := map[string]string{}
+	for _, g := range groups {
+		for _, i := range g.Instances {
+			if *i.LifecycleState == autoscaling.LifecycleStateInService {
+				asgNames[*i.InstanceId] = *g.AutoScalingGroupName
+			}
+		}
+	}
+
+	if len(asgNames) == 0 {
+		return nil, nil
+	}
+
+	var instanceIDs []*string
+	for id := range asgNames {
+		instanceIDs = append(instanceIDs, aws.String(id))
+	}
+
+	i := &ec2.DescribeSpotInstanceRequestsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-id"),
+				Values: instanceIDs,
+			},
+			{
+				Name: aws.String("status-code"),
+				Values: []*string{
+					aws.String(spotStatusMarkedForTermination),
+				},
+			},
+		},
+	}
+
+	o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSpotInstanceRequests(i)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	instances := map[string]string{}
+	for _, req := range o.SpotInstanceRequests {
+		if req.InstanceId == nil {
+			continue
+		}
+
+		instances[*req.InstanceId] = asgNames[*req.InstanceId]
+	}
+
+	return instances, nil
+}
+
 func (r *Resource) privateDNSForInstance(ctx context.Context, instanceID string) (string, error) {
 	i := &ec2.DescribeInstancesInput{
 		InstanceIds: []*string{
@@ -0,0 +1,173 @@
+package drainer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	corev1alpha1 "github.com/giantswarm/apiextensions/pkg/apis/core/v1alpha1"
+	providerv1alpha1 "github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/apiextensions/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func Test_Resource_EnsureCreated_SpotInstances(t *testing.T) {
+	testCases := []struct {
+		name                       string
+		instances                  map[string]string
+		markedForTermination       []string
+		drainerConfigs             []runtime.Object
+		expectedDrainerConfigs     map[string]map[string]string
+		expectedDescribedInstances []string
+	}{
+		{
+			name: "case 0: spot instance marked for termination gets a drainer config",
+			instances: map[string]string{
+				"i-spot":   autoscaling.LifecycleStateInService,
+				"i-normal": autoscaling.LifecycleStateInService,
+			},
+			markedForTermination: []string{"i-spot"},
+			expectedDrainerConfigs: map[string]map[string]string{
+				"i-spot.eu-central-1.compute.internal": {
+					key.ASGNameAnnotation:          "worker-asg",
+					key.InstanceIDAnnotation:       "i-spot",
+					key.SpotInterruptionAnnotation: "true",
+				},
+			},
+			expectedDescribedInstances: []string{"i-spot"},
+		},
+		{
+			name: "case 1: spot instance marked for termination having a drainer config is skipped",
+			instances: map[string]string{
+				"i-spot": autoscaling.LifecycleStateInService,
+			},
+			markedForTermination: []string{"i-spot"},
+			drainerConfigs: []runtime.Object{
+				newTestDrainerConfig("i-spot.eu-central-1.compute.internal", map[string]string{
+					key.ASGNameAnnotation:          "worker-asg",
+					key.InstanceIDAnnotation:       "i-spot",
+					key.SpotInterruptionAnnotation: "true",
+				}),
+			},
+			expectedDrainerConfigs: map[string]map[string]string{
+				"i-spot.eu-central-1.compute.internal": {
+					key.ASGNameAnnotation:          "worker-asg",
+					key.InstanceIDAnnotation:       "i-spot",
+					key.SpotInterruptionAnnotation: "true",
+				},
+			},
+			expectedDescribedInstances: nil,
+		},
+		{
+			name: "case 2: terminating instance gets a drainer config without spot interruption annotation",
+			instances: map[string]string{
+				"i-terminating": autoscaling.LifecycleStateTerminatingWait,
+			},
+			expectedDrainerConfigs: map[string]map[string]string{
+				"i-terminating.eu-central-1.compute.internal": {
+					key.ASGNameAnnotation:    "worker-asg",
+					key.InstanceIDAnnotation: "i-terminating",
+				},
+			},
+			expectedDescribedInstances: []string{"i-terminating"},
+		},
+		{
+			name: "case 3: spot instance not marked for termination is not drained",
+			instances: map[string]string{
+				"i-spot": autoscaling.LifecycleStateInService,
+			},
+			expectedDrainerConfigs:     map[string]map[string]string{},
+			expectedDescribedInstances: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g8sClient := fake.NewSimpleClientset(tc.drainerConfigs...)
+
+			r, err := NewResource(ResourceConfig{
+				G8sClient: g8sClient,
+				Logger:    microloggertest.New(),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			group := &autoscaling.Group{
+				AutoScalingGroupName: aws.String("worker-asg"),
+			}
+			for id, state := range tc.instances {
+				group.Instances = append(group.Instances, &autoscaling.Instance{
+					InstanceId:     aws.String(id),
+					LifecycleState: aws.String(state),
+				})
+			}
+
+			ec2Client := &EC2ClientMock{
+				markedForTermination: tc.markedForTermination,
+			}
+
+			cc := controllercontext.Context{}
+			cc.Client.TenantCluster.AWS.AutoScaling = &AutoScalingClientMock{groups: []*autoscaling.Group{group}}
+			cc.Client.TenantCluster.AWS.EC2 = ec2Client
+			cc.Status.TenantCluster.TCCP.ASG.Name = "worker-asg"
+			ctx := controllercontext.NewContext(context.Background(), cc)
+
+			cr := &providerv1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+				},
+				Spec: providerv1alpha1.AWSConfigSpec{
+					Cluster: providerv1alpha1.Cluster{
+						ID: "test-cluster",
+					},
+				},
+			}
+
+			err = r.EnsureCreated(ctx, cr)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(ec2Client.describedInstances, tc.expectedDescribedInstances) {
+				t.Fatalf("expected described instances %#v, got %#v", tc.expectedDescribedInstances, ec2Client.describedInstances)
+			}
+
+			list, err := g8sClient.CoreV1alpha1().DrainerConfigs("default").List(metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			drainerConfigs := map[string]map[string]string{}
+			for _, c := range list.Items {
+				drainerConfigs[c.GetName()] = c.GetAnnotations()
+			}
+
+			if !reflect.DeepEqual(drainerConfigs, tc.expectedDrainerConfigs) {
+				t.Fatalf("expected drainer configs %#v, got %#v", tc.expectedDrainerConfigs, drainerConfigs)
+			}
+		})
+	}
+}
+
+func newTestDrainerConfig(name string, annotations map[string]string) *corev1alpha1.DrainerConfig {
+	c := &corev1alpha1.DrainerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: annotations,
+			Labels: map[string]string{
+				key.ClusterIDLabel: "test-cluster",
+			},
+			Name:      name,
+			Namespace: "default",
+		},
+	}
+
+	return c
+}
@@ -0,0 +1,79 @@
+package drainer
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+type AutoScalingClientMock struct {
+	autoscalingiface.AutoScalingAPI
+
+	groups []*autoscaling.Group
+}
+
+func (a *AutoScalingClientMock) DescribeAutoScalingGroups(*autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	o := &autoscaling.DescribeAutoScalingGroupsOutput{
+		AutoScalingGroups: a.groups,
+	}
+
+	return o, nil
+}
+
+type EC2ClientMock struct {
+	ec2iface.EC2API
+
+	// markedForTermination are the IDs of the spot instances which received a
+	// spot interruption notice.
+	markedForTermination []string
+
+	describedInstances []string
+}
+
+// DescribeInstances returns the given instances with their private DNS names
+// derived from their IDs.
+func (e *EC2ClientMock) DescribeInstances(i *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	o := &ec2.DescribeInstancesOutput{}
+
+	for _, id := range aws.StringValueSlice(i.InstanceIds) {
+		e.describedInstances = append(e.describedInstances, id)
+
+		o.Reservations = append(o.Reservations, &ec2.Reservation{
+			Instances: []*ec2.Instance{
+				{
+					InstanceId:     aws.String(id),
+					PrivateDnsName: aws.String(fmt.Sprintf("%s.eu-central-1.compute.internal", id)),
+				},
+			},
+		})
+	}
+
+	return o, nil
+}
+
+func (e *EC2ClientMock) DescribeSpotInstanceRequests(i *ec2.DescribeSpotInstanceRequestsInput) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
+	o := &ec2.DescribeSpotInstanceRequestsOutput{}
+
+	var instanceIDs []string
+	for _, f := range i.Filters {
+		if aws.StringValue(f.Name) == "instance-id" {
+			instanceIDs = aws.StringValueSlice(f.Values)
+		}
+	}
+
+	for _, id := range instanceIDs {
+		for _, m := range e.markedForTermination {
+			if id == m {
+				o.SpotInstanceRequests = append(o.SpotInstanceRequests, &ec2.SpotInstanceRequest{
+					InstanceId: aws.String(id),
+				})
+			}
+		}
+	}
+
+	return o, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	corev1alpha1 "github.com/giantswarm/apiextensions/pkg/apis/core/v1alpha1"
 	"github.com/giantswarm/microerror"
 	"k8s.io/api/core/v1"
@@ -16,7 +17,9 @@ import (
 )
 
 // EnsureCreated completes ASG lifecycle hooks for nodes drained by
-// node-operator, and then deletes drained DrainerConfigs.
+// node-operator, and then deletes drained DrainerConfigs. DrainerConfigs of
+// spot instances marked for termination are deleted once EC2 terminated their
+// instances.
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 	customObject, err := key.ToCustomObject(obj)
 	if err != nil {
@@ -84,7 +87,39 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 				return microerror.Mask(err)
 			}
 
-			err = r.completeLifecycleHook(ctx, instanceID, workerASGName)
+			// Spot instances marked for termination are terminated by EC2 on its
+			// own, so there is no lifecycle action to complete. Their drainer
+			// configs are kept until the instances are gone, so that the drainer
+			// resource does not drain them again in the meantime.
+			if drainerConfig.GetAnnotations()[key.SpotInterruptionAnnotation] == "true" {
+				terminated, err := r.instanceTerminated(ctx, instanceID)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+
+				if !terminated {
+					r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("not deleting drainer config '%s' of guest cluster", drainerConfig.GetName()))
+					r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("spot instance '%s' is not terminated yet", instanceID))
+					continue
+				}
+
+				err = r.deleteDrainerConfig(ctx, drainerConfig)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+
+				continue
+			}
+
+			// Drainer configs created before worker pools were supported do not
+			// have the ASG name annotation. Their instances always belong to the
+			// ASG of the default worker pool.
+			asgName, ok := drainerConfig.GetAnnotations()[key.ASGNameAnnotation]
+			if !ok || asgName == "" {
+				asgName = workerASGName
+			}
+
+			err = r.completeLifecycleHook(ctx, instanceID, asgName)
 			if err != nil {
 				return microerror.Mask(err)
 			}
@@ -128,6 +163,44 @@ func (r *Resource) completeLifecycleHook(ctx context.Context, instanceID, worker
 	return nil
 }
 
+// instanceTerminated returns whether the given EC2 instance is shutting down,
+// terminated or does not exist anymore.
+func (r *Resource) instanceTerminated(ctx context.Context, instanceID string) (bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	i := &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{
+			aws.String(instanceID),
+		},
+	}
+
+	o, err := cc.Client.TenantCluster.AWS.EC2.DescribeInstances(i)
+	if IsInstanceNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	for _, reservation := range o.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.State == nil {
+				continue
+			}
+
+			switch aws.StringValue(instance.State.Name) {
+			case ec2.InstanceStateNameShuttingDown, ec2.InstanceStateNameTerminated:
+			default:
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
 func (r *Resource) deleteDrainerConfig(ctx context.Context, drainerConfig corev1alpha1.DrainerConfig) error {
 	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleting drainer config for guest cluster node '%s'", drainerConfig.Name))
 
@@ -0,0 +1,143 @@
+package drainfinisher
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	corev1alpha1 "github.com/giantswarm/apiextensions/pkg/apis/core/v1alpha1"
+	providerv1alpha1 "github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/apiextensions/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func Test_Resource_EnsureCreated_SpotInstances(t *testing.T) {
+	testCases := []struct {
+		name                       string
+		drainerConfig              *corev1alpha1.DrainerConfig
+		instanceStates             map[string]string
+		expectedCompletedInstances []string
+		expectedDrainerConfigs     []string
+	}{
+		{
+			name:                       "case 0: lifecycle action of drained instance is completed",
+			drainerConfig:              newTestDrainerConfig("node-1", "i-1", false, true),
+			instanceStates:             map[string]string{"i-1": ec2.InstanceStateNameRunning},
+			expectedCompletedInstances: []string{"i-1"},
+			expectedDrainerConfigs:     nil,
+		},
+		{
+			name:                       "case 1: drainer config of drained running spot instance is kept",
+			drainerConfig:              newTestDrainerConfig("node-1", "i-1", true, true),
+			instanceStates:             map[string]string{"i-1": ec2.InstanceStateNameRunning},
+			expectedCompletedInstances: nil,
+			expectedDrainerConfigs:     []string{"node-1"},
+		},
+		{
+			name:                       "case 2: drainer config of terminated spot instance is deleted without completing a lifecycle action",
+			drainerConfig:              newTestDrainerConfig("node-1", "i-1", true, true),
+			instanceStates:             map[string]string{"i-1": ec2.InstanceStateNameTerminated},
+			expectedCompletedInstances: nil,
+			expectedDrainerConfigs:     nil,
+		},
+		{
+			name:                       "case 3: drainer config of spot instance which does not exist anymore is deleted",
+			drainerConfig:              newTestDrainerConfig("node-1", "i-1", true, true),
+			instanceStates:             map[string]string{},
+			expectedCompletedInstances: nil,
+			expectedDrainerConfigs:     nil,
+		},
+		{
+			name:                       "case 4: drainer config of spot instance not drained yet is kept",
+			drainerConfig:              newTestDrainerConfig("node-1", "i-1", true, false),
+			instanceStates:             map[string]string{"i-1": ec2.InstanceStateNameTerminated},
+			expectedCompletedInstances: nil,
+			expectedDrainerConfigs:     []string{"node-1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g8sClient := fake.NewSimpleClientset(tc.drainerConfig)
+
+			r, err := NewResource(ResourceConfig{
+				G8sClient: g8sClient,
+				Logger:    microloggertest.New(),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			autoScalingClient := &AutoScalingClientMock{}
+
+			cc := controllercontext.Context{}
+			cc.Client.TenantCluster.AWS.AutoScaling = autoScalingClient
+			cc.Client.TenantCluster.AWS.EC2 = &EC2ClientMock{instanceStates: tc.instanceStates}
+			cc.Status.TenantCluster.TCCP.ASG.Name = "worker-asg"
+			ctx := controllercontext.NewContext(context.Background(), cc)
+
+			cr := &providerv1alpha1.AWSConfig{
+				Spec: providerv1alpha1.AWSConfigSpec{
+					Cluster: providerv1alpha1.Cluster{
+						ID: "test-cluster",
+					},
+				},
+			}
+
+			err = r.EnsureCreated(ctx, cr)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(autoScalingClient.completedInstances, tc.expectedCompletedInstances) {
+				t.Fatalf("expected completed lifecycle actions of %#v, got %#v", tc.expectedCompletedInstances, autoScalingClient.completedInstances)
+			}
+
+			list, err := g8sClient.CoreV1alpha1().DrainerConfigs("default").List(metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			var drainerConfigs []string
+			for _, c := range list.Items {
+				drainerConfigs = append(drainerConfigs, c.GetName())
+			}
+			sort.Strings(drainerConfigs)
+
+			if !reflect.DeepEqual(drainerConfigs, tc.expectedDrainerConfigs) {
+				t.Fatalf("expected drainer configs %#v, got %#v", tc.expectedDrainerConfigs, drainerConfigs)
+			}
+		})
+	}
+}
+
+func newTestDrainerConfig(name, instanceID string, spot, drained bool) *corev1alpha1.DrainerConfig {
+	c := &corev1alpha1.DrainerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				key.ASGNameAnnotation:    "worker-asg",
+				key.InstanceIDAnnotation: instanceID,
+			},
+			Labels: map[string]string{
+				key.ClusterIDLabel: "test-cluster",
+			},
+			Name:      name,
+			Namespace: "default",
+		},
+	}
+
+	if spot {
+		c.Annotations[key.SpotInterruptionAnnotation] = "true"
+	}
+	if drained {
+		c.Status.Conditions = append(c.Status.Conditions, c.Status.NewDrainedCondition())
+	}
+
+	return c
+}
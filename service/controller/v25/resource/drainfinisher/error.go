@@ -15,6 +15,30 @@ func IsExecutionFailed(err error) bool {
 	return microerror.Cause(err) == executionFailedError
 }
 
+var instanceNotFoundError = &microerror.Error{
+	Kind: "instanceNotFoundError",
+}
+
+// IsInstanceNotFound asserts instanceNotFoundError. It also checks for the
+// error code the AWS API returns for EC2 instances which do not exist.
+func IsInstanceNotFound(err error) bool {
+	c := microerror.Cause(err)
+
+	if c == nil {
+		return false
+	}
+
+	if strings.Contains(c.Error(), "InvalidInstanceID.NotFound") {
+		return true
+	}
+
+	if c == instanceNotFoundError {
+		return true
+	}
+
+	return false
+}
+
 var invalidConfigError = &microerror.Error{
 	Kind: "invalidConfigError",
 }
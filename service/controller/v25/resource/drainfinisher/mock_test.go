@@ -0,0 +1,55 @@
+package drainfinisher
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+type AutoScalingClientMock struct {
+	autoscalingiface.AutoScalingAPI
+
+	completedInstances []string
+}
+
+func (a *AutoScalingClientMock) CompleteLifecycleAction(i *autoscaling.CompleteLifecycleActionInput) (*autoscaling.CompleteLifecycleActionOutput, error) {
+	a.completedInstances = append(a.completedInstances, aws.StringValue(i.InstanceId))
+	return &autoscaling.CompleteLifecycleActionOutput{}, nil
+}
+
+type EC2ClientMock struct {
+	ec2iface.EC2API
+
+	// instanceStates maps instance IDs to their states. Instances not in the
+	// map do not exist.
+	instanceStates map[string]string
+}
+
+func (e *EC2ClientMock) DescribeInstances(i *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	o := &ec2.DescribeInstancesOutput{}
+
+	for _, id := range aws.StringValueSlice(i.InstanceIds) {
+		state, ok := e.instanceStates[id]
+		if !ok {
+			return nil, awserr.New("InvalidInstanceID.NotFound", fmt.Sprintf("The instance ID '%s' does not exist", id), nil)
+		}
+
+		o.Reservations = append(o.Reservations, &ec2.Reservation{
+			Instances: []*ec2.Instance{
+				{
+					InstanceId: aws.String(id),
+					State: &ec2.InstanceState{
+						Name: aws.String(state),
+					},
+				},
+			},
+		})
+	}
+
+	return o, nil
+}
@@ -2,10 +2,67 @@ package ebsvolume
 
 import (
 	"context"
+	"time"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/ebs"
 )
 
-// EnsureCreated is a no-op, because the ebsvolume resource is only
-// interested in delete events.
+// EnsureCreated deletes the expired snapshots of EBS volumes of deleted tenant
+// clusters. Snapshots are kept in the AWS account of the tenant cluster they
+// were taken from, so expired snapshots are deleted while any tenant cluster
+// of the same AWS account is reconciled. This happens at most once per
+// snapshotSweepInterval and AWS account.
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	accountID := cc.Status.TenantCluster.AWSAccountID
+	now := time.Now()
+
+	{
+		r.mutex.Lock()
+		last, ok := r.lastSnapshotSweeps[accountID]
+		r.mutex.Unlock()
+
+		if ok && now.Sub(last) < snapshotSweepInterval {
+			r.logger.LogCtx(ctx, "level", "debug", "message", "not deleting expired snapshots of EBS volumes")
+			r.logger.LogCtx(ctx, "level", "debug", "message", "expired snapshots of EBS volumes got deleted recently")
+			return nil
+		}
+	}
+
+	var ebsService ebs.Interface
+	{
+		c := ebs.Config{
+			Client: cc.Client.TenantCluster.AWS.EC2,
+			Logger: r.logger,
+		}
+
+		ebsService, err = ebs.New(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", "deleting expired snapshots of EBS volumes")
+
+		err = r.deleteExpiredSnapshots(ctx, ebsService)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.mutex.Lock()
+		r.lastSnapshotSweeps[accountID] = now
+		r.mutex.Unlock()
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", "deleted expired snapshots of EBS volumes")
+	}
+
 	return nil
 }
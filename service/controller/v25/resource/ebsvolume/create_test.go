@@ -0,0 +1,129 @@
+package ebsvolume
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func newTestCustomObject(clusterID string, annotations map[string]string) v1alpha1.AWSConfig {
+	cr := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: clusterID,
+			},
+		},
+	}
+	cr.SetAnnotations(annotations)
+
+	return cr
+}
+
+func newTestContext(accountID string, ec2Client *EC2ClientMock) context.Context {
+	cc := controllercontext.Context{}
+	cc.Client.TenantCluster.AWS.EC2 = ec2Client
+	cc.Status.TenantCluster.AWSAccountID = accountID
+
+	return controllercontext.NewContext(context.Background(), cc)
+}
+
+func newTestResource(snapshotOnDelete bool) *Resource {
+	c := Config{
+		Logger: microloggertest.New(),
+
+		InstallationName:      "test-installation",
+		SnapshotOnDelete:      snapshotOnDelete,
+		SnapshotRetentionDays: 30,
+	}
+
+	r, err := New(c)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// newTestSnapshot returns a snapshot of the given volume of the given tenant
+// cluster of the test installation. The expiration tag is omitted in case the
+// given expiration is empty.
+func newTestSnapshot(clusterID, volumeID, state, expiration string) *ec2.Snapshot {
+	tags := key.ClusterTags(newTestCustomObject(clusterID, nil), "test-installation")
+	if expiration != "" {
+		tags[key.EBSSnapshotExpirationTagName] = expiration
+	}
+
+	s := &ec2.Snapshot{
+		SnapshotId: aws.String("snap-" + volumeID),
+		State:      aws.String(state),
+		VolumeId:   aws.String(volumeID),
+	}
+	for k, v := range tags {
+		s.Tags = append(s.Tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	return s
+}
+
+func Test_Resource_EnsureCreated_ExpiredSnapshots(t *testing.T) {
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	notExpired := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	ec2Client := &EC2ClientMock{
+		snapshots: []*ec2.Snapshot{
+			newTestSnapshot("deleted1", "vol-expired", ec2.SnapshotStateCompleted, expired),
+			newTestSnapshot("deleted2", "vol-not-expired", ec2.SnapshotStateCompleted, notExpired),
+			newTestSnapshot("deleted2", "vol-pending", ec2.SnapshotStatePending, expired),
+			newTestSnapshot("deleted3", "vol-untagged", ec2.SnapshotStateCompleted, ""),
+			newTestSnapshot("deleted3", "vol-malformed", ec2.SnapshotStateCompleted, "tomorrow"),
+		},
+	}
+
+	r := newTestResource(false)
+	cr := newTestCustomObject("test-cluster", nil)
+
+	err := r.EnsureCreated(newTestContext("123456789012", ec2Client), &cr)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expectedCalls := []string{
+		"DescribeSnapshots",
+		"DeleteSnapshot snap-vol-expired",
+	}
+	if !reflect.DeepEqual(ec2Client.calls, expectedCalls) {
+		t.Fatalf("expected calls %#v, got %#v", expectedCalls, ec2Client.calls)
+	}
+
+	// Expired snapshots are not looked up again in the same AWS account within
+	// the sweep interval.
+	ec2Client.calls = nil
+
+	err = r.EnsureCreated(newTestContext("123456789012", ec2Client), &cr)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if len(ec2Client.calls) != 0 {
+		t.Fatalf("expected no calls, got %#v", ec2Client.calls)
+	}
+
+	// Other AWS accounts are swept on their own.
+	err = r.EnsureCreated(newTestContext("210987654321", ec2Client), &cr)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if !reflect.DeepEqual(ec2Client.calls, expectedCalls) {
+		t.Fatalf("expected calls %#v, got %#v", expectedCalls, ec2Client.calls)
+	}
+}
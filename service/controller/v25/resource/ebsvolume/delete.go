@@ -6,6 +6,8 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/controller/context/finalizerskeptcontext"
+	"github.com/giantswarm/operatorkit/controller/context/reconciliationcanceledcontext"
 
 	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
 	"github.com/giantswarm/aws-operator/service/controller/v25/ebs"
@@ -13,7 +15,10 @@ import (
 )
 
 // EnsureDeleted detaches and deletes the EBS volumes. We don't return
-// errors so deletion logic in following resources is executed.
+// errors so deletion logic in following resources is executed. The only
+// exception are snapshots. In case they are enabled, the deletion of the
+// tenant cluster only proceeds once all etcd and persistent volumes have
+// completed snapshots.
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 	cr, err := key.ToCustomObject(obj)
 	if err != nil {
@@ -37,6 +42,57 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 		}
 	}
 
+	// Snapshot all etcd and persistent volumes and wait for the snapshots to
+	// complete before any volume is deleted. Docker volumes only hold data
+	// which can be recreated.
+	{
+		snapshot, err := key.EBSSnapshotOnDelete(cr, r.snapshotOnDelete)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if snapshot {
+			r.logger.LogCtx(ctx, "level", "debug", "message", "ensuring snapshots of EBS volumes")
+
+			filterFuncs := []func(t *ec2.Tag) bool{
+				ebs.NewEtcdVolumeFilter(cr),
+				ebs.NewPersistentVolumeFilter(cr),
+			}
+			volumes, err := ebsService.ListVolumes(cr, filterFuncs...)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			completed, err := r.ensureSnapshots(ctx, cr, ebsService, volumes)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			if !completed {
+				r.logger.LogCtx(ctx, "level", "debug", "message", "snapshots of EBS volumes are not completed yet")
+
+				r.logger.LogCtx(ctx, "level", "debug", "message", "keeping finalizers")
+				finalizerskeptcontext.SetKept(ctx)
+
+				r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
+				reconciliationcanceledcontext.SetCanceled(ctx)
+
+				return nil
+			}
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", "ensured snapshots of EBS volumes")
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", "deleting expired snapshots of EBS volumes")
+
+			err = r.deleteExpiredSnapshots(ctx, ebsService)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", "deleted expired snapshots of EBS volumes")
+		}
+	}
+
 	// Get all etcd, docker and persistent volumes.
 	filterFuncs := []func(t *ec2.Tag) bool{
 		ebs.NewDockerVolumeFilter(cr),
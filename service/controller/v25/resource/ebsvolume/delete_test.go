@@ -0,0 +1,149 @@
+package ebsvolume
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/operatorkit/controller/context/finalizerskeptcontext"
+	"github.com/giantswarm/operatorkit/controller/context/reconciliationcanceledcontext"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func Test_Resource_EnsureDeleted_Snapshots(t *testing.T) {
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	notExpired := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	testCases := []struct {
+		name             string
+		snapshotOnDelete bool
+		annotations      map[string]string
+		snapshots        []*ec2.Snapshot
+		expectedCalls    []string
+		expectedKept     bool
+	}{
+		{
+			name:             "case 0: volumes are deleted without snapshots when snapshots are disabled",
+			snapshotOnDelete: false,
+			expectedCalls: []string{
+				"DeleteVolume vol-etcd",
+				"DeleteVolume vol-docker",
+				"DeleteVolume vol-pv",
+			},
+			expectedKept: false,
+		},
+		{
+			name:             "case 1: snapshots of etcd and persistent volumes are requested and finalizers are kept",
+			snapshotOnDelete: true,
+			expectedCalls: []string{
+				"DescribeSnapshots",
+				"CreateSnapshot vol-etcd",
+				"CreateSnapshot vol-pv",
+			},
+			expectedKept: true,
+		},
+		{
+			name:             "case 2: annotation enables snapshots disabled by default",
+			snapshotOnDelete: false,
+			annotations: map[string]string{
+				key.EBSSnapshotOnDeleteAnnotation: "true",
+			},
+			expectedCalls: []string{
+				"DescribeSnapshots",
+				"CreateSnapshot vol-etcd",
+				"CreateSnapshot vol-pv",
+			},
+			expectedKept: true,
+		},
+		{
+			name:             "case 3: finalizers are kept while snapshots are pending",
+			snapshotOnDelete: true,
+			snapshots: []*ec2.Snapshot{
+				newTestSnapshot("test-cluster", "vol-etcd", ec2.SnapshotStatePending, notExpired),
+				newTestSnapshot("test-cluster", "vol-pv", ec2.SnapshotStateCompleted, notExpired),
+			},
+			expectedCalls: []string{
+				"DescribeSnapshots",
+			},
+			expectedKept: true,
+		},
+		{
+			name:             "case 4: failed snapshots are requested again",
+			snapshotOnDelete: true,
+			snapshots: []*ec2.Snapshot{
+				newTestSnapshot("test-cluster", "vol-etcd", ec2.SnapshotStateError, notExpired),
+				newTestSnapshot("test-cluster", "vol-pv", ec2.SnapshotStateCompleted, notExpired),
+			},
+			expectedCalls: []string{
+				"DescribeSnapshots",
+				"CreateSnapshot vol-etcd",
+			},
+			expectedKept: true,
+		},
+		{
+			name:             "case 5: volumes are deleted once snapshots are completed",
+			snapshotOnDelete: true,
+			snapshots: []*ec2.Snapshot{
+				newTestSnapshot("test-cluster", "vol-etcd", ec2.SnapshotStateCompleted, notExpired),
+				newTestSnapshot("test-cluster", "vol-pv", ec2.SnapshotStateCompleted, notExpired),
+				newTestSnapshot("deleted", "vol-old", ec2.SnapshotStateCompleted, expired),
+			},
+			expectedCalls: []string{
+				"DescribeSnapshots",
+				"DescribeSnapshots",
+				"DeleteSnapshot snap-vol-old",
+				"DeleteVolume vol-etcd",
+				"DeleteVolume vol-docker",
+				"DeleteVolume vol-pv",
+			},
+			expectedKept: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Client := &EC2ClientMock{
+				snapshots: tc.snapshots,
+				volumes: []*ec2.Volume{
+					{
+						Tags:     []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-cluster-etcd")}},
+						VolumeId: aws.String("vol-etcd"),
+					},
+					{
+						Tags:     []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-cluster-docker")}},
+						VolumeId: aws.String("vol-docker"),
+					},
+					{
+						Tags:     []*ec2.Tag{{Key: aws.String("kubernetes.io/created-for/pv/name"), Value: aws.String("pvc-1")}},
+						VolumeId: aws.String("vol-pv"),
+					},
+				},
+			}
+
+			r := newTestResource(tc.snapshotOnDelete)
+			cr := newTestCustomObject("test-cluster", tc.annotations)
+
+			ctx := newTestContext("123456789012", ec2Client)
+			ctx = finalizerskeptcontext.NewContext(ctx, make(chan struct{}))
+			ctx = reconciliationcanceledcontext.NewContext(ctx, make(chan struct{}))
+
+			err := r.EnsureDeleted(ctx, &cr)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(ec2Client.calls, tc.expectedCalls) {
+				t.Fatalf("expected calls %#v, got %#v", tc.expectedCalls, ec2Client.calls)
+			}
+			if finalizerskeptcontext.IsKept(ctx) != tc.expectedKept {
+				t.Fatalf("expected finalizers kept to be %t, got %t", tc.expectedKept, finalizerskeptcontext.IsKept(ctx))
+			}
+			if reconciliationcanceledcontext.IsCanceled(ctx) != tc.expectedKept {
+				t.Fatalf("expected reconciliation canceled to be %t, got %t", tc.expectedKept, reconciliationcanceledcontext.IsCanceled(ctx))
+			}
+		})
+	}
+}
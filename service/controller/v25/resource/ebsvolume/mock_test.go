@@ -0,0 +1,92 @@
+package ebsvolume
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+type EC2ClientMock struct {
+	ec2iface.EC2API
+
+	snapshots []*ec2.Snapshot
+	volumes   []*ec2.Volume
+
+	calls []string
+}
+
+func (e *EC2ClientMock) CreateSnapshot(i *ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	e.calls = append(e.calls, fmt.Sprintf("CreateSnapshot %s", aws.StringValue(i.VolumeId)))
+
+	s := &ec2.Snapshot{
+		SnapshotId: aws.String(fmt.Sprintf("snap-%s", aws.StringValue(i.VolumeId))),
+	}
+
+	return s, nil
+}
+
+func (e *EC2ClientMock) DeleteSnapshot(i *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
+	e.calls = append(e.calls, fmt.Sprintf("DeleteSnapshot %s", aws.StringValue(i.SnapshotId)))
+	return &ec2.DeleteSnapshotOutput{}, nil
+}
+
+func (e *EC2ClientMock) DeleteVolume(i *ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error) {
+	e.calls = append(e.calls, fmt.Sprintf("DeleteVolume %s", aws.StringValue(i.VolumeId)))
+	return &ec2.DeleteVolumeOutput{}, nil
+}
+
+// DescribeSnapshotsPages returns the snapshots matching all tag filters of the
+// given input.
+func (e *EC2ClientMock) DescribeSnapshotsPages(i *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool) error {
+	e.calls = append(e.calls, "DescribeSnapshots")
+
+	o := &ec2.DescribeSnapshotsOutput{}
+
+	for _, s := range e.snapshots {
+		matches := true
+		for _, f := range i.Filters {
+			k := strings.TrimPrefix(aws.StringValue(f.Name), "tag:")
+
+			var found bool
+			for _, t := range s.Tags {
+				if aws.StringValue(t.Key) == k && aws.StringValue(t.Value) == aws.StringValue(f.Values[0]) {
+					found = true
+				}
+			}
+			if !found {
+				matches = false
+			}
+		}
+
+		if matches {
+			o.Snapshots = append(o.Snapshots, s)
+		}
+	}
+
+	fn(o, true)
+
+	return nil
+}
+
+func (e *EC2ClientMock) DescribeVolumes(*ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	o := &ec2.DescribeVolumesOutput{
+		Volumes: e.volumes,
+	}
+
+	return o, nil
+}
+
+func (e *EC2ClientMock) DetachVolume(*ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error) {
+	return &ec2.VolumeAttachment{}, nil
+}
+
+func (e *EC2ClientMock) StopInstances(*ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error) {
+	return &ec2.StopInstancesOutput{}, nil
+}
+
+func (e *EC2ClientMock) WaitUntilInstanceStopped(*ec2.DescribeInstancesInput) error {
+	return nil
+}
@@ -1,6 +1,9 @@
 package ebsvolume
 
 import (
+	"sync"
+	"time"
+
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 )
@@ -8,16 +11,39 @@ import (
 const (
 	// Name is the identifier of the resource.
 	Name = "ebsvolumev25"
+
+	// snapshotSweepInterval is the minimum interval between two deletions of
+	// expired snapshots in the same AWS account.
+	snapshotSweepInterval = time.Hour
 )
 
 // Config represents the configuration used to create a new ebsvolume resource.
 type Config struct {
 	Logger micrologger.Logger
+
+	InstallationName string
+	// SnapshotOnDelete defines whether the etcd and persistent volumes of
+	// tenant clusters are snapshotted before they are deleted. Tenant clusters
+	// can override it via key.EBSSnapshotOnDeleteAnnotation.
+	SnapshotOnDelete bool
+	// SnapshotRetentionDays is the number of days snapshots are kept before
+	// they get deleted. Expired snapshots are only deleted while tenant
+	// clusters of the same AWS account are reconciled.
+	SnapshotRetentionDays int
 }
 
 // Resource implements the ebsvolume resource.
 type Resource struct {
 	logger micrologger.Logger
+
+	installationName      string
+	snapshotOnDelete      bool
+	snapshotRetentionDays int
+
+	mutex sync.Mutex
+	// lastSnapshotSweeps maps AWS account IDs to the time expired snapshots
+	// got deleted in them the last time.
+	lastSnapshotSweeps map[string]time.Time
 }
 
 // New creates a new configured ebsvolume resource.
@@ -26,9 +52,23 @@ func New(config Config) (*Resource, error) {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
+	if config.InstallationName == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.InstallationName must not be empty", config)
+	}
+	if config.SnapshotRetentionDays < 1 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.SnapshotRetentionDays must be at least 1", config)
+	}
+
 	newResource := &Resource{
 		// Dependencies.
 		logger: config.Logger,
+
+		// Settings.
+		installationName:      config.InstallationName,
+		snapshotOnDelete:      config.SnapshotOnDelete,
+		snapshotRetentionDays: config.SnapshotRetentionDays,
+
+		lastSnapshotSweeps: map[string]time.Time{},
 	}
 
 	return newResource, nil
@@ -0,0 +1,101 @@
+package ebsvolume
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/ebs"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+// ensureSnapshots makes sure every given EBS volume has a completed snapshot.
+// Snapshots are requested for volumes not having any yet. The returned bool
+// tells whether all snapshots are completed, so the volumes can be deleted.
+func (r *Resource) ensureSnapshots(ctx context.Context, cr v1alpha1.AWSConfig, ebsService ebs.Interface, volumes []ebs.Volume) (bool, error) {
+	tags := key.ClusterTags(cr, r.installationName)
+
+	snapshots, err := ebsService.ListSnapshots(tags)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	completed := true
+
+	for _, vol := range volumes {
+		s, ok := snapshotOfVolume(snapshots, vol.VolumeID)
+		if !ok {
+			t := map[string]string{}
+			for k, v := range tags {
+				t[k] = v
+			}
+			t[key.EBSSnapshotExpirationTagName] = key.EBSSnapshotExpiration(time.Now(), r.snapshotRetentionDays)
+
+			d := fmt.Sprintf("Snapshot of EBS volume %s of tenant cluster %s taken before deletion.", vol.VolumeID, key.ClusterID(cr))
+
+			_, err := ebsService.CreateSnapshot(ctx, vol.VolumeID, d, t)
+			if err != nil {
+				return false, microerror.Mask(err)
+			}
+
+			completed = false
+			continue
+		}
+
+		if s.State != ec2.SnapshotStateCompleted {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("snapshot %#q of EBS volume %#q is %s", s.SnapshotID, vol.VolumeID, s.State))
+			completed = false
+		}
+	}
+
+	return completed, nil
+}
+
+// deleteExpiredSnapshots deletes all completed snapshots of the installation
+// whose retention window is over. Snapshots outlive their tenant clusters,
+// which is why expired snapshots of all tenant clusters are deleted.
+func (r *Resource) deleteExpiredSnapshots(ctx context.Context, ebsService ebs.Interface) error {
+	tags := map[string]string{
+		key.InstallationTagName: r.installationName,
+	}
+
+	snapshots, err := ebsService.ListSnapshots(tags)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	now := time.Now()
+
+	for _, s := range snapshots {
+		expiration, ok := s.Tags[key.EBSSnapshotExpirationTagName]
+		if !ok || s.State != ec2.SnapshotStateCompleted {
+			continue
+		}
+		if !key.EBSSnapshotExpired(expiration, now) {
+			continue
+		}
+
+		err := ebsService.DeleteSnapshot(ctx, s.SnapshotID)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// snapshotOfVolume returns the snapshot of the given EBS volume. Failed
+// snapshots are ignored, so they are requested again.
+func snapshotOfVolume(snapshots []ebs.Snapshot, volumeID string) (ebs.Snapshot, bool) {
+	for _, s := range snapshots {
+		if s.VolumeID == volumeID && s.State != ec2.SnapshotStateError {
+			return s, true
+		}
+	}
+
+	return ebs.Snapshot{}, false
+}
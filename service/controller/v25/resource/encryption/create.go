@@ -51,5 +51,10 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		cc.Status.TenantCluster.Encryption.Key = encryptionKey
 	}
 
+	err = r.ensureRotation(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
 	return nil
 }
@@ -1,8 +1,10 @@
 package encryption
 
 import (
+	"github.com/giantswarm/apiextensions/pkg/clientset/versioned"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
+	"github.com/giantswarm/tenantcluster"
 
 	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter"
 )
@@ -11,27 +13,45 @@ const (
 	name = "encryptionv25"
 )
 
+const (
+	// conditionTypeRotationPrefix prefixes the phases of encryption key
+	// rotations reported in the CR status, e.g. EncryptionKeyRotationCompleted.
+	conditionTypeRotationPrefix = "EncryptionKeyRotation"
+)
+
 type Config struct {
-	Encrypter encrypter.Interface
-	Logger    micrologger.Logger
+	Encrypter     encrypter.Interface
+	G8sClient     versioned.Interface
+	Logger        micrologger.Logger
+	TenantCluster tenantcluster.Interface
 }
 
 type Resource struct {
-	encrypter encrypter.Interface
-	logger    micrologger.Logger
+	encrypter     encrypter.Interface
+	g8sClient     versioned.Interface
+	logger        micrologger.Logger
+	tenantCluster tenantcluster.Interface
 }
 
 func New(config Config) (*Resource, error) {
 	if config.Encrypter == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Encrypter must not be empty", config)
 	}
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
+	if config.TenantCluster == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.TenantCluster must not be empty", config)
+	}
 
 	r := &Resource{
-		encrypter: config.Encrypter,
-		logger:    config.Logger,
+		encrypter:     config.Encrypter,
+		g8sClient:     config.G8sClient,
+		logger:        config.Logger,
+		tenantCluster: config.TenantCluster,
 	}
 
 	return r, nil
@@ -0,0 +1,281 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/errors/guest"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/controller/context/reconciliationcanceledcontext"
+	"github.com/giantswarm/tenantcluster"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter"
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter/ssm"
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter/vault"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+const (
+	// reencryptionConfigMapName is the name of the config map in the
+	// kube-system namespace of the tenant cluster the reencrypt-secrets script
+	// of the masters records the KMS key in, once every master runs the KMS
+	// plugin with that key and all secrets got re-encrypted with it.
+	reencryptionConfigMapName = "encryption-provider"
+	reencryptionConfigMapKey  = "key"
+)
+
+// ensureRotation drives the encryption key rotation requested for the tenant
+// cluster. The previous key is recorded before the encryption key gets
+// rotated, so that an interrupted rotation is resumed instead of rotating the
+// encryption key twice. Rotating the encryption key changes the S3 object keys
+// of the cloud configs, so the s3object resource uploads them encrypted with
+// the new key and the tccp resource replaces all nodes. The previous key is
+// retired as soon as the tenant cluster's control plane cloud formation stack
+// finished replacing the nodes and, in case secrets are encrypted through the
+// KMS plugin, every master re-encrypted the secrets with the new key.
+func (r *Resource) ensureRotation(ctx context.Context, cr v1alpha1.AWSConfig) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	requested, err := key.EncryptionKeyRotation(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	state, err := key.EncryptionKeyRotationStatus(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if requested != "" && requested != state.ID {
+		if state.Phase == key.EncryptionKeyRotationPhaseRotating || state.Phase == key.EncryptionKeyRotationPhaseKeyRotated {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("not starting encryption key rotation %#q before encryption key rotation %#q completed", requested, state.ID))
+		} else {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("starting encryption key rotation %#q", requested))
+
+			previousKey, err := r.encrypter.CurrentEncryptionKey(ctx, cr)
+			if ssm.IsNotSupported(err) {
+				r.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("not starting encryption key rotation %#q, since the encrypter backend does not support encryption key rotation", requested))
+				return nil
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+
+			state = key.EncryptionKeyRotationState{
+				ID:          requested,
+				Phase:       key.EncryptionKeyRotationPhaseRotating,
+				PreviousKey: previousKey,
+			}
+
+			err = r.updateCR(ctx, cr, state)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("started encryption key rotation %#q", requested))
+		}
+	}
+
+	if state.Phase == key.EncryptionKeyRotationPhaseRotating {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("rotating encryption key for encryption key rotation %#q", state.ID))
+
+		err = r.encrypter.RotateEncryptionKey(ctx, cr, state.PreviousKey)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		state.Phase = key.EncryptionKeyRotationPhaseKeyRotated
+
+		err = r.updateCR(ctx, cr, state)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("rotated encryption key for encryption key rotation %#q", state.ID))
+
+		// The cloud configs have to be uploaded and the nodes have to be replaced
+		// based on the updated CR and the new encryption key, which is only the
+		// case in the next reconciliation.
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
+		reconciliationcanceledcontext.SetCanceled(ctx)
+
+		return nil
+	}
+
+	if state.Phase != key.EncryptionKeyRotationPhaseKeyRotated {
+		return nil
+	}
+
+	if cc.Status.TenantCluster.TCCP.IsTransitioning || cc.Status.TenantCluster.Encryption.Rotation != state.ID {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("nodes are not yet replaced for encryption key rotation %#q", state.ID))
+		return nil
+	}
+
+	{
+		reencrypted, err := r.secretsReencrypted(ctx, cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if !reencrypted {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("secrets are not yet re-encrypted for encryption key rotation %#q", state.ID))
+			return nil
+		}
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("completing encryption key rotation %#q", state.ID))
+
+		err = r.encrypter.RetireEncryptionKey(ctx, cr, state.PreviousKey)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		state.Phase = key.EncryptionKeyRotationPhaseCompleted
+
+		err = r.updateCR(ctx, cr, state)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("completed encryption key rotation %#q", state.ID))
+	}
+
+	return nil
+}
+
+// secretsReencrypted returns whether every master of the tenant cluster runs
+// the KMS plugin with the current encryption key and the secrets got
+// re-encrypted with it. Secrets are only encrypted through the KMS plugin when
+// the tenant cluster assets are encrypted with KMS.
+func (r *Resource) secretsReencrypted(ctx context.Context, cr v1alpha1.AWSConfig) (bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	if !isKMSEncrypter(r.encrypter) {
+		return true, nil
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "finding out if secrets are re-encrypted")
+
+	k8sClient, err := r.tenantCluster.NewK8sClient(ctx, key.ClusterID(cr), key.ClusterAPIEndpoint(cr))
+	if tenantcluster.IsTimeout(err) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "did not create Kubernetes client for tenant cluster")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "waiting for certificates timed out")
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	cm, err := k8sClient.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(reencryptionConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "secrets are not re-encrypted")
+		return false, nil
+	} else if guest.IsAPINotAvailable(err) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "tenant cluster API is not available")
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	if cm.Data[reencryptionConfigMapKey] != cc.Status.TenantCluster.Encryption.Key {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "secrets are not re-encrypted with the current encryption key")
+		return false, nil
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "secrets are re-encrypted with the current encryption key")
+
+	return true, nil
+}
+
+// updateCR persists the given encryption key rotation state in the CR
+// annotations and reports its phase in the CR status. The latest version of
+// the CR is fetched on every attempt, so that conflicting updates of other
+// resources do not get lost.
+func (r *Resource) updateCR(ctx context.Context, cr v1alpha1.AWSConfig, state key.EncryptionKeyRotationState) error {
+	r.logger.LogCtx(ctx, "level", "debug", "message", "updating CR annotations and status")
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := r.g8sClient.ProviderV1alpha1().AWSConfigs(cr.Namespace).Get(cr.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		annotations := latest.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key.EncryptionKeyRotationStateAnnotation] = string(b)
+		latest.SetAnnotations(annotations)
+
+		updated, err := r.g8sClient.ProviderV1alpha1().AWSConfigs(cr.Namespace).Update(latest)
+		if err != nil {
+			return err
+		}
+
+		updated.Status.Cluster.Resources = withRotationCondition(updated.Status.Cluster.Resources, state.Phase)
+
+		_, err = r.g8sClient.ProviderV1alpha1().AWSConfigs(cr.Namespace).UpdateStatus(updated)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "updated CR annotations and status")
+
+	return nil
+}
+
+// withRotationCondition returns the given resources with the given phase of
+// the latest encryption key rotation reported for the encryption resource.
+func withRotationCondition(resources []v1alpha1.StatusClusterResource, phase string) []v1alpha1.StatusClusterResource {
+	c := v1alpha1.StatusClusterResourceCondition{
+		LastTransitionTime: v1alpha1.DeepCopyTime{Time: time.Now()},
+		Status:             "True",
+		Type:               conditionTypeRotationPrefix + phase,
+	}
+
+	for i, r := range resources {
+		if r.Name == name {
+			resources[i].Conditions = []v1alpha1.StatusClusterResourceCondition{c}
+			return resources
+		}
+	}
+
+	return append(resources, v1alpha1.StatusClusterResource{
+		Name:       name,
+		Conditions: []v1alpha1.StatusClusterResourceCondition{c},
+	})
+}
+
+// isKMSEncrypter returns whether the tenant cluster assets are encrypted with
+// the KMS key of the tenant cluster, in which case the API servers encrypt
+// secrets through the KMS plugin as well.
+func isKMSEncrypter(e encrypter.Interface) bool {
+	switch e.(type) {
+	case *vault.Encrypter, *ssm.Encrypter:
+		return false
+	default:
+		return true
+	}
+}
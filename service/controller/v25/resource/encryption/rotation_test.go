@@ -0,0 +1,237 @@
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/apiextensions/pkg/clientset/versioned"
+	"github.com/giantswarm/apiextensions/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/helmclient"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/giantswarm/operatorkit/controller/context/reconciliationcanceledcontext"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter"
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter/ssm"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func Test_Resource_ensureRotation(t *testing.T) {
+	testCases := []struct {
+		description      string
+		annotations      map[string]string
+		ssm              bool
+		rotation         string
+		isTransitioning  bool
+		reencryptedKey   string
+		expectedState    string
+		expectedCanceled bool
+	}{
+		{
+			description:      "no rotation requested",
+			annotations:      nil,
+			expectedState:    "",
+			expectedCanceled: false,
+		},
+		{
+			description: "rotation requested",
+			annotations: map[string]string{
+				key.EncryptionKeyRotationAnnotation: "2019-05-01",
+			},
+			expectedState:    `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			expectedCanceled: true,
+		},
+		{
+			description: "nodes not yet replaced",
+			annotations: map[string]string{
+				key.EncryptionKeyRotationAnnotation:      "2019-05-01",
+				key.EncryptionKeyRotationStateAnnotation: `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			},
+			rotation:         "",
+			expectedState:    `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			expectedCanceled: false,
+		},
+		{
+			description: "nodes being replaced",
+			annotations: map[string]string{
+				key.EncryptionKeyRotationAnnotation:      "2019-05-01",
+				key.EncryptionKeyRotationStateAnnotation: `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			},
+			rotation:         "2019-05-01",
+			isTransitioning:  true,
+			expectedState:    `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			expectedCanceled: false,
+		},
+		{
+			description: "rotation interrupted before the key got rotated",
+			annotations: map[string]string{
+				key.EncryptionKeyRotationAnnotation:      "2019-05-01",
+				key.EncryptionKeyRotationStateAnnotation: `{"id":"2019-05-01","phase":"Rotating","previousKey":"previous-key"}`,
+			},
+			expectedState:    `{"id":"2019-05-01","phase":"KeyRotated","previousKey":"previous-key"}`,
+			expectedCanceled: true,
+		},
+		{
+			description: "nodes replaced but secrets not yet re-encrypted",
+			annotations: map[string]string{
+				key.EncryptionKeyRotationAnnotation:      "2019-05-01",
+				key.EncryptionKeyRotationStateAnnotation: `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			},
+			rotation:         "2019-05-01",
+			reencryptedKey:   "previous-key",
+			expectedState:    `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			expectedCanceled: false,
+		},
+		{
+			description: "nodes replaced and secrets re-encrypted",
+			annotations: map[string]string{
+				key.EncryptionKeyRotationAnnotation:      "2019-05-01",
+				key.EncryptionKeyRotationStateAnnotation: `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			},
+			rotation:         "2019-05-01",
+			reencryptedKey:   "current-key",
+			expectedState:    `{"id":"2019-05-01","phase":"Completed"}`,
+			expectedCanceled: false,
+		},
+		{
+			description: "new rotation requested during rotation",
+			annotations: map[string]string{
+				key.EncryptionKeyRotationAnnotation:      "2019-06-01",
+				key.EncryptionKeyRotationStateAnnotation: `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			},
+			rotation:         "",
+			expectedState:    `{"id":"2019-05-01","phase":"KeyRotated"}`,
+			expectedCanceled: false,
+		},
+		{
+			description: "new rotation requested after rotation",
+			annotations: map[string]string{
+				key.EncryptionKeyRotationAnnotation:      "2019-06-01",
+				key.EncryptionKeyRotationStateAnnotation: `{"id":"2019-05-01","phase":"Completed"}`,
+			},
+			rotation:         "2019-05-01",
+			expectedState:    `{"id":"2019-06-01","phase":"KeyRotated"}`,
+			expectedCanceled: true,
+		},
+		{
+			description: "rotation requested with the SSM encrypter",
+			annotations: map[string]string{
+				key.EncryptionKeyRotationAnnotation: "2019-05-01",
+			},
+			ssm:              true,
+			expectedState:    "",
+			expectedCanceled: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cr := v1alpha1.AWSConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+					Name:        "test-cluster",
+					Namespace:   "default",
+				},
+			}
+
+			g8sClient := fake.NewSimpleClientset(&cr)
+
+			k8sClient := k8sfake.NewSimpleClientset()
+			if tc.reencryptedKey != "" {
+				cm := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      reencryptionConfigMapName,
+						Namespace: metav1.NamespaceSystem,
+					},
+					Data: map[string]string{
+						reencryptionConfigMapKey: tc.reencryptedKey,
+					},
+				}
+				k8sClient = k8sfake.NewSimpleClientset(cm)
+			}
+
+			var e encrypter.Interface = &encrypter.EncrypterMock{}
+			if tc.ssm {
+				c := &ssm.EncrypterConfig{
+					Logger: microloggertest.New(),
+
+					InstallationName: "test-installation",
+				}
+
+				var err error
+				e, err = ssm.NewEncrypter(c)
+				if err != nil {
+					t.Fatalf("unexpected error %#v", err)
+				}
+			}
+
+			var r *Resource
+			{
+				c := Config{
+					Encrypter:     e,
+					G8sClient:     g8sClient,
+					Logger:        microloggertest.New(),
+					TenantCluster: &tenantClusterMock{k8sClient: k8sClient},
+				}
+
+				var err error
+				r, err = New(c)
+				if err != nil {
+					t.Fatalf("unexpected error %#v", err)
+				}
+			}
+
+			ctx := context.Background()
+			{
+				c := controllercontext.Context{}
+				c.Status.TenantCluster.Encryption.Key = "current-key"
+				c.Status.TenantCluster.Encryption.Rotation = tc.rotation
+				c.Status.TenantCluster.TCCP.IsTransitioning = tc.isTransitioning
+
+				ctx = controllercontext.NewContext(ctx, c)
+				ctx = reconciliationcanceledcontext.NewContext(ctx, make(chan struct{}))
+			}
+
+			err := r.ensureRotation(ctx, cr)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			updated, err := g8sClient.ProviderV1alpha1().AWSConfigs(cr.Namespace).Get(cr.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			state := updated.GetAnnotations()[key.EncryptionKeyRotationStateAnnotation]
+			if state != tc.expectedState {
+				t.Fatalf("expected state %#q, got %#q", tc.expectedState, state)
+			}
+
+			canceled := reconciliationcanceledcontext.IsCanceled(ctx)
+			if canceled != tc.expectedCanceled {
+				t.Fatalf("expected canceled %t, got %t", tc.expectedCanceled, canceled)
+			}
+		})
+	}
+}
+
+type tenantClusterMock struct {
+	k8sClient kubernetes.Interface
+}
+
+func (t *tenantClusterMock) NewG8sClient(ctx context.Context, clusterID, apiDomain string) (versioned.Interface, error) {
+	return nil, nil
+}
+
+func (t *tenantClusterMock) NewHelmClient(ctx context.Context, clusterID, apiDomain string) (helmclient.Interface, error) {
+	return nil, nil
+}
+
+func (t *tenantClusterMock) NewK8sClient(ctx context.Context, clusterID, apiDomain string) (kubernetes.Interface, error) {
+	return t.k8sClient, nil
+}
@@ -0,0 +1,161 @@
+package etcdbackup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/controller/context/reconciliationcanceledcontext"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	bucketName := key.BucketName(cr, cc.Status.TenantCluster.AWSAccountID)
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("ensuring etcd backup lifecycle rule of S3 bucket %#q", bucketName))
+
+		days, err := key.EtcdBackupRetentionDays(cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		var rules []*s3.LifecycleRule
+		{
+			i := &s3.GetBucketLifecycleConfigurationInput{
+				Bucket: aws.String(bucketName),
+			}
+
+			o, err := cc.Client.TenantCluster.AWS.S3.GetBucketLifecycleConfiguration(i)
+			if IsNoSuchBucket(err) {
+				r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("S3 bucket %#q does not exist yet", bucketName))
+				r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+				return nil
+			} else if IsNoSuchLifecycleConfiguration(err) {
+				// Fall through.
+			} else if err != nil {
+				return microerror.Mask(err)
+			} else {
+				rules = o.Rules
+			}
+		}
+
+		merged, changed := mergeLifecycleRules(rules, newLifecycleRule(days))
+
+		if changed {
+			i := &s3.PutBucketLifecycleConfigurationInput{
+				Bucket: aws.String(bucketName),
+				LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+					Rules: merged,
+				},
+			}
+
+			_, err := cc.Client.TenantCluster.AWS.S3.PutBucketLifecycleConfiguration(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("ensured etcd backup lifecycle rule of S3 bucket %#q expiring backups after %d days", bucketName, days))
+		} else {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("etcd backup lifecycle rule of S3 bucket %#q is up to date", bucketName))
+		}
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", "finding latest etcd backup")
+
+		latest, err := r.latestBackup(ctx, bucketName)
+		if IsNotFound(err) {
+			r.logger.LogCtx(ctx, "level", "debug", "message", "did not find any etcd backup")
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			age := time.Since(*latest.LastModified).Round(time.Second)
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found latest etcd backup %#q taken %s ago", *latest.Key, age))
+		}
+	}
+
+	{
+		snapshot, err := key.EtcdRestoreSnapshot(cr)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// The masters are only replaced for restoring etcd when the selected etcd
+		// backup changes. Then we make sure the etcd backup actually exists,
+		// because masters failing to restore it would not be able to start etcd.
+		if snapshot != "" && snapshot != cc.Status.TenantCluster.MasterInstance.EtcdRestoreSnapshot {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding etcd backup %#q to restore", snapshot))
+
+			i := &s3.HeadObjectInput{
+				Bucket: aws.String(bucketName),
+				Key:    aws.String(snapshot),
+			}
+
+			_, err := cc.Client.TenantCluster.AWS.S3.HeadObject(i)
+			if IsNotFound(err) {
+				r.logger.LogCtx(ctx, "level", "error", "message", fmt.Sprintf("did not find etcd backup %#q to restore in S3 bucket %#q", snapshot, bucketName))
+				r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
+				reconciliationcanceledcontext.SetCanceled(ctx)
+				return nil
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found etcd backup %#q to restore", snapshot))
+		}
+	}
+
+	return nil
+}
+
+// latestBackup returns the most recent etcd backup in the given S3 bucket.
+func (r *Resource) latestBackup(ctx context.Context, bucketName string) (*s3.Object, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var latest *s3.Object
+
+	i := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(key.EtcdBackupPrefix),
+	}
+
+	err = cc.Client.TenantCluster.AWS.S3.ListObjectsV2Pages(i, func(o *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range o.Contents {
+			if obj.LastModified == nil {
+				continue
+			}
+			if latest == nil || obj.LastModified.After(*latest.LastModified) {
+				latest = obj
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if latest == nil {
+		return nil, microerror.Maskf(notFoundError, "etcd backup")
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,11 @@
+package etcdbackup
+
+import (
+	"context"
+)
+
+// EnsureDeleted is a no-op. The etcd backups are deleted together with the S3
+// bucket of the tenant cluster.
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	return nil
+}
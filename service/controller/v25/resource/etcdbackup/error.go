@@ -0,0 +1,65 @@
+package etcdbackup
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var notFoundError = &microerror.Error{
+	Kind: "notFoundError",
+}
+
+// IsNotFound asserts notFoundError. HeadObject responds with the plain HTTP
+// status text in case the object does not exist.
+func IsNotFound(err error) bool {
+	c := microerror.Cause(err)
+
+	aerr, ok := c.(awserr.Error)
+	if ok {
+		if aerr.Code() == "NotFound" || aerr.Code() == s3.ErrCodeNoSuchKey {
+			return true
+		}
+	}
+
+	if c == notFoundError {
+		return true
+	}
+
+	return false
+}
+
+// IsNoSuchBucket asserts the AWS error returned in case the S3 bucket does not
+// exist yet.
+func IsNoSuchBucket(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok {
+		if aerr.Code() == s3.ErrCodeNoSuchBucket {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNoSuchLifecycleConfiguration asserts the AWS error returned in case the S3
+// bucket does not have any lifecycle rules.
+func IsNoSuchLifecycleConfiguration(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok {
+		if aerr.Code() == "NoSuchLifecycleConfiguration" {
+			return true
+		}
+	}
+
+	return false
+}
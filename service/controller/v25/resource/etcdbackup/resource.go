@@ -0,0 +1,40 @@
+package etcdbackup
+
+import (
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+)
+
+const (
+	// Name is the identifier of the resource.
+	Name = "etcdbackupv25"
+)
+
+type Config struct {
+	Logger micrologger.Logger
+}
+
+// Resource implements the etcd backup resource. The masters of a tenant
+// cluster take periodic etcd backups into the S3 bucket of the tenant cluster
+// themselves. The resource manages the retention of these backups and ensures
+// the etcd backup selected for restoring the masters exists, before the TCCP
+// resource replaces the masters.
+type Resource struct {
+	logger micrologger.Logger
+}
+
+func New(config Config) (*Resource, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	r := &Resource{
+		logger: config.Logger,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
@@ -0,0 +1,73 @@
+package etcdbackup
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+// mergeLifecycleRules replaces the etcd backup lifecycle rule in the given
+// lifecycle rules of an S3 bucket with the desired one. Other lifecycle rules
+// are kept as they are. The returned bool tells whether the lifecycle rules
+// have to be updated.
+func mergeLifecycleRules(current []*s3.LifecycleRule, desired *s3.LifecycleRule) ([]*s3.LifecycleRule, bool) {
+	var merged []*s3.LifecycleRule
+	var found bool
+	var changed bool
+
+	for _, r := range current {
+		if aws.StringValue(r.ID) != key.EtcdBackupLifecycleRuleID {
+			merged = append(merged, r)
+			continue
+		}
+
+		found = true
+		if !lifecycleRuleEqual(r, desired) {
+			changed = true
+		}
+		merged = append(merged, desired)
+	}
+
+	if !found {
+		merged = append(merged, desired)
+		changed = true
+	}
+
+	return merged, changed
+}
+
+func lifecycleRuleEqual(a, b *s3.LifecycleRule) bool {
+	if aws.StringValue(a.Status) != aws.StringValue(b.Status) {
+		return false
+	}
+	if a.Expiration == nil || b.Expiration == nil {
+		return a.Expiration == b.Expiration
+	}
+	if aws.Int64Value(a.Expiration.Days) != aws.Int64Value(b.Expiration.Days) {
+		return false
+	}
+	if a.Filter == nil || b.Filter == nil {
+		return a.Filter == b.Filter
+	}
+	if aws.StringValue(a.Filter.Prefix) != aws.StringValue(b.Filter.Prefix) {
+		return false
+	}
+
+	return true
+}
+
+func newLifecycleRule(days int) *s3.LifecycleRule {
+	r := &s3.LifecycleRule{
+		Expiration: &s3.LifecycleExpiration{
+			Days: aws.Int64(int64(days)),
+		},
+		Filter: &s3.LifecycleRuleFilter{
+			Prefix: aws.String(key.EtcdBackupPrefix),
+		},
+		ID:     aws.String(key.EtcdBackupLifecycleRuleID),
+		Status: aws.String(s3.ExpirationStatusEnabled),
+	}
+
+	return r
+}
@@ -0,0 +1,91 @@
+package etcdbackup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func Test_mergeLifecycleRules(t *testing.T) {
+	t.Parallel()
+	logsRule := &s3.LifecycleRule{
+		Expiration: &s3.LifecycleExpiration{
+			Days: aws.Int64(365),
+		},
+		Filter: &s3.LifecycleRuleFilter{},
+		ID:     aws.String("ExpirationLogs"),
+		Status: aws.String(s3.ExpirationStatusEnabled),
+	}
+
+	testCases := []struct {
+		name            string
+		current         []*s3.LifecycleRule
+		desired         *s3.LifecycleRule
+		expectedRules   []*s3.LifecycleRule
+		expectedChanged bool
+	}{
+		{
+			name:            "case 0: no lifecycle rules",
+			current:         nil,
+			desired:         newLifecycleRule(7),
+			expectedRules:   []*s3.LifecycleRule{newLifecycleRule(7)},
+			expectedChanged: true,
+		},
+		{
+			name:            "case 1: other lifecycle rules are kept",
+			current:         []*s3.LifecycleRule{logsRule},
+			desired:         newLifecycleRule(7),
+			expectedRules:   []*s3.LifecycleRule{logsRule, newLifecycleRule(7)},
+			expectedChanged: true,
+		},
+		{
+			name:            "case 2: etcd backup lifecycle rule is up to date",
+			current:         []*s3.LifecycleRule{logsRule, newLifecycleRule(7)},
+			desired:         newLifecycleRule(7),
+			expectedRules:   []*s3.LifecycleRule{logsRule, newLifecycleRule(7)},
+			expectedChanged: false,
+		},
+		{
+			name:            "case 3: etcd backup retention changes",
+			current:         []*s3.LifecycleRule{newLifecycleRule(7), logsRule},
+			desired:         newLifecycleRule(30),
+			expectedRules:   []*s3.LifecycleRule{newLifecycleRule(30), logsRule},
+			expectedChanged: true,
+		},
+		{
+			name: "case 4: disabled etcd backup lifecycle rule gets enabled",
+			current: []*s3.LifecycleRule{
+				{
+					Expiration: &s3.LifecycleExpiration{
+						Days: aws.Int64(7),
+					},
+					Filter: &s3.LifecycleRuleFilter{
+						Prefix: aws.String(key.EtcdBackupPrefix),
+					},
+					ID:     aws.String(key.EtcdBackupLifecycleRuleID),
+					Status: aws.String(s3.ExpirationStatusDisabled),
+				},
+			},
+			desired:         newLifecycleRule(7),
+			expectedRules:   []*s3.LifecycleRule{newLifecycleRule(7)},
+			expectedChanged: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, changed := mergeLifecycleRules(tc.current, tc.desired)
+
+			if changed != tc.expectedChanged {
+				t.Fatalf("expected changed %t, got %t", tc.expectedChanged, changed)
+			}
+			if !reflect.DeepEqual(rules, tc.expectedRules) {
+				t.Fatalf("expected rules %v, got %v", tc.expectedRules, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package etcdpeercert
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/core/v1alpha1"
+	"github.com/giantswarm/certs"
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	masterCount := key.EtcdMemberCount(cr, cc.Status.TenantCluster.MasterInstance.EtcdMemberCount)
+	if masterCount < 2 {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "tenant cluster has a single master")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "not ensuring etcd peer cert config")
+		return nil
+	}
+
+	if len(key.StatusAvailabilityZones(cr)) == 0 {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "availability zones of the tenant cluster are not allocated yet")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
+	ipSANs, err := key.EtcdPeerIPSANs(cr, masterCount)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	name := certs.K8sName(key.ClusterID(cr), key.EtcdPeerCert)
+
+	var etcdCertConfig *v1alpha1.CertConfig
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", "finding etcd cert config")
+
+		n := certs.K8sName(key.ClusterID(cr), certs.EtcdCert)
+
+		etcdCertConfig, err = r.g8sClient.CoreV1alpha1().CertConfigs(certConfigNamespace).Get(n, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("did not find etcd cert config %#q", n))
+			r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+			return nil
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found etcd cert config %#q", n))
+	}
+
+	desired := newEtcdPeerCertConfig(etcdCertConfig, name, ipSANs, r.projectName)
+
+	var current *v1alpha1.CertConfig
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding etcd peer cert config %#q", name))
+
+		current, err = r.g8sClient.CoreV1alpha1().CertConfigs(certConfigNamespace).Get(name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("did not find etcd peer cert config %#q", name))
+			current = nil
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found etcd peer cert config %#q", name))
+		}
+	}
+
+	if current == nil {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("creating etcd peer cert config %#q", name))
+
+		_, err = r.g8sClient.CoreV1alpha1().CertConfigs(certConfigNamespace).Create(desired)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("created etcd peer cert config %#q", name))
+
+		return nil
+	}
+
+	if reflect.DeepEqual(current.Spec, desired.Spec) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("etcd peer cert config %#q is up to date", name))
+		return nil
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("updating etcd peer cert config %#q", name))
+
+		u := current.DeepCopy()
+		u.Spec = desired.Spec
+
+		_, err = r.g8sClient.CoreV1alpha1().CertConfigs(certConfigNamespace).Update(u)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("updated etcd peer cert config %#q", name))
+	}
+
+	return nil
+}
+
+// newEtcdPeerCertConfig returns the cert config of the etcd peer certificate
+// based on the cert config of the etcd server certificate, so that both are
+// issued by the same CA with the same settings.
+func newEtcdPeerCertConfig(etcdCertConfig *v1alpha1.CertConfig, name string, ipSANs []string, projectName string) *v1alpha1.CertConfig {
+	labels := map[string]string{}
+	for k, v := range etcdCertConfig.GetLabels() {
+		labels[k] = v
+	}
+	labels[certificateLabel] = key.EtcdPeerCert.String()
+	labels[managedByLabel] = projectName
+
+	c := &v1alpha1.CertConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: certConfigNamespace,
+			Labels:    labels,
+		},
+		Spec: *etcdCertConfig.Spec.DeepCopy(),
+	}
+
+	c.Spec.Cert.ClusterComponent = key.EtcdPeerCert.String()
+	c.Spec.Cert.IPSANs = ipSANs
+
+	return c
+}
@@ -0,0 +1,38 @@
+package etcdpeercert
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/giantswarm/certs"
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+// EnsureDeleted deletes the cert config of the etcd peer certificate.
+// cert-operator then deletes the secret holding the certificate.
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	name := certs.K8sName(key.ClusterID(cr), key.EtcdPeerCert)
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleting etcd peer cert config %#q", name))
+
+	err = r.g8sClient.CoreV1alpha1().CertConfigs(certConfigNamespace).Delete(name, &metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("etcd peer cert config %#q already deleted", name))
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleted etcd peer cert config %#q", name))
+
+	return nil
+}
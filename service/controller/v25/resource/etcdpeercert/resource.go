@@ -0,0 +1,67 @@
+package etcdpeercert
+
+import (
+	"github.com/giantswarm/apiextensions/pkg/clientset/versioned"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+)
+
+const (
+	// Name is the identifier of the resource.
+	Name = "etcdpeercertv25"
+)
+
+const (
+	// certConfigNamespace is the namespace cluster-operator creates the cert
+	// configs of tenant clusters in.
+	certConfigNamespace = "default"
+
+	certificateLabel = "giantswarm.io/certificate"
+	managedByLabel   = "giantswarm.io/managed-by"
+)
+
+type Config struct {
+	G8sClient versioned.Interface
+	Logger    micrologger.Logger
+
+	ProjectName string
+}
+
+// Resource manages the cert config of the etcd peer certificate of tenant
+// clusters with multiple masters. The stacked etcd members reach each other
+// through the fixed private IPs of the masters, which the etcd server
+// certificate issued for the etcd domain does not cover. cert-operator issues
+// the etcd peer certificate with the same CA and settings as the etcd server
+// certificate, but with the fixed private IPs of the masters as IP SANs.
+type Resource struct {
+	g8sClient versioned.Interface
+	logger    micrologger.Logger
+
+	projectName string
+}
+
+func New(config Config) (*Resource, error) {
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	if config.ProjectName == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ProjectName must not be empty", config)
+	}
+
+	r := &Resource{
+		g8sClient: config.G8sClient,
+		logger:    config.Logger,
+
+		projectName: config.ProjectName,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
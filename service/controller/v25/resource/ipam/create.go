@@ -18,6 +18,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/giantswarm/aws-operator/pkg/ipamledger"
 	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
@@ -29,6 +30,8 @@ func init() {
 
 // EnsureCreated allocates guest cluster network segment. It gathers existing
 // subnets from existing AWSConfig/Status objects and existing VPCs from AWS.
+// Tenant clusters created in an existing VPC get the network of that VPC
+// recorded instead.
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 	var err error
 
@@ -55,10 +58,24 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 	if key.StatusNetworkCIDR(cr) == "" {
 		var statusAZs []v1alpha1.AWSConfigStatusAWSAvailabilityZone
 		var subnetCIDR net.IPNet
-		{
+		if key.IsExistingVPC(cr) {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding the network of the existing VPC %#q", key.ExistingVPCID(cr)))
+
+			subnetCIDR, statusAZs, err = r.existingNetwork(ctx, cr)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found the network %#q of the existing VPC %#q", subnetCIDR.String(), key.ExistingVPCID(cr)))
+
+			err = r.recordSubnet(key.ClusterID(cr), subnetCIDR)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		} else {
 			r.logger.LogCtx(ctx, "level", "debug", "message", "allocating cluster subnet CIDR")
 
-			subnetCIDR, err = r.allocateSubnet(ctx)
+			subnetCIDR, err = r.allocateSubnet(ctx, key.ClusterID(cr))
 			if err != nil {
 				return microerror.Mask(err)
 			}
@@ -98,12 +115,24 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 
 	} else {
 		r.logger.LogCtx(ctx, "level", "debug", "message", "found out subnet doesn't need to be allocated for cluster")
+
+		// Tenant clusters created before the ledger was introduced get their
+		// subnet recorded in the ledger.
+		_, n, err := net.ParseCIDR(key.StatusNetworkCIDR(cr))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		err = r.recordSubnet(key.ClusterID(cr), *n)
+		if err != nil {
+			return microerror.Mask(err)
+		}
 	}
 
 	return nil
 }
 
-func (r *Resource) allocateSubnet(ctx context.Context) (net.IPNet, error) {
+func (r *Resource) allocateSubnet(ctx context.Context, clusterID string) (net.IPNet, error) {
 	var err error
 	var mutex sync.Mutex
 	var reservedSubnets []net.IPNet
@@ -147,15 +176,13 @@ func (r *Resource) allocateSubnet(ctx context.Context) (net.IPNet, error) {
 		return net.IPNet{}, microerror.Mask(err)
 	}
 
-	reservedSubnets = ipam.CanonicalizeSubnets(r.networkRange, reservedSubnets)
-
 	var subnet net.IPNet
 	{
 		r.logger.LogCtx(ctx, "level", "debug", "message", "finding free subnet")
 
-		subnet, err = ipam.Free(r.networkRange, r.allocatedSubnetMask, reservedSubnets)
+		subnet, err = r.reserveSubnet(clusterID, reservedSubnets)
 		if err != nil {
-			return net.IPNet{}, microerror.Maskf(err, "networkRange: %s, allocatedSubnetMask: %s, reservedSubnets: %#v", r.networkRange.String(), r.allocatedSubnetMask.String(), reservedSubnets)
+			return net.IPNet{}, microerror.Mask(err)
 		}
 
 		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found free subnet %#q", subnet.String()))
@@ -164,6 +191,85 @@ func (r *Resource) allocateSubnet(ctx context.Context) (net.IPNet, error) {
 	return subnet, nil
 }
 
+// reserveSubnet finds a free subnet in the network ranges, ordered by their
+// priority, which neither overlaps with the given reserved subnets nor with the
+// subnets of the ledger and records it in the ledger for the given cluster. The subnet already recorded for the cluster is
+// returned in case a previous reconciliation reserved it without being able to
+// update the CR status.
+func (r *Resource) reserveSubnet(clusterID string, reservedSubnets []net.IPNet) (net.IPNet, error) {
+	var subnet net.IPNet
+
+	err := r.ledger.Update(func(entries map[string]ipamledger.Entry) (bool, error) {
+		e, ok := entries[clusterID]
+		if ok {
+			_, n, err := net.ParseCIDR(e.Subnet)
+			if err != nil {
+				return false, microerror.Maskf(invalidConfigError, "ledger entry %#q: %s", clusterID, err)
+			}
+			subnet = *n
+
+			return false, nil
+		}
+
+		ledgerSubnets, err := ipamledger.Subnets(entries)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		networkRanges, err := ipamledger.NetworkRanges(r.networkRanges, entries)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		var subnets []net.IPNet
+		subnets = append(subnets, reservedSubnets...)
+		subnets = append(subnets, ledgerSubnets...)
+
+		subnet, err = freeSubnet(networkRanges, r.allocatedSubnetMask, subnets)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		entries[clusterID] = newClusterLedgerEntry(subnet)
+
+		return true, nil
+	})
+	if err != nil {
+		return net.IPNet{}, microerror.Mask(err)
+	}
+
+	return subnet, nil
+}
+
+// recordSubnet records the given subnet of the given cluster in the ledger in
+// case it is not recorded yet.
+func (r *Resource) recordSubnet(clusterID string, subnet net.IPNet) error {
+	err := r.ledger.Update(func(entries map[string]ipamledger.Entry) (bool, error) {
+		_, ok := entries[clusterID]
+		if ok {
+			return false, nil
+		}
+
+		entries[clusterID] = newClusterLedgerEntry(subnet)
+
+		return true, nil
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func newClusterLedgerEntry(subnet net.IPNet) ipamledger.Entry {
+	return ipamledger.Entry{
+		Subnet:    subnet.String(),
+		Kind:      ipamledger.EntryKindCluster,
+		CreatedAt: time.Now().UTC(),
+		CreatedBy: Name,
+	}
+}
+
 func (r *Resource) selectRandomAZs(n int) ([]string, error) {
 	if n > len(r.availabilityZones) {
 		return nil, microerror.Maskf(invalidParameterError, "requested nubmer of AZs %d is bigger than number of available AZs %d", n, len(r.availabilityZones))
@@ -1,9 +1,59 @@
 package ipam
 
-import "context"
+import (
+	"context"
+	"fmt"
 
-// EnsureDeleted is a NOP for IPAM resource as allocated subnet will get
-// released when the guest cluster VPC and AWSConfig CR is deleted.
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/pkg/ipamledger"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+// EnsureDeleted releases the subnet the ledger records for the guest cluster.
+// Subnets of VPCs which still exist are not handed out again, because the
+// subnets of existing VPCs are reserved on allocation as well.
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "releasing the cluster subnet in the ledger")
+
+	released, err := r.releaseSubnet(key.ClusterID(cr))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if released == "" {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "did not find the cluster subnet in the ledger")
+	} else {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("released the cluster subnet %#q in the ledger", released))
+	}
+
 	return nil
 }
+
+// releaseSubnet removes the cluster allocation of the given holder from the
+// ledger and returns the released subnet. Reservations are never released.
+func (r *Resource) releaseSubnet(holder string) (string, error) {
+	var released string
+
+	err := r.ledger.Update(func(entries map[string]ipamledger.Entry) (bool, error) {
+		e, ok := entries[holder]
+		if !ok || e.Kind != ipamledger.EntryKindCluster {
+			return false, nil
+		}
+
+		delete(entries, holder)
+		released = e.Subnet
+
+		return true, nil
+	})
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return released, nil
+}
@@ -19,3 +19,12 @@ var invalidParameterError = &microerror.Error{
 func IsInvalidParameter(err error) bool {
 	return microerror.Cause(err) == invalidParameterError
 }
+
+var networkRangesExhaustedError = &microerror.Error{
+	Kind: "network ranges exhausted",
+}
+
+// IsNetworkRangesExhausted asserts networkRangesExhaustedError.
+func IsNetworkRangesExhausted(err error) bool {
+	return microerror.Cause(err) == networkRangesExhaustedError
+}
@@ -0,0 +1,130 @@
+package ipam
+
+import (
+	"context"
+	"net"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+// existingNetwork looks up the CIDR and the subnets of the existing VPC the
+// tenant cluster is created in. Nothing gets allocated from the network range
+// in this case.
+func (r *Resource) existingNetwork(ctx context.Context, cr v1alpha1.AWSConfig) (net.IPNet, []v1alpha1.AWSConfigStatusAWSAvailabilityZone, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return net.IPNet{}, nil, microerror.Mask(err)
+	}
+
+	privateIDs, publicIDs, err := key.ExistingSubnetIDs(cr)
+	if err != nil {
+		return net.IPNet{}, nil, microerror.Mask(err)
+	}
+
+	var vpcCIDR net.IPNet
+	{
+		i := &ec2.DescribeVpcsInput{
+			VpcIds: []*string{
+				aws.String(key.ExistingVPCID(cr)),
+			},
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeVpcs(i)
+		if err != nil {
+			return net.IPNet{}, nil, microerror.Mask(err)
+		}
+		if len(o.Vpcs) != 1 {
+			return net.IPNet{}, nil, microerror.Maskf(invalidConfigError, "expected one VPC with ID %#q, got %d", key.ExistingVPCID(cr), len(o.Vpcs))
+		}
+
+		_, n, err := net.ParseCIDR(aws.StringValue(o.Vpcs[0].CidrBlock))
+		if err != nil {
+			return net.IPNet{}, nil, microerror.Mask(err)
+		}
+		vpcCIDR = *n
+	}
+
+	var statusAZs []v1alpha1.AWSConfigStatusAWSAvailabilityZone
+	{
+		i := &ec2.DescribeSubnetsInput{
+			SubnetIds: aws.StringSlice(append(append([]string{}, privateIDs...), publicIDs...)),
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSubnets(i)
+		if err != nil {
+			return net.IPNet{}, nil, microerror.Mask(err)
+		}
+
+		statusAZs, err = existingSubnetsToStatusAZs(key.ExistingVPCID(cr), privateIDs, publicIDs, o.Subnets)
+		if err != nil {
+			return net.IPNet{}, nil, microerror.Mask(err)
+		}
+	}
+
+	return vpcCIDR, statusAZs, nil
+}
+
+// existingSubnetsToStatusAZs computes the availability zones of the CR status
+// from the given existing subnets. The private and public subnet with the same
+// index must be located in the same availability zone of the given VPC and
+// the subnets must be ordered by the names of their availability zones.
+func existingSubnetsToStatusAZs(vpcID string, privateIDs, publicIDs []string, subnets []*ec2.Subnet) ([]v1alpha1.AWSConfigStatusAWSAvailabilityZone, error) {
+	byID := map[string]*ec2.Subnet{}
+	for _, s := range subnets {
+		byID[aws.StringValue(s.SubnetId)] = s
+	}
+
+	lookup := func(id string) (*ec2.Subnet, error) {
+		s, ok := byID[id]
+		if !ok {
+			return nil, microerror.Maskf(invalidConfigError, "subnet %#q not found", id)
+		}
+		if aws.StringValue(s.VpcId) != vpcID {
+			return nil, microerror.Maskf(invalidConfigError, "subnet %#q does not belong to VPC %#q", id, vpcID)
+		}
+
+		return s, nil
+	}
+
+	var statusAZs []v1alpha1.AWSConfigStatusAWSAvailabilityZone
+	for i := range privateIDs {
+		private, err := lookup(privateIDs[i])
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		public, err := lookup(publicIDs[i])
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		az := aws.StringValue(private.AvailabilityZone)
+		if aws.StringValue(public.AvailabilityZone) != az {
+			return nil, microerror.Maskf(invalidConfigError, "subnets %#q and %#q must be located in the same availability zone", privateIDs[i], publicIDs[i])
+		}
+		if i > 0 && az <= statusAZs[i-1].Name {
+			return nil, microerror.Maskf(invalidConfigError, "subnets must be ordered by the names of their distinct availability zones")
+		}
+
+		statusAZ := v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+			Name: az,
+			Subnet: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnet{
+				Private: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPrivate{
+					CIDR: aws.StringValue(private.CidrBlock),
+				},
+				Public: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPublic{
+					CIDR: aws.StringValue(public.CidrBlock),
+				},
+			},
+		}
+
+		statusAZs = append(statusAZs, statusAZ)
+	}
+
+	return statusAZs, nil
+}
@@ -0,0 +1,106 @@
+package ipam
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_existingSubnetsToStatusAZs(t *testing.T) {
+	subnets := []*ec2.Subnet{
+		newSubnet("subnet-private-a", "vpc-1", "eu-west-1a", "10.1.0.0/24"),
+		newSubnet("subnet-public-a", "vpc-1", "eu-west-1a", "10.1.1.0/24"),
+		newSubnet("subnet-private-b", "vpc-1", "eu-west-1b", "10.1.2.0/24"),
+		newSubnet("subnet-public-b", "vpc-1", "eu-west-1b", "10.1.3.0/24"),
+		newSubnet("subnet-other", "vpc-2", "eu-west-1a", "10.2.0.0/24"),
+	}
+
+	testCases := []struct {
+		name              string
+		privateIDs        []string
+		publicIDs         []string
+		expectedStatusAZs []v1alpha1.AWSConfigStatusAWSAvailabilityZone
+		errorMatcher      func(error) bool
+	}{
+		{
+			name:       "case 0: subnets in two availability zones",
+			privateIDs: []string{"subnet-private-a", "subnet-private-b"},
+			publicIDs:  []string{"subnet-public-a", "subnet-public-b"},
+			expectedStatusAZs: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+				newStatusAZ("eu-west-1a", "10.1.0.0/24", "10.1.1.0/24"),
+				newStatusAZ("eu-west-1b", "10.1.2.0/24", "10.1.3.0/24"),
+			},
+		},
+		{
+			name:         "case 1: error when subnets are not ordered by availability zone",
+			privateIDs:   []string{"subnet-private-b", "subnet-private-a"},
+			publicIDs:    []string{"subnet-public-b", "subnet-public-a"},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 2: error when subnets of one index are in different availability zones",
+			privateIDs:   []string{"subnet-private-a"},
+			publicIDs:    []string{"subnet-public-b"},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 3: error when a subnet belongs to another VPC",
+			privateIDs:   []string{"subnet-private-a"},
+			publicIDs:    []string{"subnet-other"},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 4: error when a subnet does not exist",
+			privateIDs:   []string{"subnet-private-a"},
+			publicIDs:    []string{"subnet-missing"},
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statusAZs, err := existingSubnetsToStatusAZs("vpc-1", tc.privateIDs, tc.publicIDs, subnets)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if !reflect.DeepEqual(statusAZs, tc.expectedStatusAZs) {
+				t.Fatalf("expected %#v, got %#v", tc.expectedStatusAZs, statusAZs)
+			}
+		})
+	}
+}
+
+func newSubnet(id, vpcID, az, cidr string) *ec2.Subnet {
+	return &ec2.Subnet{
+		AvailabilityZone: aws.String(az),
+		CidrBlock:        aws.String(cidr),
+		SubnetId:         aws.String(id),
+		VpcId:            aws.String(vpcID),
+	}
+}
+
+func newStatusAZ(name, privateCIDR, publicCIDR string) v1alpha1.AWSConfigStatusAWSAvailabilityZone {
+	return v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+		Name: name,
+		Subnet: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnet{
+			Private: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPrivate{
+				CIDR: privateCIDR,
+			},
+			Public: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPublic{
+				CIDR: publicCIDR,
+			},
+		},
+	}
+}
@@ -0,0 +1,163 @@
+package ipam
+
+import (
+	"net"
+	"testing"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/aws-operator/pkg/ipamledger"
+)
+
+func Test_Resource_reserveSubnet(t *testing.T) {
+	testCases := []struct {
+		name            string
+		ledgerData      map[string]string
+		reservedSubnets []net.IPNet
+		clusterIDs      []string
+		expectedSubnets []string
+		errorMatcher    func(error) bool
+	}{
+		{
+			name:            "case 0: allocate subnets for two clusters in a row",
+			clusterIDs:      []string{"al9qy", "5xchu"},
+			expectedSubnets: []string{"10.100.0.0/24", "10.100.1.0/24"},
+		},
+		{
+			name: "case 1: reuse the subnet already recorded for the cluster",
+			ledgerData: map[string]string{
+				"al9qy": `{"subnet":"10.100.3.0/24","kind":"cluster"}`,
+			},
+			clusterIDs:      []string{"al9qy"},
+			expectedSubnets: []string{"10.100.3.0/24"},
+		},
+		{
+			name: "case 2: skip reservations and reserved subnets",
+			ledgerData: map[string]string{
+				"peered-network": `{"subnet":"10.100.0.0/24","kind":"reservation"}`,
+			},
+			reservedSubnets: []net.IPNet{
+				mustParseCIDR("10.100.1.0/24"),
+			},
+			clusterIDs:      []string{"al9qy"},
+			expectedSubnets: []string{"10.100.2.0/24"},
+		},
+		{
+			name: "case 3: error on malformed ledger entries",
+			ledgerData: map[string]string{
+				"al9qy": `{"subnet":`,
+			},
+			clusterIDs:   []string{"5xchu"},
+			errorMatcher: ipamledger.IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newLedgerTestResource(tc.ledgerData)
+
+			var subnets []string
+			var err error
+			for _, clusterID := range tc.clusterIDs {
+				var subnet net.IPNet
+				subnet, err = r.reserveSubnet(clusterID, tc.reservedSubnets)
+				if err != nil {
+					break
+				}
+
+				subnets = append(subnets, subnet.String())
+			}
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			if len(subnets) != len(tc.expectedSubnets) {
+				t.Fatalf("expected subnets %#v, got %#v", tc.expectedSubnets, subnets)
+			}
+			for i := range subnets {
+				if subnets[i] != tc.expectedSubnets[i] {
+					t.Fatalf("expected subnets %#v, got %#v", tc.expectedSubnets, subnets)
+				}
+			}
+
+			entries, err := r.ledger.Entries()
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			for i, clusterID := range tc.clusterIDs {
+				if entries[clusterID].Subnet != tc.expectedSubnets[i] {
+					t.Fatalf("expected ledger entry of %#q to hold %#q, got %#v", clusterID, tc.expectedSubnets[i], entries[clusterID])
+				}
+			}
+		})
+	}
+}
+
+func Test_Resource_releaseSubnet(t *testing.T) {
+	r := newLedgerTestResource(map[string]string{
+		"al9qy":          `{"subnet":"10.100.0.0/24","kind":"cluster"}`,
+		"peered-network": `{"subnet":"10.100.1.0/24","kind":"reservation"}`,
+	})
+
+	for _, holder := range []string{"al9qy", "peered-network"} {
+		_, err := r.releaseSubnet(holder)
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+	}
+
+	entries, err := r.ledger.Entries()
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if _, ok := entries["al9qy"]; ok {
+		t.Fatalf("expected cluster allocation to be released")
+	}
+	if _, ok := entries["peered-network"]; !ok {
+		t.Fatalf("expected reservation to be kept")
+	}
+}
+
+func newLedgerTestResource(ledgerData map[string]string) *Resource {
+	k8sClient := fake.NewSimpleClientset()
+	if ledgerData != nil {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ipamledger.Name,
+				Namespace: ipamledger.Namespace,
+			},
+			Data: ledgerData,
+		}
+		k8sClient = fake.NewSimpleClientset(cm)
+	}
+
+	ledger, err := ipamledger.New(ipamledger.Config{K8sClient: k8sClient})
+	if err != nil {
+		panic(err)
+	}
+
+	r := &Resource{
+		ledger: ledger,
+		logger: microloggertest.New(),
+
+		allocatedSubnetMask: net.CIDRMask(24, 32),
+		networkRanges:       []net.IPNet{mustParseCIDR("10.100.0.0/16")},
+	}
+
+	return r
+}
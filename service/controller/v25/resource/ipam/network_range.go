@@ -0,0 +1,32 @@
+package ipam
+
+import (
+	"net"
+
+	"github.com/giantswarm/ipam"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/pkg/ipamledger"
+)
+
+// freeSubnet returns a free subnet of the given mask from the first network
+// range which is not exhausted yet.
+func freeSubnet(ranges []ipamledger.NetworkRange, mask net.IPMask, reservedSubnets []net.IPNet) (net.IPNet, error) {
+	for _, r := range ranges {
+		// CanonicalizeSubnets modifies the given subnets so every range gets its
+		// own copy.
+		subnets := append([]net.IPNet{}, reservedSubnets...)
+		subnets = ipam.CanonicalizeSubnets(r.CIDR, subnets)
+
+		subnet, err := ipam.Free(r.CIDR, mask, subnets)
+		if ipam.IsSpaceExhausted(err) || ipam.IsMaskTooBig(err) {
+			continue
+		} else if err != nil {
+			return net.IPNet{}, microerror.Maskf(err, "networkRange: %s, allocatedSubnetMask: %s, reservedSubnets: %#v", r.CIDR.String(), mask.String(), subnets)
+		}
+
+		return subnet, nil
+	}
+
+	return net.IPNet{}, microerror.Maskf(networkRangesExhaustedError, "no free subnet with mask %s in any of %d network ranges", mask.String(), len(ranges))
+}
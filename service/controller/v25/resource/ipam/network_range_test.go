@@ -0,0 +1,65 @@
+package ipam
+
+import (
+	"net"
+	"testing"
+
+	"github.com/giantswarm/aws-operator/pkg/ipamledger"
+)
+
+func Test_freeSubnet(t *testing.T) {
+	ranges := []ipamledger.NetworkRange{
+		{CIDR: mustParseCIDR("10.1.0.0/23")},
+		{CIDR: mustParseCIDR("10.2.0.0/23"), Priority: 1},
+	}
+
+	testCases := []struct {
+		name           string
+		reserved       []net.IPNet
+		expectedSubnet string
+		errorMatcher   func(error) bool
+	}{
+		{
+			name:           "case 0: allocate from the first range",
+			expectedSubnet: "10.1.0.0/24",
+		},
+		{
+			name: "case 1: allocate from the second range once the first one is exhausted",
+			reserved: []net.IPNet{
+				mustParseCIDR("10.1.0.0/24"),
+				mustParseCIDR("10.1.1.0/24"),
+				mustParseCIDR("10.2.0.0/24"),
+			},
+			expectedSubnet: "10.2.1.0/24",
+		},
+		{
+			name: "case 2: error when all ranges are exhausted",
+			reserved: []net.IPNet{
+				mustParseCIDR("10.1.0.0/23"),
+				mustParseCIDR("10.2.0.0/23"),
+			},
+			errorMatcher: IsNetworkRangesExhausted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			subnet, err := freeSubnet(ranges, net.CIDRMask(24, 32), tc.reserved)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher == nil && subnet.String() != tc.expectedSubnet {
+				t.Fatalf("expected %#q, got %#q", tc.expectedSubnet, subnet.String())
+			}
+		})
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/giantswarm/apiextensions/pkg/clientset/versioned"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/giantswarm/aws-operator/pkg/ipamledger"
 )
 
 const (
@@ -15,26 +18,34 @@ const (
 
 type Config struct {
 	G8sClient versioned.Interface
+	K8sClient kubernetes.Interface
 	Logger    micrologger.Logger
 
 	AllocatedSubnetMaskBits int
 	AvailabilityZones       []string
-	NetworkRange            net.IPNet
+	// NetworkRanges are the network segments subnets are allocated from,
+	// ordered by their priority. Further ranges can be added at runtime via
+	// the ledger.
+	NetworkRanges []net.IPNet
 }
 
 type Resource struct {
 	g8sClient versioned.Interface
+	ledger    *ipamledger.Ledger
 	logger    micrologger.Logger
 
 	allocatedSubnetMask net.IPMask
 	availabilityZones   []string
-	networkRange        net.IPNet
+	networkRanges       []net.IPNet
 }
 
 func New(config Config) (*Resource, error) {
 	if config.G8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
 	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
@@ -42,17 +53,37 @@ func New(config Config) (*Resource, error) {
 	if len(config.AvailabilityZones) == 0 {
 		return nil, microerror.Maskf(invalidConfigError, "%T.AvailabilityZones must not be empty", config)
 	}
-	if reflect.DeepEqual(config.NetworkRange, net.IPNet{}) {
-		return nil, microerror.Maskf(invalidConfigError, "%T.NetworkRange must not be empty", config)
+	if len(config.NetworkRanges) == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.NetworkRanges must not be empty", config)
+	}
+	for _, n := range config.NetworkRanges {
+		if reflect.DeepEqual(n, net.IPNet{}) {
+			return nil, microerror.Maskf(invalidConfigError, "%T.NetworkRanges must not contain empty network ranges", config)
+		}
+	}
+
+	var err error
+
+	var ledger *ipamledger.Ledger
+	{
+		c := ipamledger.Config{
+			K8sClient: config.K8sClient,
+		}
+
+		ledger, err = ipamledger.New(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
 	}
 
 	newResource := &Resource{
 		g8sClient: config.G8sClient,
+		ledger:    ledger,
 		logger:    config.Logger,
 
 		allocatedSubnetMask: net.CIDRMask(config.AllocatedSubnetMaskBits, 32),
 		availabilityZones:   config.AvailabilityZones,
-		networkRange:        config.NetworkRange,
+		networkRanges:       config.NetworkRanges,
 	}
 
 	return newResource, nil
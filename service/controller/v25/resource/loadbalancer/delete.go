@@ -6,14 +6,20 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/controller/context/finalizerskeptcontext"
+	"github.com/giantswarm/operatorkit/controller/context/reconciliationcanceledcontext"
 
 	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
 	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 // EnsureDeleted ensures that any ELBs from Kubernetes LoadBalancer services
-// are deleted. This is needed because the use the VPC public subnet.
+// are deleted. This is needed because the use the VPC public subnet. The same
+// applies to NLBs and ALBs including their target groups and to the security
+// groups of the ELBs.
 func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 	customObject, err := key.ToCustomObject(obj)
 	if err != nil {
@@ -47,5 +53,84 @@ func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
 		r.logger.LogCtx(ctx, "level", "debug", "message", "not deleting load balancers because there aren't any")
 	}
 
+	err = r.deleteELBV2Resources(ctx, customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if reconciliationcanceledcontext.IsCanceled(ctx) {
+		return nil
+	}
+
+	err = r.deleteLoadBalancerSecurityGroups(ctx, customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *Resource) deleteELBV2Resources(ctx context.Context, customObject v1alpha1.AWSConfig) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	state, err := r.clusterELBV2Resources(ctx, customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(state.LoadBalancerARNs) > 0 {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleting %d network and application load balancers", len(state.LoadBalancerARNs)))
+
+		for _, arn := range state.LoadBalancerARNs {
+			_, err := cc.Client.TenantCluster.AWS.ELBV2.DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{
+				LoadBalancerArn: aws.String(arn),
+			})
+			if IsLoadBalancerNotFound(err) {
+				// Fall through.
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleted %d network and application load balancers", len(state.LoadBalancerARNs)))
+	} else {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "not deleting network and application load balancers because there aren't any")
+	}
+
+	if len(state.TargetGroupARNs) > 0 {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleting %d target groups", len(state.TargetGroupARNs)))
+
+		for _, arn := range state.TargetGroupARNs {
+			_, err := cc.Client.TenantCluster.AWS.ELBV2.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{
+				TargetGroupArn: aws.String(arn),
+			})
+			if IsTargetGroupNotFound(err) {
+				// Fall through.
+			} else if IsResourceInUse(err) {
+				// The listeners of the load balancers deleted above may not be
+				// gone yet. The target groups are deleted in the next
+				// reconciliation loop then.
+				r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("target group %#q is still in use", arn))
+
+				r.logger.LogCtx(ctx, "level", "debug", "message", "keeping finalizers")
+				finalizerskeptcontext.SetKept(ctx)
+
+				r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
+				reconciliationcanceledcontext.SetCanceled(ctx)
+
+				return nil
+			} else if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleted %d target groups", len(state.TargetGroupARNs)))
+	} else {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "not deleting target groups because there aren't any")
+	}
+
 	return nil
 }
@@ -2,6 +2,7 @@ package loadbalancer
 
 import (
 	"context"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
@@ -12,54 +13,96 @@ import (
 )
 
 const (
-	cloudProviderClusterTagValue = "owned"
-	cloudProviderServiceTagKey   = "kubernetes.io/service-name"
-	loadBalancerTagChunkSize     = 20
+	cloudProviderClusterTagPrefix = "kubernetes.io/cluster/"
+	cloudProviderClusterTagValue  = "owned"
+	cloudProviderServiceTagKey    = "kubernetes.io/service-name"
+	loadBalancerTagChunkSize      = 20
 )
 
 func (r *Resource) clusterLoadBalancers(ctx context.Context, customObject v1alpha1.AWSConfig) (*LoadBalancerState, error) {
 	lbState := &LoadBalancerState{}
 	clusterLBNames := []string{}
 
+	lbs, err := r.describeClassicLoadBalancers(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	allLBNames := []*string{}
+	for _, lb := range lbs {
+		allLBNames = append(allLBNames, lb.LoadBalancerName)
+	}
+
+	tags, err := r.describeClassicLoadBalancerTags(ctx, allLBNames)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	// We filter based on the AWS cloud provider tags to find load balancers
+	// associated with the cluster being processed.
+	for _, lbName := range allLBNames {
+		if isClusterServiceResource(tags[*lbName], customObject) {
+			clusterLBNames = append(clusterLBNames, *lbName)
+		}
+	}
+
+	lbState.LoadBalancerNames = clusterLBNames
+
+	return lbState, nil
+}
+
+// describeClassicLoadBalancers returns all classic ELBs of the tenant
+// cluster's account and region, following all result pages.
+func (r *Resource) describeClassicLoadBalancers(ctx context.Context) ([]*elb.LoadBalancerDescription, error) {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
+	var lbs []*elb.LoadBalancerDescription
+	fn := func(o *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+		lbs = append(lbs, o.LoadBalancerDescriptions...)
+		return true
+	}
+
 	// We get all load balancers because the API does not allow tag filters.
-	output, err := cc.Client.TenantCluster.AWS.ELB.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{})
+	err = cc.Client.TenantCluster.AWS.ELB.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{}, fn)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	allLBNames := []*string{}
-	for _, lb := range output.LoadBalancerDescriptions {
-		allLBNames = append(allLBNames, lb.LoadBalancerName)
+	return lbs, nil
+}
+
+// describeClassicLoadBalancerTags returns the tags of the given classic ELBs
+// by their names.
+func (r *Resource) describeClassicLoadBalancerTags(ctx context.Context, lbNames []*string) (map[string]map[string]string, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
 	}
 
-	lbChunks := splitLoadBalancers(allLBNames, loadBalancerTagChunkSize)
+	tags := map[string]map[string]string{}
 
-	for _, lbNames := range lbChunks {
+	for _, chunk := range splitLoadBalancers(lbNames, loadBalancerTagChunkSize) {
 		tagsInput := &elb.DescribeTagsInput{
-			LoadBalancerNames: lbNames,
+			LoadBalancerNames: chunk,
 		}
 		tagsOutput, err := cc.Client.TenantCluster.AWS.ELB.DescribeTags(tagsInput)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
 
-		// We filter based on the AWS cloud provider tags to find load balancers
-		// associated with the cluster being processed.
-		for _, lb := range tagsOutput.TagDescriptions {
-			if containsClusterTag(lb.Tags, customObject) && containsServiceTag(lb.Tags) {
-				clusterLBNames = append(clusterLBNames, *lb.LoadBalancerName)
+		for _, d := range tagsOutput.TagDescriptions {
+			m := map[string]string{}
+			for _, t := range d.Tags {
+				m[*t.Key] = *t.Value
 			}
+			tags[*d.LoadBalancerName] = m
 		}
 	}
 
-	lbState.LoadBalancerNames = clusterLBNames
-
-	return lbState, nil
+	return tags, nil
 }
 
 func splitLoadBalancers(loadBalancerNames []*string, chunkSize int) [][]*string {
@@ -78,23 +121,33 @@ func splitLoadBalancers(loadBalancerNames []*string, chunkSize int) [][]*string
 	return chunks
 }
 
-func containsClusterTag(tags []*elb.Tag, customObject v1alpha1.AWSConfig) bool {
-	tagKey := key.ClusterCloudProviderTag(customObject)
+// cloudProviderClusterID returns the ID of the tenant cluster owning a load
+// balancer of a Kubernetes Service or Ingress according to its tags. False is
+// returned in case the load balancer does not belong to a Service or Ingress.
+func cloudProviderClusterID(tags map[string]string) (string, bool) {
+	_, service := tags[cloudProviderServiceTagKey]
+	_, ingress := tags[cloudProviderIngressTagKey]
+	if !service && !ingress {
+		return "", false
+	}
 
-	for _, tag := range tags {
-		if *tag.Key == tagKey && *tag.Value == cloudProviderClusterTagValue {
-			return true
+	for k, v := range tags {
+		if strings.HasPrefix(k, cloudProviderClusterTagPrefix) && v == cloudProviderClusterTagValue {
+			return strings.TrimPrefix(k, cloudProviderClusterTagPrefix), true
 		}
 	}
-	return false
+
+	return "", false
 }
 
-func containsServiceTag(tags []*elb.Tag) bool {
-	for _, tag := range tags {
-		if *tag.Key == cloudProviderServiceTagKey {
-			return true
-		}
+// isClusterServiceResource returns whether a load balancer or target group
+// with the given tags belongs to a Kubernetes Service or Ingress of the tenant
+// cluster.
+func isClusterServiceResource(tags map[string]string, customObject v1alpha1.AWSConfig) bool {
+	if tags[key.ClusterCloudProviderTag(customObject)] != cloudProviderClusterTagValue {
+		return false
 	}
 
-	return false
+	_, ok := cloudProviderClusterID(tags)
+	return ok
 }
@@ -0,0 +1,145 @@
+package loadbalancer
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+)
+
+const (
+	// cloudProviderIngressTagKey is set by the ALB ingress controller on the
+	// application load balancers and target groups it creates for Ingresses.
+	cloudProviderIngressTagKey = "kubernetes.io/ingress-name"
+)
+
+// clusterELBV2Resources returns the network and application load balancers
+// and target groups the cloud provider or the ALB ingress controller of the
+// tenant cluster created for its Services and Ingresses. The NLBs of the
+// tenant cluster's control plane are managed by its TCCP stack and are not
+// returned, because they are not tagged with a Service or Ingress name.
+func (r *Resource) clusterELBV2Resources(ctx context.Context, customObject v1alpha1.AWSConfig) (*ELBV2State, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	lbs, err := r.describeELBV2LoadBalancers(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var allLBARNs []*string
+	for _, lb := range lbs {
+		allLBARNs = append(allLBARNs, lb.LoadBalancerArn)
+	}
+
+	// We get all target groups because the API does not allow tag filters.
+	var allTGARNs []*string
+	{
+		fn := func(o *elbv2.DescribeTargetGroupsOutput, lastPage bool) bool {
+			for _, tg := range o.TargetGroups {
+				allTGARNs = append(allTGARNs, tg.TargetGroupArn)
+			}
+			return true
+		}
+
+		err := cc.Client.TenantCluster.AWS.ELBV2.DescribeTargetGroupsPages(&elbv2.DescribeTargetGroupsInput{}, fn)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	lbARNs, err := r.filterELBV2Resources(ctx, customObject, allLBARNs)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	tgARNs, err := r.filterELBV2Resources(ctx, customObject, allTGARNs)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	state := &ELBV2State{
+		LoadBalancerARNs: lbARNs,
+		TargetGroupARNs:  tgARNs,
+	}
+
+	return state, nil
+}
+
+// describeELBV2LoadBalancers returns all network and application load
+// balancers of the tenant cluster's account and region, following all result
+// pages.
+func (r *Resource) describeELBV2LoadBalancers(ctx context.Context) ([]*elbv2.LoadBalancer, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var lbs []*elbv2.LoadBalancer
+	fn := func(o *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+		lbs = append(lbs, o.LoadBalancers...)
+		return true
+	}
+
+	// We get all load balancers because the API does not allow tag filters.
+	err = cc.Client.TenantCluster.AWS.ELBV2.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{}, fn)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return lbs, nil
+}
+
+// filterELBV2Resources returns the ARNs of the given load balancers or target
+// groups which are tagged as belonging to a Service or Ingress of the tenant
+// cluster.
+func (r *Resource) filterELBV2Resources(ctx context.Context, customObject v1alpha1.AWSConfig, arns []*string) ([]string, error) {
+	tags, err := r.describeELBV2Tags(ctx, arns)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterARNs := []string{}
+	for _, arn := range arns {
+		if isClusterServiceResource(tags[*arn], customObject) {
+			clusterARNs = append(clusterARNs, *arn)
+		}
+	}
+
+	return clusterARNs, nil
+}
+
+// describeELBV2Tags returns the tags of the given load balancers or target
+// groups by their ARNs.
+func (r *Resource) describeELBV2Tags(ctx context.Context, arns []*string) (map[string]map[string]string, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	tags := map[string]map[string]string{}
+
+	for _, chunk := range splitLoadBalancers(arns, loadBalancerTagChunkSize) {
+		i := &elbv2.DescribeTagsInput{
+			ResourceArns: chunk,
+		}
+		o, err := cc.Client.TenantCluster.AWS.ELBV2.DescribeTags(i)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, d := range o.TagDescriptions {
+			m := map[string]string{}
+			for _, t := range d.Tags {
+				m[*t.Key] = *t.Value
+			}
+			tags[*d.ResourceArn] = m
+		}
+	}
+
+	return tags, nil
+}
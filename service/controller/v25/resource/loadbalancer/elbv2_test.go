@@ -0,0 +1,128 @@
+package loadbalancer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+
+	awsclient "github.com/giantswarm/aws-operator/client/aws"
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+)
+
+func Test_clusterELBV2Resources(t *testing.T) {
+	t.Parallel()
+	customObject := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: v1alpha1.Cluster{
+				ID: "test-cluster",
+			},
+		},
+	}
+
+	testCases := []struct {
+		description   string
+		loadBalancers []ELBV2ResourceMock
+		targetGroups  []ELBV2ResourceMock
+		expectedState *ELBV2State
+	}{
+		{
+			description: "no load balancers",
+			expectedState: &ELBV2State{
+				LoadBalancerARNs: []string{},
+				TargetGroupARNs:  []string{},
+			},
+		},
+		{
+			description: "service and ingress load balancers of the cluster",
+			loadBalancers: []ELBV2ResourceMock{
+				{
+					arn: "arn:nlb-service",
+					tags: map[string]string{
+						"kubernetes.io/cluster/test-cluster": "owned",
+						"kubernetes.io/service-name":         "default/hello-world",
+					},
+				},
+				{
+					arn: "arn:alb-ingress",
+					tags: map[string]string{
+						"kubernetes.io/cluster/test-cluster": "owned",
+						"kubernetes.io/ingress-name":         "hello-world",
+					},
+				},
+				{
+					arn: "arn:nlb-other-cluster",
+					tags: map[string]string{
+						"kubernetes.io/cluster/other-cluster": "owned",
+						"kubernetes.io/service-name":          "default/hello-world",
+					},
+				},
+				{
+					arn: "arn:nlb-control-plane",
+					tags: map[string]string{
+						"kubernetes.io/cluster/test-cluster": "owned",
+					},
+				},
+			},
+			targetGroups: []ELBV2ResourceMock{
+				{
+					arn: "arn:tg-service",
+					tags: map[string]string{
+						"kubernetes.io/cluster/test-cluster": "owned",
+						"kubernetes.io/service-name":         "default/hello-world",
+					},
+				},
+				{
+					arn: "arn:tg-other-cluster",
+					tags: map[string]string{
+						"kubernetes.io/cluster/other-cluster": "owned",
+						"kubernetes.io/service-name":          "default/hello-world",
+					},
+				},
+			},
+			expectedState: &ELBV2State{
+				LoadBalancerARNs: []string{
+					"arn:nlb-service",
+					"arn:alb-ingress",
+				},
+				TargetGroupARNs: []string{
+					"arn:tg-service",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			newResource, err := New(Config{Logger: microloggertest.New()})
+			if err != nil {
+				t.Fatalf("expected nil, got %#v", err)
+			}
+
+			cc := controllercontext.Context{
+				Client: controllercontext.ContextClient{
+					TenantCluster: controllercontext.ContextClientTenantCluster{
+						AWS: awsclient.Clients{
+							ELBV2: &ELBV2ClientMock{
+								loadBalancers: tc.loadBalancers,
+								targetGroups:  tc.targetGroups,
+							},
+						},
+					},
+				},
+			}
+			ctx := controllercontext.NewContext(context.Background(), cc)
+
+			state, err := newResource.clusterELBV2Resources(ctx, customObject)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(state, tc.expectedState) {
+				t.Fatalf("expected state %#v, got %#v", tc.expectedState, state)
+			}
+		})
+	}
+}
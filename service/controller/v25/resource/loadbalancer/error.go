@@ -1,6 +1,8 @@
 package loadbalancer
 
 import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/giantswarm/microerror"
 )
 
@@ -21,3 +23,68 @@ var wrongTypeError = &microerror.Error{
 func IsWrongType(err error) bool {
 	return microerror.Cause(err) == wrongTypeError
 }
+
+// IsLoadBalancerNotFound asserts the AWS error returned in case a network or
+// application load balancer does not exist anymore.
+func IsLoadBalancerNotFound(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok {
+		if aerr.Code() == elbv2.ErrCodeLoadBalancerNotFoundException {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsResourceInUse asserts the AWS error returned in case a target group is
+// still used by a listener of a load balancer being deleted.
+func IsResourceInUse(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok {
+		if aerr.Code() == elbv2.ErrCodeResourceInUseException {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTargetGroupNotFound asserts the AWS error returned in case a target group
+// does not exist anymore.
+func IsTargetGroupNotFound(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok {
+		if aerr.Code() == elbv2.ErrCodeTargetGroupNotFoundException {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsDependencyViolation asserts the AWS error returned in case a security
+// group is still used, e.g. by network interfaces.
+func IsDependencyViolation(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok {
+		if aerr.Code() == "DependencyViolation" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSecurityGroupNotFound asserts the AWS error returned in case a security
+// group does not exist anymore.
+func IsSecurityGroupNotFound(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok {
+		if aerr.Code() == "InvalidGroup.NotFound" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 )
 
 type ELBClientMock struct {
@@ -21,19 +23,22 @@ func (e *ELBClientMock) DeleteLoadBalancer(*elb.DeleteLoadBalancerInput) (*elb.D
 	return nil, nil
 }
 
-func (e *ELBClientMock) DescribeLoadBalancers(*elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error) {
-	output := &elb.DescribeLoadBalancersOutput{}
-	lbDescs := []*elb.LoadBalancerDescription{}
-
-	for _, lb := range e.loadBalancers {
-		lbDesc := &elb.LoadBalancerDescription{
-			LoadBalancerName: aws.String(lb.loadBalancerName),
+func (e *ELBClientMock) DescribeLoadBalancersPages(i *elb.DescribeLoadBalancersInput, fn func(*elb.DescribeLoadBalancersOutput, bool) bool) error {
+	// Every load balancer is returned on its own page to cover pagination.
+	for n, lb := range e.loadBalancers {
+		o := &elb.DescribeLoadBalancersOutput{
+			LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+				{
+					LoadBalancerName: aws.String(lb.loadBalancerName),
+				},
+			},
+		}
+		if !fn(o, n == len(e.loadBalancers)-1) {
+			break
 		}
-		lbDescs = append(lbDescs, lbDesc)
 	}
-	output.SetLoadBalancerDescriptions(lbDescs)
 
-	return output, nil
+	return nil
 }
 
 func (e *ELBClientMock) DescribeTags(*elb.DescribeTagsInput) (*elb.DescribeTagsOutput, error) {
@@ -51,3 +56,72 @@ func (e *ELBClientMock) DescribeTags(*elb.DescribeTagsInput) (*elb.DescribeTagsO
 
 	return output, nil
 }
+
+type ELBV2ClientMock struct {
+	elbv2iface.ELBV2API
+
+	loadBalancers []ELBV2ResourceMock
+	targetGroups  []ELBV2ResourceMock
+}
+
+type ELBV2ResourceMock struct {
+	arn  string
+	tags map[string]string
+}
+
+func (e *ELBV2ClientMock) DescribeLoadBalancersPages(i *elbv2.DescribeLoadBalancersInput, fn func(*elbv2.DescribeLoadBalancersOutput, bool) bool) error {
+	// Every load balancer is returned on its own page to cover pagination.
+	for n, lb := range e.loadBalancers {
+		o := &elbv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []*elbv2.LoadBalancer{
+				{
+					LoadBalancerArn: aws.String(lb.arn),
+				},
+			},
+		}
+		if !fn(o, n == len(e.loadBalancers)-1) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (e *ELBV2ClientMock) DescribeTargetGroupsPages(i *elbv2.DescribeTargetGroupsInput, fn func(*elbv2.DescribeTargetGroupsOutput, bool) bool) error {
+	for n, tg := range e.targetGroups {
+		o := &elbv2.DescribeTargetGroupsOutput{
+			TargetGroups: []*elbv2.TargetGroup{
+				{
+					TargetGroupArn: aws.String(tg.arn),
+				},
+			},
+		}
+		if !fn(o, n == len(e.targetGroups)-1) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (e *ELBV2ClientMock) DescribeTags(i *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error) {
+	output := &elbv2.DescribeTagsOutput{}
+
+	for _, arn := range i.ResourceArns {
+		for _, r := range append(e.loadBalancers, e.targetGroups...) {
+			if r.arn != *arn {
+				continue
+			}
+
+			d := &elbv2.TagDescription{
+				ResourceArn: aws.String(r.arn),
+			}
+			for k, v := range r.tags {
+				d.Tags = append(d.Tags, &elbv2.Tag{Key: aws.String(k), Value: aws.String(v)})
+			}
+			output.TagDescriptions = append(output.TagDescriptions, d)
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,176 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/controller/context/finalizerskeptcontext"
+	"github.com/giantswarm/operatorkit/controller/context/reconciliationcanceledcontext"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+const (
+	// cloudProviderSecurityGroupNamePattern matches the names of the security
+	// groups the cloud provider creates for the classic ELBs of Services. They
+	// are named k8s-elb-<load balancer name>.
+	cloudProviderSecurityGroupNamePattern = "k8s-elb-*"
+)
+
+// deleteLoadBalancerSecurityGroups deletes the security groups the cloud
+// provider created for the classic ELBs of the tenant cluster's Services. They
+// would otherwise block the deletion of the VPC. The ingress rules of other
+// security groups referencing them, e.g. the worker security group, are
+// revoked first.
+func (r *Resource) deleteLoadBalancerSecurityGroups(ctx context.Context, customObject v1alpha1.AWSConfig) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var groups []*ec2.SecurityGroup
+	{
+		i := &ec2.DescribeSecurityGroupsInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String(fmt.Sprintf("tag:%s", key.ClusterCloudProviderTag(customObject))),
+					Values: []*string{aws.String(cloudProviderClusterTagValue)},
+				},
+				{
+					Name:   aws.String("group-name"),
+					Values: []*string{aws.String(cloudProviderSecurityGroupNamePattern)},
+				},
+			},
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSecurityGroups(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		groups = o.SecurityGroups
+	}
+
+	if len(groups) == 0 {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "not deleting load balancer security groups because there aren't any")
+		return nil
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleting %d load balancer security groups", len(groups)))
+
+	for _, g := range groups {
+		err := r.revokeReferencingIngressRules(ctx, *g.GroupId)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		i := &ec2.DeleteSecurityGroupInput{
+			GroupId: g.GroupId,
+		}
+
+		_, err = cc.Client.TenantCluster.AWS.EC2.DeleteSecurityGroup(i)
+		if IsSecurityGroupNotFound(err) {
+			// Fall through.
+		} else if IsDependencyViolation(err) {
+			// The network interfaces of the load balancers deleted before may
+			// still use the security group. It is deleted in the next
+			// reconciliation loop then.
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("security group %#q is still in use", *g.GroupId))
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", "keeping finalizers")
+			finalizerskeptcontext.SetKept(ctx)
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
+			reconciliationcanceledcontext.SetCanceled(ctx)
+
+			return nil
+		} else if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleted %d load balancer security groups", len(groups)))
+
+	return nil
+}
+
+func (r *Resource) revokeReferencingIngressRules(ctx context.Context, groupID string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var groups []*ec2.SecurityGroup
+	{
+		i := &ec2.DescribeSecurityGroupsInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("ip-permission.group-id"),
+					Values: []*string{aws.String(groupID)},
+				},
+			},
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeSecurityGroups(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		groups = o.SecurityGroups
+	}
+
+	for _, g := range groups {
+		permissions := referencingPermissions(g.IpPermissions, groupID)
+		if len(permissions) == 0 {
+			continue
+		}
+
+		i := &ec2.RevokeSecurityGroupIngressInput{
+			GroupId:       g.GroupId,
+			IpPermissions: permissions,
+		}
+
+		_, err := cc.Client.TenantCluster.AWS.EC2.RevokeSecurityGroupIngress(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("revoked ingress rules of security group %#q referencing security group %#q", *g.GroupId, groupID))
+	}
+
+	return nil
+}
+
+// referencingPermissions returns the parts of the given ingress rules which
+// allow traffic from the given security group. Other sources of the rules are
+// omitted, so that revoking the returned rules leaves them untouched.
+func referencingPermissions(permissions []*ec2.IpPermission, groupID string) []*ec2.IpPermission {
+	var referencing []*ec2.IpPermission
+
+	for _, p := range permissions {
+		var pairs []*ec2.UserIdGroupPair
+		for _, pair := range p.UserIdGroupPairs {
+			if aws.StringValue(pair.GroupId) == groupID {
+				pairs = append(pairs, &ec2.UserIdGroupPair{GroupId: pair.GroupId})
+			}
+		}
+
+		if len(pairs) == 0 {
+			continue
+		}
+
+		referencing = append(referencing, &ec2.IpPermission{
+			FromPort:         p.FromPort,
+			IpProtocol:       p.IpProtocol,
+			ToPort:           p.ToPort,
+			UserIdGroupPairs: pairs,
+		})
+	}
+
+	return referencing
+}
@@ -0,0 +1,95 @@
+package loadbalancer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func Test_referencingPermissions(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name                string
+		permissions         []*ec2.IpPermission
+		expectedPermissions []*ec2.IpPermission
+	}{
+		{
+			name:                "case 0: no permissions",
+			permissions:         nil,
+			expectedPermissions: nil,
+		},
+		{
+			name: "case 1: permissions not referencing the group are omitted",
+			permissions: []*ec2.IpPermission{
+				{
+					FromPort:   aws.Int64(443),
+					IpProtocol: aws.String("tcp"),
+					IpRanges: []*ec2.IpRange{
+						{CidrIp: aws.String("0.0.0.0/0")},
+					},
+					ToPort: aws.Int64(443),
+				},
+				{
+					FromPort:   aws.Int64(22),
+					IpProtocol: aws.String("tcp"),
+					ToPort:     aws.Int64(22),
+					UserIdGroupPairs: []*ec2.UserIdGroupPair{
+						{GroupId: aws.String("sg-other")},
+					},
+				},
+			},
+			expectedPermissions: nil,
+		},
+		{
+			name: "case 2: only the pairs referencing the group are returned",
+			permissions: []*ec2.IpPermission{
+				{
+					FromPort:   aws.Int64(30010),
+					IpProtocol: aws.String("tcp"),
+					IpRanges: []*ec2.IpRange{
+						{CidrIp: aws.String("10.0.0.0/16")},
+					},
+					ToPort: aws.Int64(30010),
+					UserIdGroupPairs: []*ec2.UserIdGroupPair{
+						{GroupId: aws.String("sg-other")},
+						{GroupId: aws.String("sg-elb"), UserId: aws.String("123456789012")},
+					},
+				},
+				{
+					IpProtocol: aws.String("-1"),
+					UserIdGroupPairs: []*ec2.UserIdGroupPair{
+						{GroupId: aws.String("sg-elb")},
+					},
+				},
+			},
+			expectedPermissions: []*ec2.IpPermission{
+				{
+					FromPort:   aws.Int64(30010),
+					IpProtocol: aws.String("tcp"),
+					ToPort:     aws.Int64(30010),
+					UserIdGroupPairs: []*ec2.UserIdGroupPair{
+						{GroupId: aws.String("sg-elb")},
+					},
+				},
+				{
+					IpProtocol: aws.String("-1"),
+					UserIdGroupPairs: []*ec2.UserIdGroupPair{
+						{GroupId: aws.String("sg-elb")},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := referencingPermissions(tc.permissions, "sg-elb")
+
+			if !reflect.DeepEqual(result, tc.expectedPermissions) {
+				t.Fatalf("permissions == %#v, want %#v", result, tc.expectedPermissions)
+			}
+		})
+	}
+}
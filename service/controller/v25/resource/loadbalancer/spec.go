@@ -2,20 +2,40 @@ package loadbalancer
 
 import (
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 )
 
 type Clients struct {
-	ELB ELBClient
+	ELB   ELBClient
+	ELBV2 ELBV2Client
 }
 
 // ELBClient describes the methods required to be implemented by an ELB AWS
 // client. The ELB API provides support for classic ELBs.
 type ELBClient interface {
 	DeleteLoadBalancer(*elb.DeleteLoadBalancerInput) (*elb.DeleteLoadBalancerOutput, error)
-	DescribeLoadBalancers(*elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error)
+	DescribeLoadBalancersPages(*elb.DescribeLoadBalancersInput, func(*elb.DescribeLoadBalancersOutput, bool) bool) error
 	DescribeTags(*elb.DescribeTagsInput) (*elb.DescribeTagsOutput, error)
 }
 
+// ELBV2Client describes the methods required to be implemented by an ELBV2 AWS
+// client. The ELBV2 API provides support for network and application load
+// balancers.
+type ELBV2Client interface {
+	DeleteLoadBalancer(*elbv2.DeleteLoadBalancerInput) (*elbv2.DeleteLoadBalancerOutput, error)
+	DeleteTargetGroup(*elbv2.DeleteTargetGroupInput) (*elbv2.DeleteTargetGroupOutput, error)
+	DescribeLoadBalancersPages(*elbv2.DescribeLoadBalancersInput, func(*elbv2.DescribeLoadBalancersOutput, bool) bool) error
+	DescribeTags(*elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error)
+	DescribeTargetGroupsPages(*elbv2.DescribeTargetGroupsInput, func(*elbv2.DescribeTargetGroupsOutput, bool) bool) error
+}
+
 type LoadBalancerState struct {
 	LoadBalancerNames []string
 }
+
+// ELBV2State holds the network and application load balancers and their
+// target groups created by the cloud provider of a tenant cluster.
+type ELBV2State struct {
+	LoadBalancerARNs []string
+	TargetGroupARNs  []string
+}
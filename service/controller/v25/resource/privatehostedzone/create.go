@@ -0,0 +1,110 @@
+package privatehostedzone
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	customObject, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	privateAPI, err := key.PrivateAPI(customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !privateAPI || !r.route53Enabled {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "private API or route53 disabled")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "finding the private API hosted zone")
+
+	zoneID, vpcs, ok, err := r.privateHostedZone(ctx, customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !ok {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "did not find the private API hosted zone")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found the private API hosted zone %#q", zoneID))
+
+	if containsVPC(vpcs, key.PeerID(customObject)) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "control plane VPC is already associated with the private API hosted zone")
+		return nil
+	}
+
+	vpc := &route53.VPC{
+		VPCId:     aws.String(key.PeerID(customObject)),
+		VPCRegion: aws.String(key.Region(customObject)),
+	}
+	crossAccount := cc.Status.ControlPlane.AWSAccountID != cc.Status.TenantCluster.AWSAccountID
+
+	if crossAccount {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "authorizing the association of the control plane VPC with the private API hosted zone")
+
+		i := &route53.CreateVPCAssociationAuthorizationInput{
+			HostedZoneId: aws.String(zoneID),
+			VPC:          vpc,
+		}
+
+		_, err := cc.Client.TenantCluster.AWS.Route53.CreateVPCAssociationAuthorization(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", "authorized the association of the control plane VPC with the private API hosted zone")
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", "associating the control plane VPC with the private API hosted zone")
+
+		i := &route53.AssociateVPCWithHostedZoneInput{
+			HostedZoneId: aws.String(zoneID),
+			VPC:          vpc,
+		}
+
+		_, err := cc.Client.ControlPlane.AWS.Route53.AssociateVPCWithHostedZone(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", "associated the control plane VPC with the private API hosted zone")
+	}
+
+	// The authorization is not needed anymore once the VPC is associated.
+	// Removing it prevents the control plane account from associating the VPC
+	// again after the tenant cluster got deleted.
+	if crossAccount {
+		i := &route53.DeleteVPCAssociationAuthorizationInput{
+			HostedZoneId: aws.String(zoneID),
+			VPC:          vpc,
+		}
+
+		_, err := cc.Client.TenantCluster.AWS.Route53.DeleteVPCAssociationAuthorization(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package privatehostedzone
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+// EnsureDeleted disassociates the control plane VPC from the private API
+// hosted zone, so that the TCCP stack is able to delete the hosted zone.
+func (r *Resource) EnsureDeleted(ctx context.Context, obj interface{}) error {
+	customObject, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	privateAPI, err := key.PrivateAPI(customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !privateAPI || !r.route53Enabled {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "private API or route53 disabled")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
+	zoneID, vpcs, ok, err := r.privateHostedZone(ctx, customObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !ok || !containsVPC(vpcs, key.PeerID(customObject)) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "control plane VPC is not associated with the private API hosted zone")
+		return nil
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "disassociating the control plane VPC from the private API hosted zone")
+
+	i := &route53.DisassociateVPCFromHostedZoneInput{
+		HostedZoneId: aws.String(zoneID),
+		VPC: &route53.VPC{
+			VPCId:     aws.String(key.PeerID(customObject)),
+			VPCRegion: aws.String(key.Region(customObject)),
+		},
+	}
+
+	_, err = cc.Client.ControlPlane.AWS.Route53.DisassociateVPCFromHostedZone(i)
+	if IsNoSuchHostedZone(err) || IsVPCAssociationNotFound(err) {
+		// Fall through.
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "disassociated the control plane VPC from the private API hosted zone")
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package privatehostedzone
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+// IsNoSuchHostedZone asserts the AWS error returned in case a hosted zone does
+// not exist.
+func IsNoSuchHostedZone(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok {
+		if aerr.Code() == route53.ErrCodeNoSuchHostedZone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsVPCAssociationNotFound asserts the AWS error returned in case a VPC is not
+// associated with a hosted zone.
+func IsVPCAssociationNotFound(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if ok {
+		if aerr.Code() == route53.ErrCodeVPCAssociationNotFound {
+			return true
+		}
+	}
+
+	return false
+}
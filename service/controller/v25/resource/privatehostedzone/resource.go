@@ -0,0 +1,130 @@
+package privatehostedzone
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+const (
+	// Name is the identifier of the resource.
+	Name = "privatehostedzonev25"
+)
+
+type Config struct {
+	Logger micrologger.Logger
+
+	Route53Enabled bool
+}
+
+// Resource associates the control plane VPC with the private hosted zone of
+// the Kubernetes API of tenant clusters with a private API. The hosted zone is
+// managed by the TCCP stack, which associates it with the tenant cluster VPC.
+// Cloud Formation cannot associate VPCs of other accounts, so the control
+// plane VPC is associated here. In case the tenant cluster and the control
+// plane live in different accounts, the tenant cluster account authorizes the
+// association first.
+type Resource struct {
+	logger micrologger.Logger
+
+	route53Enabled bool
+}
+
+func New(config Config) (*Resource, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	r := &Resource{
+		logger: config.Logger,
+
+		route53Enabled: config.Route53Enabled,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
+
+// privateHostedZone returns the ID and the associated VPCs of the tenant
+// cluster's private API hosted zone. False is returned in case the hosted zone
+// does not exist.
+func (r *Resource) privateHostedZone(ctx context.Context, customObject v1alpha1.AWSConfig) (string, []*route53.VPC, bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return "", nil, false, microerror.Mask(err)
+	}
+
+	var id string
+	{
+		i := &route53.ListHostedZonesByNameInput{
+			DNSName: aws.String(key.PrivateAPIHostedZoneName(customObject)),
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.Route53.ListHostedZonesByName(i)
+		if err != nil {
+			return "", nil, false, microerror.Mask(err)
+		}
+
+		var ok bool
+		id, ok = findPrivateHostedZoneID(o.HostedZones, key.PrivateAPIHostedZoneName(customObject))
+		if !ok {
+			return "", nil, false, nil
+		}
+	}
+
+	var vpcs []*route53.VPC
+	{
+		i := &route53.GetHostedZoneInput{
+			Id: aws.String(id),
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.Route53.GetHostedZone(i)
+		if IsNoSuchHostedZone(err) {
+			return "", nil, false, nil
+		} else if err != nil {
+			return "", nil, false, microerror.Mask(err)
+		}
+
+		vpcs = o.VPCs
+	}
+
+	return id, vpcs, true, nil
+}
+
+// findPrivateHostedZoneID returns the ID of the private hosted zone with the
+// given name. The name may or may not be fully qualified.
+func findPrivateHostedZoneID(zones []*route53.HostedZone, name string) (string, bool) {
+	for _, z := range zones {
+		if aws.StringValue(z.Name) != fmt.Sprintf("%s.", name) && aws.StringValue(z.Name) != name {
+			continue
+		}
+		if z.Config == nil || !aws.BoolValue(z.Config.PrivateZone) {
+			continue
+		}
+
+		return aws.StringValue(z.Id), true
+	}
+
+	return "", false
+}
+
+func containsVPC(vpcs []*route53.VPC, vpcID string) bool {
+	for _, v := range vpcs {
+		if aws.StringValue(v.VPCId) == vpcID {
+			return true
+		}
+	}
+
+	return false
+}
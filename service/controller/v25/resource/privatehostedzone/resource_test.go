@@ -0,0 +1,65 @@
+package privatehostedzone
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+func Test_findPrivateHostedZoneID(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		zones      []*route53.HostedZone
+		expectedID string
+		expectedOK bool
+	}{
+		{
+			name:       "case 0: no hosted zones",
+			zones:      nil,
+			expectedOK: false,
+		},
+		{
+			name: "case 1: public hosted zone with the same name is ignored",
+			zones: []*route53.HostedZone{
+				{
+					Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(false)},
+					Id:     aws.String("/hostedzone/public"),
+					Name:   aws.String("api.al9qy.k8s.gauss.eu-central-1.aws.gigantic.io."),
+				},
+			},
+			expectedOK: false,
+		},
+		{
+			name: "case 2: private hosted zone found among other zones",
+			zones: []*route53.HostedZone{
+				{
+					Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(true)},
+					Id:     aws.String("/hostedzone/other"),
+					Name:   aws.String("api.al9qy.k8s.gauss.eu-central-1.aws.gigantic.io.example."),
+				},
+				{
+					Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(true)},
+					Id:     aws.String("/hostedzone/private"),
+					Name:   aws.String("api.al9qy.k8s.gauss.eu-central-1.aws.gigantic.io."),
+				},
+			},
+			expectedID: "/hostedzone/private",
+			expectedOK: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id, ok := findPrivateHostedZoneID(tc.zones, "api.al9qy.k8s.gauss.eu-central-1.aws.gigantic.io")
+
+			if ok != tc.expectedOK {
+				t.Fatalf("ok == %t, want %t", ok, tc.expectedOK)
+			}
+			if id != tc.expectedID {
+				t.Fatalf("id == %#q, want %#q", id, tc.expectedID)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 
 	var clusterCerts certs.Cluster
 	var clusterKeys randomkeys.Cluster
+	var etcdPeerCert certs.TLS
 	{
 		g := &errgroup.Group{}
 
@@ -38,6 +39,20 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 			return nil
 		})
 
+		// Only the masters of tenant clusters with multiple masters use the etcd
+		// peer certificate.
+		if key.EtcdMemberCount(customObject, cc.Status.TenantCluster.MasterInstance.EtcdMemberCount) > 1 {
+			g.Go(func() error {
+				tls, err := r.certsSearcher.SearchTLS(key.ClusterID(customObject), key.EtcdPeerCert)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+				etcdPeerCert = tls
+
+				return nil
+			})
+		}
+
 		g.Go(func() error {
 			keys, err := r.randomKeysSearcher.SearchCluster(key.ClusterID(customObject))
 			if err != nil {
@@ -60,7 +75,7 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 		m := sync.Mutex{}
 
 		g.Go(func() error {
-			b, err := r.cloudConfig.NewMasterTemplate(ctx, customObject, clusterCerts, clusterKeys)
+			b, err := r.cloudConfig.NewMasterTemplate(ctx, customObject, clusterCerts, etcdPeerCert, clusterKeys)
 			if err != nil {
 				return microerror.Mask(err)
 			}
@@ -95,6 +110,33 @@ func (r *Resource) GetDesiredState(ctx context.Context, obj interface{}) (interf
 			return nil
 		})
 
+		pools, err := key.WorkerPools(customObject)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, p := range pools {
+			pool := p
+
+			g.Go(func() error {
+				b, err := r.cloudConfig.NewWorkerPoolTemplate(ctx, customObject, clusterCerts, pool)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+
+				m.Lock()
+				k := key.BucketObjectName(customObject, key.WorkerPoolRole(pool.Name))
+				output[k] = BucketObjectState{
+					Bucket: key.BucketName(customObject, cc.Status.TenantCluster.AWSAccountID),
+					Body:   b,
+					Key:    k,
+				}
+				m.Unlock()
+
+				return nil
+			})
+		}
+
 		err = g.Wait()
 		if err != nil {
 			return nil, microerror.Mask(err)
@@ -14,6 +14,8 @@ import (
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/certs"
 	"github.com/giantswarm/randomkeys"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
 )
 
 // nopCloser is required to implement the ReadCloser interface required by
@@ -71,7 +73,7 @@ type CloudConfigMock struct {
 	template string
 }
 
-func (c *CloudConfigMock) NewMasterTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, randomKeys randomkeys.Cluster) (string, error) {
+func (c *CloudConfigMock) NewMasterTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, etcdPeerCert certs.TLS, randomKeys randomkeys.Cluster) (string, error) {
 	return c.template, nil
 }
 
@@ -79,6 +81,10 @@ func (c *CloudConfigMock) NewWorkerTemplate(ctx context.Context, customObject v1
 	return c.template, nil
 }
 
+func (c *CloudConfigMock) NewWorkerPoolTemplate(ctx context.Context, customObject v1alpha1.AWSConfig, clusterCerts certs.Cluster, pool key.WorkerPool) (string, error) {
+	return c.template, nil
+}
+
 type KMSClientMock struct {
 	kmsiface.KMSAPI
 }
@@ -0,0 +1,379 @@
+package stackrecovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/operatorkit/controller/context/reconciliationcanceledcontext"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
+	cr, err := key.ToCustomObject(obj)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	attempts, err := recoveryAttempts(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	protected, err := key.DeletionProtection(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	stacks := []stack{
+		{
+			CloudFormation: cc.Client.ControlPlane.AWS.CloudFormation,
+			Kind:           stackKindCPI,
+			Name:           key.MainHostPreStackName(cr),
+		},
+		{
+			CloudFormation: cc.Client.TenantCluster.AWS.CloudFormation,
+			EC2:            cc.Client.TenantCluster.AWS.EC2,
+			Kind:           stackKindTCCP,
+			Name:           key.MainGuestStackName(cr),
+		},
+		{
+			CloudFormation: cc.Client.ControlPlane.AWS.CloudFormation,
+			Kind:           stackKindCPF,
+			Name:           key.MainHostPostStackName(cr),
+		},
+	}
+
+	var conditions []v1alpha1.StatusClusterResourceCondition
+	for _, s := range stacks {
+		c, err := r.recoverStack(ctx, s, attempts, protected, cr.Status.Cluster.Resources)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if c != nil {
+			conditions = append(conditions, *c)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	err = r.updateCR(ctx, cr, attempts, conditions)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
+	reconciliationcanceledcontext.SetCanceled(ctx)
+
+	return nil
+}
+
+// recoverStack recovers the given stack in case it is in a terminal failure
+// state. The given attempts are updated accordingly. The returned condition is
+// nil in case nothing has to be reported. The given resources are the ones
+// currently reported in the CR status. Stacks of tenant clusters protected
+// from deletion are never deleted for being recreated, since that requires
+// disabling their termination protection.
+func (r *Resource) recoverStack(ctx context.Context, s stack, attempts map[string]int, protected bool, resources []v1alpha1.StatusClusterResource) (*v1alpha1.StatusClusterResourceCondition, error) {
+	var status string
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding the status of the %s cloud formation stack", s.Kind))
+
+		i := &cloudformation.DescribeStacksInput{
+			StackName: aws.String(s.Name),
+		}
+
+		o, err := s.CloudFormation.DescribeStacks(i)
+		if IsNotExists(err) {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("did not find the %s cloud formation stack", s.Kind))
+			return nil, nil
+
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+
+		} else if len(o.Stacks) != 1 {
+			return nil, microerror.Maskf(executionFailedError, "expected one stack, got %d", len(o.Stacks))
+		}
+
+		status = *o.Stacks[0].StackStatus
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found the status %#q of the %s cloud formation stack", status, s.Kind))
+	}
+
+	a := recoveryActionForStatus(status)
+
+	if a == actionNone {
+		return nil, nil
+	}
+
+	if a == actionRecovered {
+		if attempts[s.Kind] == 0 {
+			return nil, nil
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("the %s cloud formation stack recovered", s.Kind))
+		delete(attempts, s.Kind)
+
+		return newCondition(s.Kind, conditionRecovered), nil
+	}
+
+	if a == actionRecreate && protected {
+		r.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("not recreating the %s cloud formation stack from status %#q", s.Kind, status))
+		r.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("the tenant cluster is protected from deletion by annotation %#q", key.DeletionProtectionAnnotation))
+
+		if hasCondition(resources, s.Kind, conditionRecreationBlocked) {
+			return nil, nil
+		}
+
+		return newCondition(s.Kind, conditionRecreationBlocked), nil
+	}
+
+	if attempts[s.Kind] >= r.maxAttempts {
+		r.logger.LogCtx(ctx, "level", "error", "message", fmt.Sprintf("not recovering the %s cloud formation stack from status %#q", s.Kind, status))
+		r.logger.LogCtx(ctx, "level", "error", "message", fmt.Sprintf("gave up after %d attempts", attempts[s.Kind]))
+
+		if hasCondition(resources, s.Kind, conditionRecoveryBudgetExhausted) {
+			return nil, nil
+		}
+
+		return newCondition(s.Kind, conditionRecoveryBudgetExhausted), nil
+	}
+	attempts[s.Kind]++
+
+	switch a {
+	case actionContinueUpdateRollback:
+		err := r.continueUpdateRollback(ctx, s)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		return newCondition(s.Kind, conditionUpdateRollbackContinued), nil
+
+	case actionRecreate:
+		err := r.deleteStack(ctx, s)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		return newCondition(s.Kind, conditionRecreating), nil
+	}
+
+	return nil, nil
+}
+
+// continueUpdateRollback continues the rollback of the given stack. Resources
+// which failed to roll back and are safe to be skipped are skipped, so that
+// the rollback does not fail again for the same reason.
+func (r *Resource) continueUpdateRollback(ctx context.Context, s stack) error {
+	var skip []string
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding failed resources of the %s cloud formation stack", s.Kind))
+
+		i := &cloudformation.DescribeStackResourcesInput{
+			StackName: aws.String(s.Name),
+		}
+
+		o, err := s.CloudFormation.DescribeStackResources(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		skip = resourcesToSkip(o.StackResources)
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d failed resources of the %s cloud formation stack safe to be skipped", len(skip), s.Kind))
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("continuing the update rollback of the %s cloud formation stack", s.Kind))
+
+		i := &cloudformation.ContinueUpdateRollbackInput{
+			StackName: aws.String(s.Name),
+		}
+		if len(skip) > 0 {
+			i.ResourcesToSkip = aws.StringSlice(skip)
+		}
+
+		_, err := s.CloudFormation.ContinueUpdateRollback(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("continued the update rollback of the %s cloud formation stack", s.Kind))
+	}
+
+	return nil
+}
+
+// deleteStack requests the deletion of the given stack, so that it gets
+// created again by the resource managing it.
+func (r *Resource) deleteStack(ctx context.Context, s stack) error {
+	if s.EC2 != nil {
+		err := r.disableInstanceTerminationProtection(ctx, s)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("disabling the termination protection of the %s cloud formation stack", s.Kind))
+
+		i := &cloudformation.UpdateTerminationProtectionInput{
+			EnableTerminationProtection: aws.Bool(false),
+			StackName:                   aws.String(s.Name),
+		}
+
+		_, err := s.CloudFormation.UpdateTerminationProtection(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("disabled the termination protection of the %s cloud formation stack", s.Kind))
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("requesting the deletion of the %s cloud formation stack", s.Kind))
+
+		i := &cloudformation.DeleteStackInput{
+			StackName: aws.String(s.Name),
+		}
+
+		_, err := s.CloudFormation.DeleteStack(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("requested the deletion of the %s cloud formation stack", s.Kind))
+	}
+
+	return nil
+}
+
+// disableInstanceTerminationProtection disables the termination protection of
+// all instances of the given stack. Instances with termination protection
+// would otherwise prevent the deletion of the stack.
+func (r *Resource) disableInstanceTerminationProtection(ctx context.Context, s stack) error {
+	var instanceIDs []string
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding instances of the %s cloud formation stack", s.Kind))
+
+		i := &ec2.DescribeInstancesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name: aws.String(fmt.Sprintf("tag:%s", tagStackName)),
+					Values: []*string{
+						aws.String(s.Name),
+					},
+				},
+				{
+					Name: aws.String("instance-state-name"),
+					Values: []*string{
+						aws.String(ec2.InstanceStateNamePending),
+						aws.String(ec2.InstanceStateNameRunning),
+						aws.String(ec2.InstanceStateNameStopped),
+						aws.String(ec2.InstanceStateNameStopping),
+					},
+				},
+			},
+		}
+
+		o, err := s.EC2.DescribeInstances(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for _, reservation := range o.Reservations {
+			for _, instance := range reservation.Instances {
+				instanceIDs = append(instanceIDs, *instance.InstanceId)
+			}
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d instances of the %s cloud formation stack", len(instanceIDs), s.Kind))
+	}
+
+	for _, id := range instanceIDs {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("disabling termination protection for instance %#q", id))
+
+		i := &ec2.ModifyInstanceAttributeInput{
+			DisableApiTermination: &ec2.AttributeBooleanValue{
+				Value: aws.Bool(false),
+			},
+			InstanceId: aws.String(id),
+		}
+
+		_, err := s.EC2.ModifyInstanceAttribute(i)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("disabled termination protection for instance %#q", id))
+	}
+
+	return nil
+}
+
+// updateCR persists the given recovery attempts in the CR annotations and
+// reports the given conditions in the CR status.
+func (r *Resource) updateCR(ctx context.Context, cr v1alpha1.AWSConfig, attempts map[string]int, conditions []v1alpha1.StatusClusterResourceCondition) error {
+	r.logger.LogCtx(ctx, "level", "debug", "message", "updating CR annotations")
+
+	b, err := json.Marshal(attempts)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	annotations := cr.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key.StackRecoveryAttemptsAnnotation] = string(b)
+	cr.SetAnnotations(annotations)
+
+	updated, err := r.g8sClient.ProviderV1alpha1().AWSConfigs(cr.Namespace).Update(&cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "updated CR annotations")
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "updating CR status")
+
+	updated.Status.Cluster.Resources = withConditions(updated.Status.Cluster.Resources, conditions)
+
+	_, err = r.g8sClient.ProviderV1alpha1().AWSConfigs(cr.Namespace).UpdateStatus(updated)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "updated CR status")
+
+	return nil
+}
+
+func recoveryAttempts(cr v1alpha1.AWSConfig) (map[string]int, error) {
+	attempts := map[string]int{}
+
+	v, ok := cr.GetAnnotations()[key.StackRecoveryAttemptsAnnotation]
+	if !ok || v == "" {
+		return attempts, nil
+	}
+
+	err := json.Unmarshal([]byte(v), &attempts)
+	if err != nil {
+		return nil, microerror.Maskf(executionFailedError, "annotation %#q: %s", key.StackRecoveryAttemptsAnnotation, err)
+	}
+
+	return attempts, nil
+}
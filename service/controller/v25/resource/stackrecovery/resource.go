@@ -0,0 +1,69 @@
+package stackrecovery
+
+import (
+	"github.com/giantswarm/apiextensions/pkg/clientset/versioned"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+)
+
+const (
+	// Name is the identifier of the resource.
+	Name = "stackrecoveryv25"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of times the resource tries to
+	// recover a cloud formation stack before giving up.
+	DefaultMaxAttempts = 3
+)
+
+type Config struct {
+	G8sClient versioned.Interface
+	Logger    micrologger.Logger
+
+	// MaxAttempts is the number of recovery attempts per cloud formation stack.
+	// It defaults to DefaultMaxAttempts.
+	MaxAttempts int
+}
+
+// Resource implements the stack recovery resource. It recovers the TCCP, CPF
+// and CPI cloud formation stacks of a tenant cluster from terminal failure
+// states. Stacks in UPDATE_ROLLBACK_FAILED get their rollback continued.
+// Stacks which failed to be created get deleted, so they are recreated by
+// their resources, unless the tenant cluster is protected from deletion. Every
+// action is reported in the CR status.
+type Resource struct {
+	g8sClient versioned.Interface
+	logger    micrologger.Logger
+
+	maxAttempts int
+}
+
+func New(config Config) (*Resource, error) {
+	if config.G8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.MaxAttempts < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.MaxAttempts must not be negative", config)
+	}
+
+	r := &Resource{
+		g8sClient: config.G8sClient,
+		logger:    config.Logger,
+
+		maxAttempts: config.MaxAttempts,
+	}
+
+	return r, nil
+}
+
+func (r *Resource) Name() string {
+	return Name
+}
@@ -0,0 +1,159 @@
+package stackrecovery
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+const (
+	stackKindCPF  = "CPF"
+	stackKindCPI  = "CPI"
+	stackKindTCCP = "TCCP"
+)
+
+const (
+	conditionRecovered               = "Recovered"
+	conditionRecreating              = "Recreating"
+	conditionRecreationBlocked       = "RecreationBlocked"
+	conditionRecoveryBudgetExhausted = "RecoveryBudgetExhausted"
+	conditionUpdateRollbackContinued = "UpdateRollbackContinued"
+)
+
+const (
+	// tagStackName is the tag cloud formation puts on all resources it creates
+	// with the name of their stack as value.
+	tagStackName = "aws:cloudformation:stack-name"
+)
+
+type action int
+
+const (
+	// actionNone means the stack is transitioning or in a state the resource
+	// does not act upon.
+	actionNone action = iota
+	// actionContinueUpdateRollback means the stack failed to roll back an
+	// update and its rollback has to be continued.
+	actionContinueUpdateRollback
+	// actionRecovered means the stack is in a stable and healthy state.
+	actionRecovered
+	// actionRecreate means the stack failed to be created and has to be deleted
+	// so it gets created again.
+	actionRecreate
+)
+
+// skippableResourceTypes are the types of resources which are safe to be
+// skipped when continuing the update rollback of a stack. Instances and their
+// volumes are managed out of band by the operator during master updates, which
+// is the most common reason for them to fail to roll back.
+var skippableResourceTypes = map[string]bool{
+	"AWS::AutoScaling::LifecycleHook": true,
+	"AWS::EC2::Instance":              true,
+	"AWS::EC2::Volume":                true,
+}
+
+type stack struct {
+	CloudFormation *cloudformation.CloudFormation
+	// EC2 is only set for stacks containing instances.
+	EC2  ec2iface.EC2API
+	Kind string
+	Name string
+}
+
+func hasCondition(resources []v1alpha1.StatusClusterResource, kind string, condition string) bool {
+	for _, r := range resources {
+		if r.Name != Name {
+			continue
+		}
+
+		for _, c := range r.Conditions {
+			if c.Type == kind+condition {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func newCondition(kind string, condition string) *v1alpha1.StatusClusterResourceCondition {
+	return &v1alpha1.StatusClusterResourceCondition{
+		LastTransitionTime: v1alpha1.DeepCopyTime{Time: time.Now()},
+		Status:             "True",
+		Type:               kind + condition,
+	}
+}
+
+func recoveryActionForStatus(status string) action {
+	switch status {
+	case cloudformation.StackStatusCreateComplete, cloudformation.StackStatusUpdateComplete, cloudformation.StackStatusUpdateRollbackComplete:
+		return actionRecovered
+	case cloudformation.StackStatusUpdateRollbackFailed:
+		return actionContinueUpdateRollback
+	case cloudformation.StackStatusCreateFailed, cloudformation.StackStatusRollbackComplete, cloudformation.StackStatusRollbackFailed:
+		return actionRecreate
+	}
+
+	return actionNone
+}
+
+// resourcesToSkip returns the logical IDs of the given resources which failed
+// to be updated and are safe to be skipped.
+func resourcesToSkip(resources []*cloudformation.StackResource) []string {
+	var skip []string
+
+	for _, r := range resources {
+		if r.ResourceStatus == nil || *r.ResourceStatus != cloudformation.ResourceStatusUpdateFailed {
+			continue
+		}
+		if r.ResourceType == nil || !skippableResourceTypes[*r.ResourceType] {
+			continue
+		}
+
+		skip = append(skip, *r.LogicalResourceId)
+	}
+
+	return skip
+}
+
+// withConditions returns the given resources with the given conditions
+// reported for the stack recovery resource. Every stack keeps only its latest
+// condition.
+func withConditions(resources []v1alpha1.StatusClusterResource, conditions []v1alpha1.StatusClusterResourceCondition) []v1alpha1.StatusClusterResource {
+	idx := -1
+	for i, r := range resources {
+		if r.Name == Name {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		resources = append(resources, v1alpha1.StatusClusterResource{Name: Name})
+		idx = len(resources) - 1
+	}
+
+	for _, c := range conditions {
+		var kept []v1alpha1.StatusClusterResourceCondition
+		for _, e := range resources[idx].Conditions {
+			if stackKindOf(e.Type) != stackKindOf(c.Type) {
+				kept = append(kept, e)
+			}
+		}
+		resources[idx].Conditions = append(kept, c)
+	}
+
+	return resources
+}
+
+func stackKindOf(conditionType string) string {
+	for _, k := range []string{stackKindCPF, stackKindCPI, stackKindTCCP} {
+		if strings.HasPrefix(conditionType, k) {
+			return k
+		}
+	}
+
+	return ""
+}
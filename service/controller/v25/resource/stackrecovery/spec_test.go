@@ -0,0 +1,130 @@
+package stackrecovery
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+)
+
+func Test_recoveryActionForStatus(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		status         string
+		expectedAction action
+	}{
+		{
+			status:         cloudformation.StackStatusCreateComplete,
+			expectedAction: actionRecovered,
+		},
+		{
+			status:         cloudformation.StackStatusUpdateRollbackComplete,
+			expectedAction: actionRecovered,
+		},
+		{
+			status:         cloudformation.StackStatusUpdateRollbackFailed,
+			expectedAction: actionContinueUpdateRollback,
+		},
+		{
+			status:         cloudformation.StackStatusCreateFailed,
+			expectedAction: actionRecreate,
+		},
+		{
+			status:         cloudformation.StackStatusRollbackComplete,
+			expectedAction: actionRecreate,
+		},
+		{
+			status:         cloudformation.StackStatusUpdateInProgress,
+			expectedAction: actionNone,
+		},
+		{
+			status:         cloudformation.StackStatusDeleteInProgress,
+			expectedAction: actionNone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.status, func(t *testing.T) {
+			a := recoveryActionForStatus(tc.status)
+			if a != tc.expectedAction {
+				t.Fatalf("expected action %d, got %d", tc.expectedAction, a)
+			}
+		})
+	}
+}
+
+func Test_resourcesToSkip(t *testing.T) {
+	t.Parallel()
+	resources := []*cloudformation.StackResource{
+		{
+			LogicalResourceId: aws.String("MasterInstance"),
+			ResourceStatus:    aws.String(cloudformation.ResourceStatusUpdateFailed),
+			ResourceType:      aws.String("AWS::EC2::Instance"),
+		},
+		{
+			LogicalResourceId: aws.String("EtcdVolume"),
+			ResourceStatus:    aws.String(cloudformation.ResourceStatusUpdateComplete),
+			ResourceType:      aws.String("AWS::EC2::Volume"),
+		},
+		{
+			LogicalResourceId: aws.String("WorkerAutoScalingGroup"),
+			ResourceStatus:    aws.String(cloudformation.ResourceStatusUpdateFailed),
+			ResourceType:      aws.String("AWS::AutoScaling::AutoScalingGroup"),
+		},
+	}
+
+	skip := resourcesToSkip(resources)
+
+	expected := []string{"MasterInstance"}
+	if !reflect.DeepEqual(skip, expected) {
+		t.Fatalf("expected resources to skip %#v, got %#v", expected, skip)
+	}
+}
+
+func Test_withConditions(t *testing.T) {
+	t.Parallel()
+	resources := []v1alpha1.StatusClusterResource{
+		{
+			Name: "other",
+			Conditions: []v1alpha1.StatusClusterResourceCondition{
+				{Status: "True", Type: "Other"},
+			},
+		},
+	}
+
+	resources = withConditions(resources, []v1alpha1.StatusClusterResourceCondition{
+		{Status: "True", Type: stackKindTCCP + conditionRecreating},
+		{Status: "True", Type: stackKindCPF + conditionUpdateRollbackContinued},
+	})
+	resources = withConditions(resources, []v1alpha1.StatusClusterResourceCondition{
+		{Status: "True", Type: stackKindTCCP + conditionRecovered},
+	})
+
+	expected := []v1alpha1.StatusClusterResource{
+		{
+			Name: "other",
+			Conditions: []v1alpha1.StatusClusterResourceCondition{
+				{Status: "True", Type: "Other"},
+			},
+		},
+		{
+			Name: Name,
+			Conditions: []v1alpha1.StatusClusterResourceCondition{
+				{Status: "True", Type: stackKindCPF + conditionUpdateRollbackContinued},
+				{Status: "True", Type: stackKindTCCP + conditionRecovered},
+			},
+		},
+	}
+	if !reflect.DeepEqual(resources, expected) {
+		t.Fatalf("expected resources %#v, got %#v", expected, resources)
+	}
+
+	if !hasCondition(resources, stackKindTCCP, conditionRecovered) {
+		t.Fatalf("expected condition %#q", stackKindTCCP+conditionRecovered)
+	}
+	if hasCondition(resources, stackKindTCCP, conditionRecreating) {
+		t.Fatalf("expected condition %#q to be replaced", stackKindTCCP+conditionRecreating)
+	}
+}
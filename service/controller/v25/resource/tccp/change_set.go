@@ -0,0 +1,361 @@
+package tccp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+// ensureChangeSet makes sure a change set applying the given template body to
+// the tenant cluster's control plane cloud formation stack exists. The
+// resource level diff of a newly created change set is recorded as event of
+// the CR, so replacements can be reviewed before they happen. The returned name is the
+// name of the change set. The returned boolean is true in case the change set
+// is ready to be executed, which means it contains changes and is approved in
+// case approval is required for the tenant cluster.
+func (r *Resource) ensureChangeSet(ctx context.Context, cr v1alpha1.AWSConfig, templateBody string) (string, bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return "", false, microerror.Mask(err)
+	}
+
+	name := key.ChangeSetName(cr, templateBody)
+
+	var created bool
+
+	err = r.deleteStaleChangeSets(ctx, cr, name)
+	if err != nil {
+		return "", false, microerror.Mask(err)
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding change set %#q", name))
+
+		i := &cloudformation.DescribeChangeSetInput{
+			ChangeSetName: aws.String(name),
+			StackName:     aws.String(key.MainGuestStackName(cr)),
+		}
+
+		_, err := cc.Client.TenantCluster.AWS.CloudFormation.DescribeChangeSet(i)
+		if IsNotExists(err) {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("did not find change set %#q", name))
+
+			err = r.createChangeSet(ctx, cr, name, templateBody)
+			if err != nil {
+				return "", false, microerror.Mask(err)
+			}
+
+			created = true
+		} else if err != nil {
+			return "", false, microerror.Mask(err)
+		} else {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found change set %#q", name))
+		}
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("waiting for change set %#q to be created", name))
+
+		i := &cloudformation.DescribeChangeSetInput{
+			ChangeSetName: aws.String(name),
+			StackName:     aws.String(key.MainGuestStackName(cr)),
+		}
+
+		// The waiter fails in case the change set creation fails. The reason is
+		// inspected below, so only unexpected errors are returned here.
+		err := cc.Client.TenantCluster.AWS.CloudFormation.WaitUntilChangeSetCreateComplete(i)
+		if err != nil && !isWaiterResourceNotReady(err) {
+			return "", false, microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("waited for change set %#q to be created", name))
+	}
+
+	var preview key.ChangeSetPreview
+	{
+		preview.Name = name
+
+		i := &cloudformation.DescribeChangeSetInput{
+			ChangeSetName: aws.String(name),
+			StackName:     aws.String(key.MainGuestStackName(cr)),
+		}
+
+		for {
+			o, err := cc.Client.TenantCluster.AWS.CloudFormation.DescribeChangeSet(i)
+			if err != nil {
+				return "", false, microerror.Mask(err)
+			}
+
+			if *o.Status == cloudformation.ChangeSetStatusFailed {
+				reason := aws.StringValue(o.StatusReason)
+
+				if isNoChangesReason(reason) {
+					r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("change set %#q does not contain changes", name))
+
+					err = r.deleteChangeSet(ctx, cr, name)
+					if err != nil {
+						return "", false, microerror.Mask(err)
+					}
+
+					return "", false, nil
+				}
+
+				return "", false, microerror.Maskf(executionFailedError, "change set %#q failed: %s", name, reason)
+			}
+
+			for _, c := range o.Changes {
+				if c.ResourceChange == nil {
+					continue
+				}
+
+				preview.Changes = append(preview.Changes, key.ChangeSetPreviewChange{
+					Action:            aws.StringValue(c.ResourceChange.Action),
+					LogicalResourceID: aws.StringValue(c.ResourceChange.LogicalResourceId),
+					Replacement:       aws.StringValue(c.ResourceChange.Replacement),
+					ResourceType:      aws.StringValue(c.ResourceChange.ResourceType),
+				})
+			}
+
+			if o.NextToken == nil {
+				break
+			}
+			i.NextToken = o.NextToken
+		}
+	}
+
+	for _, c := range preview.Changes {
+		if c.Replacement == cloudformation.ReplacementTrue {
+			r.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("change set %#q replaces resource %#q of type %#q", name, c.LogicalResourceID, c.ResourceType))
+		}
+	}
+
+	if created {
+		err = r.createPreviewEvent(ctx, cr, preview)
+		if err != nil {
+			return "", false, microerror.Mask(err)
+		}
+	}
+
+	if key.ChangeSetApprovalRequired(cr) && !key.ChangeSetApproved(cr, name) {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("change set %#q is not approved", name))
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("set annotation %#q to %#q to approve it", key.ChangeSetApprovedAnnotation, name))
+		return name, false, nil
+	}
+
+	return name, true, nil
+}
+
+func (r *Resource) createChangeSet(ctx context.Context, cr v1alpha1.AWSConfig, name string, templateBody string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("creating change set %#q", name))
+
+	i := &cloudformation.CreateChangeSetInput{
+		Capabilities: []*string{
+			// CAPABILITY_NAMED_IAM is required for updating worker policy IAM
+			// roles.
+			aws.String(namedIAMCapability),
+		},
+		ChangeSetName: aws.String(name),
+		ChangeSetType: aws.String(cloudformation.ChangeSetTypeUpdate),
+		Description:   aws.String(fmt.Sprintf("Update to version bundle version %s.", key.VersionBundleVersion(cr))),
+		Parameters: []*cloudformation.Parameter{
+			{
+				ParameterKey:   aws.String(versionBundleVersionParameterKey),
+				ParameterValue: aws.String(key.VersionBundleVersion(cr)),
+			},
+		},
+		StackName:    aws.String(key.MainGuestStackName(cr)),
+		TemplateBody: aws.String(templateBody),
+	}
+
+	_, err = cc.Client.TenantCluster.AWS.CloudFormation.CreateChangeSet(i)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("created change set %#q", name))
+
+	return nil
+}
+
+func (r *Resource) deleteChangeSet(ctx context.Context, cr v1alpha1.AWSConfig, name string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleting change set %#q", name))
+
+	i := &cloudformation.DeleteChangeSetInput{
+		ChangeSetName: aws.String(name),
+		StackName:     aws.String(key.MainGuestStackName(cr)),
+	}
+
+	_, err = cc.Client.TenantCluster.AWS.CloudFormation.DeleteChangeSet(i)
+	if IsNotExists(err) {
+		// fall through
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("deleted change set %#q", name))
+
+	return nil
+}
+
+// deleteStaleChangeSets deletes all change sets the operator created for the
+// tenant cluster's control plane cloud formation stack, except the one with
+// the given name. Change sets become stale when the desired template changes
+// before they got executed.
+func (r *Resource) deleteStaleChangeSets(ctx context.Context, cr v1alpha1.AWSConfig, name string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var stale []string
+	{
+		i := &cloudformation.ListChangeSetsInput{
+			StackName: aws.String(key.MainGuestStackName(cr)),
+		}
+
+		for {
+			o, err := cc.Client.TenantCluster.AWS.CloudFormation.ListChangeSets(i)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			for _, s := range o.Summaries {
+				n := aws.StringValue(s.ChangeSetName)
+				if strings.HasPrefix(n, key.ChangeSetNamePrefix) && n != name {
+					stale = append(stale, n)
+				}
+			}
+
+			if o.NextToken == nil {
+				break
+			}
+			i.NextToken = o.NextToken
+		}
+	}
+
+	for _, n := range stale {
+		err := r.deleteChangeSet(ctx, cr, n)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// createPreviewEvent records the given change set preview as event of the CR.
+// The event is a warning in case the change set replaces resources.
+func (r *Resource) createPreviewEvent(ctx context.Context, cr v1alpha1.AWSConfig, preview key.ChangeSetPreview) error {
+	b, err := json.Marshal(preview)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	eventType := corev1.EventTypeNormal
+	for _, c := range preview.Changes {
+		if c.Replacement == cloudformation.ReplacementTrue {
+			eventType = corev1.EventTypeWarning
+		}
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("creating event for change set %#q", preview.Name))
+
+	now := metav1.Now()
+	e := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: cr.GetName() + ".",
+			Namespace:    cr.GetNamespace(),
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      v1alpha1.SchemeGroupVersion.String(),
+			Kind:            "AWSConfig",
+			Name:            cr.GetName(),
+			Namespace:       cr.GetNamespace(),
+			ResourceVersion: cr.GetResourceVersion(),
+			UID:             cr.GetUID(),
+		},
+		Count:          1,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Message:        string(b),
+		Reason:         key.ChangeSetPreviewEventReason,
+		Source: corev1.EventSource{
+			Component: Name,
+		},
+		Type: eventType,
+	}
+
+	_, err = r.k8sClient.CoreV1().Events(cr.GetNamespace()).Create(e)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("created event for change set %#q", preview.Name))
+
+	return nil
+}
+
+func (r *Resource) executeChangeSet(ctx context.Context, cr v1alpha1.AWSConfig, name string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("executing change set %#q", name))
+
+	i := &cloudformation.ExecuteChangeSetInput{
+		ChangeSetName: aws.String(name),
+		StackName:     aws.String(key.MainGuestStackName(cr)),
+	}
+
+	_, err = cc.Client.TenantCluster.AWS.CloudFormation.ExecuteChangeSet(i)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("executed change set %#q", name))
+
+	return nil
+}
+
+// isNoChangesReason checks if the given status reason of a failed change set
+// means that the template did not change anything. The status reason looks
+// like the following example.
+//
+//     The submitted information didn't contain changes. Submit different information to create a change set.
+//
+func isNoChangesReason(reason string) bool {
+	return strings.Contains(reason, "didn't contain changes") || strings.Contains(reason, "No updates are to be performed")
+}
+
+func isWaiterResourceNotReady(err error) bool {
+	aerr, ok := microerror.Cause(err).(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	return aerr.Code() == request.WaiterResourceNotReadyErrorCode
+}
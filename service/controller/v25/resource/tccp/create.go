@@ -2,6 +2,7 @@ package tccp
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
@@ -86,8 +87,19 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		}
 
 		r.logger.LogCtx(ctx, "level", "debug", "message", "found the tenant cluster's control plane cloud formation stack")
+
+		err = r.ensureTerminationProtection(ctx, cr, aws.BoolValue(o.Stacks[0].EnableTerminationProtection))
+		if err != nil {
+			return microerror.Mask(err)
+		}
 	}
 
+	// The number of masters is only applied when the TCCP cloud formation stack
+	// gets created. Changing it afterwards would require changing the etcd
+	// cluster membership, which is not supported. We therefore keep the number
+	// of masters the stack was created with.
+	r.warnIgnoredMasterCount(ctx, cr, cc.Status.TenantCluster.MasterInstance.EtcdMemberCount)
+
 	{
 		update, err := r.detection.ShouldUpdate(ctx, cr)
 		if err != nil {
@@ -136,11 +148,34 @@ func (r *Resource) createStack(ctx context.Context, cr v1alpha1.AWSConfig) error
 		}
 	}
 
+	// The TCCP cloud formation stack is always created with termination
+	// protection. The deletion protection of the tenant cluster can only add
+	// to it.
+	protected, err := key.DeletionProtection(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Tenant clusters have an odd number of etcd members to keep quorum. Even
+	// numbers of masters only occur temporarily during rolling master updates.
+	if key.MasterReplicas(cr)%2 == 0 {
+		return microerror.Maskf(invalidConfigError, "master count must be odd for etcd to keep quorum, got %d", key.MasterReplicas(cr))
+	}
+
 	var templateBody string
 	{
+		masters, err := newDesiredMasters(cr, key.MasterReplicas(cr), r.ebsEncryptionKey, nil)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		etcdVolumeEncryption, err := key.VolumeEncryption(cr, r.ebsEncryptionKey)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
 		tp := templateParams{
-			MasterInstanceResourceName: key.MasterInstanceResourceName(cr),
-			DockerVolumeResourceName:   key.DockerVolumeResourceName(cr),
+			EtcdVolumeEncryption: etcdVolumeEncryption,
+			Masters:              masters,
 		}
 
 		templateBody, err = r.newTemplateBody(ctx, cr, tp)
@@ -157,7 +192,7 @@ func (r *Resource) createStack(ctx context.Context, cr v1alpha1.AWSConfig) error
 			Capabilities: []*string{
 				aws.String(namedIAMCapability),
 			},
-			EnableTerminationProtection: aws.Bool(key.EnableTerminationProtection),
+			EnableTerminationProtection: aws.Bool(key.EnableTerminationProtection || protected),
 			Parameters: []*cloudformation.Parameter{
 				{
 					ParameterKey:   aws.String(versionBundleVersionParameterKey),
@@ -180,7 +215,10 @@ func (r *Resource) createStack(ctx context.Context, cr v1alpha1.AWSConfig) error
 	return nil
 }
 
-func (r *Resource) detachVolumes(ctx context.Context, cr v1alpha1.AWSConfig) error {
+// detachVolumes detaches the docker and etcd volumes of the master instance
+// with the given ID. In case the given instance ID is empty, the volumes of all
+// master instances are detached.
+func (r *Resource) detachVolumes(ctx context.Context, cr v1alpha1.AWSConfig, instanceID string) error {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
 		return microerror.Mask(err)
@@ -218,6 +256,10 @@ func (r *Resource) detachVolumes(ctx context.Context, cr v1alpha1.AWSConfig) err
 
 		for _, v := range volumes {
 			for _, a := range v.Attachments {
+				if instanceID != "" && a.InstanceID != instanceID {
+					continue
+				}
+
 				err := ebsService.DetachVolume(ctx, v.VolumeID, a, force, shutdown, wait)
 				if err != nil {
 					return microerror.Mask(err)
@@ -229,39 +271,68 @@ func (r *Resource) detachVolumes(ctx context.Context, cr v1alpha1.AWSConfig) err
 	return nil
 }
 
+// ensureStack applies the given template body to the tenant cluster's control
+// plane cloud formation stack by means of a change set. The change set is only
+// executed once it is ready. See ensureChangeSet.
 func (r *Resource) ensureStack(ctx context.Context, cr v1alpha1.AWSConfig, templateBody string) error {
+	r.logger.LogCtx(ctx, "level", "debug", "message", "ensuring the tenant cluster's control plane cloud formation stack")
+
+	name, ready, err := r.ensureChangeSet(ctx, cr, templateBody)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !ready {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "did not ensure the tenant cluster's control plane cloud formation stack")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
+	err = r.executeChangeSet(ctx, cr, name)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "ensured the tenant cluster's control plane cloud formation stack")
+
+	return nil
+}
+
+// ensureTerminationProtection enables the termination protection of the TCCP
+// cloud formation stack in case it got disabled, e.g. by a deletion which got
+// refused because of the deletion protection of the tenant cluster. The
+// termination protection is never disabled here, only the delete path does
+// that right before deleting the stack.
+func (r *Resource) ensureTerminationProtection(ctx context.Context, cr v1alpha1.AWSConfig, current bool) error {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	{
-		r.logger.LogCtx(ctx, "level", "debug", "message", "ensuring the tenant cluster's control plane cloud formation stack")
+	protected, err := key.DeletionProtection(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
 
-		i := &cloudformation.UpdateStackInput{
-			Capabilities: []*string{
-				// CAPABILITY_NAMED_IAM is required for updating worker policy IAM
-				// roles.
-				aws.String(namedIAMCapability),
-			},
-			Parameters: []*cloudformation.Parameter{
-				{
-					ParameterKey:   aws.String(versionBundleVersionParameterKey),
-					ParameterValue: aws.String(key.VersionBundleVersion(cr)),
-				},
-			},
-			StackName:    aws.String(key.MainGuestStackName(cr)),
-			TemplateBody: aws.String(templateBody),
-		}
+	desired := key.EnableTerminationProtection || protected
+	if current || !desired {
+		return nil
+	}
 
-		_, err = cc.Client.TenantCluster.AWS.CloudFormation.UpdateStack(i)
-		if err != nil {
-			return microerror.Mask(err)
-		}
+	r.logger.LogCtx(ctx, "level", "debug", "message", "enabling the termination protection of the tenant cluster's control plane cloud formation stack")
 
-		r.logger.LogCtx(ctx, "level", "debug", "message", "ensured the tenant cluster's control plane cloud formation stack")
+	i := &cloudformation.UpdateTerminationProtectionInput{
+		EnableTerminationProtection: aws.Bool(true),
+		StackName:                   aws.String(key.MainGuestStackName(cr)),
 	}
 
+	_, err = cc.Client.TenantCluster.AWS.CloudFormation.UpdateTerminationProtection(i)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", "enabled the termination protection of the tenant cluster's control plane cloud formation stack")
+
 	return nil
 }
 
@@ -279,6 +350,64 @@ func (r *Resource) newTemplateBody(ctx context.Context, cr v1alpha1.AWSConfig, t
 	if err != nil {
 		return "", microerror.Mask(err)
 	}
+	etcdRestoreSnapshot, err := key.EtcdRestoreSnapshot(cr)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+	rotation, err := key.EncryptionKeyRotationStatus(cr)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+	volumeEncryption, err := key.VolumeEncryption(cr, r.ebsEncryptionKey)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	// The etcd volumes keep the encryption they were created with, since
+	// changing it would replace them and lose the etcd data. The unencrypted
+	// etcd volumes of tenant clusters created before all volumes got encrypted
+	// are the exception when restoring an etcd backup, because all masters get
+	// replaced and take their etcd data from the backup then.
+	etcdVolumeEncryption := tp.EtcdVolumeEncryption
+	if etcdVolumeEncryption == "" {
+		etcdVolumeEncryption = cc.Status.TenantCluster.MasterInstance.EtcdVolumeEncryption
+	}
+	if etcdVolumeEncryption == "" {
+		restore := etcdRestoreSnapshot != "" && etcdRestoreSnapshot != cc.Status.TenantCluster.MasterInstance.EtcdRestoreSnapshot
+
+		if restore {
+			etcdVolumeEncryption = volumeEncryption
+		} else if r.encrypterBackend != encrypter.KMSBackend {
+			r.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("the etcd volumes of the masters are not encrypted, restore an etcd backup via annotation %#q to recreate them encrypted", key.EtcdRestoreSnapshotAnnotation))
+		}
+	}
+
+	// The encryption key of the SSM encrypter is a parameter path and not a KMS
+	// key, which is why the nodes do not get any KMS permissions then.
+	var kmsKeyARN string
+	if r.encrypterBackend != encrypter.SSMBackend {
+		kmsKeyARN = cc.Status.TenantCluster.Encryption.Key
+	}
+
+	// The masters keep decrypting secrets with the previous KMS key until the
+	// encryption key rotation completed.
+	var previousKMSKeyARN string
+	if r.encrypterBackend == encrypter.KMSBackend && rotation.Phase == key.EncryptionKeyRotationPhaseKeyRotated {
+		previousKMSKeyARN = rotation.PreviousKey
+	}
+
+	workerPoolsDesired := map[string]int{}
+	for _, p := range cc.Status.TenantCluster.TCCP.WorkerPools {
+		workerPoolsDesired[p.Name] = p.ASG.DesiredCapacity
+	}
+
+	// Tenant clusters which are already peered with the control plane VPC keep
+	// their peering connection when a transit gateway gets configured, because
+	// the routes of the control plane finalizer stack are never updated.
+	transitGatewayID := r.transitGatewayID
+	if cc.Status.TenantCluster.TCCP.VPC.PeeringConnectionID != "" {
+		transitGatewayID = ""
+	}
 
 	var templateBody string
 	{
@@ -296,11 +425,13 @@ func (r *Resource) newTemplateBody(ctx context.Context, cr v1alpha1.AWSConfig, t
 			StackState: adapter.StackState{
 				Name: key.MainGuestStackName(cr),
 
-				DockerVolumeResourceName:   tp.DockerVolumeResourceName,
-				MasterImageID:              im,
-				MasterInstanceResourceName: tp.MasterInstanceResourceName,
-				MasterInstanceType:         key.MasterInstanceType(cr),
+				EncryptionKeyRotation: key.EncryptionKeyGeneration(cr),
+
+				Masters:                    tp.Masters,
+				MasterEtcdMemberCount:      tp.EtcdMemberCount,
 				MasterCloudConfigVersion:   key.CloudConfigVersion,
+				MasterEtcdRestoreSnapshot:  etcdRestoreSnapshot,
+				MasterEtcdVolumeEncryption: etcdVolumeEncryption,
 				MasterInstanceMonitoring:   r.instanceMonitoring,
 
 				WorkerCloudConfigVersion: key.CloudConfigVersion,
@@ -314,11 +445,16 @@ func (r *Resource) newTemplateBody(ctx context.Context, cr v1alpha1.AWSConfig, t
 				WorkerInstanceType:        key.WorkerInstanceType(cr),
 				WorkerMax:                 cc.Status.TenantCluster.TCCP.ASG.MaxSize,
 				WorkerMin:                 cc.Status.TenantCluster.TCCP.ASG.MinSize,
+				WorkerPoolsDesired:        workerPoolsDesired,
+				WorkerVolumeEncryption:    volumeEncryption,
 
 				VersionBundleVersion: key.VersionBundleVersion(cr),
 			},
-			TenantClusterAccountID: cc.Status.TenantCluster.AWSAccountID,
-			TenantClusterKMSKeyARN: cc.Status.TenantCluster.Encryption.Key,
+			TenantClusterAccountID:         cc.Status.TenantCluster.AWSAccountID,
+			TenantClusterKMSKeyARN:         kmsKeyARN,
+			TenantClusterPreviousKMSKeyARN: previousKMSKeyARN,
+			TransitGatewayID:               transitGatewayID,
+			VPCEndpoints:                   r.vpcEndpoints,
 		}
 
 		a, err := adapter.NewGuest(c)
@@ -342,8 +478,8 @@ func (r *Resource) scaleStack(ctx context.Context, cr v1alpha1.AWSConfig) error
 	}
 
 	tp := templateParams{
-		MasterInstanceResourceName: cc.Status.TenantCluster.MasterInstance.ResourceName,
-		DockerVolumeResourceName:   cc.Status.TenantCluster.MasterInstance.DockerVolumeResourceName,
+		EtcdMemberCount: cc.Status.TenantCluster.MasterInstance.EtcdMemberCount,
+		Masters:         newCurrentMasters(cc),
 	}
 
 	templateBody, err := r.newTemplateBody(ctx, cr, tp)
@@ -360,9 +496,56 @@ func (r *Resource) scaleStack(ctx context.Context, cr v1alpha1.AWSConfig) error
 }
 
 func (r *Resource) updateStack(ctx context.Context, cr v1alpha1.AWSConfig) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	etcdRestoreSnapshot, err := key.EtcdRestoreSnapshot(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Restoring etcd from a backup requires all members to start from the same
+	// data. Masters are therefore never replaced one after another in this
+	// case.
+	restore := etcdRestoreSnapshot != "" && etcdRestoreSnapshot != cc.Status.TenantCluster.MasterInstance.EtcdRestoreSnapshot
+
+	// The previous encryption key is retired as soon as the stack reports the
+	// current encryption key rotation. Masters are therefore never replaced one
+	// after another while the encryption key is rotated, since the stack
+	// reports the rotation already after replacing the first master.
+	rotation := cc.Status.TenantCluster.Encryption.Rotation != key.EncryptionKeyGeneration(cr)
+
+	if key.MasterUpdateStrategy(cr) == key.MasterUpdateStrategyRolling && restore {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("restoring etcd backup %#q", etcdRestoreSnapshot))
+		r.logger.LogCtx(ctx, "level", "debug", "message", "replacing all masters at once")
+	} else if key.MasterUpdateStrategy(cr) == key.MasterUpdateStrategyRolling && rotation {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("rotating encryption key for rotation %#q", key.EncryptionKeyGeneration(cr)))
+		r.logger.LogCtx(ctx, "level", "debug", "message", "replacing all masters at once")
+	} else if key.MasterUpdateStrategy(cr) == key.MasterUpdateStrategyRolling {
+		if len(cc.Status.TenantCluster.Masters) >= minRollingUpdateMasters {
+			err = r.rollingUpdateStack(ctx, cr)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			return nil
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("rolling master updates require at least %d masters", minRollingUpdateMasters))
+		r.logger.LogCtx(ctx, "level", "debug", "message", "replacing all masters at once")
+	}
+
+	// Replacing all masters at once also removes the surge master of an
+	// interrupted rolling master update.
+	masters, err := newDesiredMasters(cr, key.EtcdMemberCount(cr, cc.Status.TenantCluster.MasterInstance.EtcdMemberCount), r.ebsEncryptionKey, newCurrentMasters(cc))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
 	tp := templateParams{
-		MasterInstanceResourceName: key.MasterInstanceResourceName(cr),
-		DockerVolumeResourceName:   key.DockerVolumeResourceName(cr),
+		Masters: masters,
 	}
 
 	templateBody, err := r.newTemplateBody(ctx, cr, tp)
@@ -370,20 +553,136 @@ func (r *Resource) updateStack(ctx context.Context, cr v1alpha1.AWSConfig) error
 		return microerror.Mask(err)
 	}
 
-	err = r.detachVolumes(ctx, cr)
+	// Updating the stack terminates all masters. We therefore only do so once
+	// the change set is ready to be executed, which gives humans the chance to
+	// review the replacements before any master is gone.
+	name, ready, err := r.ensureChangeSet(ctx, cr, templateBody)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = r.terminateMasterInstance(ctx, cr)
+	if !ready {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "not updating the tenant cluster's control plane cloud formation stack yet")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
+	err = r.detachVolumes(ctx, cr, "")
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = r.ensureStack(ctx, cr, templateBody)
+	err = r.terminateMasterInstances(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = r.executeChangeSet(ctx, cr, name)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
 	return nil
 }
+
+// warnIgnoredMasterCount logs a warning in case the master count of the spec
+// differs from the given etcd member count of the tenant cluster's control
+// plane cloud formation stack. The warning is only logged once per master
+// count of the spec, so that it does not flood the logs on every
+// reconciliation.
+func (r *Resource) warnIgnoredMasterCount(ctx context.Context, cr v1alpha1.AWSConfig, etcdMemberCount int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	desired := key.MasterReplicas(cr)
+
+	if desired == etcdMemberCount {
+		delete(r.ignoredMasterCounts, key.ClusterID(cr))
+		return
+	}
+
+	ignored, ok := r.ignoredMasterCounts[key.ClusterID(cr)]
+	if ok && ignored == desired {
+		return
+	}
+
+	r.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("ignoring master count changes from %d to %d for the tenant cluster's control plane", etcdMemberCount, desired))
+
+	r.ignoredMasterCounts[key.ClusterID(cr)] = desired
+}
+
+// newCurrentMasters returns the masters as they are currently defined in the
+// tenant cluster's control plane cloud formation stack.
+func newCurrentMasters(cc *controllercontext.Context) []adapter.StackStateMaster {
+	var masters []adapter.StackStateMaster
+
+	for _, m := range cc.Status.TenantCluster.Masters {
+		masters = append(masters, adapter.StackStateMaster{
+			DockerVolumeResourceName: m.DockerVolumeResourceName,
+			ImageID:                  m.Image,
+			InstanceResourceName:     m.ResourceName,
+			InstanceType:             m.Type,
+			VersionBundleVersion:     m.VersionBundleVersion,
+			VolumeEncryption:         m.VolumeEncryption,
+		})
+	}
+
+	return masters
+}
+
+// newDesiredMasters returns the given number of masters as defined by the
+// custom object, replacing the given current masters. All masters get new
+// resource names, which causes Cloud Formation to replace them. The names are
+// derived from the ones of the current masters, so that the change set
+// replacing them stays the same until it got executed. The volumes of the
+// masters are encrypted with the KMS key of the tenant cluster, falling back
+// to the given key of the installation.
+func newDesiredMasters(cr v1alpha1.AWSConfig, count int, ebsEncryptionKey string, current []adapter.StackStateMaster) ([]adapter.StackStateMaster, error) {
+	im, err := key.ImageID(cr)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	volumeEncryption, err := key.VolumeEncryption(cr, ebsEncryptionKey)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	dockerVolumeResourceName := key.DockerVolumeResourceName(cr)
+	instanceResourceName := key.MasterInstanceResourceName(cr)
+	if len(current) > 0 {
+		dockerVolumeResourceName, instanceResourceName = replacingResourceNames(cr, current...)
+	}
+
+	var masters []adapter.StackStateMaster
+	for i := 0; i < count; i++ {
+		masters = append(masters, newDesiredMaster(cr, i, im, volumeEncryption, dockerVolumeResourceName, instanceResourceName))
+	}
+
+	return masters, nil
+}
+
+func newDesiredMaster(cr v1alpha1.AWSConfig, idx int, imageID, volumeEncryption, dockerVolumeResourceName, instanceResourceName string) adapter.StackStateMaster {
+	m := adapter.StackStateMaster{
+		DockerVolumeResourceName: key.MasterDockerVolumeResourceName(dockerVolumeResourceName, idx),
+		ImageID:                  imageID,
+		InstanceResourceName:     key.MasterInstanceResourceNameByIndex(instanceResourceName, idx),
+		InstanceType:             key.MasterInstanceTypeByIndex(cr, idx),
+		VersionBundleVersion:     key.VersionBundleVersion(cr),
+		VolumeEncryption:         volumeEncryption,
+	}
+
+	return m
+}
+
+// replacingResourceNames returns the docker volume and instance resource names
+// of the masters replacing the given masters.
+func replacingResourceNames(cr v1alpha1.AWSConfig, replaced ...adapter.StackStateMaster) (string, string) {
+	var dockerVolumeResourceNames []string
+	var instanceResourceNames []string
+	for _, m := range replaced {
+		dockerVolumeResourceNames = append(dockerVolumeResourceNames, m.DockerVolumeResourceName)
+		instanceResourceNames = append(instanceResourceNames, m.InstanceResourceName)
+	}
+
+	return key.DockerVolumeResourceNameReplacing(cr, dockerVolumeResourceNames...), key.MasterInstanceResourceNameReplacing(cr, instanceResourceNames...)
+}
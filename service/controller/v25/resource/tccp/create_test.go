@@ -0,0 +1,161 @@
+package tccp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/micrologger/microloggertest"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/encrypter"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+var (
+	defaultCluster = v1alpha1.Cluster{
+		Etcd: v1alpha1.ClusterEtcd{
+			Domain: "etcd.domain",
+		},
+		ID: "test-cluster",
+		Kubernetes: v1alpha1.ClusterKubernetes{
+			API: v1alpha1.ClusterKubernetesAPI{
+				Domain: "api.domain",
+			},
+			IngressController: v1alpha1.ClusterKubernetesIngressController{
+				Domain: "ingress.domain",
+			},
+		},
+		Scaling: v1alpha1.ClusterScaling{
+			Max: 1,
+			Min: 1,
+		},
+	}
+)
+
+func newTestCustomObject(masters int) v1alpha1.AWSConfig {
+	cr := v1alpha1.AWSConfig{
+		Spec: v1alpha1.AWSConfigSpec{
+			Cluster: defaultCluster,
+			AWS: v1alpha1.AWSConfigSpecAWS{
+				AZ:     "eu-central-1a",
+				Region: "eu-central-1",
+				Workers: []v1alpha1.AWSConfigSpecAWSNode{
+					{
+						InstanceType: "m5.large",
+					},
+				},
+			},
+		},
+		Status: v1alpha1.AWSConfigStatus{
+			AWS: v1alpha1.AWSConfigStatusAWS{
+				AvailabilityZones: []v1alpha1.AWSConfigStatusAWSAvailabilityZone{
+					{
+						Name: "eu-central-1a",
+						Subnet: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnet{
+							Private: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPrivate{
+								CIDR: "10.1.4.0/25",
+							},
+							Public: v1alpha1.AWSConfigStatusAWSAvailabilityZoneSubnetPublic{
+								CIDR: "10.1.4.128/25",
+							},
+						},
+					},
+				},
+			},
+			Cluster: v1alpha1.StatusCluster{
+				Network: v1alpha1.StatusClusterNetwork{
+					CIDR: "10.1.4.0/24",
+				},
+			},
+		},
+	}
+
+	for i := 0; i < masters; i++ {
+		cr.Spec.AWS.Masters = append(cr.Spec.AWS.Masters, v1alpha1.AWSConfigSpecAWSNode{
+			InstanceType: "m5.xlarge",
+		})
+	}
+
+	return cr
+}
+
+func newTestContext(masters []controllercontext.ContextStatusTenantClusterMaster) context.Context {
+	cc := controllercontext.Context{}
+	cc.Status.ControlPlane.AWSAccountID = "123456789012"
+	cc.Status.TenantCluster.AWSAccountID = "123456789012"
+	cc.Status.TenantCluster.Encryption.Key = "arn:aws:kms:eu-central-1:123456789012:key/test"
+	cc.Status.TenantCluster.MasterInstance.Count = len(masters)
+	cc.Status.TenantCluster.Masters = masters
+
+	return controllercontext.NewContext(context.Background(), cc)
+}
+
+func newTestResource() *Resource {
+	r := &Resource{
+		logger: microloggertest.New(),
+
+		encrypterBackend: encrypter.KMSBackend,
+		installationName: "test-installation",
+
+		ignoredMasterCounts: map[string]int{},
+	}
+
+	return r
+}
+
+// Test_Resource_ChangeSetName_Stable ensures the change set replacing all
+// masters is the same in every reconciliation as long as the spec does not
+// change, so that it can be approved.
+func Test_Resource_ChangeSetName_Stable(t *testing.T) {
+	cr := newTestCustomObject(3)
+	r := newTestResource()
+
+	ctx := newTestContext([]controllercontext.ContextStatusTenantClusterMaster{
+		{DockerVolumeResourceName: "DockerVolumeTESTCLUSTERAAAAA", Image: "ami-old", ResourceName: "MasterInstanceTESTCLUSTERAAAAA", Type: "m5.large", VersionBundleVersion: "1.0.0"},
+		{DockerVolumeResourceName: "DockerVolumeTESTCLUSTERAAAAA01", Image: "ami-old", ResourceName: "MasterInstanceTESTCLUSTERAAAAA01", Type: "m5.large", VersionBundleVersion: "1.0.0"},
+		{DockerVolumeResourceName: "DockerVolumeTESTCLUSTERAAAAA02", Image: "ami-old", ResourceName: "MasterInstanceTESTCLUSTERAAAAA02", Type: "m5.large", VersionBundleVersion: "1.0.0"},
+	})
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	var names []string
+	for i := 0; i < 2; i++ {
+		masters, err := newDesiredMasters(cr, key.EtcdMemberCount(cr, len(cc.Status.TenantCluster.Masters)), r.ebsEncryptionKey, newCurrentMasters(cc))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		for j, m := range masters {
+			if m.InstanceResourceName == cc.Status.TenantCluster.Masters[j].ResourceName {
+				t.Fatalf("expected master %d to get a new instance resource name, got %#q", j, m.InstanceResourceName)
+			}
+			if m.DockerVolumeResourceName == cc.Status.TenantCluster.Masters[j].DockerVolumeResourceName {
+				t.Fatalf("expected master %d to get a new docker volume resource name, got %#q", j, m.DockerVolumeResourceName)
+			}
+		}
+
+		templateBody, err := r.newTemplateBody(ctx, cr, templateParams{Masters: masters})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		names = append(names, key.ChangeSetName(cr, templateBody))
+	}
+
+	if names[0] != names[1] {
+		t.Fatalf("expected change set name %#q, got %#q", names[0], names[1])
+	}
+}
+
+func Test_Resource_createStack_EvenMasterCount(t *testing.T) {
+	cr := newTestCustomObject(4)
+	r := newTestResource()
+
+	err := r.createStack(newTestContext(nil), cr)
+	if !IsInvalidConfig(err) {
+		t.Fatalf("expected invalid config error, got %#v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package tccp
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+)
+
+type EC2ClientMock struct {
+	ec2iface.EC2API
+
+	// instances maps master indexes to the IDs of their instances.
+	instances map[string]string
+	volumes   []VolumeMock
+
+	calls []string
+}
+
+type VolumeMock struct {
+	instanceID string
+	name       string
+	volumeID   string
+}
+
+func (e *EC2ClientMock) DescribeInstances(i *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	o := &ec2.DescribeInstancesOutput{}
+
+	for _, f := range i.Filters {
+		if aws.StringValue(f.Name) != "tag:giantswarm.io/master-index" {
+			continue
+		}
+
+		for _, v := range f.Values {
+			id, ok := e.instances[aws.StringValue(v)]
+			if !ok {
+				continue
+			}
+
+			o.Reservations = append(o.Reservations, &ec2.Reservation{
+				Instances: []*ec2.Instance{
+					{
+						InstanceId: aws.String(id),
+					},
+				},
+			})
+		}
+	}
+
+	return o, nil
+}
+
+func (e *EC2ClientMock) DescribeVolumes(*ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	o := &ec2.DescribeVolumesOutput{}
+
+	for _, v := range e.volumes {
+		o.Volumes = append(o.Volumes, &ec2.Volume{
+			Attachments: []*ec2.VolumeAttachment{
+				{
+					Device:     aws.String("/dev/xvdh"),
+					InstanceId: aws.String(v.instanceID),
+				},
+			},
+			Tags: []*ec2.Tag{
+				{
+					Key:   aws.String("Name"),
+					Value: aws.String(v.name),
+				},
+			},
+			VolumeId: aws.String(v.volumeID),
+		})
+	}
+
+	return o, nil
+}
+
+func (e *EC2ClientMock) DetachVolume(i *ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error) {
+	e.calls = append(e.calls, fmt.Sprintf("DetachVolume %s %s", aws.StringValue(i.VolumeId), aws.StringValue(i.InstanceId)))
+	return &ec2.VolumeAttachment{}, nil
+}
+
+func (e *EC2ClientMock) ModifyInstanceAttribute(i *ec2.ModifyInstanceAttributeInput) (*ec2.ModifyInstanceAttributeOutput, error) {
+	e.calls = append(e.calls, fmt.Sprintf("ModifyInstanceAttribute %s", aws.StringValue(i.InstanceId)))
+	return &ec2.ModifyInstanceAttributeOutput{}, nil
+}
+
+func (e *EC2ClientMock) StopInstances(i *ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error) {
+	e.calls = append(e.calls, fmt.Sprintf("StopInstances %s", aws.StringValueSlice(i.InstanceIds)))
+	return &ec2.StopInstancesOutput{}, nil
+}
+
+func (e *EC2ClientMock) TerminateInstances(i *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	e.calls = append(e.calls, fmt.Sprintf("TerminateInstances %s", aws.StringValueSlice(i.InstanceIds)))
+	return &ec2.TerminateInstancesOutput{}, nil
+}
+
+func (e *EC2ClientMock) WaitUntilInstanceStopped(i *ec2.DescribeInstancesInput) error {
+	e.calls = append(e.calls, fmt.Sprintf("WaitUntilInstanceStopped %s", aws.StringValueSlice(i.InstanceIds)))
+	return nil
+}
+
+func (e *EC2ClientMock) WaitUntilInstanceTerminated(i *ec2.DescribeInstancesInput) error {
+	e.calls = append(e.calls, fmt.Sprintf("WaitUntilInstanceTerminated %s", aws.StringValueSlice(i.InstanceIds)))
+	return nil
+}
+
+type ELBClientMock struct {
+	elbiface.ELBAPI
+
+	// inService maps load balancer names to the number of instances in
+	// service behind them.
+	inService map[string]int
+}
+
+func (e *ELBClientMock) DescribeInstanceHealth(i *elb.DescribeInstanceHealthInput) (*elb.DescribeInstanceHealthOutput, error) {
+	o := &elb.DescribeInstanceHealthOutput{
+		InstanceStates: []*elb.InstanceState{
+			{
+				State: aws.String("OutOfService"),
+			},
+		},
+	}
+
+	for n := 0; n < e.inService[aws.StringValue(i.LoadBalancerName)]; n++ {
+		o.InstanceStates = append(o.InstanceStates, &elb.InstanceState{
+			State: aws.String("InService"),
+		})
+	}
+
+	return o, nil
+}
+
+type ELBV2ClientMock struct {
+	elbv2iface.ELBV2API
+
+	// healthy maps target group ARNs to the number of healthy targets in them.
+	// Every load balancer has two target groups whose ARNs are the name of the
+	// load balancer suffixed with the ports 443 and 2379.
+	healthy map[string]int
+}
+
+func (e *ELBV2ClientMock) DescribeLoadBalancers(i *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+	o := &elbv2.DescribeLoadBalancersOutput{}
+
+	for _, n := range i.Names {
+		o.LoadBalancers = append(o.LoadBalancers, &elbv2.LoadBalancer{
+			LoadBalancerArn:  n,
+			LoadBalancerName: n,
+		})
+	}
+
+	return o, nil
+}
+
+func (e *ELBV2ClientMock) DescribeTargetGroups(i *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+	o := &elbv2.DescribeTargetGroupsOutput{}
+
+	for _, p := range []string{"443", "2379"} {
+		arn := fmt.Sprintf("%s-%s", aws.StringValue(i.LoadBalancerArn), p)
+
+		o.TargetGroups = append(o.TargetGroups, &elbv2.TargetGroup{
+			TargetGroupArn:  aws.String(arn),
+			TargetGroupName: aws.String(arn),
+		})
+	}
+
+	return o, nil
+}
+
+func (e *ELBV2ClientMock) DescribeTargetHealth(i *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+	o := &elbv2.DescribeTargetHealthOutput{
+		TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
+			{
+				TargetHealth: &elbv2.TargetHealth{
+					State: aws.String(elbv2.TargetHealthStateEnumUnhealthy),
+				},
+			},
+		},
+	}
+
+	for n := 0; n < e.healthy[aws.StringValue(i.TargetGroupArn)]; n++ {
+		o.TargetHealthDescriptions = append(o.TargetHealthDescriptions, &elbv2.TargetHealthDescription{
+			TargetHealth: &elbv2.TargetHealth{
+				State: aws.String(elbv2.TargetHealthStateEnumHealthy),
+			},
+		})
+	}
+
+	return o, nil
+}
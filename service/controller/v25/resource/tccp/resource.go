@@ -3,12 +3,15 @@ package tccp
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/giantswarm/aws-operator/service/controller/v25/adapter"
 	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
@@ -30,6 +33,10 @@ const (
 	versionBundleVersionParameterKey = "VersionBundleVersionParameter"
 )
 
+// vpcEndpointRegexp matches the names of the AWS services interface endpoints
+// are created for, e.g. ec2 or ecr.api.
+var vpcEndpointRegexp = regexp.MustCompile(`^[a-z0-9]+([.-][a-z0-9]+)*$`)
+
 type AWSConfig struct {
 	AccessKeyID     string
 	AccessKeySecret string
@@ -45,9 +52,14 @@ type Config struct {
 	// EncrypterRoleManager manages role encryption. This can be supported by
 	// different implementations and thus is optional.
 	EncrypterRoleManager encrypter.RoleManager
+	K8sClient            kubernetes.Interface
 	Logger               micrologger.Logger
 
-	Detection                  *detection.Detection
+	Detection *detection.Detection
+	// EBSEncryptionKey is the ARN of the KMS key the EBS volumes of tenant
+	// clusters are encrypted with. The default EBS key of the tenant cluster
+	// account is used when empty.
+	EBSEncryptionKey           string
 	EncrypterBackend           string
 	GuestPrivateSubnetMaskBits int
 	GuestPublicSubnetMaskBits  int
@@ -55,20 +67,37 @@ type Config struct {
 	InstanceMonitoring         bool
 	PublicRouteTables          string
 	Route53Enabled             bool
+	// TransitGatewayID is the ID of the transit gateway new tenant clusters
+	// are attached to instead of being peered with the control plane VPC.
+	TransitGatewayID string
+	// VPCEndpoints are the names of the AWS services, e.g. ec2 or ecr.api, the
+	// tenant cluster VPCs get interface endpoints for.
+	VPCEndpoints []string
 }
 
 // Resource implements the cloudformation resource.
 type Resource struct {
 	apiWhiteList         adapter.APIWhitelist
 	encrypterRoleManager encrypter.RoleManager
+	k8sClient            kubernetes.Interface
 	logger               micrologger.Logger
 
+	ebsEncryptionKey   string
 	encrypterBackend   string
 	detection          *detection.Detection
 	installationName   string
 	instanceMonitoring bool
 	publicRouteTables  string
 	route53Enabled     bool
+	transitGatewayID   string
+	vpcEndpoints       []string
+
+	// mutex guards ignoredMasterCounts.
+	mutex sync.Mutex
+	// ignoredMasterCounts holds the master count of the spec of every tenant
+	// cluster which got ignored last, so that the warning about it is only
+	// logged once per change.
+	ignoredMasterCounts map[string]int
 }
 
 // New creates a new configured cloudformation resource.
@@ -76,6 +105,9 @@ func New(config Config) (*Resource, error) {
 	if config.Detection == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Detection must not be empty", config)
 	}
+	if config.K8sClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.K8sClient must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
@@ -83,18 +115,32 @@ func New(config Config) (*Resource, error) {
 	if config.EncrypterBackend == "" {
 		return nil, microerror.Maskf(invalidConfigError, "%T.EncrypterBackend must not be empty", config)
 	}
+	if config.EBSEncryptionKey != "" && !key.IsEBSEncryptionKey(config.EBSEncryptionKey) {
+		return nil, microerror.Maskf(invalidConfigError, "%T.EBSEncryptionKey must be the ARN of a KMS key, got %#q", config, config.EBSEncryptionKey)
+	}
+	for _, e := range config.VPCEndpoints {
+		if !vpcEndpointRegexp.MatchString(e) {
+			return nil, microerror.Maskf(invalidConfigError, "%T.VPCEndpoints must only contain AWS service names, got %#q", config, e)
+		}
+	}
 
 	r := &Resource{
 		apiWhiteList:         config.APIWhitelist,
 		detection:            config.Detection,
 		encrypterRoleManager: config.EncrypterRoleManager,
+		k8sClient:            config.K8sClient,
 		logger:               config.Logger,
 
+		ebsEncryptionKey:   config.EBSEncryptionKey,
 		encrypterBackend:   config.EncrypterBackend,
 		installationName:   config.InstallationName,
 		instanceMonitoring: config.InstanceMonitoring,
 		publicRouteTables:  config.PublicRouteTables,
 		route53Enabled:     config.Route53Enabled,
+		transitGatewayID:   config.TransitGatewayID,
+		vpcEndpoints:       config.VPCEndpoints,
+
+		ignoredMasterCounts: map[string]int{},
 	}
 
 	return r, nil
@@ -104,11 +150,12 @@ func (r *Resource) Name() string {
 	return Name
 }
 
-// searchMasterInstanceID tries to find any "active" master instance. The method
-// ignores instances that are shutting down or are already terminated. This is
-// because we only need to find the master instance in order to terminate it
-// before updating the TCCP Cloud Formation stack. In case the master instance
-// is already terminated, we ignore it. The used filter name is the following.
+// searchMasterInstanceIDs tries to find all "active" master instances. The
+// method ignores instances that are shutting down or are already terminated.
+// This is because we only need to find the master instances in order to
+// terminate them before updating the TCCP Cloud Formation stack. In case a
+// master instance is already terminated, we ignore it. The used filter name is
+// the following.
 //
 //     instance-state-name
 //
@@ -116,13 +163,16 @@ func (r *Resource) Name() string {
 //
 //     pending, running, stopping, stopped
 //
-func (r *Resource) searchMasterInstanceID(ctx context.Context, cr v1alpha1.AWSConfig) (string, error) {
+// Additional filters can be given to narrow down the master instances found,
+// e.g. to a single master by its index tag.
+//
+func (r *Resource) searchMasterInstanceIDs(ctx context.Context, cr v1alpha1.AWSConfig, filters ...*ec2.Filter) ([]string, error) {
 	cc, err := controllercontext.FromContext(ctx)
 	if err != nil {
-		return "", microerror.Mask(err)
+		return nil, microerror.Mask(err)
 	}
 
-	var instanceID string
+	var instanceIDs []string
 	{
 		i := &ec2.DescribeInstancesInput{
 			Filters: []*ec2.Filter{
@@ -149,49 +199,70 @@ func (r *Resource) searchMasterInstanceID(ctx context.Context, cr v1alpha1.AWSCo
 				},
 			},
 		}
+		i.Filters = append(i.Filters, filters...)
 
 		o, err := cc.Client.TenantCluster.AWS.EC2.DescribeInstances(i)
 		if err != nil {
-			return "", microerror.Mask(err)
+			return nil, microerror.Mask(err)
 		}
 
-		if len(o.Reservations) == 0 {
-			return "", microerror.Maskf(notExistsError, "master instance")
-		}
-		if len(o.Reservations) != 1 {
-			return "", microerror.Maskf(executionFailedError, "expected one master instance, got %d", len(o.Reservations))
-		}
-		if len(o.Reservations[0].Instances) != 1 {
-			return "", microerror.Maskf(executionFailedError, "expected one master instance, got %d", len(o.Reservations[0].Instances))
+		for _, reservation := range o.Reservations {
+			for _, instance := range reservation.Instances {
+				instanceIDs = append(instanceIDs, *instance.InstanceId)
+			}
 		}
 
-		instanceID = *o.Reservations[0].Instances[0].InstanceId
+		if len(instanceIDs) == 0 {
+			return nil, microerror.Maskf(notExistsError, "master instance")
+		}
 	}
 
-	return instanceID, nil
+	return instanceIDs, nil
 }
 
-func (r *Resource) terminateMasterInstance(ctx context.Context, cr v1alpha1.AWSConfig) error {
-	cc, err := controllercontext.FromContext(ctx)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	var instanceID string
+func (r *Resource) terminateMasterInstances(ctx context.Context, cr v1alpha1.AWSConfig) error {
+	var instanceIDs []string
 	{
-		r.logger.LogCtx(ctx, "level", "debug", "message", "finding master instance ID")
+		r.logger.LogCtx(ctx, "level", "debug", "message", "finding master instance IDs")
 
-		instanceID, err = r.searchMasterInstanceID(ctx, cr)
+		var err error
+		instanceIDs, err = r.searchMasterInstanceIDs(ctx, cr)
 		if IsNotExists(err) {
-			r.logger.LogCtx(ctx, "level", "debug", "message", "did not find master instance ID")
-			r.logger.LogCtx(ctx, "level", "debug", "message", "master instance does not exist")
+			r.logger.LogCtx(ctx, "level", "debug", "message", "did not find master instance IDs")
+			r.logger.LogCtx(ctx, "level", "debug", "message", "master instances do not exist")
 			return nil
 
 		} else if err != nil {
 			return microerror.Mask(err)
 		}
 
-		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found master instance ID %#q", instanceID))
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found master instance IDs %#q", instanceIDs))
+	}
+
+	for _, instanceID := range instanceIDs {
+		err := r.terminateMasterInstance(ctx, instanceID)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	// Multiple masters use network interfaces with fixed private IPs. These
+	// interfaces can only be attached to the replacing master instances once
+	// the old master instances are terminated.
+	if len(instanceIDs) > 1 {
+		err := r.waitForMasterInstancesTerminated(ctx, instanceIDs)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Resource) terminateMasterInstance(ctx context.Context, instanceID string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
 	}
 
 	{
@@ -236,3 +307,25 @@ func (r *Resource) terminateMasterInstance(ctx context.Context, cr v1alpha1.AWSC
 
 	return nil
 }
+
+func (r *Resource) waitForMasterInstancesTerminated(ctx context.Context, instanceIDs []string) error {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("waiting for master instances %#q to be terminated", instanceIDs))
+
+	i := &ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice(instanceIDs),
+	}
+
+	err = cc.Client.TenantCluster.AWS.EC2.WaitUntilInstanceTerminated(i)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("waited for master instances %#q to be terminated", instanceIDs))
+
+	return nil
+}
@@ -0,0 +1,441 @@
+package tccp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/adapter"
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+const (
+	// minRollingUpdateMasters is the minimum number of masters required for
+	// rolling master updates. Together with the surge master, the etcd
+	// cluster keeps its quorum while one of the masters is replaced.
+	minRollingUpdateMasters = 3
+)
+
+const (
+	// rollingUpdateActionAddSurge adds the surge master to the stack.
+	rollingUpdateActionAddSurge = "addSurge"
+	// rollingUpdateActionReplace replaces one outdated master.
+	rollingUpdateActionReplace = "replace"
+	// rollingUpdateActionUpdate updates the stack without replacing any
+	// master. The surge master is removed in case there is one.
+	rollingUpdateActionUpdate = "update"
+	// rollingUpdateActionWait waits for all masters to be healthy.
+	rollingUpdateActionWait = "wait"
+)
+
+// rollingUpdateStep is the step of the rolling master update performed in the
+// current reconciliation.
+type rollingUpdateStep struct {
+	// Action is the action to perform, e.g. rollingUpdateActionReplace.
+	Action string
+	// Index is the index of the outdated master, or -1 if all masters are up to
+	// date.
+	Index int
+	// TemplateParams are the template parameters of the desired stack. They are
+	// empty for rollingUpdateActionWait.
+	TemplateParams templateParams
+}
+
+// rollingUpdateStack replaces the masters of the tenant cluster one after
+// another without ever reducing the number of healthy masters below the
+// number of masters of the tenant cluster. Each reconciliation performs at
+// most one of the following steps, and only when all masters are healthy
+// behind the API and etcd load balancers.
+//
+//     1. A surge master with the desired spec is added to the cloud formation
+//        stack. It gets the index following the ones of the other masters and
+//        joins the etcd cluster as additional member.
+//     2. Once the surge master is healthy, one outdated master is retired and
+//        replaced. The replacing master gets the etcd volume and the fixed
+//        private IP of the master it replaces, so it rejoins the etcd cluster
+//        as the same member. Masters which are not replaced yet are kept as
+//        they are in the cloud formation stack.
+//     3. Once all masters are up to date, the surge master is removed from the
+//        cloud formation stack. It leaves the etcd cluster when it shuts down.
+//
+func (r *Resource) rollingUpdateStack(ctx context.Context, cr v1alpha1.AWSConfig) error {
+	step, err := r.nextRollingUpdateStep(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	switch step.Action {
+	case rollingUpdateActionWait:
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("not replacing master %d yet", step.Index))
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+
+	case rollingUpdateActionUpdate:
+		templateBody, err := r.newTemplateBody(ctx, cr, step.TemplateParams)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		err = r.ensureStack(ctx, cr, templateBody)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		return nil
+	}
+
+	surgeIdx := step.TemplateParams.EtcdMemberCount
+
+	templateBody, err := r.newTemplateBody(ctx, cr, step.TemplateParams)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	name, ready, err := r.ensureChangeSet(ctx, cr, templateBody)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if !ready {
+		if step.Action == rollingUpdateActionAddSurge {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("not adding surge master %d yet", surgeIdx))
+		} else {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("not replacing master %d yet", step.Index))
+		}
+		r.logger.LogCtx(ctx, "level", "debug", "message", "canceling resource")
+		return nil
+	}
+
+	if step.Action == rollingUpdateActionAddSurge {
+		err = r.executeChangeSet(ctx, cr, name)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("added surge master %d", surgeIdx))
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("replacing master %d once surge master %d is healthy", step.Index, surgeIdx))
+
+		return nil
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("replacing master %d", step.Index))
+
+	err = r.retireMaster(ctx, cr, step.Index)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = r.executeChangeSet(ctx, cr, name)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("replaced master %d", step.Index))
+
+	return nil
+}
+
+// nextRollingUpdateStep computes the step of the rolling master update to
+// perform in the current reconciliation. See rollingUpdateStack. Whether the
+// surge master is there is derived from the etcd member count recorded in the
+// stack outputs.
+func (r *Resource) nextRollingUpdateStep(ctx context.Context, cr v1alpha1.AWSConfig) (rollingUpdateStep, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return rollingUpdateStep{}, microerror.Mask(err)
+	}
+
+	masters := newCurrentMasters(cc)
+	memberCount := key.EtcdMemberCount(cr, cc.Status.TenantCluster.MasterInstance.EtcdMemberCount)
+	surge := len(masters) > memberCount
+
+	idx := -1
+	for i, m := range masters[:memberCount] {
+		if m.InstanceType != key.MasterInstanceTypeByIndex(cr, i) || m.VersionBundleVersion != key.VersionBundleVersion(cr) {
+			idx = i
+			break
+		}
+	}
+
+	if idx == -1 {
+		r.logger.LogCtx(ctx, "level", "debug", "message", "all masters are up to date")
+
+		if surge {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("removing surge master %d", memberCount))
+		}
+
+		step := rollingUpdateStep{
+			Action: rollingUpdateActionUpdate,
+			Index:  idx,
+			TemplateParams: templateParams{
+				Masters: masters[:memberCount],
+			},
+		}
+
+		return step, nil
+	}
+
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", "finding out if all masters are healthy")
+
+		healthy, err := r.mastersHealthy(ctx, cr, len(masters))
+		if err != nil {
+			return rollingUpdateStep{}, microerror.Mask(err)
+		}
+
+		if !healthy {
+			r.logger.LogCtx(ctx, "level", "debug", "message", "not all masters are healthy")
+
+			step := rollingUpdateStep{
+				Action: rollingUpdateActionWait,
+				Index:  idx,
+			}
+
+			return step, nil
+		}
+
+		r.logger.LogCtx(ctx, "level", "debug", "message", "all masters are healthy")
+	}
+
+	var desiredMaster adapter.StackStateMaster
+	{
+		im, err := key.ImageID(cr)
+		if err != nil {
+			return rollingUpdateStep{}, microerror.Mask(err)
+		}
+		volumeEncryption, err := key.VolumeEncryption(cr, r.ebsEncryptionKey)
+		if err != nil {
+			return rollingUpdateStep{}, microerror.Mask(err)
+		}
+
+		// The surge master is added first, so the outdated master is only
+		// replaced once the surge master is there. The resource names are
+		// derived from the ones of the masters which are there, so that the
+		// change set stays the same until it got executed.
+		i := memberCount
+		replaced := masters
+		if surge {
+			i = idx
+			replaced = masters[idx : idx+1]
+		}
+
+		dockerVolumeResourceName, instanceResourceName := replacingResourceNames(cr, replaced...)
+		desiredMaster = newDesiredMaster(cr, i, im, volumeEncryption, dockerVolumeResourceName, instanceResourceName)
+	}
+
+	if !surge {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("adding surge master %d", memberCount))
+
+		step := rollingUpdateStep{
+			Action: rollingUpdateActionAddSurge,
+			Index:  idx,
+			TemplateParams: templateParams{
+				EtcdMemberCount: memberCount,
+				Masters:         append(masters, desiredMaster),
+			},
+		}
+
+		return step, nil
+	}
+
+	masters[idx] = desiredMaster
+
+	step := rollingUpdateStep{
+		Action: rollingUpdateActionReplace,
+		Index:  idx,
+		TemplateParams: templateParams{
+			EtcdMemberCount: memberCount,
+			Masters:         masters,
+		},
+	}
+
+	return step, nil
+}
+
+// retireMaster shuts down the master with the given index, detaches its
+// volumes and terminates it, so that the master replacing it can take over
+// its etcd volume and network interface. Masters which do not exist anymore
+// are ignored.
+func (r *Resource) retireMaster(ctx context.Context, cr v1alpha1.AWSConfig, idx int) error {
+	var instanceID string
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("finding instance ID of master %d", idx))
+
+		f := &ec2.Filter{
+			Name: aws.String(fmt.Sprintf("tag:%s", key.MasterIndexTagName)),
+			Values: []*string{
+				aws.String(strconv.Itoa(idx)),
+			},
+		}
+
+		instanceIDs, err := r.searchMasterInstanceIDs(ctx, cr, f)
+		if IsNotExists(err) {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("did not find instance ID of master %d", idx))
+			return nil
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else if len(instanceIDs) != 1 {
+			return microerror.Maskf(executionFailedError, "expected one instance of master %d, got %d", idx, len(instanceIDs))
+		}
+
+		instanceID = instanceIDs[0]
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found instance ID %#q of master %d", instanceID, idx))
+	}
+
+	err := r.detachVolumes(ctx, cr, instanceID)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = r.terminateMasterInstance(ctx, instanceID)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = r.waitForMasterInstancesTerminated(ctx, []string{instanceID})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// mastersHealthy checks if the given number of masters is in service behind
+// the API and the etcd load balancers of the tenant cluster. Classic ELBs
+// report the health of their instances directly. NLBs report it per target
+// group, so the masters have to be healthy in all target groups of the NLB.
+func (r *Resource) mastersHealthy(ctx context.Context, cr v1alpha1.AWSConfig, masterCount int) (bool, error) {
+	apiELBName, err := key.LoadBalancerName(cr.Spec.Cluster.Kubernetes.API.Domain, cr)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	etcdELBName, err := key.LoadBalancerName(key.EtcdDomain(cr), cr)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	lbType, err := key.LoadBalancerType(cr)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	for _, n := range []string{apiELBName, etcdELBName} {
+		var healthy bool
+		if lbType == key.LoadBalancerTypeNetwork {
+			healthy, err = r.mastersHealthyNLB(ctx, n, masterCount)
+		} else {
+			healthy, err = r.mastersHealthyELB(ctx, n, masterCount)
+		}
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		if !healthy {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r *Resource) mastersHealthyELB(ctx context.Context, name string, masterCount int) (bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	i := &elb.DescribeInstanceHealthInput{
+		LoadBalancerName: aws.String(name),
+	}
+
+	o, err := cc.Client.TenantCluster.AWS.ELB.DescribeInstanceHealth(i)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	var inService int
+	for _, s := range o.InstanceStates {
+		if *s.State == "InService" {
+			inService++
+		}
+	}
+
+	if inService != masterCount {
+		r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d out of %d masters in service behind load balancer %#q", inService, masterCount, name))
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (r *Resource) mastersHealthyNLB(ctx context.Context, name string, masterCount int) (bool, error) {
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	var lbARN *string
+	{
+		i := &elbv2.DescribeLoadBalancersInput{
+			Names: []*string{
+				aws.String(name),
+			},
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.ELBV2.DescribeLoadBalancers(i)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+		if len(o.LoadBalancers) != 1 {
+			return false, microerror.Maskf(executionFailedError, "expected one load balancer %#q, got %d", name, len(o.LoadBalancers))
+		}
+
+		lbARN = o.LoadBalancers[0].LoadBalancerArn
+	}
+
+	var targetGroups []*elbv2.TargetGroup
+	{
+		i := &elbv2.DescribeTargetGroupsInput{
+			LoadBalancerArn: lbARN,
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.ELBV2.DescribeTargetGroups(i)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		targetGroups = o.TargetGroups
+	}
+
+	for _, tg := range targetGroups {
+		i := &elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: tg.TargetGroupArn,
+		}
+
+		o, err := cc.Client.TenantCluster.AWS.ELBV2.DescribeTargetHealth(i)
+		if err != nil {
+			return false, microerror.Mask(err)
+		}
+
+		var healthy int
+		for _, d := range o.TargetHealthDescriptions {
+			if d.TargetHealth != nil && aws.StringValue(d.TargetHealth.State) == elbv2.TargetHealthStateEnumHealthy {
+				healthy++
+			}
+		}
+
+		if healthy != masterCount {
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d out of %d masters healthy in target group %#q of load balancer %#q", healthy, masterCount, aws.StringValue(tg.TargetGroupName), name))
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,322 @@
+package tccp
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/aws-operator/service/controller/v25/controllercontext"
+	"github.com/giantswarm/aws-operator/service/controller/v25/key"
+)
+
+// newTestMasters returns current masters of type m5.xlarge, one for every
+// given version bundle version.
+func newTestMasters(versions ...string) []controllercontext.ContextStatusTenantClusterMaster {
+	var masters []controllercontext.ContextStatusTenantClusterMaster
+
+	for i, v := range versions {
+		masters = append(masters, controllercontext.ContextStatusTenantClusterMaster{
+			DockerVolumeResourceName: key.MasterDockerVolumeResourceName("DockerVolumeTESTCLUSTERAAAAA", i),
+			Image:                    "ami-old",
+			ResourceName:             key.MasterInstanceResourceNameByIndex("MasterInstanceTESTCLUSTERAAAAA", i),
+			Type:                     "m5.xlarge",
+			VersionBundleVersion:     v,
+		})
+	}
+
+	return masters
+}
+
+func Test_Resource_nextRollingUpdateStep(t *testing.T) {
+	testCases := []struct {
+		name             string
+		loadBalancerType string
+		masters          []controllercontext.ContextStatusTenantClusterMaster
+		inService        map[string]int
+		healthy          map[string]int
+		expectedAction   string
+		expectedIndex    int
+		// expectedReplaced are the indexes of the masters which are expected
+		// to get new resource names and the desired version bundle version.
+		expectedReplaced []int
+		expectedMasters  int
+		expectedMembers  int
+	}{
+		{
+			name:            "case 0: all masters up to date without surge master updates the stack",
+			masters:         newTestMasters("2.0.0", "2.0.0", "2.0.0"),
+			expectedAction:  rollingUpdateActionUpdate,
+			expectedIndex:   -1,
+			expectedMasters: 3,
+		},
+		{
+			name:            "case 1: all masters up to date with surge master removes the surge master",
+			masters:         newTestMasters("2.0.0", "2.0.0", "2.0.0", "2.0.0"),
+			expectedAction:  rollingUpdateActionUpdate,
+			expectedIndex:   -1,
+			expectedMasters: 3,
+		},
+		{
+			name:    "case 2: outdated master without surge master adds the surge master",
+			masters: newTestMasters("2.0.0", "1.0.0", "1.0.0"),
+			inService: map[string]int{
+				"test-cluster-api":  3,
+				"test-cluster-etcd": 3,
+			},
+			expectedAction:   rollingUpdateActionAddSurge,
+			expectedIndex:    1,
+			expectedReplaced: []int{3},
+			expectedMasters:  4,
+			expectedMembers:  3,
+		},
+		{
+			name:    "case 3: outdated master with healthy surge master replaces the outdated master",
+			masters: newTestMasters("1.0.0", "1.0.0", "1.0.0", "2.0.0"),
+			inService: map[string]int{
+				"test-cluster-api":  4,
+				"test-cluster-etcd": 4,
+			},
+			expectedAction:   rollingUpdateActionReplace,
+			expectedIndex:    0,
+			expectedReplaced: []int{0},
+			expectedMasters:  4,
+			expectedMembers:  3,
+		},
+		{
+			name:    "case 4: outdated master with surge master not in service behind the etcd load balancer waits",
+			masters: newTestMasters("2.0.0", "1.0.0", "1.0.0", "2.0.0"),
+			inService: map[string]int{
+				"test-cluster-api":  4,
+				"test-cluster-etcd": 3,
+			},
+			expectedAction: rollingUpdateActionWait,
+			expectedIndex:  1,
+		},
+		{
+			name:    "case 5: outdated master with unhealthy master without surge master waits",
+			masters: newTestMasters("1.0.0", "1.0.0", "1.0.0"),
+			inService: map[string]int{
+				"test-cluster-api":  2,
+				"test-cluster-etcd": 3,
+			},
+			expectedAction: rollingUpdateActionWait,
+			expectedIndex:  0,
+		},
+		{
+			name:             "case 6: outdated master behind healthy NLBs adds the surge master",
+			loadBalancerType: key.LoadBalancerTypeNetwork,
+			masters:          newTestMasters("1.0.0", "1.0.0", "1.0.0"),
+			healthy: map[string]int{
+				"test-cluster-api-443":   3,
+				"test-cluster-api-2379":  3,
+				"test-cluster-etcd-443":  3,
+				"test-cluster-etcd-2379": 3,
+			},
+			expectedAction:   rollingUpdateActionAddSurge,
+			expectedIndex:    0,
+			expectedReplaced: []int{3},
+			expectedMasters:  4,
+			expectedMembers:  3,
+		},
+		{
+			name:             "case 7: outdated master with surge master healthy in all target groups replaces the outdated master",
+			loadBalancerType: key.LoadBalancerTypeNetwork,
+			masters:          newTestMasters("2.0.0", "2.0.0", "1.0.0", "2.0.0"),
+			healthy: map[string]int{
+				"test-cluster-api-443":   4,
+				"test-cluster-api-2379":  4,
+				"test-cluster-etcd-443":  4,
+				"test-cluster-etcd-2379": 4,
+			},
+			expectedAction:   rollingUpdateActionReplace,
+			expectedIndex:    2,
+			expectedReplaced: []int{2},
+			expectedMasters:  4,
+			expectedMembers:  3,
+		},
+		{
+			name:             "case 8: outdated master with surge master unhealthy in one target group waits",
+			loadBalancerType: key.LoadBalancerTypeNetwork,
+			masters:          newTestMasters("2.0.0", "2.0.0", "1.0.0", "2.0.0"),
+			healthy: map[string]int{
+				"test-cluster-api-443":   4,
+				"test-cluster-api-2379":  4,
+				"test-cluster-etcd-443":  4,
+				"test-cluster-etcd-2379": 3,
+			},
+			expectedAction: rollingUpdateActionWait,
+			expectedIndex:  2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := newTestCustomObject(3)
+			cr.Spec.VersionBundle.Version = "2.0.0"
+			if tc.loadBalancerType != "" {
+				cr.SetAnnotations(map[string]string{
+					key.LoadBalancerTypeAnnotation: tc.loadBalancerType,
+				})
+			}
+
+			r := newTestResource()
+
+			ctx := newTestContext(tc.masters)
+			cc, err := controllercontext.FromContext(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			cc.Client.TenantCluster.AWS.ELB = &ELBClientMock{inService: tc.inService}
+			cc.Client.TenantCluster.AWS.ELBV2 = &ELBV2ClientMock{healthy: tc.healthy}
+			cc.Status.TenantCluster.MasterInstance.EtcdMemberCount = 3
+
+			current := newCurrentMasters(cc)
+
+			step, err := r.nextRollingUpdateStep(ctx, cr)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if step.Action != tc.expectedAction {
+				t.Fatalf("expected action %#q, got %#q", tc.expectedAction, step.Action)
+			}
+			if step.Index != tc.expectedIndex {
+				t.Fatalf("expected index %d, got %d", tc.expectedIndex, step.Index)
+			}
+			if len(step.TemplateParams.Masters) != tc.expectedMasters {
+				t.Fatalf("expected %d masters, got %d", tc.expectedMasters, len(step.TemplateParams.Masters))
+			}
+			if step.TemplateParams.EtcdMemberCount != tc.expectedMembers {
+				t.Fatalf("expected %d etcd members, got %d", tc.expectedMembers, step.TemplateParams.EtcdMemberCount)
+			}
+
+			for i, m := range step.TemplateParams.Masters {
+				replaced := false
+				for _, j := range tc.expectedReplaced {
+					if i == j {
+						replaced = true
+					}
+				}
+
+				if !replaced {
+					if !reflect.DeepEqual(m, current[i]) {
+						t.Fatalf("expected master %d to be kept as %#v, got %#v", i, current[i], m)
+					}
+					continue
+				}
+
+				for _, c := range current {
+					if m.InstanceResourceName == c.InstanceResourceName {
+						t.Fatalf("expected master %d to get a new instance resource name, got %#q", i, m.InstanceResourceName)
+					}
+					if m.DockerVolumeResourceName == c.DockerVolumeResourceName {
+						t.Fatalf("expected master %d to get a new docker volume resource name, got %#q", i, m.DockerVolumeResourceName)
+					}
+				}
+				if m.VersionBundleVersion != "2.0.0" {
+					t.Fatalf("expected master %d to get version bundle version %#q, got %#q", i, "2.0.0", m.VersionBundleVersion)
+				}
+			}
+		})
+	}
+}
+
+// Test_Resource_rollingUpdateStack_Unhealthy ensures the rolling update bails
+// out before touching the cloud formation stack as long as not all masters are
+// healthy. The cloud formation client of the test context is nil, so any
+// attempt to create a change set would panic.
+func Test_Resource_rollingUpdateStack_Unhealthy(t *testing.T) {
+	cr := newTestCustomObject(3)
+	cr.Spec.VersionBundle.Version = "2.0.0"
+
+	r := newTestResource()
+
+	ctx := newTestContext(newTestMasters("1.0.0", "1.0.0", "1.0.0", "2.0.0"))
+	cc, err := controllercontext.FromContext(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	cc.Client.TenantCluster.AWS.ELB = &ELBClientMock{inService: map[string]int{"test-cluster-api": 3, "test-cluster-etcd": 3}}
+	cc.Status.TenantCluster.MasterInstance.EtcdMemberCount = 3
+
+	err = r.rollingUpdateStack(ctx, cr)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+}
+
+func Test_Resource_retireMaster(t *testing.T) {
+	testCases := []struct {
+		name          string
+		index         int
+		instances     map[string]string
+		volumes       []VolumeMock
+		expectedCalls []string
+	}{
+		{
+			name:  "case 0: master is stopped, its volumes are detached and it is terminated",
+			index: 1,
+			instances: map[string]string{
+				"0": "i-0",
+				"1": "i-1",
+			},
+			volumes: []VolumeMock{
+				{instanceID: "i-0", name: "test-cluster-etcd", volumeID: "vol-etcd-0"},
+				{instanceID: "i-1", name: "test-cluster-etcd", volumeID: "vol-etcd-1"},
+				{instanceID: "i-1", name: "test-cluster-docker", volumeID: "vol-docker-1"},
+				{instanceID: "i-1", name: "test-cluster-pv", volumeID: "vol-pv-1"},
+			},
+			expectedCalls: []string{
+				"StopInstances [i-1]",
+				"WaitUntilInstanceStopped [i-1]",
+				"DetachVolume vol-etcd-1 i-1",
+				"StopInstances [i-1]",
+				"WaitUntilInstanceStopped [i-1]",
+				"DetachVolume vol-docker-1 i-1",
+				"ModifyInstanceAttribute i-1",
+				"TerminateInstances [i-1]",
+				"WaitUntilInstanceTerminated [i-1]",
+			},
+		},
+		{
+			name:  "case 1: master which does not exist anymore is ignored",
+			index: 2,
+			instances: map[string]string{
+				"0": "i-0",
+				"1": "i-1",
+			},
+			volumes: []VolumeMock{
+				{instanceID: "i-0", name: "test-cluster-etcd", volumeID: "vol-etcd-0"},
+			},
+			expectedCalls: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := newTestCustomObject(3)
+			r := newTestResource()
+
+			ec2Client := &EC2ClientMock{
+				instances: tc.instances,
+				volumes:   tc.volumes,
+			}
+
+			ctx := newTestContext(newTestMasters("1.0.0", "1.0.0", "1.0.0", "2.0.0"))
+			cc, err := controllercontext.FromContext(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+			cc.Client.TenantCluster.AWS.EC2 = ec2Client
+
+			err = r.retireMaster(ctx, cr, tc.index)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !reflect.DeepEqual(ec2Client.calls, tc.expectedCalls) {
+				t.Fatalf("expected calls %s, got %s", fmt.Sprint(tc.expectedCalls), fmt.Sprint(ec2Client.calls))
+			}
+		})
+	}
+}
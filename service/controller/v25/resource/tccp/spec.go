@@ -1,6 +1,16 @@
 package tccp
 
+import (
+	"github.com/giantswarm/aws-operator/service/controller/v25/adapter"
+)
+
 type templateParams struct {
-	DockerVolumeResourceName   string
-	MasterInstanceResourceName string
+	// EtcdVolumeEncryption is only set when the stack gets created. Otherwise
+	// the etcd volumes keep the encryption of the existing stack.
+	EtcdVolumeEncryption string
+	// EtcdMemberCount is the number of Masters which are etcd members. It only
+	// has to be set while a rolling master update added a surge master. All
+	// Masters are etcd members otherwise.
+	EtcdMemberCount int
+	Masters         []adapter.StackStateMaster
 }
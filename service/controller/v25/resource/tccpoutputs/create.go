@@ -3,6 +3,8 @@ package tccpoutputs
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -14,10 +16,10 @@ import (
 )
 
 const (
-	HostedZoneNameServersKey  = "HostedZoneNameServers"
-	VPCIDKey                  = "VPCID"
-	VPCPeeringConnectionIDKey = "VPCPeeringConnectionID"
-	WorkerASGNameKey          = "WorkerASGName"
+	HostedZoneNameServersKey      = "HostedZoneNameServers"
+	TransitGatewayAttachmentIDKey = "TransitGatewayAttachmentID"
+	VPCIDKey                      = "VPCID"
+	VPCPeeringConnectionIDKey     = "VPCPeeringConnectionID"
 )
 
 func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
@@ -85,6 +87,40 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		cc.Status.TenantCluster.HostedZoneNameServers = v
 	}
 
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.MasterCountKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// Tenant clusters created before multiple masters were supported do not
+			// have the master count output. These always have exactly one master.
+			cc.Status.TenantCluster.MasterInstance.Count = 1
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			cc.Status.TenantCluster.MasterInstance.Count = i
+		}
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.MasterEtcdMemberCountKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// Tenant clusters created before the etcd member count was tracked
+			// never have a surge master. All their masters are etcd members.
+			cc.Status.TenantCluster.MasterInstance.EtcdMemberCount = cc.Status.TenantCluster.MasterInstance.Count
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			cc.Status.TenantCluster.MasterInstance.EtcdMemberCount = i
+		}
+	}
+
 	{
 		v, err := cloudFormation.GetOutputValue(outputs, key.MasterImageIDKey)
 		if err != nil {
@@ -118,7 +154,45 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 	}
 
 	{
-		v, err := cloudFormation.GetOutputValue(outputs, WorkerASGNameKey)
+		v, err := cloudFormation.GetOutputValue(outputs, key.EtcdRestoreSnapshotKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// The output only exists in case the masters were restored from an
+			// etcd backup.
+			cc.Status.TenantCluster.MasterInstance.EtcdRestoreSnapshot = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			cc.Status.TenantCluster.MasterInstance.EtcdRestoreSnapshot = v
+		}
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.EtcdVolumeEncryptionKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// Tenant clusters created before all volumes got encrypted do not have
+			// the etcd volume encryption output.
+			cc.Status.TenantCluster.MasterInstance.EtcdVolumeEncryption = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			cc.Status.TenantCluster.MasterInstance.EtcdVolumeEncryption = v
+		}
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.EncryptionKeyRotationKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// The output only exists in case the encryption key was rotated.
+			cc.Status.TenantCluster.Encryption.Rotation = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			cc.Status.TenantCluster.Encryption.Rotation = v
+		}
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.WorkerASGNameKey)
 		if err != nil {
 			return microerror.Mask(err)
 		}
@@ -155,6 +229,21 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 	}
 
 	{
+		v, err := cloudFormation.GetOutputValue(outputs, TransitGatewayAttachmentIDKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// Only tenant clusters attached to a transit gateway have the transit
+			// gateway attachment output.
+			cc.Status.TenantCluster.TCCP.VPC.TransitGatewayAttachmentID = ""
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			cc.Status.TenantCluster.TCCP.VPC.TransitGatewayAttachmentID = v
+		}
+	}
+
+	// Tenant clusters created in an existing VPC or attached to a transit
+	// gateway are not peered with the control plane VPC.
+	if !key.IsExistingVPC(cr) && cc.Status.TenantCluster.TCCP.VPC.TransitGatewayAttachmentID == "" {
 		v, err := cloudFormation.GetOutputValue(outputs, VPCPeeringConnectionIDKey)
 		if cloudformation.IsOutputNotFound(err) {
 			// TODO this exception is necessary for clusters upgrading from v23 to
@@ -199,6 +288,14 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		cc.Status.TenantCluster.WorkerInstance.Image = v
 	}
 
+	{
+		v, err := getInstanceDistribution(cloudFormation, outputs, key.WorkerInstanceDistributionKey)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		cc.Status.TenantCluster.WorkerInstance.InstanceDistribution = v
+	}
+
 	{
 		v, err := cloudFormation.GetOutputValue(outputs, key.WorkerInstanceTypeKey)
 		if err != nil {
@@ -207,9 +304,169 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		cc.Status.TenantCluster.WorkerInstance.Type = v
 	}
 
+	{
+		var masters []controllercontext.ContextStatusTenantClusterMaster
+
+		for i := 0; i < cc.Status.TenantCluster.MasterInstance.Count; i++ {
+			m, err := getMaster(cloudFormation, outputs, i, cc.Status.TenantCluster.VersionBundleVersion)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			masters = append(masters, m)
+		}
+
+		cc.Status.TenantCluster.Masters = masters
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.WorkerPoolNamesKey)
+		if cloudformation.IsOutputNotFound(err) {
+			// Tenant clusters without named worker pools do not have the worker
+			// pool names output.
+			cc.Status.TenantCluster.TCCP.WorkerPools = nil
+		} else if err != nil {
+			return microerror.Mask(err)
+		} else {
+			var workerPools []controllercontext.ContextStatusTenantClusterTCCPWorkerPool
+
+			for _, name := range strings.Split(v, ",") {
+				p, err := getWorkerPool(cloudFormation, outputs, name)
+				if err != nil {
+					return microerror.Mask(err)
+				}
+
+				workerPools = append(workerPools, p)
+			}
+
+			cc.Status.TenantCluster.TCCP.WorkerPools = workerPools
+		}
+	}
+
 	return nil
 }
 
+// getMaster reads the outputs of the master with the given index. Tenant
+// clusters created before masters were tracked individually do not have the
+// master version bundle version output. Their masters always have the version
+// bundle version of the stack. Masters created before all volumes got
+// encrypted do not have the master volume encryption output.
+func getMaster(cloudFormation *cloudformation.CloudFormation, outputs []cloudformation.Output, idx int, versionBundleVersion string) (controllercontext.ContextStatusTenantClusterMaster, error) {
+	var m controllercontext.ContextStatusTenantClusterMaster
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.MasterOutputKey(key.DockerVolumeResourceNameKey, idx))
+		if err != nil {
+			return controllercontext.ContextStatusTenantClusterMaster{}, microerror.Mask(err)
+		}
+		m.DockerVolumeResourceName = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.MasterOutputKey(key.MasterImageIDKey, idx))
+		if err != nil {
+			return controllercontext.ContextStatusTenantClusterMaster{}, microerror.Mask(err)
+		}
+		m.Image = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.MasterOutputKey(key.MasterInstanceResourceNameKey, idx))
+		if err != nil {
+			return controllercontext.ContextStatusTenantClusterMaster{}, microerror.Mask(err)
+		}
+		m.ResourceName = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.MasterOutputKey(key.MasterInstanceTypeKey, idx))
+		if err != nil {
+			return controllercontext.ContextStatusTenantClusterMaster{}, microerror.Mask(err)
+		}
+		m.Type = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.MasterOutputKey(key.MasterVersionBundleVersionKey, idx))
+		if cloudformation.IsOutputNotFound(err) {
+			m.VersionBundleVersion = versionBundleVersion
+		} else if err != nil {
+			return controllercontext.ContextStatusTenantClusterMaster{}, microerror.Mask(err)
+		} else {
+			m.VersionBundleVersion = v
+		}
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.MasterOutputKey(key.MasterVolumeEncryptionKey, idx))
+		if cloudformation.IsOutputNotFound(err) {
+			m.VolumeEncryption = ""
+		} else if err != nil {
+			return controllercontext.ContextStatusTenantClusterMaster{}, microerror.Mask(err)
+		} else {
+			m.VolumeEncryption = v
+		}
+	}
+
+	return m, nil
+}
+
+// getWorkerPool reads the outputs of the named worker pool with the given name.
+func getWorkerPool(cloudFormation *cloudformation.CloudFormation, outputs []cloudformation.Output, name string) (controllercontext.ContextStatusTenantClusterTCCPWorkerPool, error) {
+	p := controllercontext.ContextStatusTenantClusterTCCPWorkerPool{
+		Name: name,
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.WorkerPoolOutputKey(key.WorkerASGNameKey, name))
+		if err != nil {
+			return controllercontext.ContextStatusTenantClusterTCCPWorkerPool{}, microerror.Mask(err)
+		}
+		p.ASG.Name = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.WorkerPoolOutputKey(key.WorkerDockerVolumeSizeKey, name))
+		if err != nil {
+			return controllercontext.ContextStatusTenantClusterTCCPWorkerPool{}, microerror.Mask(err)
+		}
+		p.DockerVolumeSizeGB = v
+	}
+
+	{
+		v, err := getInstanceDistribution(cloudFormation, outputs, key.WorkerPoolOutputKey(key.WorkerInstanceDistributionKey, name))
+		if err != nil {
+			return controllercontext.ContextStatusTenantClusterTCCPWorkerPool{}, microerror.Mask(err)
+		}
+		p.InstanceDistribution = v
+	}
+
+	{
+		v, err := cloudFormation.GetOutputValue(outputs, key.WorkerPoolOutputKey(key.WorkerInstanceTypeKey, name))
+		if err != nil {
+			return controllercontext.ContextStatusTenantClusterTCCPWorkerPool{}, microerror.Mask(err)
+		}
+		p.InstanceType = v
+	}
+
+	return p, nil
+}
+
+// getInstanceDistribution reads the instance distribution output with the
+// given key. Stacks created before instance distributions were supported do
+// not have this output. Their workers are always on-demand instances of a
+// single instance type.
+func getInstanceDistribution(cloudFormation *cloudformation.CloudFormation, outputs []cloudformation.Output, outputKey string) (string, error) {
+	v, err := cloudFormation.GetOutputValue(outputs, outputKey)
+	if cloudformation.IsOutputNotFound(err) {
+		return key.InstanceDistributionString(key.InstanceDistribution{}), nil
+	} else if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return v, nil
+}
+
 func searchPeeringConnectionID(client EC2, clusterID string) (string, error) {
 	var peeringID string
 	{
@@ -2,6 +2,9 @@ package workerasgname
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
 
 	"github.com/giantswarm/apiextensions/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/microerror"
@@ -36,6 +39,72 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 		r.logger.LogCtx(ctx, "level", "debug", "message", "found latest version of custom resource")
 	}
 
+	{
+		r.logger.LogCtx(ctx, "level", "debug", "message", "finding the tenant cluster worker pool ASG names in the CR")
+
+		asgNames, err := key.WorkerPoolASGNames(customObject)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// The version bundle version is only known when the tccpoutputs resource
+		// was able to read the stack outputs. Only then the worker pools in the
+		// controller context reflect the current state of the stack. Otherwise
+		// we fall back to the worker pool ASG names persisted in the CR.
+		outputsAvailable := cc.Status.TenantCluster.VersionBundleVersion != ""
+
+		if !outputsAvailable {
+			var names []string
+			for n := range asgNames {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+
+			for _, n := range names {
+				p := controllercontext.ContextStatusTenantClusterTCCPWorkerPool{
+					Name: n,
+				}
+				p.ASG.Name = asgNames[n]
+
+				cc.Status.TenantCluster.TCCP.WorkerPools = append(cc.Status.TenantCluster.TCCP.WorkerPools, p)
+			}
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("found %d tenant cluster worker pool ASG names in the CR", len(names)))
+		} else if !workerPoolASGNamesEqual(asgNames, cc.Status.TenantCluster.TCCP.WorkerPools) {
+			r.logger.LogCtx(ctx, "level", "debug", "message", "did not find the current tenant cluster worker pool ASG names in the CR")
+			r.logger.LogCtx(ctx, "level", "debug", "message", "updating CR annotations")
+
+			desired := map[string]string{}
+			for _, p := range cc.Status.TenantCluster.TCCP.WorkerPools {
+				desired[p.Name] = p.ASG.Name
+			}
+			b, err := json.Marshal(desired)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			annotations := customObject.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[key.WorkerPoolASGNamesAnnotation] = string(b)
+			customObject.SetAnnotations(annotations)
+
+			_, err = r.g8sClient.ProviderV1alpha1().AWSConfigs(customObject.Namespace).Update(&customObject)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+
+			r.logger.LogCtx(ctx, "level", "debug", "message", "updated CR annotations")
+			r.logger.LogCtx(ctx, "level", "debug", "message", "canceling reconciliation")
+			reconciliationcanceledcontext.SetCanceled(ctx)
+
+			return nil
+		} else {
+			r.logger.LogCtx(ctx, "level", "debug", "message", "found the current tenant cluster worker pool ASG names in the CR")
+		}
+	}
+
 	{
 		r.logger.LogCtx(ctx, "level", "debug", "message", "finding the tenant cluster worker ASG name in the CR")
 
@@ -67,3 +136,17 @@ func (r *Resource) EnsureCreated(ctx context.Context, obj interface{}) error {
 
 	return nil
 }
+
+func workerPoolASGNamesEqual(asgNames map[string]string, workerPools []controllercontext.ContextStatusTenantClusterTCCPWorkerPool) bool {
+	if len(asgNames) != len(workerPools) {
+		return false
+	}
+
+	for _, p := range workerPools {
+		if asgNames[p.Name] != p.ASG.Name {
+			return false
+		}
+	}
+
+	return true
+}
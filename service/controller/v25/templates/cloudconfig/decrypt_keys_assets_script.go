@@ -132,11 +132,19 @@ rkt run \
       echo decrypting $encKey
       f=$(mktemp $encKey.XXXXXXXX)
       /usr/bin/aws \
+{{- if eq .EncrypterType "ssm" }}
+        --region {{.AWS.Region}} ssm get-parameter \
+        --name $(cat $encKey) \
+        --with-decryption \
+        --output text \
+        --query Parameter.Value > $f
+{{- else }}
         --region {{.AWS.Region}} kms decrypt \
         --ciphertext-blob fileb://$encKey \
         --output text \
         --query Plaintext \
       | base64 -d > $f
+{{- end }}
       mv -f $f ${encKey%.enc}
     done;
     echo done.'
@@ -11,3 +11,23 @@ resources:
         - name: key1
           secret: {{.EncryptionKey}}
     - identity: {}`
+
+// KMSEncryptionConfig encrypts secrets with the KMS key of the tenant cluster
+// through the KMS plugin running on the masters. The aescbc provider stays in
+// place so that secrets written before the migration can still be read.
+const KMSEncryptionConfig = `kind: EncryptionConfig
+apiVersion: v1
+resources:
+  - resources:
+    - secrets
+    providers:
+    - kms:
+        name: aws-encryption-provider
+        endpoint: unix:///var/run/kmsplugin/socket.sock
+        cachesize: 1000
+        timeout: 3s
+    - aescbc:
+        keys:
+        - name: key1
+          secret: {{.EncryptionKey}}
+    - identity: {}`
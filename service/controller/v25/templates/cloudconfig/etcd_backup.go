@@ -0,0 +1,162 @@
+package cloudconfig
+
+// EtcdBackupScript takes a snapshot of the etcd cluster of a tenant cluster
+// and uploads it to the S3 bucket of the tenant cluster. Tenant clusters with
+// multiple masters only take snapshots on the master running the first etcd
+// member, since all members hold the same data.
+const EtcdBackupScript = `#!/bin/bash
+set -eu
+
+source /etc/network-environment
+
+{{ if .EtcdInitialCluster -}}
+INITIAL_CLUSTER="{{ .EtcdInitialCluster }}"
+FIRST_MEMBER="${INITIAL_CLUSTER%%,*}"
+if [ "${FIRST_MEMBER#*=}" != "https://${DEFAULT_IPV4}:2380" ]; then
+  echo "not running the first etcd member, skipping backup"
+  exit 0
+fi
+{{- end }}
+
+BACKUP_DIR=/var/lib/etcd-backup
+BACKUP_NAME="$(date -u +%Y%m%dT%H%M%SZ)-$(hostname).db"
+
+mkdir -p ${BACKUP_DIR}
+trap "rm -f /var/lib/etcd/etcd-backup.db ${BACKUP_DIR}/${BACKUP_NAME}" EXIT
+
+/usr/bin/docker exec \
+  -e ETCDCTL_API=3 \
+  etcd3.service \
+  etcdctl \
+  --endpoints https://127.0.0.1:2379 \
+  --cacert /etc/etcd/server-ca.pem \
+  --cert /etc/etcd/server-crt.pem \
+  --key /etc/etcd/server-key.pem \
+  snapshot save /var/lib/etcd/etcd-backup.db
+
+mv /var/lib/etcd/etcd-backup.db ${BACKUP_DIR}/${BACKUP_NAME}
+
+/usr/bin/docker run --rm \
+  -v ${BACKUP_DIR}:/backup \
+  --net=host \
+  --entrypoint=/usr/bin/aws \
+  {{ .AWSCliImage }} \
+  --region {{ .AWS.Region }} s3 cp \
+  /backup/${BACKUP_NAME} \
+  s3://{{ .EtcdBackupBucket }}/{{ .EtcdBackupPrefix }}${BACKUP_NAME} \
+{{- if eq .EncrypterType "kms" }}
+  --sse aws:kms \
+  --sse-kms-key-id {{ .EncryptionKey }}
+{{- else }}
+  --sse AES256
+{{- end }}
+`
+
+const EtcdBackupService = `
+[Unit]
+Description=etcd backup
+Requires=etcd3.service
+After=etcd3.service
+
+[Service]
+Type=oneshot
+ExecStart=/opt/bin/etcd-backup
+`
+
+const EtcdBackupTimer = `
+[Unit]
+Description=etcd backup timer
+
+[Timer]
+OnCalendar=hourly
+RandomizedDelaySec=600
+
+[Install]
+WantedBy=timers.target
+`
+
+// EtcdRestoreDropIn restores the etcd data of the master before etcd starts.
+// The restore script runs after the etcd image got pulled.
+const EtcdRestoreDropIn = `
+[Service]
+ExecStartPre=/opt/bin/etcd-restore
+`
+
+// EtcdRestoreScript restores the etcd data on the etcd volume of the master
+// from the etcd backup selected for the tenant cluster. Every backup is only
+// restored once per etcd volume. The current etcd data is kept next to the
+// restored one. The surge master of rolling master updates never restores,
+// since it joins the existing etcd cluster.
+const EtcdRestoreScript = `#!/bin/bash
+set -eu
+
+SNAPSHOT="{{ .EtcdRestoreSnapshot }}"
+MARKER="/var/lib/etcd/restored-$(echo -n ${SNAPSHOT} | sha256sum | cut -c1-16)"
+
+if [ -f "${MARKER}" ]; then
+  echo "etcd backup ${SNAPSHOT} is already restored"
+  exit 0
+fi
+
+{{ if .EtcdInitialCluster -}}
+INITIAL_CLUSTER="{{ .EtcdInitialCluster }}"
+SURGE_MEMBER="{{ .EtcdSurgeMember }}"
+ETCD_NAME=""
+PEER_URL="https://${DEFAULT_IPV4}:2380"
+if [ "${SURGE_MEMBER#*=}" == "${PEER_URL}" ]; then
+  echo "running the etcd surge member, which joins the existing etcd cluster instead of restoring"
+  exit 0
+fi
+
+for member in ${INITIAL_CLUSTER//,/ }; do
+  if [ "${member#*=}" == "${PEER_URL}" ]; then
+    ETCD_NAME="${member%%=*}"
+  fi
+done
+
+if [ -z "${ETCD_NAME}" ]; then
+  echo "IP ${DEFAULT_IPV4} is not part of the etcd initial cluster ${INITIAL_CLUSTER}" >&2
+  exit 1
+fi
+{{- else -}}
+INITIAL_CLUSTER="etcd0=https://127.0.0.1:2380"
+ETCD_NAME="etcd0"
+PEER_URL="https://127.0.0.1:2380"
+{{- end }}
+
+RESTORE_DIR=/var/lib/etcd-restore
+
+rm -rf ${RESTORE_DIR} /var/lib/etcd/restore
+mkdir -p ${RESTORE_DIR}
+
+echo "downloading etcd backup ${SNAPSHOT}"
+/usr/bin/docker run --rm \
+  -v ${RESTORE_DIR}:/restore \
+  --net=host \
+  --entrypoint=/usr/bin/aws \
+  {{ .AWSCliImage }} \
+  --region {{ .AWS.Region }} s3 cp \
+  s3://{{ .EtcdBackupBucket }}/${SNAPSHOT} \
+  /restore/snapshot.db
+
+echo "restoring etcd backup ${SNAPSHOT} as member ${ETCD_NAME}"
+/usr/bin/docker run --rm \
+  -v ${RESTORE_DIR}:/restore \
+  -v /var/lib/etcd:/var/lib/etcd \
+  -e ETCDCTL_API=3 \
+  ${IMAGE} \
+  etcdctl snapshot restore /restore/snapshot.db \
+  --name ${ETCD_NAME} \
+  --initial-cluster ${INITIAL_CLUSTER} \
+  --initial-cluster-token k8s-etcd-cluster \
+  --initial-advertise-peer-urls ${PEER_URL} \
+  --data-dir /var/lib/etcd/restore
+
+if [ -d /var/lib/etcd/member ]; then
+  mv /var/lib/etcd/member /var/lib/etcd/member.bak-$(date -u +%Y%m%dT%H%M%SZ)
+fi
+mv /var/lib/etcd/restore/member /var/lib/etcd/member
+
+rm -rf ${RESTORE_DIR} /var/lib/etcd/restore
+touch ${MARKER}
+`
@@ -1,5 +1,8 @@
 package cloudconfig
 
+// IngressControllerConfigMap configures the ingress controller. Classic ELBs
+// pass the client IP by means of the proxy protocol. NLBs preserve the client
+// IP, so the proxy protocol is not used behind them.
 const IngressControllerConfigMap = `kind: ConfigMap
 apiVersion: v1
 metadata:
@@ -10,5 +13,5 @@ metadata:
 data:
   server-name-hash-bucket-size: "1024"
   server-name-hash-max-size: "1024"
-  use-proxy-protocol: "true"
+  use-proxy-protocol: "{{ .IngressControllerUseProxyProtocol }}"
 `
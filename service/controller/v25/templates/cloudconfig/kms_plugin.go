@@ -0,0 +1,121 @@
+package cloudconfig
+
+// KMSPluginManifest is the static pod of the KMS plugin the API server
+// encrypts secrets through. The plugin listens on a unix socket shared with the
+// API server and encrypts data with the KMS key of the tenant cluster.
+const KMSPluginManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: aws-encryption-provider
+  namespace: kube-system
+  annotations:
+    scheduler.alpha.kubernetes.io/critical-pod: ''
+spec:
+  hostNetwork: true
+  priorityClassName: system-node-critical
+  containers:
+  - name: aws-encryption-provider
+    image: {{ .RegistryDomain }}/giantswarm/aws-encryption-provider:0.1.0
+    command:
+    - /aws-encryption-provider
+    - --key={{ .EncryptionKey }}
+    - --region={{ .AWS.Region }}
+    - --listen=/var/run/kmsplugin/socket.sock
+    - --health-port=:8083
+    livenessProbe:
+      httpGet:
+        host: 127.0.0.1
+        path: /healthz
+        port: 8083
+      initialDelaySeconds: 15
+      timeoutSeconds: 15
+    resources:
+      requests:
+        cpu: 50m
+        memory: 50Mi
+    volumeMounts:
+    - mountPath: /var/run/kmsplugin
+      name: kmsplugin
+  volumes:
+  - hostPath:
+      path: /var/run/kmsplugin
+      type: DirectoryOrCreate
+    name: kmsplugin
+`
+
+// ReencryptSecretsScript rewrites all secrets of the tenant cluster once per
+// KMS key, so that secrets written with the aescbc provider or encrypted with
+// a rotated KMS key get encrypted with the current KMS key. The secrets are
+// only rewritten once every master runs the KMS plugin with the current key,
+// because API servers of outdated masters would otherwise keep writing secrets
+// the other API servers cannot decrypt. The migration is recorded in a config
+// map and skipped afterwards.
+const ReencryptSecretsScript = `#!/bin/bash
+set -eu
+
+export KUBECONFIG=/etc/kubernetes/kubeconfig/addons.yaml
+# kubectl 1.12.2
+KUBECTL={{ .RegistryDomain }}/giantswarm/docker-kubectl:f5cae44c480bd797dc770dd5f62d40b74063c0d7
+
+kubectl() {
+  /usr/bin/docker run -i -e KUBECONFIG=${KUBECONFIG} --net=host --rm -v /etc/kubernetes:/etc/kubernetes $KUBECTL "$@"
+}
+
+until kubectl get --raw /healthz; do
+  echo "waiting for k8s api"
+  sleep 5
+done
+
+KEY="{{ .EncryptionKey }}"
+MASTER_COUNT={{ .MasterCount }}
+
+if [ "$(kubectl -n kube-system get configmap encryption-provider -o jsonpath='{.data.key}' 2>/dev/null)" == "${KEY}" ]; then
+  echo "secrets are already encrypted with the kms provider and the current key"
+  exit 0
+fi
+
+# all_masters_use_key succeeds once the expected number of masters is
+# registered and every one of them runs a ready KMS plugin with the current
+# key. Masters of a rolling update which are not replaced yet still lack the
+# plugin or run it with the previous key.
+all_masters_use_key() {
+  masters=$(kubectl get nodes --selector role=master -o jsonpath='{range .items[*]}{.metadata.name}{"\n"}{end}')
+  if [ "$(echo "${masters}" | grep -c .)" -lt "${MASTER_COUNT}" ]; then
+    return 1
+  fi
+
+  plugins=$(kubectl -n kube-system get pods -o jsonpath='{range .items[*]}{.metadata.name}{" "}{.spec.nodeName}{" "}{.status.containerStatuses[0].ready}{" "}{.spec.containers[0].command}{"\n"}{end}' | grep '^aws-encryption-provider-' | grep ' true ' | grep -F -- "--key=${KEY}" | awk '{print $2}')
+  for master in ${masters}; do
+    if ! echo "${plugins}" | grep -qx "${master}"; then
+      return 1
+    fi
+  done
+
+  return 0
+}
+
+until all_masters_use_key; do
+  echo "waiting for all masters to run the kms plugin with the current key"
+  sleep 30
+done
+
+kubectl get secrets --all-namespaces -o json | kubectl replace -f -
+
+kubectl -n kube-system create configmap encryption-provider --from-literal=provider=kms --from-literal=key=${KEY} --dry-run -o yaml | kubectl apply -f -
+`
+
+const ReencryptSecretsService = `
+[Unit]
+Description=re-encrypt secrets with the kms provider
+Wants=k8s-addons.service
+After=k8s-addons.service
+
+[Service]
+Type=oneshot
+Restart=on-failure
+RestartSec=30
+ExecStart=/opt/bin/reencrypt-secrets
+
+[Install]
+WantedBy=multi-user.target
+`
@@ -0,0 +1,155 @@
+package cloudconfig
+
+// StackedEtcdDropIn overrides the single node etcd3 unit of k8scloudconfig for
+// tenant clusters with multiple masters. All masters run an etcd member and
+// form one etcd cluster.
+const StackedEtcdDropIn = `
+[Service]
+ExecStart=
+ExecStart=/opt/bin/stacked-etcd
+`
+
+// StackedEtcdScript starts the local etcd member of a tenant cluster with
+// multiple masters. The members are reachable through the fixed private IPs of
+// the masters, so the master finds its member name by looking up its own IP in
+// the initial cluster. The etcd data directory lives on the persistent etcd
+// volume of the master, which is why a replaced master rejoins the cluster as
+// the same member. Peer traffic uses the etcd peer certificate, which has the
+// fixed private IPs of the masters as IP SANs. The surge master added during
+// rolling master updates is no member of the initial cluster. It joins the
+// existing etcd cluster instead.
+const StackedEtcdScript = `#!/bin/bash
+set -eu
+
+INITIAL_CLUSTER="{{ .EtcdInitialCluster }}"
+INITIAL_CLUSTER_STATE="new"
+SURGE_MEMBER="{{ .EtcdSurgeMember }}"
+
+ETCD_NAME=""
+for member in ${INITIAL_CLUSTER//,/ }; do
+  if [ "${member#*=}" == "https://${DEFAULT_IPV4}:2380" ]; then
+    ETCD_NAME="${member%%=*}"
+  fi
+done
+
+if [ -z "${ETCD_NAME}" ] && [ "${SURGE_MEMBER#*=}" == "https://${DEFAULT_IPV4}:2380" ]; then
+  ETCD_NAME="${SURGE_MEMBER%%=*}"
+  INITIAL_CLUSTER="${INITIAL_CLUSTER},${SURGE_MEMBER}"
+  INITIAL_CLUSTER_STATE="existing"
+
+  if [ ! -d /var/lib/etcd/member ]; then
+    /opt/bin/etcd-surge-member join
+  fi
+fi
+
+if [ -z "${ETCD_NAME}" ]; then
+  echo "IP ${DEFAULT_IPV4} is not part of the etcd initial cluster ${INITIAL_CLUSTER}" >&2
+  exit 1
+fi
+
+exec /usr/bin/docker run \
+  -v /etc/ssl/certs/ca-certificates.crt:/etc/ssl/certs/ca-certificates.crt \
+  -v /etc/kubernetes/ssl/etcd/:/etc/etcd \
+  -v /var/lib/etcd/:/var/lib/etcd \
+  --net=host \
+  --name ${NAME} \
+  ${IMAGE} \
+  etcd \
+  --name ${ETCD_NAME} \
+  --trusted-ca-file /etc/etcd/server-ca.pem \
+  --cert-file /etc/etcd/server-crt.pem \
+  --key-file /etc/etcd/server-key.pem \
+  --client-cert-auth=true \
+  --peer-trusted-ca-file /etc/etcd/peer-ca.pem \
+  --peer-cert-file /etc/etcd/peer-crt.pem \
+  --peer-key-file /etc/etcd/peer-key.pem \
+  --peer-client-cert-auth=true \
+  --advertise-client-urls=https://{{ .Cluster.Etcd.Domain }}:{{ .Cluster.Etcd.Port }} \
+  --initial-advertise-peer-urls=https://${DEFAULT_IPV4}:2380 \
+  --listen-client-urls=https://0.0.0.0:2379 \
+  --listen-peer-urls=https://${DEFAULT_IPV4}:2380 \
+  --initial-cluster-token k8s-etcd-cluster \
+  --initial-cluster ${INITIAL_CLUSTER} \
+  --initial-cluster-state ${INITIAL_CLUSTER_STATE} \
+  --data-dir=/var/lib/etcd \
+  --enable-v2
+`
+
+// EtcdSurgeMemberScript adds the surge master added during rolling master
+// updates to the etcd cluster before its etcd member starts, and removes it
+// again when the surge master shuts down. Other masters do nothing. Membership
+// changes go through the etcd load balancer when joining, since the local
+// member is not running yet, and through the local member when leaving.
+const EtcdSurgeMemberScript = `#!/bin/bash
+set -eu
+
+source /etc/network-environment
+
+SURGE_MEMBER="{{ .EtcdSurgeMember }}"
+PEER_URL="https://${DEFAULT_IPV4}:2380"
+
+if [ "${SURGE_MEMBER#*=}" != "${PEER_URL}" ]; then
+  echo "not running the etcd surge member, nothing to do"
+  exit 0
+fi
+
+ETCDCTL_FLAGS="--cacert /etc/etcd/server-ca.pem --cert /etc/etcd/server-crt.pem --key /etc/etcd/server-key.pem"
+
+member_id() {
+  "$@" member list | awk -F ', ' -v url="${PEER_URL}" '$4 == url { print $1 }'
+}
+
+case "${1:-}" in
+join)
+  ETCDCTL="/usr/bin/docker run --rm --net=host -v /etc/kubernetes/ssl/etcd/:/etc/etcd -e ETCDCTL_API=3 ${IMAGE} etcdctl --endpoints https://{{ .Cluster.Etcd.Domain }}:{{ .Cluster.Etcd.Port }} ${ETCDCTL_FLAGS}"
+
+  # A previous surge master with the same IP may not have left the cluster.
+  # Its data is gone together with its etcd volume, so it is removed before
+  # joining again.
+  ID="$(member_id ${ETCDCTL})"
+  if [ -n "${ID}" ]; then
+    echo "removing stale etcd member ${ID} with peer URL ${PEER_URL}"
+    ${ETCDCTL} member remove ${ID}
+  fi
+
+  echo "adding etcd member ${SURGE_MEMBER%%=*} with peer URL ${PEER_URL}"
+  ${ETCDCTL} member add ${SURGE_MEMBER%%=*} --peer-urls=${PEER_URL}
+  ;;
+leave)
+  ETCDCTL="/usr/bin/docker exec -e ETCDCTL_API=3 etcd3.service etcdctl --endpoints https://127.0.0.1:2379 ${ETCDCTL_FLAGS}"
+
+  ID="$(member_id ${ETCDCTL})"
+  if [ -z "${ID}" ]; then
+    echo "etcd member with peer URL ${PEER_URL} already left"
+    exit 0
+  fi
+
+  echo "removing etcd member ${ID} with peer URL ${PEER_URL}"
+  ${ETCDCTL} member remove ${ID}
+  ;;
+*)
+  echo "usage: $0 join|leave" >&2
+  exit 1
+  ;;
+esac
+`
+
+// EtcdSurgeMemberLeaveService removes the surge master from the etcd cluster
+// when it shuts down. It is ordered after etcd, so it is stopped before etcd
+// on shutdown. That way the etcd cluster of the tenant cluster is back to its
+// odd number of members once the surge master is gone.
+const EtcdSurgeMemberLeaveService = `
+[Unit]
+Description=etcd surge member leave
+Requires=etcd3.service
+After=etcd3.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/true
+ExecStop=/opt/bin/etcd-surge-member leave
+
+[Install]
+WantedBy=multi-user.target
+`
@@ -3,7 +3,8 @@ package tccp
 const AutoScalingGroup = `
 {{define "autoscaling_group"}}
 {{- $v := .Guest.AutoScalingGroup }}
-  {{ $v.ASGType }}AutoScalingGroup:
+{{- range $p := $v.WorkerPools }}
+  {{ $p.ASGType }}AutoScalingGroup:
     Type: "AWS::AutoScaling::AutoScalingGroup"
     Properties:
       VPCZoneIdentifier:
@@ -14,18 +15,39 @@ const AutoScalingGroup = `
       {{- range $az := $v.WorkerAZs }}
         - {{ $az }}
       {{end}}
-      DesiredCapacity: {{ $v.ASGDesiredCapacity }}
-      MinSize: {{ $v.ASGMinSize }}
-      MaxSize: {{ $v.ASGMaxSize }}
-      LaunchConfigurationName: !Ref {{ $v.ASGType }}LaunchConfiguration
+      DesiredCapacity: {{ $p.ASGDesiredCapacity }}
+      MinSize: {{ $p.ASGMinSize }}
+      MaxSize: {{ $p.ASGMaxSize }}
+      MixedInstancesPolicy:
+        InstancesDistribution:
+          OnDemandBaseCapacity: {{ $p.InstanceDistribution.OnDemandBaseCapacity }}
+          OnDemandPercentageAboveBaseCapacity: {{ $p.InstanceDistribution.OnDemandPercentageAboveBaseCapacity }}
+          SpotAllocationStrategy: lowest-price
+        LaunchTemplate:
+          LaunchTemplateSpecification:
+            LaunchTemplateId: !Ref {{ $p.ASGType }}LaunchTemplate
+            Version: !GetAtt {{ $p.ASGType }}LaunchTemplate.LatestVersionNumber
+          {{- if $p.InstanceTypeOverrides }}
+          Overrides:
+          {{- range $p.InstanceTypeOverrides }}
+          - InstanceType: {{ . }}
+          {{- end }}
+          {{- end }}
+      {{- if $v.IngressTargetGroupResourceNames }}
+      TargetGroupARNs:
+      {{- range $v.IngressTargetGroupResourceNames }}
+        - !Ref {{ . }}
+      {{- end }}
+      {{- else }}
       LoadBalancerNames:
         - !Ref IngressLoadBalancer
+      {{- end }}
       HealthCheckGracePeriod: {{ $v.HealthCheckGracePeriod }}
       MetricsCollection:
         - Granularity: "1Minute"
       Tags:
         - Key: Name
-          Value: {{ $v.ClusterID }}-{{ $v.ASGType }}
+          Value: {{ $v.ClusterID }}-{{ $p.Name }}
           PropagateAtLaunch: true
         - Key: k8s.io/cluster-autoscaler/enabled
           Value: true
@@ -36,10 +58,11 @@ const AutoScalingGroup = `
     UpdatePolicy:
       AutoScalingRollingUpdate:
         # minimum amount of instances that must always be running during a rolling update
-        MinInstancesInService: {{ $v.MinInstancesInService }}
+        MinInstancesInService: {{ $p.MinInstancesInService }}
         # only do a rolling update of this amount of instances max
-        MaxBatchSize: {{ $v.MaxBatchSize }}
+        MaxBatchSize: {{ $p.MaxBatchSize }}
         # after creating a new instance, pause operations on the ASG for this amount of time
         PauseTime: {{ $v.RollingUpdatePauseTime }}
+{{- end }}
 {{end}}
 `
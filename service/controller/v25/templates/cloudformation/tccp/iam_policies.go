@@ -27,9 +27,27 @@ const IAMPolicies = `
             Action: "ec2:*"
             Resource: "*"
 {{ if $v.KMSKeyARN }}
+          # The KMS plugin of the API server encrypts and decrypts secrets with
+          # the KMS key of the tenant cluster.
           - Effect: "Allow"
-            Action: "kms:Decrypt"
+            Action:
+              - "kms:Decrypt"
+              - "kms:Encrypt"
+              - "kms:GenerateDataKey"
             Resource: "{{ $v.KMSKeyARN }}"
+{{ end }}
+{{ if $v.PreviousKMSKeyARN }}
+          # Secrets stay encrypted with the previous KMS key until they got
+          # re-encrypted after an encryption key rotation.
+          - Effect: "Allow"
+            Action: "kms:Decrypt"
+            Resource: "{{ $v.PreviousKMSKeyARN }}"
+{{ end }}
+{{ if $v.SSMParameterPathARN }}
+          # The nodes fetch their TLS assets and keys from the Parameter Store.
+          - Effect: "Allow"
+            Action: "ssm:GetParameter"
+            Resource: "{{ $v.SSMParameterPathARN }}"
 {{ end }}
           - Effect: "Allow"
             Action:
@@ -45,6 +63,10 @@ const IAMPolicies = `
             Action: "s3:GetObject"
             Resource: "arn:{{ $v.RegionARN }}:s3:::{{ $v.S3Bucket }}/*"
 
+          - Effect: "Allow"
+            Action: "s3:PutObject"
+            Resource: "arn:{{ $v.RegionARN }}:s3:::{{ $v.S3Bucket }}/{{ $v.EtcdBackupPrefix }}*"
+
           - Effect: "Allow"
             Action: "elasticloadbalancing:*"
             Resource: "*"
@@ -109,6 +131,11 @@ const IAMPolicies = `
           - Effect: "Allow"
             Action: "kms:Decrypt"
             Resource: "{{ $v.KMSKeyARN }}"
+{{ end }}
+{{ if $v.SSMParameterPathARN }}
+          - Effect: "Allow"
+            Action: "ssm:GetParameter"
+            Resource: "{{ $v.SSMParameterPathARN }}"
 {{ end }}
           - Effect: "Allow"
             Action:
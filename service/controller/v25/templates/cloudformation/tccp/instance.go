@@ -3,79 +3,121 @@ package tccp
 const Instance = `
 {{ define "instance" }}
 {{- $v := .Guest.Instance }}
-  {{ $v.Master.Instance.ResourceName }}:
+{{- range $m := $v.Masters }}
+  {{ $m.Instance.ResourceName }}:
     Type: "AWS::EC2::Instance"
     Description: Master instance
     DependsOn:
-    - {{ $v.Master.DockerVolume.ResourceName }}
-    - EtcdVolume
+    - {{ $m.DockerVolume.ResourceName }}
+    - {{ $m.EtcdVolume.ResourceName }}
     Properties:
-      AvailabilityZone: {{ $v.Master.AZ }}
+      AvailabilityZone: {{ $m.AZ }}
+      {{- if $m.RootVolume.Encryption.Encrypted }}
+      BlockDeviceMappings:
+      - DeviceName: {{ $m.RootVolume.DeviceName }}
+        Ebs:
+          DeleteOnTermination: true
+          Encrypted: true
+          {{- if $m.RootVolume.Encryption.KMSKeyID }}
+          KmsKeyId: {{ $m.RootVolume.Encryption.KMSKeyID }}
+          {{- end }}
+      {{- end }}
       DisableApiTermination: true
       IamInstanceProfile: !Ref MasterInstanceProfile
-      ImageId: {{ $v.Image.ID }}
-      InstanceType: {{ $v.Master.Instance.Type }}
-      Monitoring: {{ $v.Master.Instance.Monitoring }}
+      ImageId: {{ $m.ImageID }}
+      InstanceType: {{ $m.Instance.Type }}
+      Monitoring: {{ $m.Instance.Monitoring }}
+      {{- if $m.NetworkInterface }}
+      NetworkInterfaces:
+      - DeviceIndex: 0
+        NetworkInterfaceId: !Ref {{ $m.NetworkInterface.ResourceName }}
+      {{- else }}
       SecurityGroupIds:
       - !Ref MasterSecurityGroup
-      SubnetId: !Ref {{ $v.Master.PrivateSubnet }}
-      UserData: {{ $v.Master.CloudConfig }}
+      SubnetId: !Ref {{ $m.PrivateSubnet }}
+      {{- end }}
+      UserData: {{ $m.CloudConfig }}
       Tags:
       - Key: Name
         Value: {{ $v.Cluster.ID }}-master
-  {{ $v.Master.DockerVolume.ResourceName }}:
+      - Key: giantswarm.io/master-index
+        Value: "{{ $m.Index }}"
+  {{- if $m.NetworkInterface }}
+  {{ $m.NetworkInterface.ResourceName }}:
+    Type: AWS::EC2::NetworkInterface
+    Properties:
+      Description: Master network interface with fixed private IP
+      GroupSet:
+      - !Ref MasterSecurityGroup
+      PrivateIpAddress: {{ $m.NetworkInterface.PrivateIP }}
+      SubnetId: !Ref {{ $m.PrivateSubnet }}
+      Tags:
+      - Key: Name
+        Value: {{ $v.Cluster.ID }}-master
+  {{- end }}
+  {{ $m.DockerVolume.ResourceName }}:
     Type: AWS::EC2::Volume
     Properties:
-{{ if eq $v.Master.EncrypterBackend "kms" }}
+      {{- if $m.DockerVolume.Encryption.Encrypted }}
       Encrypted: true
-{{ end }}
+      {{- end }}
+      {{- if $m.DockerVolume.Encryption.KMSKeyID }}
+      KmsKeyId: {{ $m.DockerVolume.Encryption.KMSKeyID }}
+      {{- end }}
       Size: 50
       VolumeType: gp2
-      AvailabilityZone: {{ $v.Master.AZ }}
+      AvailabilityZone: {{ $m.AZ }}
       Tags:
       - Key: Name
-        Value: {{ $v.Master.DockerVolume.Name }}
-  EtcdVolume:
+        Value: {{ $m.DockerVolume.Name }}
+  {{ $m.EtcdVolume.ResourceName }}:
     Type: AWS::EC2::Volume
     Properties:
-{{ if eq $v.Master.EncrypterBackend "kms" }}
+      {{- if $m.EtcdVolume.Encryption.Encrypted }}
       Encrypted: true
-{{ end }}
+      {{- end }}
+      {{- if $m.EtcdVolume.Encryption.KMSKeyID }}
+      KmsKeyId: {{ $m.EtcdVolume.Encryption.KMSKeyID }}
+      {{- end }}
       Size: 100
       VolumeType: gp2
-      AvailabilityZone: {{ $v.Master.AZ }}
+      AvailabilityZone: {{ $m.AZ }}
       Tags:
       - Key: Name
-        Value: {{ $v.Master.EtcdVolume.Name }}
-  LogVolume:
+        Value: {{ $m.EtcdVolume.Name }}
+  {{ $m.LogVolume.ResourceName }}:
     Type: AWS::EC2::Volume
     Properties:
-{{ if eq $v.Master.EncrypterBackend "kms" }}
+      {{- if $m.LogVolume.Encryption.Encrypted }}
       Encrypted: true
-{{ end }}
+      {{- end }}
+      {{- if $m.LogVolume.Encryption.KMSKeyID }}
+      KmsKeyId: {{ $m.LogVolume.Encryption.KMSKeyID }}
+      {{- end }}
       Size: 100
       VolumeType: gp2
-      AvailabilityZone: {{ $v.Master.AZ }}
+      AvailabilityZone: {{ $m.AZ }}
       Tags:
       - Key: Name
-        Value: {{ $v.Master.LogVolume.Name }}
-  {{ $v.Master.Instance.ResourceName }}DockerMountPoint:
+        Value: {{ $m.LogVolume.Name }}
+  {{ $m.Instance.ResourceName }}DockerMountPoint:
     Type: AWS::EC2::VolumeAttachment
     Properties:
-      InstanceId: !Ref {{ $v.Master.Instance.ResourceName }}
-      VolumeId: !Ref {{ $v.Master.DockerVolume.ResourceName }}
+      InstanceId: !Ref {{ $m.Instance.ResourceName }}
+      VolumeId: !Ref {{ $m.DockerVolume.ResourceName }}
       Device: /dev/xvdc
-  {{ $v.Master.Instance.ResourceName }}EtcdMountPoint:
+  {{ $m.Instance.ResourceName }}EtcdMountPoint:
     Type: AWS::EC2::VolumeAttachment
     Properties:
-      InstanceId: !Ref {{ $v.Master.Instance.ResourceName }}
-      VolumeId: !Ref EtcdVolume
+      InstanceId: !Ref {{ $m.Instance.ResourceName }}
+      VolumeId: !Ref {{ $m.EtcdVolume.ResourceName }}
       Device: /dev/xvdh
-  {{ $v.Master.Instance.ResourceName }}LogMountPoint:
+  {{ $m.Instance.ResourceName }}LogMountPoint:
     Type: AWS::EC2::VolumeAttachment
     Properties:
-      InstanceId: !Ref {{ $v.Master.Instance.ResourceName }}
-      VolumeId: !Ref LogVolume
+      InstanceId: !Ref {{ $m.Instance.ResourceName }}
+      VolumeId: !Ref {{ $m.LogVolume.ResourceName }}
       Device: /dev/xvdf
+{{- end }}
 {{ end }}
 `
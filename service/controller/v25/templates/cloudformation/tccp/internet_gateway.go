@@ -3,6 +3,7 @@ package tccp
 const InternetGateway = `
 {{define "internet_gateway"}}
 {{- $v := .Guest.InternetGateway }}
+{{- if not $v.ExistingVPC }}
   InternetGateway:
     Type: AWS::EC2::InternetGateway
     Properties:
@@ -31,5 +32,13 @@ const InternetGateway = `
       DestinationCidrBlock: 0.0.0.0/0
       GatewayId:
         Ref: InternetGateway
+  {{- if $v.IPv6 }}
+
+  EgressOnlyInternetGateway:
+    Type: AWS::EC2::EgressOnlyInternetGateway
+    Properties:
+      VpcId: !Ref VPC
+  {{- end }}
+{{- end }}
 {{end}}
 `
@@ -0,0 +1,41 @@
+package tccp
+
+const LaunchTemplate = `
+{{define "launch_template"}}
+{{- $v := .Guest.LaunchTemplate }}
+{{- range $p := $v.WorkerPools }}
+  {{ $p.ASGType }}LaunchTemplate:
+    Type: "AWS::EC2::LaunchTemplate"
+    Properties:
+      LaunchTemplateData:
+        BlockDeviceMappings:
+        {{- range $p.BlockDeviceMappings }}
+        - DeviceName: "{{ .DeviceName }}"
+          Ebs:
+            DeleteOnTermination: {{ .DeleteOnTermination }}
+            {{- if .Encryption.Encrypted }}
+            Encrypted: true
+            {{- end }}
+            {{- if .Encryption.KMSKeyID }}
+            KmsKeyId: {{ .Encryption.KMSKeyID }}
+            {{- end }}
+            {{- if .VolumeSize }}
+            VolumeSize: {{ .VolumeSize }}
+            {{- end }}
+            VolumeType: {{ .VolumeType }}
+        {{- end }}
+        IamInstanceProfile:
+          Name: !Ref WorkerInstanceProfile
+        ImageId: {{ $v.WorkerImageID }}
+        InstanceType: {{ $p.InstanceType }}
+        Monitoring:
+          Enabled: {{ $v.WorkerInstanceMonitoring }}
+        NetworkInterfaces:
+        - AssociatePublicIpAddress: {{ $v.WorkerAssociatePublicIPAddress }}
+          DeviceIndex: 0
+          Groups:
+          - !Ref WorkerSecurityGroup
+        UserData: {{ $p.SmallCloudConfig }}
+{{- end }}
+{{end}}
+`
@@ -3,10 +3,140 @@ package tccp
 const LoadBalancers = `
 {{define "load_balancers"}}
 {{- $v := .Guest.LoadBalancers }}
+{{- if $v.NetworkLoadBalancersEnabled }}
+  ApiNetworkLoadBalancer:
+    Type: AWS::ElasticLoadBalancingV2::LoadBalancer
+    {{- if not $v.ExistingVPC }}
+    DependsOn:
+      - VPCGatewayAttachment
+    {{- end }}
+    Properties:
+      LoadBalancerAttributes:
+      - Key: load_balancing.cross_zone.enabled
+        Value: true
+      Name: {{ $v.APIElbName }}
+      Scheme: {{ $v.APIElbScheme }}
+      Subnets:
+      {{- range $s := $v.APIElbSubnets }}
+        - !Ref {{ $s }}
+      {{- end }}
+      Type: network
+  {{- range $v.APIElbPortsToOpen }}
+  {{ .ResourceName }}TargetGroup:
+    Type: AWS::ElasticLoadBalancingV2::TargetGroup
+    Properties:
+      HealthCheckIntervalSeconds: {{ $v.NLBHealthCheckInterval }}
+      HealthCheckProtocol: TCP
+      HealthyThresholdCount: {{ $v.ELBHealthCheckHealthyThreshold }}
+      Port: {{ .PortInstance }}
+      Protocol: TCP
+      TargetType: instance
+      Targets:
+      {{- range $v.MasterInstanceResourceNames }}
+      - Id: !Ref {{ . }}
+      {{- end }}
+      UnhealthyThresholdCount: {{ $v.ELBHealthCheckUnhealthyThreshold }}
+      VpcId: !Ref VPC
+  {{ .ResourceName }}Listener:
+    Type: AWS::ElasticLoadBalancingV2::Listener
+    Properties:
+      DefaultActions:
+      - TargetGroupArn: !Ref {{ .ResourceName }}TargetGroup
+        Type: forward
+      LoadBalancerArn: !Ref ApiNetworkLoadBalancer
+      Port: {{ .PortELB }}
+      Protocol: TCP
+  {{- end }}
+
+  EtcdNetworkLoadBalancer:
+    Type: AWS::ElasticLoadBalancingV2::LoadBalancer
+    Properties:
+      LoadBalancerAttributes:
+      - Key: load_balancing.cross_zone.enabled
+        Value: true
+      Name: {{ $v.EtcdElbName }}
+      Scheme: {{ $v.EtcdElbScheme }}
+      Subnets:
+      {{- range $s := $v.PrivateSubnets }}
+        - !Ref {{ $s }}
+      {{- end }}
+      Type: network
+  {{- range $v.EtcdElbPortsToOpen }}
+  {{ .ResourceName }}TargetGroup:
+    Type: AWS::ElasticLoadBalancingV2::TargetGroup
+    Properties:
+      HealthCheckIntervalSeconds: {{ $v.NLBHealthCheckInterval }}
+      HealthCheckProtocol: TCP
+      HealthyThresholdCount: {{ $v.ELBHealthCheckHealthyThreshold }}
+      Port: {{ .PortInstance }}
+      Protocol: TCP
+      TargetType: instance
+      Targets:
+      {{- range $v.MasterInstanceResourceNames }}
+      - Id: !Ref {{ . }}
+      {{- end }}
+      UnhealthyThresholdCount: {{ $v.ELBHealthCheckUnhealthyThreshold }}
+      VpcId: !Ref VPC
+  {{ .ResourceName }}Listener:
+    Type: AWS::ElasticLoadBalancingV2::Listener
+    Properties:
+      DefaultActions:
+      - TargetGroupArn: !Ref {{ .ResourceName }}TargetGroup
+        Type: forward
+      LoadBalancerArn: !Ref EtcdNetworkLoadBalancer
+      Port: {{ .PortELB }}
+      Protocol: TCP
+  {{- end }}
+
+  IngressNetworkLoadBalancer:
+    Type: AWS::ElasticLoadBalancingV2::LoadBalancer
+    {{- if not $v.ExistingVPC }}
+    DependsOn:
+      - VPCGatewayAttachment
+    {{- end }}
+    Properties:
+      LoadBalancerAttributes:
+      - Key: load_balancing.cross_zone.enabled
+        Value: true
+      Name: {{ $v.IngressElbName }}
+      Scheme: {{ $v.IngressElbScheme }}
+      Subnets:
+      {{- range $s := $v.PublicSubnets }}
+        - !Ref {{ $s }}
+      {{- end }}
+      Type: network
+  # The worker ASGs register their instances with the ingress target groups.
+  # NLBs preserve the client IP for instance targets, so the ingress
+  # controller does not use the proxy protocol behind them.
+  {{- range $v.IngressElbPortsToOpen }}
+  {{ .ResourceName }}TargetGroup:
+    Type: AWS::ElasticLoadBalancingV2::TargetGroup
+    Properties:
+      HealthCheckIntervalSeconds: {{ $v.NLBHealthCheckInterval }}
+      HealthCheckProtocol: TCP
+      HealthyThresholdCount: {{ $v.ELBHealthCheckHealthyThreshold }}
+      Port: {{ .PortInstance }}
+      Protocol: TCP
+      TargetType: instance
+      UnhealthyThresholdCount: {{ $v.ELBHealthCheckUnhealthyThreshold }}
+      VpcId: !Ref VPC
+  {{ .ResourceName }}Listener:
+    Type: AWS::ElasticLoadBalancingV2::Listener
+    Properties:
+      DefaultActions:
+      - TargetGroupArn: !Ref {{ .ResourceName }}TargetGroup
+        Type: forward
+      LoadBalancerArn: !Ref IngressNetworkLoadBalancer
+      Port: {{ .PortELB }}
+      Protocol: TCP
+  {{- end }}
+{{- else }}
   ApiLoadBalancer:
     Type: AWS::ElasticLoadBalancing::LoadBalancer
+    {{- if not $v.ExistingVPC }}
     DependsOn:
       - VPCGatewayAttachment
+    {{- end }}
     Properties:
       ConnectionSettings:
         IdleTimeout: 1200
@@ -17,7 +147,9 @@ const LoadBalancers = `
         Timeout: {{ $v.ELBHealthCheckTimeout }}
         UnhealthyThreshold: {{ $v.ELBHealthCheckUnhealthyThreshold }}
       Instances:
-      - !Ref {{ $v.MasterInstanceResourceName }}
+      {{- range $v.MasterInstanceResourceNames }}
+      - !Ref {{ . }}
+      {{- end }}
       Listeners:
       {{ range $v.APIElbPortsToOpen}}
       - InstancePort: {{ .PortInstance }}
@@ -30,7 +162,7 @@ const LoadBalancers = `
       SecurityGroups:
         - !Ref MasterSecurityGroup
       Subnets:
-      {{- range $s := $v.PublicSubnets }}
+      {{- range $s := $v.APIElbSubnets }}
         - !Ref {{ $s }}
       {{end}}
 
@@ -46,7 +178,9 @@ const LoadBalancers = `
         Timeout: {{ $v.ELBHealthCheckTimeout }}
         UnhealthyThreshold: {{ $v.ELBHealthCheckUnhealthyThreshold }}
       Instances:
-      - !Ref {{ $v.MasterInstanceResourceName }}
+      {{- range $v.MasterInstanceResourceNames }}
+      - !Ref {{ . }}
+      {{- end }}
       Listeners:
       {{ range $v.EtcdElbPortsToOpen}}
       - InstancePort: {{ .PortInstance }}
@@ -65,8 +199,10 @@ const LoadBalancers = `
 
   IngressLoadBalancer:
     Type: AWS::ElasticLoadBalancing::LoadBalancer
+    {{- if not $v.ExistingVPC }}
     DependsOn:
       - VPCGatewayAttachment
+    {{- end }}
     Properties:
       ConnectionSettings:
         IdleTimeout: 60
@@ -101,5 +237,6 @@ const LoadBalancers = `
       {{- range $s := $v.PublicSubnets }}
         - !Ref {{ $s }}
       {{end}}
+{{- end }}
 {{end}}
 `
@@ -10,6 +10,8 @@ Parameters:
   VersionBundleVersionParameter:
     Type: String
     Description: Sets the VersionBundleVersion used to generate the template.
+  {{template "vpc_parameters" .}}
+  {{template "subnet_parameters" .}}
 Resources:
   {{template "vpc" .}}
   {{template "iam_policies" .}}
@@ -20,7 +22,7 @@ Resources:
   {{template "nat_gateway" .}}
   {{template "instance" .}}
   {{template "load_balancers" .}}
-  {{template "launch_configuration" .}}
+  {{template "launch_template" .}}
   {{template "lifecycle_hooks" .}}
   {{template "autoscaling_group" .}}
   {{template "record_sets" .}}
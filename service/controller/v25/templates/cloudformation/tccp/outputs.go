@@ -2,34 +2,80 @@ package tccp
 
 const Outputs = `
 {{define "outputs"}}
-  DockerVolumeResourceName:
-    Value: {{ .Guest.Outputs.Master.DockerVolume.ResourceName }}
   {{ if .Guest.Outputs.Route53Enabled }}
   HostedZoneNameServers:
     Value: !Join [ ',', !GetAtt 'HostedZone.NameServers' ]
   {{ end }}
-  MasterImageID:
-    Value: {{ .Guest.Outputs.Master.ImageID }}
-  MasterInstanceResourceName:
-    Value: {{ .Guest.Outputs.Master.Instance.ResourceName }}
-  MasterInstanceType:
-    Value: {{ .Guest.Outputs.Master.Instance.Type }}
+  MasterCount:
+    Value: {{ .Guest.Outputs.Master.Count }}
+  MasterEtcdMemberCount:
+    Value: {{ .Guest.Outputs.Master.EtcdMemberCount }}
+  {{- range .Guest.Outputs.Master.Instances }}
+  {{ .DockerVolumeResourceName.Key }}:
+    Value: {{ .DockerVolumeResourceName.Value }}
+  {{ .ImageID.Key }}:
+    Value: {{ .ImageID.Value }}
+  {{ .ResourceName.Key }}:
+    Value: {{ .ResourceName.Value }}
+  {{ .Type.Key }}:
+    Value: {{ .Type.Value }}
+  {{ .VersionBundleVersion.Key }}:
+    Value: {{ .VersionBundleVersion.Value }}
+  {{- if .VolumeEncryption.Value }}
+  {{ .VolumeEncryption.Key }}:
+    Value: {{ .VolumeEncryption.Value }}
+  {{- end }}
+  {{- end }}
   MasterCloudConfigVersion:
     Value: {{ .Guest.Outputs.Master.CloudConfig.Version }}
+  {{- if .Guest.Outputs.Master.EtcdRestoreSnapshot }}
+  EtcdRestoreSnapshot:
+    Value: {{ .Guest.Outputs.Master.EtcdRestoreSnapshot }}
+  {{- end }}
+  {{- if .Guest.Outputs.Master.EtcdVolumeEncryption }}
+  EtcdVolumeEncryption:
+    Value: {{ .Guest.Outputs.Master.EtcdVolumeEncryption }}
+  {{- end }}
+  {{- if .Guest.Outputs.EncryptionKeyRotation }}
+  EncryptionKeyRotation:
+    Value: {{ .Guest.Outputs.EncryptionKeyRotation }}
+  {{- end }}
   VPCID:
     Value: !Ref VPC
+  {{- if .Guest.Outputs.ExistingVPC }}
+  {{- else if .Guest.Outputs.TransitGatewayEnabled }}
+  TransitGatewayAttachmentID:
+    Value: !Ref TransitGatewayAttachment
+  {{- else }}
   VPCPeeringConnectionID:
     Value: !Ref VPCPeeringConnection
+  {{- end }}
   WorkerASGName:
     Value: !Ref {{ .Guest.Outputs.Worker.ASG.Ref }}
   WorkerDockerVolumeSizeGB:
     Value: {{ .Guest.Outputs.Worker.DockerVolumeSizeGB }}
   WorkerImageID:
     Value: {{ .Guest.Outputs.Worker.ImageID }}
+  WorkerInstanceDistribution:
+    Value: "{{ .Guest.Outputs.Worker.InstanceDistribution }}"
   WorkerInstanceType:
     Value: {{ .Guest.Outputs.Worker.InstanceType }}
   WorkerCloudConfigVersion:
     Value: {{ .Guest.Outputs.Worker.CloudConfig.Version }}
+  {{- if .Guest.Outputs.Worker.PoolNames }}
+  WorkerPoolNames:
+    Value: {{ .Guest.Outputs.Worker.PoolNames }}
+  {{- end }}
+  {{- range .Guest.Outputs.Worker.Pools }}
+  {{ .ASGName.Key }}:
+    Value: !Ref {{ .ASGName.Value }}
+  {{ .DockerVolumeSizeGB.Key }}:
+    Value: {{ .DockerVolumeSizeGB.Value }}
+  {{ .InstanceDistribution.Key }}:
+    Value: "{{ .InstanceDistribution.Value }}"
+  {{ .InstanceType.Key }}:
+    Value: {{ .InstanceType.Value }}
+  {{- end }}
   VersionBundleVersion:
     Value:
       Ref: VersionBundleVersionParameter
@@ -8,22 +8,31 @@ const RecordSets = `
     Type: 'AWS::Route53::HostedZone'
     Properties:
       Name: '{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
+  {{- if $v.PrivateAPIEnabled }}
+  PrivateAPIHostedZone:
+    Type: 'AWS::Route53::HostedZone'
+    Properties:
+      Name: '{{ $v.PrivateAPIHostedZoneName }}.'
+      VPCs:
+        - VPCId: !Ref VPC
+          VPCRegion: '{{ $v.Region }}'
+  {{- end }}
   ApiRecordSet:
     Type: AWS::Route53::RecordSet
     Properties:
       AliasTarget:
-        DNSName: !GetAtt ApiLoadBalancer.DNSName
-        HostedZoneId: !GetAtt ApiLoadBalancer.CanonicalHostedZoneNameID
+        DNSName: !GetAtt {{ $v.APILoadBalancerResourceName }}.DNSName
+        HostedZoneId: !GetAtt {{ $v.APILoadBalancerResourceName }}.{{ $v.LoadBalancerHostedZoneIDAttribute }}
         EvaluateTargetHealth: false
       Name: 'api.{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
-      HostedZoneId: !Ref 'HostedZone'
+      HostedZoneId: !Ref '{{ $v.APIHostedZoneResourceName }}'
       Type: A
   EtcdRecordSet:
     Type: AWS::Route53::RecordSet
     Properties:
       AliasTarget:
-        DNSName: !GetAtt EtcdLoadBalancer.DNSName
-        HostedZoneId: !GetAtt EtcdLoadBalancer.CanonicalHostedZoneNameID
+        DNSName: !GetAtt {{ $v.EtcdLoadBalancerResourceName }}.DNSName
+        HostedZoneId: !GetAtt {{ $v.EtcdLoadBalancerResourceName }}.{{ $v.LoadBalancerHostedZoneIDAttribute }}
         EvaluateTargetHealth: false
       Name: '{{ $v.EtcdDomain }}.'
       HostedZoneId: !Ref 'HostedZone'
@@ -32,8 +41,8 @@ const RecordSets = `
     Type: AWS::Route53::RecordSet
     Properties:
       AliasTarget:
-        DNSName: !GetAtt IngressLoadBalancer.DNSName
-        HostedZoneId: !GetAtt IngressLoadBalancer.CanonicalHostedZoneNameID
+        DNSName: !GetAtt {{ $v.IngressLoadBalancerResourceName }}.DNSName
+        HostedZoneId: !GetAtt {{ $v.IngressLoadBalancerResourceName }}.{{ $v.LoadBalancerHostedZoneIDAttribute }}
         EvaluateTargetHealth: false
       Name: 'ingress.{{ $v.ClusterID }}.k8s.{{ $v.BaseDomain }}.'
       HostedZoneId: !Ref 'HostedZone'
@@ -3,6 +3,7 @@ package tccp
 const RouteTables = `
 {{ define "route_tables" }}
 {{- $v := .Guest.RouteTables }}
+{{- if not $v.ExistingVPC }}
   {{ $v.PublicRouteTableName.ResourceName }}:
     Type: AWS::EC2::RouteTable
     Properties:
@@ -11,6 +12,17 @@ const RouteTables = `
       - Key: Name
         Value: {{ $v.PublicRouteTableName.TagName }}
 
+  {{- if $v.IPv6 }}
+  InternetGatewayIPv6Route:
+    Type: AWS::EC2::Route
+    DependsOn:
+      - VPCGatewayAttachment
+    Properties:
+      RouteTableId: !Ref {{ $v.PublicRouteTableName.ResourceName }}
+      DestinationIpv6CidrBlock: '::/0'
+      GatewayId: !Ref InternetGateway
+  {{- end }}
+
   {{- range $v.PrivateRouteTableNames }}
   {{ .ResourceName }}:
     Type: AWS::EC2::RouteTable
@@ -20,6 +32,16 @@ const RouteTables = `
       - Key: Name
         Value: {{ .TagName }}
 
+  {{- if $v.TransitGatewayID }}
+  {{ .TransitGatewayRouteName }}:
+    Type: AWS::EC2::Route
+    DependsOn:
+      - TransitGatewayAttachment
+    Properties:
+      RouteTableId: !Ref {{ .ResourceName }}
+      DestinationCidrBlock: {{ $v.HostClusterCIDR }}
+      TransitGatewayId: {{ $v.TransitGatewayID }}
+  {{- else }}
   {{ .VPCPeeringRouteName }}:
     Type: AWS::EC2::Route
     Properties:
@@ -27,6 +49,17 @@ const RouteTables = `
       DestinationCidrBlock: {{ $v.HostClusterCIDR }}
       VpcPeeringConnectionId:
         Ref: "VPCPeeringConnection"
+  {{- end }}
+
+  {{- if $v.IPv6 }}
+  {{ .EgressOnlyInternetGatewayRouteName }}:
+    Type: AWS::EC2::Route
+    Properties:
+      RouteTableId: !Ref {{ .ResourceName }}
+      DestinationIpv6CidrBlock: '::/0'
+      EgressOnlyInternetGatewayId: !Ref EgressOnlyInternetGateway
+  {{- end }}
   {{ end }}
+{{- end }}
 {{ end }}
 `
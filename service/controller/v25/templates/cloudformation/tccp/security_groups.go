@@ -5,6 +5,9 @@ const SecurityGroups = `
 {{- $v := .Guest.SecurityGroups }}
   MasterSecurityGroup:
     Type: AWS::EC2::SecurityGroup
+    {{- if $v.IPv6 }}
+    DependsOn: VPCIPv6CidrBlock
+    {{- end }}
     Properties:
       GroupDescription: {{ $v.MasterSecurityGroupName }}
       VpcId: !Ref VPC
@@ -15,7 +18,13 @@ const SecurityGroups = `
         IpProtocol: {{ .Protocol }}
         FromPort: {{ .Port }}
         ToPort: {{ .Port }}
+        {{- if .SourceVPCIPv6CIDR }}
+        CidrIpv6: !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ]
+        {{- else if .SourceIPv6CIDR }}
+        CidrIpv6: '{{ .SourceIPv6CIDR }}'
+        {{- else }}
         CidrIp: {{ .SourceCIDR }}
+        {{- end }}
       {{ end }}
       {{- if $v.APIWhitelistEnabled }}
       {{- $g := .Guest.NATGateway }}
@@ -34,6 +43,9 @@ const SecurityGroups = `
 
   WorkerSecurityGroup:
     Type: AWS::EC2::SecurityGroup
+    {{- if $v.IPv6 }}
+    DependsOn: VPCIPv6CidrBlock
+    {{- end }}
     Properties:
       GroupDescription: {{ $v.WorkerSecurityGroupName }}
       VpcId: !Ref VPC
@@ -43,18 +55,34 @@ const SecurityGroups = `
         IpProtocol: {{ .Protocol }}
         FromPort: {{ .Port }}
         ToPort: {{ .Port }}
-        {{ if .SourceCIDR }}
+        {{ if .SourceVPCIPv6CIDR }}
+        CidrIpv6: !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ]
+        {{ else if .SourceIPv6CIDR }}
+        CidrIpv6: '{{ .SourceIPv6CIDR }}'
+        {{ else if .SourceCIDR }}
         CidrIp: {{ .SourceCIDR }}
         {{ else }}
         SourceSecurityGroupId: !Ref {{ .SourceSecurityGroup }}
         {{ end }}
       {{ end }}
+      {{- range $p := $v.WorkerNATGatewayPorts }}
+      {{- range $.Guest.NATGateway.Gateways }}
+      -
+        IpProtocol: tcp
+        FromPort: {{ $p }}
+        ToPort: {{ $p }}
+        CidrIp: !Join [ "/", [ !Ref {{ .NATEIPName }}, "32" ] ]
+      {{- end }}
+      {{- end }}
       Tags:
         - Key: Name
           Value:  {{ $v.WorkerSecurityGroupName }}
 
   IngressSecurityGroup:
     Type: AWS::EC2::SecurityGroup
+    {{- if $v.IPv6 }}
+    DependsOn: VPCIPv6CidrBlock
+    {{- end }}
     Properties:
       GroupDescription: {{ $v.IngressSecurityGroupName }}
       VpcId: !Ref VPC
@@ -64,14 +92,32 @@ const SecurityGroups = `
         IpProtocol: {{ .Protocol }}
         FromPort: {{ .Port }}
         ToPort: {{ .Port }}
+        {{- if .SourceVPCIPv6CIDR }}
+        CidrIpv6: !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ]
+        {{- else if .SourceIPv6CIDR }}
+        CidrIpv6: '{{ .SourceIPv6CIDR }}'
+        {{- else }}
         CidrIp: {{ .SourceCIDR }}
+        {{- end }}
       {{ end }}
+      {{- range $p := $v.IngressNATGatewayPorts }}
+      {{- range $.Guest.NATGateway.Gateways }}
+      -
+        IpProtocol: tcp
+        FromPort: {{ $p }}
+        ToPort: {{ $p }}
+        CidrIp: !Join [ "/", [ !Ref {{ .NATEIPName }}, "32" ] ]
+      {{- end }}
+      {{- end }}
       Tags:
         - Key: Name
           Value: {{ $v.IngressSecurityGroupName }}
 
   EtcdELBSecurityGroup:
     Type: AWS::EC2::SecurityGroup
+    {{- if $v.IPv6 }}
+    DependsOn: VPCIPv6CidrBlock
+    {{- end }}
     Properties:
       GroupDescription: {{ $v.EtcdELBSecurityGroupName }}
       VpcId: !Ref VPC
@@ -81,12 +127,39 @@ const SecurityGroups = `
         IpProtocol: {{ .Protocol }}
         FromPort: {{ .Port }}
         ToPort: {{ .Port }}
+        {{- if .SourceVPCIPv6CIDR }}
+        CidrIpv6: !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ]
+        {{- else if .SourceIPv6CIDR }}
+        CidrIpv6: '{{ .SourceIPv6CIDR }}'
+        {{- else }}
         CidrIp: {{ .SourceCIDR }}
+        {{- end }}
       {{ end }}
       Tags:
         - Key: Name
           Value: {{ $v.EtcdELBSecurityGroupName }}
 
+  {{- if $v.VPCEndpointSecurityGroupRules }}
+
+  VPCEndpointSecurityGroup:
+    Type: AWS::EC2::SecurityGroup
+    Properties:
+      GroupDescription: {{ $v.VPCEndpointSecurityGroupName }}
+      VpcId: !Ref VPC
+      SecurityGroupIngress:
+      {{- range $v.VPCEndpointSecurityGroupRules }}
+      -
+        Description: {{ .Description }}
+        IpProtocol: {{ .Protocol }}
+        FromPort: {{ .Port }}
+        ToPort: {{ .Port }}
+        CidrIp: {{ .SourceCIDR }}
+      {{- end }}
+      Tags:
+        - Key: Name
+          Value: {{ $v.VPCEndpointSecurityGroupName }}
+  {{- end }}
+
   # Allow all access between masters and workers for calico. This is done after
   # the other rules to avoid circular dependencies.
   MasterAllowCalicoIngressRule:
@@ -144,6 +217,8 @@ const SecurityGroups = `
       ToPort: -1
       SourceSecurityGroupId: !Ref MasterSecurityGroup
 
+  {{- if not $v.ExistingVPC }}
+
   VPCDefaultSecurityGroupEgress:
     Type: AWS::EC2::SecurityGroupEgress
     Properties:
@@ -151,5 +226,6 @@ const SecurityGroups = `
       Description: "Allow outbound traffic from loopback address."
       IpProtocol: -1
       CidrIp: 127.0.0.1/32
+  {{- end }}
 {{ end }}
 `
@@ -3,12 +3,21 @@ package tccp
 const Subnets = `
 {{ define "subnets" }}
 {{- $v := .Guest.Subnets }}
+{{- if not $v.Existing }}
   {{- range $v.PublicSubnets }}
   {{ .Name }}:
     Type: AWS::EC2::Subnet
+    {{- if $v.IPv6 }}
+    DependsOn:
+      - VPCIPv6CidrBlock
+    {{- end }}
     Properties:
       AvailabilityZone: {{ .AvailabilityZone }}
       CidrBlock: {{ .CIDR }}
+      {{- if $v.IPv6 }}
+      AssignIpv6AddressOnCreation: true
+      Ipv6CidrBlock: !Select [ {{ .IPv6CIDRIndex }}, !Cidr [ !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ], {{ $v.IPv6SubnetCount }}, 64 ] ]
+      {{- end }}
       MapPublicIpOnLaunch: {{ .MapPublicIPOnLaunch }}
       Tags:
       - Key: Name
@@ -28,9 +37,17 @@ const Subnets = `
   {{- range $v.PrivateSubnets }}
   {{ .Name }}:
     Type: AWS::EC2::Subnet
+    {{- if $v.IPv6 }}
+    DependsOn:
+      - VPCIPv6CidrBlock
+    {{- end }}
     Properties:
       AvailabilityZone: {{ .AvailabilityZone }}
       CidrBlock: {{ .CIDR }}
+      {{- if $v.IPv6 }}
+      AssignIpv6AddressOnCreation: true
+      Ipv6CidrBlock: !Select [ {{ .IPv6CIDRIndex }}, !Cidr [ !Select [ 0, !GetAtt VPC.Ipv6CidrBlocks ], {{ $v.IPv6SubnetCount }}, 64 ] ]
+      {{- end }}
       MapPublicIpOnLaunch: {{ .MapPublicIPOnLaunch }}
       Tags:
       - Key: Name
@@ -45,5 +62,23 @@ const Subnets = `
       RouteTableId: !Ref {{ .RouteTableAssociation.RouteTableName }}
       SubnetId: !Ref {{ .RouteTableAssociation.SubnetName }}
   {{ end }}
+{{- end }}
+{{ end }}
+{{ define "subnet_parameters" }}
+{{- $v := .Guest.Subnets }}
+{{- if $v.Existing }}
+  {{- range $v.PublicSubnets }}
+  {{ .Name }}:
+    Type: AWS::EC2::Subnet::Id
+    Description: Sets the ID of the existing public subnet in {{ .AvailabilityZone }}.
+    Default: {{ .ID }}
+  {{- end }}
+  {{- range $v.PrivateSubnets }}
+  {{ .Name }}:
+    Type: AWS::EC2::Subnet::Id
+    Description: Sets the ID of the existing private subnet in {{ .AvailabilityZone }}.
+    Default: {{ .ID }}
+  {{- end }}
+{{- end }}
 {{ end }}
 `
@@ -3,6 +3,7 @@ package tccp
 const VPC = `
 {{define "vpc"}}
 {{- $v := .Guest.VPC }}
+{{- if not $v.ExistingVPCID }}
   VPC:
     Type: AWS::EC2::VPC
     Properties:
@@ -14,6 +15,27 @@ const VPC = `
         Value: {{ $v.ClusterID }}
       - Key: Installation
         Value: {{ $v.InstallationName }}
+  {{- if $v.IPv6 }}
+  VPCIPv6CidrBlock:
+    Type: AWS::EC2::VPCCidrBlock
+    Properties:
+      AmazonProvidedIpv6CidrBlock: true
+      VpcId: !Ref VPC
+  {{- end }}
+  {{- if $v.TransitGatewayID }}
+  TransitGatewayAttachment:
+    Type: AWS::EC2::TransitGatewayAttachment
+    Properties:
+      SubnetIds:
+        {{- range $v.PrivateSubnetNames }}
+        - !Ref {{ . }}
+        {{- end }}
+      TransitGatewayId: {{ $v.TransitGatewayID }}
+      VpcId: !Ref VPC
+      Tags:
+        - Key: Name
+          Value: {{ $v.ClusterID }}
+  {{- else }}
   VPCPeeringConnection:
     Type: 'AWS::EC2::VPCPeeringConnection'
     Properties:
@@ -24,6 +46,7 @@ const VPC = `
       Tags:
         - Key: Name
           Value: {{ $v.ClusterID }}
+  {{- end }}
   VPCS3Endpoint:
     Type: 'AWS::EC2::VPCEndpoint'
     Properties:
@@ -46,5 +69,30 @@ const VPC = `
             Effect: "Allow"
             Action: "s3:*"
             Resource: "arn:{{ $v.RegionARN }}:s3:::*/*"
+  {{- range $v.InterfaceEndpoints }}
+  {{ .Name }}:
+    Type: AWS::EC2::VPCEndpoint
+    Properties:
+      PrivateDnsEnabled: true
+      SecurityGroupIds:
+        - !Ref VPCEndpointSecurityGroup
+      ServiceName: {{ .ServiceName }}
+      SubnetIds:
+        {{- range $v.PrivateSubnetNames }}
+        - !Ref {{ . }}
+        {{- end }}
+      VpcEndpointType: Interface
+      VpcId: !Ref VPC
+  {{- end }}
+{{- end }}
+{{end}}
+{{define "vpc_parameters"}}
+{{- $v := .Guest.VPC }}
+{{- if $v.ExistingVPCID }}
+  VPC:
+    Type: AWS::EC2::VPC::Id
+    Description: Sets the ID of the existing VPC the tenant cluster is created in.
+    Default: {{ $v.ExistingVPCID }}
+{{- end }}
 {{end}}
 `
@@ -17,6 +17,131 @@ func VersionBundle() versionbundle.Bundle {
 				Description: "Mount /var/lib/kubelet directory in an EBS Volume.",
 				Kind:        versionbundle.KindAdded,
 			},
+			{
+				Component:   "aws-operator",
+				Description: "Support multiple masters spread across availability zones with stacked etcd, using an etcd peer certificate issued for the fixed private IPs of the masters. The number of masters must be odd. The number of etcd members is recorded in the stack outputs, so a surge master is never inferred from the number of masters.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Add rolling master updates replacing one master at a time while a temporary surge master keeps the number of healthy masters, selectable per cluster via the aws-operator.giantswarm.io/master-update-strategy annotation.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Support named worker pools with their own instance type, volume size, labels, taints and scaling via the aws-operator.giantswarm.io/worker-pools annotation. Labels and taints must follow the Kubernetes syntax.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Provision workers with launch templates and mixed instances policies supporting spot instances and multiple instance types. Drain spot instances on interruption notices once, without completing lifecycle actions for them.",
+				Kind:        versionbundle.KindChanged,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Update the tenant cluster's control plane stack by means of change sets. Record the change set preview as event of the CR and optionally hold execution until it is approved via annotation. The resource names of replacing masters are derived from the replaced masters, so the change set stays the same until it is executed.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Recover the TCCP, CPF and CPI stacks from failed rollbacks and failed creations and report recovery actions in the CR status. Failed stacks of tenant clusters protected from deletion are not recreated.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Expose status, time in status and latest failed resource of the TCCP, CPF and CPI stacks as Prometheus metrics.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Back up etcd hourly into the tenant cluster's S3 bucket with configurable retention and restore masters from a backup selected via the aws-operator.giantswarm.io/etcd-restore-snapshot annotation.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Optionally snapshot etcd and persistent volumes before deleting them on cluster deletion and delete the snapshots after a retention window. Expired snapshots are deleted hourly while tenant clusters of the same AWS account are reconciled. Tenant clusters can opt in or out via the aws-operator.giantswarm.io/ebs-snapshot-on-delete annotation.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Refuse to delete tenant clusters protected via the aws-operator.giantswarm.io/deletion-protection annotation and report the refusal in the CR status. The termination protection of the TCCP stack stays enabled for all tenant clusters.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Support NLBs for the API, etcd and ingress load balancers, selectable per cluster via the aws-operator.giantswarm.io/load-balancer-type annotation. The ingress controller does not use the proxy protocol behind NLBs, which preserve the client IP. Delete NLBs, ALBs and target groups created for Services and Ingresses on cluster deletion and expose unhealthy NLB and ALB targets as metrics.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Find all load balancers of Services on cluster deletion regardless of the number of load balancers in the account and delete the security groups of their ELBs. Expose load balancers of tenant clusters which no longer exist as metrics.",
+				Kind:        versionbundle.KindFixed,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Support private Kubernetes APIs via the aws-operator.giantswarm.io/private-api annotation. The API load balancer is internal then and its DNS record lives in a private hosted zone associated with the tenant cluster VPC and the control plane VPC.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Restrict the traffic to the ingress load balancer to the CIDRs of the aws-operator.giantswarm.io/ingress-whitelist annotation. Restrict the etcd load balancer to the tenant cluster VPC, the control plane VPC and the control plane NAT gateways.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Support creating tenant clusters in an existing VPC via the aws-operator.giantswarm.io/vpc-id, aws-operator.giantswarm.io/private-subnet-ids and aws-operator.giantswarm.io/public-subnet-ids annotations. The TCCP stack then does not manage any network resources, the existing network is recorded in the CR status and deleting the tenant cluster leaves the VPC in place. Connectivity to the control plane has to be provided by the owner of the VPC.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Attach new tenant cluster VPCs to the transit gateway configured via the service.aws.transitgateway.id flag instead of peering them with the control plane VPC. The attachments can be associated with and propagate their routes to the transit gateway route table configured via the service.aws.transitgateway.routetableid flag. The transit gateway is shared with the tenant cluster account by a RAM resource share in the CPI stack, which requires both accounts to be part of the same AWS Organization with resource sharing enabled, and the transit gateway must have auto accept of shared attachments enabled. Tenant clusters which are already peered keep their peering connection.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Record IPAM allocations in the aws-operator-ipam-ledger config map in the kube-system namespace using optimistic concurrency, so that tenant clusters created at the same time never get the same subnet. Subnets can be reserved by adding reservation entries to the ledger and cluster allocations are released when the tenant cluster is deleted.",
+				Kind:        versionbundle.KindFixed,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Allocate tenant cluster subnets from the further network ranges configured via the service.installation.guest.ipam.network.additionalcidrs flag, or added at runtime as range entries of the IPAM ledger, once the primary network range is exhausted. Expose the number of allocated and free subnets per network range as metrics.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Add dual-stack networking for tenant clusters annotated with aws-operator.giantswarm.io/ipv6. Their VPC gets an Amazon-provided IPv6 CIDR block, every subnet gets a /64 of it, the private subnets route IPv6 traffic through an egress-only internet gateway and security group rules open to all IPv4 addresses or to the VPC CIDR are opened to all IPv6 addresses or to the VPC IPv6 CIDR as well.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Add VPC interface endpoints for the AWS services configured with the service.aws.vpcendpoints flag, e.g. ec2, sts or ecr.api, so that tenant cluster nodes reach them without going through the NAT gateways.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Encrypt Kubernetes secrets at rest with the KMS key of the tenant cluster through a KMS plugin on the masters when using the KMS encrypter. Existing secrets are re-encrypted once every master runs the KMS plugin with the current key.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Rotate the encryption key of a tenant cluster by setting the aws-operator.giantswarm.io/encryption-key-rotation annotation to a new rotation ID. The previous encryption key is recorded before rotating, so that interrupted rotations resume without rotating twice. The cloud configs get re-encrypted, all nodes get replaced and the previous encryption key gets retired once every master re-encrypted the secrets with the new key.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Encrypt the root, docker, kubelet, log and etcd volumes of all nodes regardless of the encrypter backend, using the default EBS key or the KMS key configured with the service.aws.ebsencryptionkey flag or the aws-operator.giantswarm.io/ebs-encryption-key annotation. Etcd volumes of existing tenant clusters keep their encryption. Unencrypted etcd volumes get recreated encrypted when restoring an etcd backup via the aws-operator.giantswarm.io/etcd-restore-snapshot annotation, and a warning is logged until then.",
+				Kind:        versionbundle.KindChanged,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Add the ssm encrypter backend, which stores the TLS assets and keys of tenant clusters as SecureString parameters under /giantswarm/<cluster-id> in the Parameter Store. Nodes fetch them with their instance profile instead of decrypting them from the cloud config, so every access is recorded in CloudTrail. Every asset has its own parameter, which only gets written when the asset changes. Encryption key rotation is not supported with the ssm encrypter backend.",
+				Kind:        versionbundle.KindAdded,
+			},
+			{
+				Component:   "aws-operator",
+				Description: "Assume the aws-operator role of tenant cluster accounts with the external ID, session duration, session name and session policy given by the optional aws.awsoperator.externalid, aws.awsoperator.sessionduration, aws.awsoperator.sessionname and aws.awsoperator.sessionpolicy keys of the credential secret.",
+				Kind:        versionbundle.KindAdded,
+			},
 		},
 		Components: []versionbundle.Component{
 			{
@@ -114,13 +114,18 @@ func New(config Config) (*Service, error) {
 		}
 	}
 
+	_, ipamNetworkRange, err := net.ParseCIDR(config.Viper.GetString(config.Flag.Service.Installation.Guest.IPAM.Network.CIDR))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	ipamAdditionalRanges, err := parseCIDRs(config.Viper.GetStringSlice(config.Flag.Service.Installation.Guest.IPAM.Network.AdditionalCIDRs))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	var clusterController *controller.Cluster
 	{
-		_, ipamNetworkRange, err := net.ParseCIDR(config.Viper.GetString(config.Flag.Service.Installation.Guest.IPAM.Network.CIDR))
-		if err != nil {
-			return nil, microerror.Mask(err)
-		}
-
 		c := controller.ClusterConfig{
 			G8sClient:    g8sClient,
 			K8sClient:    k8sClient,
@@ -134,7 +139,12 @@ func New(config Config) (*Service, error) {
 			AccessLogsExpiration:  config.Viper.GetInt(config.Flag.Service.AWS.S3AccessLogsExpiration),
 			AdvancedMonitoringEC2: config.Viper.GetBool(config.Flag.Service.AWS.AdvancedMonitoringEC2),
 			DeleteLoggingBucket:   config.Viper.GetBool(config.Flag.Service.AWS.LoggingBucket.Delete),
-			EncrypterBackend:      config.Viper.GetString(config.Flag.Service.AWS.Encrypter),
+			EBSEncryptionKey:      config.Viper.GetString(config.Flag.Service.AWS.EBSEncryptionKey),
+			EBSSnapshot: controller.ClusterConfigEBSSnapshot{
+				Enabled:       config.Viper.GetBool(config.Flag.Service.AWS.EBSSnapshot.Enabled),
+				RetentionDays: config.Viper.GetInt(config.Flag.Service.AWS.EBSSnapshot.RetentionDays),
+			},
+			EncrypterBackend: config.Viper.GetString(config.Flag.Service.AWS.Encrypter),
 			GuestAWSConfig: controller.ClusterConfigAWSConfig{
 				AccessKeyID:       config.Viper.GetString(config.Flag.Service.AWS.AccessKey.ID),
 				AccessKeySecret:   config.Viper.GetString(config.Flag.Service.AWS.AccessKey.Secret),
@@ -152,10 +162,11 @@ func New(config Config) (*Service, error) {
 				SessionToken:      config.Viper.GetString(config.Flag.Service.AWS.HostAccessKey.Session),
 				Region:            config.Viper.GetString(config.Flag.Service.AWS.Region),
 			},
-			IgnitionPath:     config.Viper.GetString(config.Flag.Service.Guest.Ignition.Path),
-			IncludeTags:      config.Viper.GetBool(config.Flag.Service.AWS.IncludeTags),
-			InstallationName: config.Viper.GetString(config.Flag.Service.Installation.Name),
-			IPAMNetworkRange: *ipamNetworkRange,
+			IgnitionPath:         config.Viper.GetString(config.Flag.Service.Guest.Ignition.Path),
+			IncludeTags:          config.Viper.GetBool(config.Flag.Service.AWS.IncludeTags),
+			InstallationName:     config.Viper.GetString(config.Flag.Service.Installation.Name),
+			IPAMAdditionalRanges: ipamAdditionalRanges,
+			IPAMNetworkRange:     *ipamNetworkRange,
 			OIDC: controller.ClusterConfigOIDC{
 				ClientID:      config.Viper.GetString(config.Flag.Service.Installation.Guest.Kubernetes.API.Auth.Provider.OIDC.ClientID),
 				IssuerURL:     config.Viper.GetString(config.Flag.Service.Installation.Guest.Kubernetes.API.Auth.Provider.OIDC.IssuerURL),
@@ -170,7 +181,12 @@ func New(config Config) (*Service, error) {
 			Route53Enabled:         config.Viper.GetBool(config.Flag.Service.AWS.Route53.Enabled),
 			RouteTables:            config.Viper.GetString(config.Flag.Service.AWS.RouteTables),
 			SSOPublicKey:           config.Viper.GetString(config.Flag.Service.Guest.SSH.SSOPublicKey),
-			VaultAddress:           config.Viper.GetString(config.Flag.Service.AWS.VaultAddress),
+			TransitGateway: controller.ClusterConfigTransitGateway{
+				ID:           config.Viper.GetString(config.Flag.Service.AWS.TransitGateway.ID),
+				RouteTableID: config.Viper.GetString(config.Flag.Service.AWS.TransitGateway.RouteTableID),
+			},
+			VaultAddress: config.Viper.GetString(config.Flag.Service.AWS.VaultAddress),
+			VPCEndpoints: config.Viper.GetStringSlice(config.Flag.Service.AWS.VPCEndpoints),
 		}
 
 		clusterController, err = controller.NewCluster(c)
@@ -218,6 +234,8 @@ func New(config Config) (*Service, error) {
 
 			AWSConfig:             awsConfig,
 			InstallationName:      config.Viper.GetString(config.Flag.Service.Installation.Name),
+			IPAMNetworkRanges:     append([]net.IPNet{*ipamNetworkRange}, ipamAdditionalRanges...),
+			IPAMSubnetMaskBits:    config.Viper.GetInt(config.Flag.Service.Installation.Guest.IPAM.Network.SubnetMaskBits),
 			TrustedAdvisorEnabled: config.Viper.GetBool(config.Flag.Service.AWS.TrustedAdvisor.Enabled),
 		}
 
@@ -278,3 +296,18 @@ func (s *Service) Boot(ctx context.Context) {
 		go s.drainerController.Boot(ctx)
 	})
 }
+
+func parseCIDRs(cidrs []string) ([]net.IPNet, error) {
+	var networks []net.IPNet
+
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		networks = append(networks, *n)
+	}
+
+	return networks, nil
+}
@@ -19,6 +19,7 @@ func commonViperSettings(f *flag.Flag, v *viper.Viper) {
 	v.Set(f.Service.AWS.HostAccessKey.Secret, "accessKeySecret")
 	v.Set(f.Service.AWS.HostAccessKey.Session, "session")
 	v.Set(f.Service.AWS.AdvancedMonitoringEC2, true)
+	v.Set(f.Service.AWS.EBSSnapshot.RetentionDays, 30)
 	v.Set(f.Service.AWS.S3AccessLogsExpiration, 365)
 	v.Set(f.Service.AWS.Region, "myregion")
 	v.Set(f.Service.AWS.PubKeyFile, "test")